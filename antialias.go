@@ -0,0 +1,403 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/goki/ki/kit"
+	"github.com/srwiley/rasterx"
+	"github.com/srwiley/scanFT"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// TextAntialias determines how text is antialiased -- set from
+// Prefs.TextRender
+var TextAntialias = AntialiasGray
+
+// TextHinting is the font hinting used for fonts when they are opened -- set
+// from Prefs.TextRender
+var TextHinting = HintingNone
+
+// GammaCorrect determines whether text and graphics are composited in
+// linear light (gamma-correct blending), instead of directly on the sRGB
+// color values -- set from Prefs.TextRender
+var GammaCorrect = false
+
+// TextAntialiases are the ways of antialiasing text
+type TextAntialiases int32
+
+const (
+	// AntialiasGray uses grayscale antialiasing: the coverage of each pixel
+	// by the glyph determines the opacity of the text color in that pixel
+	AntialiasGray TextAntialiases = iota
+
+	// AntialiasSubpixelRGB uses subpixel (LCD) antialiasing, for displays
+	// with red, green and blue subpixels in that order from left to right:
+	// the coverage of each subpixel is used separately, tripling the
+	// horizontal resolution of text
+	AntialiasSubpixelRGB
+
+	// AntialiasSubpixelBGR uses subpixel (LCD) antialiasing, for displays
+	// with blue, green and red subpixels in that order from left to right
+	AntialiasSubpixelBGR
+
+	TextAntialiasesN
+)
+
+//go:generate stringer -type=TextAntialiases
+
+var KiT_TextAntialiases = kit.Enums.AddEnumAltLower(TextAntialiasesN, false, StylePropProps, "Antialias")
+
+func (ev TextAntialiases) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *TextAntialiases) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// FontHintings are the levels of font hinting, which adjusts glyph outlines
+// to the pixel grid
+type FontHintings int32
+
+const (
+	// HintingNone renders the glyph outlines as designed
+	HintingNone FontHintings = iota
+
+	// HintingVertical only adjusts vertical positions to the pixel grid
+	HintingVertical
+
+	// HintingFull adjusts both horizontal and vertical positions to the
+	// pixel grid
+	HintingFull
+
+	FontHintingsN
+)
+
+//go:generate stringer -type=FontHintings
+
+var KiT_FontHintings = kit.Enums.AddEnumAltLower(FontHintingsN, false, StylePropProps, "Hinting")
+
+func (ev FontHintings) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *FontHintings) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// FontHinting returns the corresponding font.Hinting value
+func (fh FontHintings) FontHinting() font.Hinting {
+	switch fh {
+	case HintingVertical:
+		return font.HintingVertical
+	case HintingFull:
+		return font.HintingFull
+	}
+	return font.HintingNone
+}
+
+// TextRenderPrefs are the preferences for the low-level rendering of text
+// and graphics
+type TextRenderPrefs struct {
+	Antialias    TextAntialiases `desc:"how text is antialiased: grayscale, or subpixel (LCD) antialiasing using the separate red, green and blue subpixels of LCD displays, for sharper text on low-resolution displays -- set the order of the subpixels of your display -- subpixel antialiasing is automatically not used over transparent backgrounds, or for rotated or scaled text"`
+	Hinting      FontHintings    `desc:"font hinting: adjusts glyph outlines to the pixel grid, for sharper but less faithful text -- vertical only adjusts vertical positions, which is a good compromise for low-resolution displays"`
+	GammaCorrect bool            `desc:"composite text and graphics in linear light (gamma-correct blending), instead of directly on the sRGB color values -- gives more accurate antialiasing, and prevents light text on dark backgrounds from looking too thin"`
+}
+
+func (pf *TextRenderPrefs) Defaults() {
+	pf.Antialias = AntialiasGray
+	pf.Hinting = HintingNone
+	pf.GammaCorrect = false
+}
+
+// Apply sets the text rendering globals from these prefs -- fonts are
+// opened again if the hinting changes
+func (pf *TextRenderPrefs) Apply() {
+	TextAntialias = pf.Antialias
+	GammaCorrect = pf.GammaCorrect
+	if pf.Hinting != TextHinting {
+		TextHinting = pf.Hinting
+		FontLibrary.ResetFaces()
+	}
+}
+
+//////////////////////////////////////////////////////////////////////////////////
+//  Subpixel antialiasing
+
+// lcdFilter are the weights of the filter that spreads the coverage of each
+// subpixel to its neighbors, reducing color fringes -- as in the default
+// FreeType LCD filter (sums to 256)
+var lcdFilter = [5]int{8, 77, 86, 77, 8}
+
+// SubpixelMask returns the subpixel (LCD) antialiasing mask for given rune
+// in given face, at a dot position with given fractional horizontal offset
+// (in units of 1 / GlyphSubpixels): the R, G, B values of the mask are the
+// coverages of the respective subpixels (in display order for given
+// antialiasing mode), and A is the maximum of those, and dr is the region
+// of the mask relative to the (integer) dot position.  ok is false if the
+// glyph outline is not available, in which case grayscale antialiasing must
+// be used.  TextFontRenderMu must be locked, as the face is used.
+func SubpixelMask(face font.Face, r rune, subX int, aa TextAntialiases) (dr image.Rectangle, mask *image.RGBA, ok bool) {
+	bb, _, gok := face.GlyphBounds(r)
+	if !gok {
+		return image.ZR, nil, false
+	}
+	dx := fixed.Int26_6(subX * 64 / GlyphSubpixels)
+	dr = image.Rect((dx+bb.Min.X).Floor()-1, bb.Min.Y.Floor(), (dx+bb.Max.X).Ceil()+1, bb.Max.Y.Ceil())
+	if dr.Dx() <= 2 || dr.Dy() <= 0 {
+		return dr, nil, true // nothing to draw, e.g., a space
+	}
+	w, h := dr.Dx(), dr.Dy()
+	org := Vec2D{3 * (float32(dx)/64 - float32(dr.Min.X)), -float32(dr.Min.Y)}
+	xf := Scale2D(3, 1)
+	var p rasterx.Path
+	has := false
+	if of, isOf := face.(OutlineFace); isOf {
+		p, has = of.RuneOutline(r, org, xf)
+	} else {
+		_, f, size := FontLibrary.FaceShaper(face)
+		if f == nil {
+			return dr, nil, false
+		}
+		p, has = GlyphOutline(f, size, r, org, xf)
+	}
+	if !has {
+		return dr, nil, true
+	}
+	w3 := 3 * w
+	cov := image.NewRGBA(image.Rect(0, 0, w3, h))
+	rf := rasterx.NewFiller(w3, h, scanFT.NewScannerFT(w3, h, scanFT.NewRGBAPainter(cov)))
+	rf.SetWinding(true)
+	p.AddTo(rf)
+	rf.SetColor(color.White)
+	rf.Draw()
+	mask = image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		crow := cov.Pix[y*cov.Stride : y*cov.Stride+4*w3]
+		mrow := mask.Pix[y*mask.Stride : y*mask.Stride+4*w]
+		for x := 0; x < w; x++ {
+			var mx uint8
+			for c := 0; c < 3; c++ {
+				s := 3*x + c
+				sum := 0
+				for k, wt := range lcdFilter {
+					if si := s + k - 2; si >= 0 && si < w3 {
+						sum += wt * int(crow[4*si+3])
+					}
+				}
+				v := uint8(sum >> 8)
+				mc := c
+				if aa == AntialiasSubpixelBGR {
+					mc = 2 - c
+				}
+				mrow[4*x+mc] = v
+				if v > mx {
+					mx = v
+				}
+			}
+			mrow[4*x+3] = mx
+		}
+	}
+	return dr, mask, true
+}
+
+// DrawGlyphSubpixel draws given rune with given face and color at given dot
+// position into the dst image, within given bounds, using subpixel
+// antialiasing according to TextAntialias, and TheGlyphCache if GlyphCacheOn
+// -- pixels with a transparent background use grayscale antialiasing.
+// Returns false if subpixel antialiasing cannot be used for the glyph.
+// TextFontRenderMu must be locked.
+func DrawGlyphSubpixel(dst *image.RGBA, bounds image.Rectangle, face font.Face, r rune, dot fixed.Point26_6, clr color.Color) bool {
+	ix := dot.X.Floor()
+	subX := (int(dot.X-fixed.I(ix))*GlyphSubpixels + 32) / 64
+	if subX == GlyphSubpixels {
+		ix++
+		subX = 0
+	}
+	var mask *image.RGBA
+	var mr image.Rectangle
+	var off image.Point
+	if GlyphCacheOn {
+		page, rect, goff, ok := TheGlyphCache.SubpixelGlyph(face, r, subX, TextAntialias)
+		if !ok {
+			return false
+		}
+		mask, mr, off = page, rect, goff
+	} else {
+		dr, m, ok := SubpixelMask(face, r, subX, TextAntialias)
+		if !ok {
+			return false
+		}
+		if m != nil {
+			mask, mr, off = m, m.Bounds(), dr.Min
+		}
+	}
+	if mask == nil {
+		return true // nothing to draw
+	}
+	dr := mr.Sub(mr.Min).Add(off).Add(image.Point{ix, dot.Y.Round()})
+	idr := dr.Intersect(bounds).Intersect(dst.Bounds())
+	if idr.Empty() {
+		return true
+	}
+	sc := color.NRGBAModel.Convert(clr).(color.NRGBA)
+	sa := float32(sc.A) / (255 * 255)
+	mp := mr.Min.Add(idr.Min.Sub(dr.Min))
+	for y := idr.Min.Y; y < idr.Max.Y; y++ {
+		mi := mask.PixOffset(mp.X, mp.Y+y-idr.Min.Y)
+		di := dst.PixOffset(idr.Min.X, y)
+		for x := idr.Min.X; x < idr.Max.X; x, mi, di = x+1, mi+4, di+4 {
+			m := mask.Pix[mi : mi+4]
+			if m[3] == 0 {
+				continue
+			}
+			p := dst.Pix[di : di+4]
+			if p[3] < 255 { // transparent background: grayscale
+				g := (float32(m[0]) + float32(m[1]) + float32(m[2])) / 3 * sa
+				blendPixel(p, sc, g, g, g, g)
+			} else {
+				blendPixel(p, sc, float32(m[0])*sa, float32(m[1])*sa, float32(m[2])*sa, float32(m[3])*sa)
+			}
+		}
+	}
+	return true
+}
+
+//////////////////////////////////////////////////////////////////////////////////
+//  Gamma-correct blending
+
+// srgbToLinear converts 8-bit sRGB values to linear light, in [0..1]
+var srgbToLinear [256]float32
+
+// linearToSRGB converts linear light values, in 4096 steps, to 8-bit sRGB
+var linearToSRGB [4096]uint8
+
+func init() {
+	for i := range srgbToLinear {
+		v := float64(i) / 255
+		if v <= 0.04045 {
+			v /= 12.92
+		} else {
+			v = math.Pow((v+0.055)/1.055, 2.4)
+		}
+		srgbToLinear[i] = float32(v)
+	}
+	for i := range linearToSRGB {
+		v := float64(i) / 4095
+		if v <= 0.0031308 {
+			v *= 12.92
+		} else {
+			v = 1.055*math.Pow(v, 1/2.4) - 0.055
+		}
+		linearToSRGB[i] = uint8(math.Round(v * 255))
+	}
+}
+
+// toSRGB returns the 8-bit sRGB value for given linear light value
+func toSRGB(v float32) uint8 {
+	return linearToSRGB[int(InRange32(v, 0, 1)*4095+0.5)]
+}
+
+// blendPixel composites given (non-premultiplied) color over the
+// premultiplied RGBA pixel p, with given coverages of the red, green and
+// blue components and of the alpha, in [0..1] (including the opacity of the
+// color) -- the coverages are all the same except for subpixel
+// antialiasing.  The blending is in linear light if GammaCorrect.
+func blendPixel(p []uint8, sc color.NRGBA, cr, cg, cb, ca float32) {
+	da := float32(p[3]) / 255
+	oa := ca + da*(1-ca)
+	if oa <= 0 {
+		return
+	}
+	cv := [3]float32{cr, cg, cb}
+	sv := [3]uint8{sc.R, sc.G, sc.B}
+	for c := 0; c < 3; c++ {
+		if GammaCorrect {
+			dl := float32(0)
+			if da > 0 {
+				dl = srgbToLinear[uint8(InRange32(float32(p[c])/da, 0, 255))]
+			}
+			l := (srgbToLinear[sv[c]]*cv[c] + dl*da*(1-cv[c])) / oa
+			p[c] = uint8(float32(toSRGB(l))*oa + 0.5)
+		} else {
+			p[c] = uint8(InRange32(float32(sv[c])*cv[c]+float32(p[c])*(1-cv[c]), 0, 255) + 0.5)
+		}
+	}
+	p[3] = uint8(oa*255 + 0.5)
+}
+
+// BlendMaskGamma composites the given premultiplied src image, with the
+// given (non-premultiplied) color, over dst in region r, in linear light --
+// src is a colored glyph image (e.g., from TheGlyphCache), whose alpha is
+// the coverage times the opacity of the color
+func BlendMaskGamma(dst *image.RGBA, r image.Rectangle, src *image.RGBA, sp image.Point, clr color.Color) {
+	sc := color.NRGBAModel.Convert(clr).(color.NRGBA)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		si := src.PixOffset(sp.X, sp.Y+y-r.Min.Y)
+		di := dst.PixOffset(r.Min.X, y)
+		for x := r.Min.X; x < r.Max.X; x, si, di = x+1, si+4, di+4 {
+			sa := src.Pix[si+3]
+			if sa == 0 {
+				continue
+			}
+			a := float32(sa) / 255
+			blendPixel(dst.Pix[di:di+4], sc, a, a, a, a)
+		}
+	}
+}
+
+// renderPainter is the scanFT painter used for rendering paths into the
+// RenderState Image, which blends in linear light if GammaCorrect, and
+// otherwise uses the standard scanFT.RGBAPainter
+type renderPainter struct {
+	img   *image.RGBA
+	std   *scanFT.RGBAPainter
+	clr   color.NRGBA
+	cfunc rasterx.ColorFunc
+}
+
+// newRenderPainter returns a new renderPainter for given image
+func newRenderPainter(img *image.RGBA) *renderPainter {
+	return &renderPainter{img: img, std: scanFT.NewRGBAPainter(img)}
+}
+
+func (rp *renderPainter) SetColor(c interface{}) {
+	rp.std.SetColor(c)
+	rp.cfunc = nil
+	switch c := c.(type) {
+	case color.Color:
+		rp.clr = color.NRGBAModel.Convert(c).(color.NRGBA)
+	case rasterx.ColorFunc:
+		rp.cfunc = c
+	}
+}
+
+func (rp *renderPainter) Paint(ss []scanFT.Span, done bool, clip image.Rectangle) {
+	if !GammaCorrect {
+		rp.std.Paint(ss, done, clip)
+		return
+	}
+	b := rp.img.Bounds().Intersect(clip)
+	for _, s := range ss {
+		if s.Y < b.Min.Y || s.Y >= b.Max.Y {
+			continue
+		}
+		x0, x1 := s.X0, s.X1
+		if x0 < b.Min.X {
+			x0 = b.Min.X
+		}
+		if x1 > b.Max.X {
+			x1 = b.Max.X
+		}
+		cov := float32(s.Alpha) / 0xffff
+		di := rp.img.PixOffset(x0, s.Y)
+		for x := x0; x < x1; x, di = x+1, di+4 {
+			sc := rp.clr
+			if rp.cfunc != nil {
+				sc = color.NRGBAModel.Convert(rp.cfunc(x, s.Y)).(color.NRGBA)
+			}
+			a := cov * float32(sc.A) / 255
+			if a == 0 {
+				continue
+			}
+			blendPixel(rp.img.Pix[di:di+4], sc, a, a, a, a)
+		}
+	}
+}
@@ -0,0 +1,462 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"math"
+	"sort"
+
+	"github.com/chewxy/math32"
+	"github.com/goki/ki/ints"
+	"golang.org/x/text/unicode/bidi"
+)
+
+// This file implements the parts of the unicode bidirectional algorithm
+// (UAX #9, http://unicode.org/reports/tr9/) needed to display mixed
+// left-to-right and right-to-left text within a single line of text: the
+// bidi character classes come from golang.org/x/text/unicode/bidi, and the
+// resolution of embedding levels and reordering is done here.  Explicit
+// embedding, override and isolate control characters are not supported (they
+// are ignored), and brackets are treated as ordinary neutrals.
+
+// IsRTL returns true if the direction is right-to-left
+func (ev TextDirections) IsRTL() bool {
+	return ev == RLTB || ev == RL || ev == RTL
+}
+
+// IsLTR returns true if the direction is explicitly left-to-right -- the
+// default LRTB direction determines the paragraph direction from the first
+// strongly directional character, as in the html dir="auto" setting
+func (ev TextDirections) IsLTR() bool {
+	return ev == LR || ev == LTR
+}
+
+// BidiLevels returns the bidi embedding level of each rune in given text,
+// according to the unicode bidirectional algorithm -- even levels are
+// left-to-right and odd levels are right-to-left.  The paragraph (base)
+// direction is right-to-left for RTL directions, left-to-right for LTR, and
+// otherwise determined by the first strongly directional character.  Returns
+// nil if all of the text is left-to-right, which is the common case.
+func BidiLevels(txt []rune, dir TextDirections) []uint8 {
+	sz := len(txt)
+	if sz == 0 {
+		return nil
+	}
+	cls := make([]bidi.Class, sz)
+	hasRTL := false
+	for i, r := range txt {
+		p, _ := bidi.LookupRune(r)
+		c := p.Class()
+		switch c {
+		case bidi.R, bidi.AL, bidi.AN:
+			hasRTL = true
+		case bidi.LRO, bidi.RLO, bidi.LRE, bidi.RLE, bidi.PDF, bidi.LRI, bidi.RLI, bidi.FSI, bidi.PDI, bidi.Control:
+			c = bidi.BN // explicit formatting codes are not supported
+		}
+		cls[i] = c
+	}
+	if !hasRTL && !dir.IsRTL() {
+		return nil
+	}
+	orig := make([]bidi.Class, sz)
+	copy(orig, cls)
+
+	// paragraph level: rules P2, P3
+	var plev uint8
+	switch {
+	case dir.IsRTL():
+		plev = 1
+	case dir.IsLTR():
+		plev = 0
+	default:
+		for _, c := range cls {
+			if c == bidi.L {
+				break
+			}
+			if c == bidi.R || c == bidi.AL {
+				plev = 1
+				break
+			}
+		}
+	}
+	sos := bidi.L
+	if plev == 1 {
+		sos = bidi.R
+	}
+
+	// W1: non-spacing marks take the type of the previous char
+	prev := sos
+	for i, c := range cls {
+		switch c {
+		case bidi.NSM:
+			cls[i] = prev
+		case bidi.BN:
+		default:
+			prev = c
+		}
+	}
+	// W2, W3: european numbers after arabic letters are arabic numbers, then AL = R
+	strong := sos
+	for i, c := range cls {
+		switch c {
+		case bidi.L, bidi.R, bidi.AL:
+			strong = c
+		case bidi.EN:
+			if strong == bidi.AL {
+				cls[i] = bidi.AN
+			}
+		}
+	}
+	for i, c := range cls {
+		if c == bidi.AL {
+			cls[i] = bidi.R
+		}
+	}
+	// W4: single separators between numbers of the same type
+	for i := 1; i < sz-1; i++ {
+		pc, nc := cls[i-1], cls[i+1]
+		switch cls[i] {
+		case bidi.ES:
+			if pc == bidi.EN && nc == bidi.EN {
+				cls[i] = bidi.EN
+			}
+		case bidi.CS:
+			if pc == nc && (pc == bidi.EN || pc == bidi.AN) {
+				cls[i] = pc
+			}
+		}
+	}
+	// W5: terminators adjacent to european numbers
+	for i := 0; i < sz; i++ {
+		if cls[i] != bidi.ET {
+			continue
+		}
+		st := i
+		for i < sz && (cls[i] == bidi.ET || cls[i] == bidi.BN) {
+			i++
+		}
+		if (st > 0 && cls[st-1] == bidi.EN) || (i < sz && cls[i] == bidi.EN) {
+			for j := st; j < i; j++ {
+				cls[j] = bidi.EN
+			}
+		}
+		i--
+	}
+	// W6: remaining separators and terminators are neutral
+	for i, c := range cls {
+		switch c {
+		case bidi.ES, bidi.ET, bidi.CS:
+			cls[i] = bidi.ON
+		}
+	}
+	// W7: european numbers after left-to-right text are left-to-right
+	strong = sos
+	for i, c := range cls {
+		switch c {
+		case bidi.L, bidi.R:
+			strong = c
+		case bidi.EN:
+			if strong == bidi.L {
+				cls[i] = bidi.L
+			}
+		}
+	}
+
+	// N1, N2: neutrals take the direction of surrounding text if the same on
+	// both sides, and the paragraph direction otherwise -- numbers count as R
+	strongDir := func(c bidi.Class) (bidi.Class, bool) {
+		switch c {
+		case bidi.L:
+			return bidi.L, true
+		case bidi.R, bidi.EN, bidi.AN:
+			return bidi.R, true
+		}
+		return c, false
+	}
+	for i := 0; i < sz; i++ {
+		if _, ok := strongDir(cls[i]); ok {
+			continue
+		}
+		st := i
+		for i < sz {
+			if _, ok := strongDir(cls[i]); ok {
+				break
+			}
+			i++
+		}
+		before := sos
+		if st > 0 {
+			before, _ = strongDir(cls[st-1])
+		}
+		after := sos // eos
+		if i < sz {
+			after, _ = strongDir(cls[i])
+		}
+		nc := sos
+		if before == after {
+			nc = before
+		}
+		for j := st; j < i; j++ {
+			cls[j] = nc
+		}
+		i--
+	}
+
+	// I1, I2: implicit levels
+	lev := make([]uint8, sz)
+	for i, c := range cls {
+		l := plev
+		if plev&1 == 0 {
+			switch c {
+			case bidi.R:
+				l++
+			case bidi.AN, bidi.EN:
+				l += 2
+			}
+		} else {
+			switch c {
+			case bidi.L, bidi.EN, bidi.AN:
+				l++
+			}
+		}
+		lev[i] = l
+	}
+
+	// L1: separators, and whitespace before them and at the end of the line,
+	// are at the paragraph level
+	trail := true
+	for i := sz - 1; i >= 0; i-- {
+		switch orig[i] {
+		case bidi.S, bidi.B:
+			lev[i] = plev
+			trail = true
+		case bidi.WS, bidi.BN:
+			if trail {
+				lev[i] = plev
+			}
+		default:
+			trail = false
+		}
+	}
+	return lev
+}
+
+// BidiVisualOrder returns the visual (left-to-right display) order of runes
+// with given bidi embedding levels (as returned by BidiLevels), as indexes
+// into the logical order, by reversing runs at each odd level and above (rule
+// L2 of the unicode bidi algorithm)
+func BidiVisualOrder(levels []uint8) []int {
+	sz := len(levels)
+	order := make([]int, sz)
+	for i := range order {
+		order[i] = i
+	}
+	var maxl, minodd uint8
+	minodd = 255
+	for _, l := range levels {
+		if l > maxl {
+			maxl = l
+		}
+		if l&1 == 1 && l < minodd {
+			minodd = l
+		}
+	}
+	if minodd == 255 {
+		return order
+	}
+	for l := maxl; l >= minodd; l-- {
+		for i := 0; i < sz; i++ {
+			if levels[order[i]] < l {
+				continue
+			}
+			st := i
+			for i < sz && levels[order[i]] >= l {
+				i++
+			}
+			for a, b := st, i-1; a < b; a, b = a+1, b-1 {
+				order[a], order[b] = order[b], order[a]
+			}
+		}
+	}
+	return order
+}
+
+// bidiMirrors are the most common characters that have mirrored glyphs
+var bidiMirrors = map[rune]rune{
+	'(': ')', ')': '(',
+	'[': ']', ']': '[',
+	'{': '}', '}': '{',
+	'<': '>', '>': '<',
+	'«': '»', '»': '«',
+	'‹': '›', '›': '‹',
+	'≤': '≥', '≥': '≤',
+}
+
+// BidiMirror returns the mirror image of given rune, which is displayed in
+// place of it at right-to-left levels (e.g., ( for ) ), or the rune itself if
+// it has no mirror (rule L4 of the unicode bidi algorithm)
+func BidiMirror(r rune) rune {
+	if mr, ok := bidiMirrors[r]; ok {
+		return mr
+	}
+	return r
+}
+
+//////////////////////////////////////////////////////////////////////////////////
+//  SpanRender bidi
+
+// ReorderBidiLR computes the bidi levels of the runes in the span (unless
+// already set, e.g., for a line split from a longer paragraph), and if there
+// is any right-to-left text, saves the logical positions set by SetRunePosLR
+// in LogPos, and reorders the Render RelPos positions into visual order --
+// called at the end of SetRunePosLR
+func (sr *SpanRender) ReorderBidiLR() {
+	if len(sr.Levels) != len(sr.Text) {
+		sr.Levels = BidiLevels(sr.Text, sr.Dir)
+	}
+	if sr.Levels == nil {
+		sr.LogPos = nil
+		return
+	}
+	sr.LogPos = make([]float32, len(sr.Render))
+	for i := range sr.Render {
+		sr.LogPos[i] = sr.Render[i].RelPos.X
+	}
+	sr.bidiPosLR()
+}
+
+// bidiPosLR sets the Render RelPos X positions of bidi text in visual order,
+// from the logical positions in LogPos, keeping the logical advance of each
+// rune
+func (sr *SpanRender) bidiPosLR() {
+	sz := len(sr.Render)
+	if sr.LogPos == nil || sz == 0 {
+		return
+	}
+	order := BidiVisualOrder(sr.Levels)
+	x := sr.LogPos[0]
+	for _, li := range order {
+		ep := sr.LastPos.X
+		if li < sz-1 {
+			ep = sr.LogPos[li+1]
+		}
+		sr.Render[li].RelPos.X = x
+		x += ep - sr.LogPos[li]
+	}
+}
+
+// IsBidi returns true if the span has right-to-left text that has been
+// reordered for display, so the rune positions are not in logical order
+func (sr *SpanRender) IsBidi() bool {
+	return sr.LogPos != nil
+}
+
+// IsRTLRune returns true if the rune at given index is displayed
+// right-to-left
+func (sr *SpanRender) IsRTLRune(idx int) bool {
+	return sr.LogPos != nil && idx < len(sr.Levels) && sr.Levels[idx]&1 == 1
+}
+
+// LogPosLR returns the logical X position of the start of given rune index,
+// i.e., after the advance of all prior runes -- this is the same as the rune
+// RelPos.X except for bidi text -- returns LastPos.X if idx >= length
+func (sr *SpanRender) LogPosLR(idx int) float32 {
+	if idx >= len(sr.Render) {
+		return sr.LastPos.X
+	}
+	if sr.LogPos != nil {
+		return sr.LogPos[idx]
+	}
+	return sr.Render[idx].RelPos.X
+}
+
+// CaretPosLR returns the X position (relative to the span RelPos) of a text
+// cursor positioned before the rune at given logical index, or after the
+// last rune if idx >= length: the left edge of left-to-right runes, and the
+// right edge of right-to-left runes
+func (sr *SpanRender) CaretPosLR(idx int) float32 {
+	sz := len(sr.Render)
+	if sz == 0 {
+		return 0
+	}
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= sz {
+		if sr.LogPos == nil {
+			return sr.LastPos.X
+		}
+		if sr.IsRTLRune(sz - 1) {
+			return sr.Render[sz-1].RelPos.X
+		}
+		return sr.Render[sz-1].RelPosAfterLR()
+	}
+	if sr.IsRTLRune(idx) {
+		return sr.Render[idx].RelPosAfterLR()
+	}
+	return sr.Render[idx].RelPos.X
+}
+
+// CaretIdxLR returns the logical rune index for a text cursor at given X
+// position (relative to the span RelPos), i.e., the index (0..length) whose
+// CaretPosLR is closest to it
+func (sr *SpanRender) CaretIdxLR(x float32) int {
+	sz := len(sr.Render)
+	bi := 0
+	bd := float32(math.MaxFloat32)
+	for i := 0; i <= sz; i++ {
+		d := math32.Abs(sr.CaretPosLR(i) - x)
+		if d < bd {
+			bd = d
+			bi = i
+		}
+	}
+	return bi
+}
+
+// VisualMoveLR returns the logical rune index for moving a text cursor at
+// given logical index by given number of positions to the right (delta > 0)
+// or left (delta < 0) in visual order -- for bidi text this is not the same
+// as moving forward or backward in the text.  Cursor positions that are
+// displayed at the same location are skipped, and the index is kept within
+// 0..length.
+func (sr *SpanRender) VisualMoveLR(idx, delta int) int {
+	sz := len(sr.Render)
+	if sr.LogPos == nil {
+		return ints.MinInt(ints.MaxInt(idx+delta, 0), sz)
+	}
+	carets := make([]int, sz+1)
+	for i := range carets {
+		carets[i] = i
+	}
+	sort.SliceStable(carets, func(a, b int) bool {
+		return sr.CaretPosLR(carets[a]) < sr.CaretPosLR(carets[b])
+	})
+	vi := 0
+	for i, c := range carets {
+		if c == idx {
+			vi = i
+			break
+		}
+	}
+	cx := sr.CaretPosLR(idx)
+	for delta != 0 {
+		step := 1
+		if delta < 0 {
+			step = -1
+		}
+		nv := vi + step
+		for nv >= 0 && nv <= sz && sr.CaretPosLR(carets[nv]) == cx {
+			nv += step
+		}
+		if nv < 0 || nv > sz {
+			break
+		}
+		vi = nv
+		cx = sr.CaretPosLR(carets[vi])
+		delta -= step
+	}
+	return carets[vi]
+}
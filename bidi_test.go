@@ -0,0 +1,105 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testBidiSpec struct {
+	txt string
+	dir TextDirections
+	lev []uint8
+}
+
+var testBidiLevels = []testBidiSpec{
+	{"", LRTB, nil},
+	{"abc", LRTB, nil},
+	{"abc", LTR, nil},
+	{"a\u202bb", LRTB, nil}, // explicit embeddings are ignored
+	{"abc", RTL, []uint8{2, 2, 2}},
+	{"אבג", LRTB, []uint8{1, 1, 1}},
+	{"אבג", LTR, []uint8{1, 1, 1}},
+	{"ab אב", LRTB, []uint8{0, 0, 0, 1, 1}},
+	{"ab אב cd", LRTB, []uint8{0, 0, 0, 1, 1, 0, 0, 0}},
+	{"אב cd גד", LRTB, []uint8{1, 1, 1, 2, 2, 1, 1, 1}},
+	{"אב 12", LRTB, []uint8{1, 1, 1, 2, 2}},
+	{"אב 1,5", LRTB, []uint8{1, 1, 1, 2, 2, 2}},
+	{"ab 12 אב", RTL, []uint8{2, 2, 2, 2, 2, 1, 1, 1}},
+	{"ع 12", LRTB, []uint8{1, 1, 2, 2}}, // arabic numbers after arabic letters
+	{"אב ", LTR, []uint8{1, 1, 0}},      // trailing white space at the paragraph level
+	{"אב ", LRTB, []uint8{1, 1, 1}},
+	{"א\u05b0ב", LRTB, []uint8{1, 1, 1}}, // non-spacing mark takes the class of its base
+	{"a(ב)", LTR, []uint8{0, 0, 1, 0}},
+}
+
+func TestBidiLevels(t *testing.T) {
+	for _, tst := range testBidiLevels {
+		lev := BidiLevels([]rune(tst.txt), tst.dir)
+		if !reflect.DeepEqual(lev, tst.lev) {
+			t.Errorf("BidiLevels(%q, %v): %v != correct: %v\n", tst.txt, tst.dir, lev, tst.lev)
+		}
+	}
+}
+
+func TestBidiVisualOrder(t *testing.T) {
+	tests := []struct {
+		lev   []uint8
+		order []int
+	}{
+		{nil, []int{}},
+		{[]uint8{0, 0, 0}, []int{0, 1, 2}},
+		{[]uint8{0, 0, 1, 1, 0}, []int{0, 1, 3, 2, 4}},
+		{[]uint8{1, 1, 1}, []int{2, 1, 0}},
+		{[]uint8{1, 1, 2, 2, 1}, []int{4, 2, 3, 1, 0}},
+		{[]uint8{2, 2, 1, 2}, []int{3, 2, 0, 1}},
+	}
+	for _, tst := range tests {
+		order := BidiVisualOrder(tst.lev)
+		if !reflect.DeepEqual(order, tst.order) {
+			t.Errorf("BidiVisualOrder(%v): %v != correct: %v\n", tst.lev, order, tst.order)
+		}
+	}
+}
+
+func TestBidiMirror(t *testing.T) {
+	tests := []struct {
+		r, mr rune
+	}{
+		{'(', ')'},
+		{')', '('},
+		{'<', '>'},
+		{'«', '»'},
+		{'≥', '≤'},
+		{'a', 'a'},
+		{'א', 'א'},
+	}
+	for _, tst := range tests {
+		if mr := BidiMirror(tst.r); mr != tst.mr {
+			t.Errorf("BidiMirror(%q): %q != correct: %q\n", tst.r, mr, tst.mr)
+		}
+	}
+}
+
+func TestTextDirections(t *testing.T) {
+	tests := []struct {
+		dir      TextDirections
+		rtl, ltr bool
+	}{
+		{LRTB, false, false},
+		{RLTB, true, false},
+		{LR, false, true},
+		{RL, true, false},
+		{LTR, false, true},
+		{RTL, true, false},
+		{TBRL, false, false},
+	}
+	for _, tst := range tests {
+		if rtl, ltr := tst.dir.IsRTL(), tst.dir.IsLTR(); rtl != tst.rtl || ltr != tst.ltr {
+			t.Errorf("%v IsRTL, IsLTR: %v, %v != correct: %v, %v\n", tst.dir, rtl, ltr, tst.rtl, tst.ltr)
+		}
+	}
+}
@@ -61,6 +61,12 @@ func (bm *Bitmap) GrabRenderFrom(nii Node2D) bool {
 func (bm *Bitmap) Render2D() {
 	if bm.PushBounds() {
 		bm.DrawIntoParent(bm.Viewport)
+		if bm.Viewport != nil {
+			if prs := &bm.Viewport.Render; prs.Vector != nil {
+				pos := NewVec2DFmPoint(bm.Geom.Pos)
+				prs.Vector.DrawImage(bm.Pixels, Translate2D(pos.X, pos.Y), prs.Bounds)
+			}
+		}
 		bm.PopBounds()
 	}
 }
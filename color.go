@@ -26,9 +26,10 @@ import (
 // ColorSpec fully specifies the color for rendering -- used in FillStyle and
 // StrokeStyle
 type ColorSpec struct {
-	Source   ColorSources      `desc:"source of color (solid, gradient)"`
+	Source   ColorSources      `desc:"source of color (solid, gradient, pattern)"`
 	Color    Color             `desc:"color for solid color source"`
 	Gradient *rasterx.Gradient `desc:"gradient parameters for gradient color source"`
+	Server   PaintServer       `json:"-" xml:"-" view:"-" desc:"paint server (e.g., svg pattern) for PatternPaint color source -- see paintserver.go"`
 }
 
 var KiT_ColorSpec = kit.Types.AddType(&ColorSpec{}, nil)
@@ -42,6 +43,8 @@ const (
 	SolidColor ColorSources = iota
 	LinearGradient
 	RadialGradient
+	// PatternPaint gets the color from a PaintServer, such as an svg pattern
+	PatternPaint
 	ColorSourcesN
 )
 
@@ -65,8 +68,11 @@ const (
 
 // IsNil tests for nil solid or gradient colors
 func (cs *ColorSpec) IsNil() bool {
-	if cs.Source == SolidColor {
+	switch cs.Source {
+	case SolidColor:
 		return cs.Color.IsNil()
+	case PatternPaint:
+		return cs.Server == nil
 	}
 	return cs.Gradient == nil
 }
@@ -85,6 +91,7 @@ func (cs *ColorSpec) SetColor(cl color.Color) {
 	cs.Color.SetColor(cl)
 	cs.Source = SolidColor
 	cs.Gradient = nil
+	cs.Server = nil
 }
 
 // Copy copies a gradient, making new copies of the stops instead of
@@ -131,8 +138,11 @@ func CopyGradient(dst, src *rasterx.Gradient) {
 }
 
 // RenderColor gets the color for rendering, applying opacity and bounds for
-// gradients
+// gradients and patterns
 func (cs *ColorSpec) RenderColor(opacity float32, bounds image.Rectangle, xform Matrix2D) interface{} {
+	if cs.Source == PatternPaint && cs.Server != nil {
+		return cs.Server.PaintColor(opacity, bounds, xform)
+	}
 	if cs.Source == SolidColor || cs.Gradient == nil {
 		return rasterx.ApplyOpacity(cs.Color, float64(opacity))
 	} else {
@@ -142,7 +152,7 @@ func (cs *ColorSpec) RenderColor(opacity float32, bounds image.Rectangle, xform
 			cs.Gradient.IsRadial = false
 		}
 		SetGradientBounds(cs.Gradient, bounds)
-		return cs.Gradient.GetColorFunctionUS(float64(opacity), xform.ToRasterx())
+		return GradientColorFunc(cs.Gradient, opacity, bounds, xform)
 	}
 }
 
@@ -603,7 +613,7 @@ func (c *Color) Blend(pct float32, clr color.Color) Color {
 	pct = InRange32(pct, 0, 100.0)
 	oth := pct / 100.0
 	me := 1.0 - pct/100.0
-	f32.R = me*f32.A + oth*othc.R
+	f32.R = me*f32.R + oth*othc.R
 	f32.G = me*f32.G + oth*othc.G
 	f32.B = me*f32.B + oth*othc.B
 	f32.A = me*f32.A + oth*othc.A
@@ -823,7 +833,76 @@ func hslaf32Model(c color.Color) color.Color {
 // lookup in url
 type Gradient struct {
 	Node2DBase
-	Grad ColorSpec `desc:"the color gradient"`
+	Grad     ColorSpec `desc:"the color gradient"`
+	Ref      string    `desc:"name of another gradient that this one inherits from (e.g., svg xlink:href) -- its stops are used if this one has none, along with any attributes not set directly on this one -- cleared once resolved"`
+	RefAttrs []string  `json:"-" xml:"-" view:"-" desc:"names of the attributes set directly on this gradient, which are not inherited from the Ref gradient"`
 }
 
 var KiT_Gradient = kit.Types.AddType(&Gradient{}, nil)
+
+// gradPointAttrs are the attribute names for the gradient Points, for linear
+// and radial gradients
+var gradPointAttrs = [2][]string{{"x1", "y1", "x2", "y2"}, {"cx", "cy", "fx", "fy", "r"}}
+
+// ResolveRef resolves the inheritance from the Ref gradient, if set -- it is
+// looked up by name when needed, so it can be defined after this one, and
+// chains of references are resolved in turn
+func (gr *Gradient) ResolveRef() {
+	if gr.Ref == "" {
+		return
+	}
+	ref := strings.TrimPrefix(gr.Ref, "#")
+	gr.Ref = "" // clearing first also prevents loops
+	rg, ok := gr.FindNamedElement(ref).(*Gradient)
+	if !ok || rg == gr {
+		log.Printf("gi.Gradient ResolveRef: could not find gradient named: %v referenced from: %v\n", ref, gr.PathUnique())
+		return
+	}
+	rg.ResolveRef()
+	rgr := rg.Grad.Gradient
+	if rgr == nil {
+		return
+	}
+	if gr.Grad.Gradient == nil {
+		gr.Grad.Gradient = &rasterx.Gradient{Points: rgr.Points, Matrix: rasterx.Identity}
+		gr.Grad.Source = rg.Grad.Source
+	}
+	g := gr.Grad.Gradient
+	set := make(map[string]bool, len(gr.RefAttrs))
+	for _, an := range gr.RefAttrs {
+		set[an] = true
+	}
+	if len(g.Stops) == 0 {
+		g.Stops = make([]rasterx.GradStop, len(rgr.Stops))
+		copy(g.Stops, rgr.Stops)
+	}
+	if !set["gradientTransform"] {
+		g.Matrix = rgr.Matrix
+	}
+	if !set["gradientUnits"] {
+		g.Units = rgr.Units
+	}
+	if !set["spreadMethod"] {
+		g.Spread = rgr.Spread
+	}
+	if gr.Grad.Source != rg.Grad.Source { // geometry only inherited from same type
+		return
+	}
+	pti := 0
+	if gr.Grad.Source == RadialGradient {
+		pti = 1
+	}
+	for i, an := range gradPointAttrs[pti] {
+		if !set[an] {
+			g.Points[i] = rgr.Points[i]
+		}
+	}
+	if pti == 1 { // focus defaults to center
+		if !set["fx"] && set["cx"] {
+			g.Points[2] = g.Points[0]
+		}
+		if !set["fy"] && set["cy"] {
+			g.Points[3] = g.Points[1]
+		}
+	}
+}
@@ -47,13 +47,21 @@ func (cs *ColorSpec) SetString(clrstr string) bool {
 			ne := CurStyleNode2D.FindNamedElement(val)
 			if ne != nil {
 				if grad, ok := ne.(*Gradient); ok {
+					grad.ResolveRef()
 					*cs = grad.Grad
 					return true
 				}
+				if ps, ok := ne.(PaintServer); ok {
+					cs.Source = PatternPaint
+					cs.Server = ps
+					cs.Gradient = nil
+					return true
+				}
 			}
 		}
 		fmt.Printf("gi.Color Warning: Not able to find url: %v\n", val)
 		cs.Gradient = nil
+		cs.Server = nil
 		cs.Source = SolidColor
 		cs.Color.SetColor(color.Black)
 		return false
@@ -89,9 +97,11 @@ func (cs *ColorSpec) SetString(clrstr string) bool {
 			cs.Source = RadialGradient
 			cs.parseRadialGrad(pars)
 		}
+		cs.Server = nil
 		FixGradientStops(cs.Gradient)
 	} else {
 		cs.Gradient = nil
+		cs.Server = nil
 		cs.Source = SolidColor
 		cs.Color.SetString(clrstr, nil)
 	}
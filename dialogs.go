@@ -65,6 +65,7 @@ type Dialog struct {
 	State     DialogState `desc:"state of the dialog"`
 	SigVal    int64       `desc:"signal value that will be sent, if >= 0 (by default, DialogAccepted or DialogCanceled will be sent for standard Ok / Cancel buttons)"`
 	DialogSig ki.Signal   `json:"-" xml:"-" view:"-" desc:"signal for dialog -- sends a signal when opened, accepted, or canceled"`
+	ValidFunc func() bool `json:"-" xml:"-" view:"-" desc:"optional function that returns whether the dialog can be accepted, e.g., whether the values being edited are valid -- if it returns false, Accept does nothing, and UpdateOk makes the Ok button inactive"`
 }
 
 var KiT_Dialog = kit.Types.AddType(&Dialog{}, DialogProps)
@@ -209,11 +210,15 @@ func (dlg *Dialog) Close() {
 	}
 }
 
-// Accept accepts the dialog, activated by the default Ok button
+// Accept accepts the dialog, activated by the default Ok button -- does
+// nothing if the ValidFunc returns false
 func (dlg *Dialog) Accept() {
 	if dlg == nil {
 		return
 	}
+	if !dlg.CanAccept() {
+		return
+	}
 	dlg.State = DialogAccepted
 	if dlg.SigVal >= 0 {
 		dlg.DialogSig.Emit(dlg.This, dlg.SigVal, nil)
@@ -223,6 +228,15 @@ func (dlg *Dialog) Accept() {
 	dlg.Close()
 }
 
+// CanAccept returns whether the dialog can be accepted, based on the
+// ValidFunc if set
+func (dlg *Dialog) CanAccept() bool {
+	if dlg.ValidFunc == nil {
+		return true
+	}
+	return dlg.ValidFunc()
+}
+
 // Cancel cancels the dialog, activated by the default Cancel button
 func (dlg *Dialog) Cancel() {
 	if dlg == nil {
@@ -310,6 +324,7 @@ func (dlg *Dialog) SetPrompt(prompt string, frame *Frame) *Label {
 	if frame != nil {
 		lab := frame.AddNewChild(KiT_Label, "prompt").(*Label)
 		lab.Text = prompt
+		lab.TextSelectable = true // e.g., to copy error messages
 		dlg.StylePart(Node2D(lab))
 		return lab
 	}
@@ -348,6 +363,29 @@ func (dlg *Dialog) ButtonBox(frame *Frame) (*Layout, int) {
 	return frame.KnownChild(idx).(*Layout), idx
 }
 
+// OkButton returns the standard Ok button in the button box -- nil if not found
+func (dlg *Dialog) OkButton() *Button {
+	bb, _ := dlg.ButtonBox(dlg.Frame())
+	if bb == nil {
+		return nil
+	}
+	okk, ok := bb.ChildByName("ok", 0)
+	if !ok {
+		return nil
+	}
+	return okk.Embed(KiT_Button).(*Button)
+}
+
+// UpdateOk updates the active state of the Ok button according to CanAccept
+// -- call whenever the values that the ValidFunc checks have changed
+func (dlg *Dialog) UpdateOk() {
+	okb := dlg.OkButton()
+	if okb == nil {
+		return
+	}
+	okb.SetInactiveStateUpdt(!dlg.CanAccept())
+}
+
 // StdButtonConfig returns a kit.TypeAndNameList for calling on ConfigChildren
 // of a button box, to create standard Ok, Cancel buttons (if true),
 // optionally starting with a Stretch element that will cause the buttons to
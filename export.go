@@ -0,0 +1,118 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"os"
+
+	"github.com/goki/ki"
+)
+
+// export contains functions for exporting the rendering of any Node2D
+// subtree to image (PNG) or vector (PDF, EPS) files, using VecRecord
+
+// RecordNode2D renders the given node and everything below it, recording
+// all the drawing operations into a VecRecord, in coordinates relative to
+// the upper-left of the node's bounding box -- the node must already have
+// been laid out and rendered normally (e.g., be visible in a window)
+func RecordNode2D(gii Node2D) (*VecRecord, error) {
+	nb := gii.AsNode2D()
+	vp := nb.Viewport
+	if vp == nil {
+		return nil, fmt.Errorf("gi.RecordNode2D: node has no Viewport: %v", nb.PathUnique())
+	}
+	bb := nb.VpBBox
+	if bb.Empty() {
+		return nil, fmt.Errorf("gi.RecordNode2D: node is not visible: %v", nb.PathUnique())
+	}
+	dpi := float32(96)
+	if vp.Win != nil {
+		dpi = vp.Win.LogicalDPI()
+	}
+	rec := NewVecRecord(bb.Size(), dpi)
+
+	// every viewport within the subtree renders into its own image, so each
+	// gets a recorder with the offset of its image within the top viewport
+	var vps []*Viewport2D
+	vp.Render.Vector = rec.Renderer(image.ZP)
+	vps = append(vps, vp)
+	nb.FuncDownMeFirst(0, nb.This, func(k ki.Ki, level int, d interface{}) bool {
+		nii, _ := KiToNode2D(k)
+		if nii == nil {
+			return false
+		}
+		svp := nii.AsViewport2D()
+		if svp == nil || svp == vp {
+			return true
+		}
+		off := svp.Geom.Pos
+		for pvp := svp.Viewport; pvp != nil && pvp != vp; pvp = pvp.Viewport {
+			off = off.Add(pvp.Geom.Pos)
+		}
+		svp.Render.Vector = rec.Renderer(off)
+		vps = append(vps, svp)
+		return true
+	})
+	nb.Render2DTree()
+	for _, svp := range vps {
+		svp.Render.Vector = nil
+	}
+	rec.Translate(bb.Min.Mul(-1))
+	return rec, nil
+}
+
+// SaveNode2DPNG renders the given node and everything below it to a PNG
+// image file, at given dots-per-inch resolution (0 = screen resolution) --
+// the drawing is re-rasterized from its vector representation, so it can be
+// at a higher resolution than the screen
+func SaveNode2DPNG(gii Node2D, filename string, dpi float32) error {
+	rec, err := RecordNode2D(gii)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return saveVecRecord(filename, func(w io.Writer) error { return rec.WritePNG(w, dpi) })
+}
+
+// SaveNode2DPDF renders the given node and everything below it to a PDF
+// vector graphics file
+func SaveNode2DPDF(gii Node2D, filename string) error {
+	rec, err := RecordNode2D(gii)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return saveVecRecord(filename, rec.WritePDF)
+}
+
+// SaveNode2DEPS renders the given node and everything below it to an
+// Encapsulated PostScript vector graphics file
+func SaveNode2DEPS(gii Node2D, filename string) error {
+	rec, err := RecordNode2D(gii)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return saveVecRecord(filename, rec.WriteEPS)
+}
+
+// saveVecRecord creates the given file and calls the write function on it
+func saveVecRecord(filename string, wfun func(w io.Writer) error) error {
+	fp, err := os.Create(filename)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer fp.Close()
+	err = wfun(fp)
+	if err != nil {
+		log.Println(err)
+	}
+	return err
+}
@@ -54,26 +54,26 @@ type FontName string
 // is used in SVG text rendering -- used in Paint and in Style. Most of font
 // information is inherited.
 type FontStyle struct {
-	Color    Color           `xml:"color" inherit:"true" desc:"text color -- also defines the currentColor variable value"`
-	BgColor  ColorSpec       `xml:"background-color" desc:"background color -- not inherited, transparent by default"`
-	Opacity  float32         `xml:"opacity" desc:"alpha value to apply to all elements"`
-	Size     units.Value     `xml:"font-size" desc:"size of font to render -- convert to points when getting font to use"`
-	Family   string          `xml:"font-family" inherit:"true" desc:"font family -- ordered list of comma-separated names from more general to more specific to use -- use split on , to parse"`
-	Style    FontStyles      `xml:"font-style" inherit:"true" desc:"style -- normal, italic, etc"`
-	Weight   FontWeights     `xml:"font-weight" inherit:"true" desc:"weight: normal, bold, etc"`
-	Stretch  FontStretch     `xml:"font-stretch" inherit:"true" desc:"font stretch / condense options"`
-	Variant  FontVariants    `xml:"font-variant" inherit:"true" desc:"normal or small caps"`
-	Deco     TextDecorations `xml:"text-decoration" desc:"underline, line-through, etc -- not inherited"`
-	Shift    BaselineShifts  `xml:"baseline-shift" desc:"super / sub script -- not inherited"`
-	Face     font.Face       `view:"-" desc:"actual font codes for drawing text -- just a pointer into FontLibrary of loaded fonts"`
-	Height   float32         `desc:"reference 1.0 spacing line height of font in dots -- computed from font as ascent + descent + lineGap, where lineGap is specified by the font as the recommended line spacing"`
-	Em       float32         `desc:"Em size of font -- this is NOT actually the width of the letter M, but rather the specified point size of the font (in actual display dots, not points) -- it does NOT include the descender and will not fit the entire height of the font"`
-	Ex       float32         `desc:"Ex size of font -- this is the actual height of the letter x in the font"`
-	Ch       float32         `desc:"Ch size of font -- this is the actual width of the 0 glyph in the font"`
-	Rem      float32         `desc:"Rem size of font -- 12pt converted to same effective DPI as above measurements"`
-	FaceName string          `desc:"full name of font face as loaded -- computed based on Family, Style, Weight, etc"`
-	// todo: kerning
-	// todo: stretch -- css 3 -- not supported
+	Color     Color           `xml:"color" inherit:"true" desc:"text color -- also defines the currentColor variable value"`
+	BgColor   ColorSpec       `xml:"background-color" desc:"background color -- not inherited, transparent by default"`
+	Opacity   float32         `xml:"opacity" desc:"alpha value to apply to all elements"`
+	Size      units.Value     `xml:"font-size" desc:"size of font to render -- convert to points when getting font to use"`
+	Family    string          `xml:"font-family" inherit:"true" desc:"font family -- ordered list of comma-separated names from more general to more specific to use -- use split on , to parse"`
+	Style     FontStyles      `xml:"font-style" inherit:"true" desc:"style -- normal, italic, etc"`
+	Weight    FontWeights     `xml:"font-weight" inherit:"true" desc:"weight: normal, bold, etc"`
+	Stretch   FontStretch     `xml:"font-stretch" inherit:"true" desc:"font stretch / condense options"`
+	Variant   FontVariants    `xml:"font-variant" inherit:"true" desc:"normal or small caps -- small caps are synthesized from smaller capitals if the font does not have them"`
+	Features  string          `xml:"font-feature-settings" inherit:"true" desc:"OpenType features to turn on or off, as a comma-separated list of quoted tags with optional on, off or 1, 0 values, e.g., \"tnum\" for tabular numbers, \"smcp\" for small caps, \"ss01\" for stylistic set 1, \"liga\" off -- see FontFeatures"`
+	Variation string          `xml:"font-variation-settings" inherit:"true" desc:"values of the variation axes of variable fonts, as a comma-separated list of quoted tags and values, e.g., \"wght\" 650, \"wdth\" 80 -- these override the values from the weight, stretch and style -- see FontVariations"`
+	Deco      TextDecorations `xml:"text-decoration" desc:"underline, line-through, etc -- not inherited"`
+	Shift     BaselineShifts  `xml:"baseline-shift" desc:"super / sub script -- not inherited"`
+	Face      font.Face       `view:"-" desc:"actual font codes for drawing text -- just a pointer into FontLibrary of loaded fonts"`
+	Height    float32         `desc:"reference 1.0 spacing line height of font in dots -- computed from font as ascent + descent + lineGap, where lineGap is specified by the font as the recommended line spacing"`
+	Em        float32         `desc:"Em size of font -- this is NOT actually the width of the letter M, but rather the specified point size of the font (in actual display dots, not points) -- it does NOT include the descender and will not fit the entire height of the font"`
+	Ex        float32         `desc:"Ex size of font -- this is the actual height of the letter x in the font"`
+	Ch        float32         `desc:"Ch size of font -- this is the actual width of the 0 glyph in the font"`
+	Rem       float32         `desc:"Rem size of font -- 12pt converted to same effective DPI as above measurements"`
+	FaceName  string          `desc:"full name of font face as loaded -- computed based on Family, Style, Weight, etc"`
 }
 
 func (fs *FontStyle) Defaults() {
@@ -93,6 +93,11 @@ func (fs *FontStyle) SetStylePost(props ki.Props) {
 			}
 		}
 	}
+	if pfw, ok := props["font-weight"]; ok { // numeric weights, e.g., 650
+		if w, ok := kit.ToFloat(pfw); ok && w > 0 {
+			fs.Weight = FontWeightFromNumeric(float32(w))
+		}
+	}
 	if tds, ok := props["text-decoration"]; ok {
 		if td, ok := tds.(string); ok {
 			if td == "none" {
@@ -111,6 +116,8 @@ func (fs *FontStyle) InheritFields(par *FontStyle) {
 	fs.Weight = par.Weight
 	fs.Stretch = par.Stretch
 	fs.Variant = par.Variant
+	fs.Features = par.Features
+	fs.Variation = par.Variation
 }
 
 // SetDeco sets decoration (underline, etc), which uses bitflag to allow multiple combinations
@@ -278,6 +285,16 @@ iterloop:
 			if FontLibrary.FontAvail(fn) {
 				break iterloop
 			}
+			if iter == 0 { // variable fonts provide all weights and stretches
+				if sty != FontNormal {
+					if fn = FontNameFromMods(basenm, FontStrNormal, WeightNormal, sty); FontLibrary.IsVarFont(fn) {
+						return fn
+					}
+				}
+				if FontLibrary.IsVarFont(basenm) {
+					return basenm
+				}
+			}
 		}
 		if str != FontStrNormal {
 			hasStr := false
@@ -341,9 +358,11 @@ iterloop:
 // This is the primary method to use for loading fonts, as it uses a robust
 // fallback method to finding an appropriate font, and falls back on the
 // builtin Go font as a last resort.  The Face field will have the resulting
-// font.  The font size is always rounded to nearest integer, to produce
-// better-looking results (presumably).  The current metrics and given
-// unit.Context are updated based on the properties of the font.
+// font, with the options from FontOpts (font features, small caps, and the
+// axis values of variable fonts).  The font size is always rounded to
+// nearest integer, to produce better-looking results (presumably).  The
+// current metrics and given unit.Context are updated based on the
+// properties of the font.
 func (fs *FontStyle) OpenFont(ctxt *units.Context) {
 	fs.FaceName = fs.FaceNm()
 	if fs.Size.Dots == 0 {
@@ -354,7 +373,7 @@ func (fs *FontStyle) OpenFont(ctxt *units.Context) {
 		fmt.Printf("FontStyle Error: bad font size: %v or units context: %v\n", fs.Size, *ctxt)
 		intDots = 12
 	}
-	face, err := FontLibrary.Font(fs.FaceName, intDots)
+	face, err := FontLibrary.FontOpts(fs.FaceName, intDots, fs.FontOpts())
 	if err != nil {
 		log.Printf("%v\n", err)
 		if fs.Face == nil {
@@ -436,6 +455,9 @@ func (fs *FontStyle) SetStyleProps(parent *FontStyle, props ki.Props) {
 	// }
 	FontStyleFields.Style(fs, parent, props)
 	fs.SetStylePost(props)
+	if parent != nil {
+		fs.SetRelWeight(parent)
+	}
 }
 
 // ToDots calls ToDots on all units.Value fields in the style (recursively)
@@ -563,8 +585,8 @@ var FontWeightToNameMap = map[FontWeights]string{
 	WeightExtraBold:  "ExtraBold",
 	Weight900:        "Black",
 	WeightBlack:      "Black",
-	WeightBolder:     "Medium", // todo: lame but assumes normal and goes one bolder
-	WeightLighter:    "Light",  // todo: lame but assumes normal and goes one lighter
+	WeightBolder:     "Bold",  // only if not resolved relative to parent (see SetRelWeight)
+	WeightLighter:    "Light", // only if not resolved relative to parent
 }
 
 // FontStretch are different stretch levels of font.  These are less typically
@@ -656,7 +678,9 @@ var KiT_BaselineShifts = kit.Enums.AddEnumAltLower(BaselineShiftsN, false, Style
 func (ev BaselineShifts) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
 func (ev *BaselineShifts) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
 
-// FontVariants is just normal vs. small caps. todo: not currently supported
+// FontVariants is just normal vs. small caps -- small caps use the smcp
+// feature of the font if it has it, and are otherwise synthesized (see
+// FontStyle.FontOpts)
 type FontVariants int32
 
 const (
@@ -699,6 +723,19 @@ type FontLib struct {
 	FontsAvail map[string]string            `desc:"map of font name to path to file"`
 	FontInfo   []FontInfo                   `desc:"information about each font -- this list should be used for selecting valid regularized font names"`
 	Faces      map[string]map[int]font.Face `desc:"double-map of cached fonts, by font name and then integer font size within that"`
+	TTFonts    map[string]*truetype.Font    `desc:"cached parsed truetype fonts, by font name -- used for glyph outlines in vector rendering"`
+	ttErrs     map[string]error             // errors reading or parsing fonts in TrueTypeFont, so they are not read again
+	sfntFonts  map[string]*sfnt.Font
+	shapers    map[string]*FontShaper
+	fontVars   map[string]*FontVar
+	fontData   map[string]fontDataInfo
+	faceInfo   map[font.Face]fontFaceInfo
+	capsInfo   sync.Map // fontFaceInfo of the faces with synthesized small caps, read by RuneFace for each rune without locking
+	optFaces   map[fontOptsKey]font.Face
+	optMu      sync.Mutex
+	fbChains   map[string][]string
+	fbRunes    map[fallbackKey]string
+	fbMu       sync.Mutex
 }
 
 // FontLibrary is the gi font library, initialized from fonts available on font paths
@@ -722,6 +759,13 @@ func (fl *FontLib) Init() {
 		fl.FontsAvail = make(map[string]string)
 		fl.FontInfo = make([]FontInfo, 0, 1000)
 		fl.Faces = make(map[string]map[int]font.Face)
+		fl.TTFonts = make(map[string]*truetype.Font)
+		fl.ttErrs = make(map[string]error)
+		fl.sfntFonts = make(map[string]*sfnt.Font)
+		fl.shapers = make(map[string]*FontShaper)
+		fl.fontVars = make(map[string]*FontVar)
+		fl.fontData = make(map[string]fontDataInfo)
+		fl.faceInfo = make(map[font.Face]fontFaceInfo)
 		loadFontMu.Unlock()
 	} else if len(fl.FontsAvail) == 0 {
 		// fmt.Printf("updating fonts avail in %v\n", fl.FontPaths)
@@ -744,7 +788,7 @@ func (fl *FontLib) Font(fontnm string, size int) (font.Face, error) {
 	if path := fl.FontsAvail[fontnm]; path != "" {
 		loadFontMu.Lock()
 		defer loadFontMu.Unlock()
-		face, err := OpenFontFace(path, size, 0)
+		face, err := fl.openFontFace(path, size)
 		if err != nil {
 			log.Printf("gi.FontLib: error loading font %v, removed from list\n", fontnm)
 			fl.DeleteFont(fontnm)
@@ -756,14 +800,131 @@ func (fl *FontLib) Font(fontnm string, size int) (font.Face, error) {
 			fl.Faces[fontnm] = facemap
 		}
 		facemap[size] = face
+		fl.faceInfo[face] = fontFaceInfo{name: fontnm, size: size}
 		// fmt.Printf("Opened font face: %v %v\n", fontnm, size)
 		return face, nil
 	}
 	return nil, fmt.Errorf("gi.FontLib: Font named: %v not found in list of available fonts, try adding to FontPaths in gi.FontLibrary, searched paths: %v\n", fontnm, fl.FontPaths)
 }
 
+// FaceInfo returns the font name and integer dots size of given font face,
+// which must have been obtained from the library -- returns false if not found
+func (fl *FontLib) FaceInfo(face font.Face) (fontnm string, size int, ok bool) {
+	loadFontMu.Lock()
+	defer loadFontMu.Unlock()
+	if fi, has := fl.faceInfo[face]; has {
+		return fi.name, fi.size, true
+	}
+	for fnm, facemap := range fl.Faces {
+		for sz, fc := range facemap {
+			if fc == face {
+				return fnm, sz, true
+			}
+		}
+	}
+	return "", 0, false
+}
+
+// TrueTypeFont returns the parsed truetype font for given font name, which
+// provides access to the glyph outlines (e.g., for vector rendering) --
+// opentype (.otf) fonts are not supported and return an error
+func (fl *FontLib) TrueTypeFont(fontnm string) (*truetype.Font, error) {
+	fontnm = strings.ToLower(fontnm)
+	fl.Init()
+	loadFontMu.Lock()
+	defer loadFontMu.Unlock()
+	if f, ok := fl.TTFonts[fontnm]; ok {
+		return f, nil
+	}
+	if err, ok := fl.ttErrs[fontnm]; ok {
+		return nil, err
+	}
+	path := fl.FontsAvail[fontnm]
+	if path == "" {
+		return nil, fmt.Errorf("gi.FontLib: Font named: %v not found in list of available fonts\n", fontnm)
+	}
+	f, fontBytes, err := fl.parseTrueTypeFont(path)
+	if err != nil {
+		fl.ttErrs[fontnm] = err // not read again, e.g., by HasRune for each fallback rune
+		return nil, err
+	}
+	fl.TTFonts[fontnm] = f
+	fl.shapers[fontnm] = NewFontShaper(fontBytes)
+	fl.fontVars[fontnm] = ParseFontVar(fontBytes)
+	return f, nil
+}
+
+// parseTrueTypeFont reads and parses the truetype font at given path in
+// FontsAvail, returning the font and its data -- loadFontMu must be locked
+func (fl *FontLib) parseTrueTypeFont(path string) (*truetype.Font, []byte, error) {
+	var fontBytes []byte
+	if gf, ok := GoFonts[path]; ok {
+		fontBytes = gf.ttf
+	} else if fd, ok := fl.fontData[path]; ok {
+		if IsOpenTypeCFF(fd.data) {
+			return nil, nil, fmt.Errorf("gi.FontLib: opentype font outlines not supported: %v\n", fd.name)
+		}
+		fontBytes = fd.data
+	} else {
+		if strings.ToLower(filepath.Ext(path)) == ".otf" {
+			return nil, nil, fmt.Errorf("gi.FontLib: opentype font outlines not supported: %v\n", path)
+		}
+		var err error
+		fontBytes, err = ioutil.ReadFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	f, err := truetype.Parse(fontBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, fontBytes, nil
+}
+
+// SfntFont returns the parsed sfnt font for given font name, which reads both
+// truetype and opentype (CFF) fonts, e.g., for looking up their glyphs --
+// nil if the font cannot be read
+func (fl *FontLib) SfntFont(fontnm string) *sfnt.Font {
+	fontnm = strings.ToLower(fontnm)
+	fl.Init()
+	loadFontMu.Lock()
+	defer loadFontMu.Unlock()
+	if f, ok := fl.sfntFonts[fontnm]; ok {
+		return f
+	}
+	path := fl.FontsAvail[fontnm]
+	if path == "" {
+		return nil
+	}
+	var fontBytes []byte
+	if gf, ok := GoFonts[path]; ok {
+		fontBytes = gf.ttf
+	} else if fd, ok := fl.fontData[path]; ok {
+		fontBytes = fd.data
+	} else {
+		var err error
+		fontBytes, err = ioutil.ReadFile(path)
+		if err != nil {
+			log.Printf("gi.FontLib SfntFont: error reading font: %v err: %v\n", path, err)
+		}
+	}
+	var f *sfnt.Font
+	if fontBytes != nil {
+		var err error
+		f, err = sfnt.Parse(fontBytes)
+		if err != nil {
+			log.Printf("gi.FontLib SfntFont: error parsing font: %v err: %v\n", path, err)
+			f = nil
+		}
+	}
+	fl.sfntFonts[fontnm] = f // nil is cached too, so it is not read again
+	return f
+}
+
 func (fl *FontLib) DeleteFont(fontnm string) {
 	delete(fl.FontsAvail, fontnm)
+	fl.ResetFallbacks()
 	for i, fi := range fl.FontInfo {
 		if strings.ToLower(fi.Name) == fontnm {
 			sz := len(fl.FontInfo)
@@ -774,6 +935,20 @@ func (fl *FontLib) DeleteFont(fontnm string) {
 	}
 }
 
+// ResetFaces clears all of the cached font faces, so that they are opened
+// again with the current font rendering settings, e.g., TextHinting --
+// styles must be updated to use the new faces
+func (fl *FontLib) ResetFaces() {
+	loadFontMu.Lock()
+	fl.Faces = make(map[string]map[int]font.Face)
+	loadFontMu.Unlock()
+	fl.optMu.Lock()
+	fl.optFaces = nil
+	fl.optMu.Unlock()
+	fl.ResetFallbacks()
+	TheGlyphCache.Reset()
+}
+
 // OpenAllFonts attempts to load all fonts that were found -- call this before
 // displaying the font chooser to eliminate any bad fonts.
 func (fl *FontLib) OpenAllFonts(size int) {
@@ -803,7 +978,7 @@ func (fl *FontLib) AddFontPaths(paths ...string) bool {
 
 // UpdateFontsAvail scans for all fonts we can use on the FontPaths
 func (fl *FontLib) UpdateFontsAvail() bool {
-	if len(fl.FontPaths) == 0 {
+	if len(fl.FontPaths) == 0 && len(fl.fontData) == 0 {
 		log.Print("gi.FontLib: no font paths -- need to add some\n")
 		return false
 	}
@@ -811,14 +986,18 @@ func (fl *FontLib) UpdateFontsAvail() bool {
 	defer loadFontMu.Unlock()
 	if len(fl.FontsAvail) > 0 {
 		fl.FontsAvail = make(map[string]string)
+		fl.FontInfo = fl.FontInfo[:0]
+		fl.ttErrs = make(map[string]error) // paths can change
 	}
 	fl.GoFontsAvail()
+	fl.DataFontsAvail()
 	for _, p := range fl.FontPaths {
 		fl.FontsAvailFromPath(p)
 	}
 	sort.Slice(fl.FontInfo, func(i, j int) bool {
 		return fl.FontInfo[i].Name < fl.FontInfo[j].Name
 	})
+	fl.ResetFallbacks()
 
 	return len(fl.FontsAvail) > 0
 }
@@ -932,39 +1111,7 @@ func (fl *FontLib) FontsAvailFromPath(path string) error {
 		if !ok {
 			return nil
 		}
-		_, fn := filepath.Split(path)
-		fn = fn[:len(fn)-len(ext)]
-		bfn := fn
-		bfn = strings.TrimSuffix(fn, "bd")
-		bfn = strings.TrimSuffix(bfn, "bi")
-		bfn = strings.TrimSuffix(bfn, "z")
-		bfn = strings.TrimSuffix(bfn, "b")
-		if bfn != "calibri" && bfn != "gadugui" && bfn != "segoeui" && bfn != "segui" {
-			bfn = strings.TrimSuffix(bfn, "i")
-		}
-		if afn, ok := altFontMap[bfn]; ok {
-			sfx := ""
-			if strings.HasSuffix(fn, "bd") || strings.HasSuffix(fn, "b") {
-				sfx = " Bold"
-			} else if strings.HasSuffix(fn, "bi") || strings.HasSuffix(fn, "z") {
-				sfx = " Bold Italic"
-			} else if strings.HasSuffix(fn, "i") {
-				sfx = " Italic"
-			}
-			fn = afn + sfx
-		} else {
-			fn = strings.Replace(fn, "_", " ", -1)
-			fn = strings.Replace(fn, "-", " ", -1)
-			// fn = strings.Title(fn)
-			for sc, rp := range shortFontMods {
-				if strings.HasSuffix(fn, sc) {
-					fn = strings.TrimSuffix(fn, sc)
-					fn += rp
-					break
-				}
-			}
-		}
-		fn = FixFontMods(fn)
+		fn := FontFileName(path)
 		basefn := strings.ToLower(fn)
 		if _, ok := fl.FontsAvail[basefn]; !ok {
 			fl.FontsAvail[basefn] = path
@@ -982,6 +1129,50 @@ func (fl *FontLib) FontsAvailFromPath(path string) error {
 	return err
 }
 
+// FontFileName returns the regularized font name for the font file at given
+// path, based on the file name
+func FontFileName(path string) string {
+	_, fn := filepath.Split(path)
+	fn = fn[:len(fn)-len(filepath.Ext(fn))]
+	if vi := strings.Index(fn, "["); vi > 0 { // variable font, e.g., Name[wdth,wght]
+		fn = fn[:vi]
+	}
+	if vi := strings.Index(fn, "-VariableFont"); vi > 0 {
+		fn = fn[:vi]
+	}
+	bfn := fn
+	bfn = strings.TrimSuffix(fn, "bd")
+	bfn = strings.TrimSuffix(bfn, "bi")
+	bfn = strings.TrimSuffix(bfn, "z")
+	bfn = strings.TrimSuffix(bfn, "b")
+	if bfn != "calibri" && bfn != "gadugui" && bfn != "segoeui" && bfn != "segui" {
+		bfn = strings.TrimSuffix(bfn, "i")
+	}
+	if afn, ok := altFontMap[bfn]; ok {
+		sfx := ""
+		if strings.HasSuffix(fn, "bd") || strings.HasSuffix(fn, "b") {
+			sfx = " Bold"
+		} else if strings.HasSuffix(fn, "bi") || strings.HasSuffix(fn, "z") {
+			sfx = " Bold Italic"
+		} else if strings.HasSuffix(fn, "i") {
+			sfx = " Italic"
+		}
+		fn = afn + sfx
+	} else {
+		fn = strings.Replace(fn, "_", " ", -1)
+		fn = strings.Replace(fn, "-", " ", -1)
+		// fn = strings.Title(fn)
+		for sc, rp := range shortFontMods {
+			if strings.HasSuffix(fn, sc) {
+				fn = strings.TrimSuffix(fn, sc)
+				fn += rp
+				break
+			}
+		}
+	}
+	return FixFontMods(fn)
+}
+
 // altFontMap is an alternative font map that maps file names to more standard
 // full names (e.g., Times -> Times New Roman) -- also looks for b,i suffixes
 // for these cases -- some are added here just to pick up those suffixes.
@@ -1041,16 +1232,22 @@ func OpenFontFace(path string, size int, strokeWidth int) (font.Face, error) {
 	if err != nil {
 		return nil, err
 	}
-	ext := strings.ToLower(filepath.Ext(path))
-	if ext == ".otf" {
+	return OpenFontData(fontBytes, size, strokeWidth)
+}
+
+// OpenFontData loads a font from given font file data (truetype or
+// opentype), with given raw size in display dots, and if strokeWidth is > 0,
+// the font is drawn in outline form (stroked) instead of filled
+func OpenFontData(fontBytes []byte, size int, strokeWidth int) (font.Face, error) {
+	if IsOpenTypeCFF(fontBytes) {
 		// note: this compiles but otf fonts are NOT yet supported apparently
 		f, err := sfnt.Parse(fontBytes)
 		if err != nil {
 			return nil, err
 		}
 		face, err := opentype.NewFace(f, &opentype.FaceOptions{
-			Size: float64(size),
-			// Hinting: font.HintingFull,
+			Size:    float64(size),
+			Hinting: TextHinting.FontHinting(),
 		})
 		return face, err
 	} else {
@@ -1059,9 +1256,9 @@ func OpenFontFace(path string, size int, strokeWidth int) (font.Face, error) {
 			return nil, err
 		}
 		face := truetype.NewFace(f, &truetype.Options{
-			Size:   float64(size),
-			Stroke: strokeWidth,
-			// Hinting: font.HintingFull,
+			Size:    float64(size),
+			Stroke:  strokeWidth,
+			Hinting: TextHinting.FontHinting(),
 			// GlyphCacheEntries: 1024, // default is 512 -- todo benchmark
 		})
 		return face, nil
@@ -1097,9 +1294,9 @@ func OpenGoFont(path string, size int, strokeWidth int) (font.Face, error) {
 	}
 	f, _ := truetype.Parse(gf.ttf)
 	face := truetype.NewFace(f, &truetype.Options{
-		Size:   float64(size),
-		Stroke: strokeWidth,
-		// Hinting: font.HintingFull,
+		Size:    float64(size),
+		Stroke:  strokeWidth,
+		Hinting: TextHinting.FontHinting(),
 		// GlyphCacheEntries: 1024, // default is 512 -- todo benchmark
 
 	})
@@ -0,0 +1,210 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/goki/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+)
+
+// Fonts can be registered in the FontLib directly from font file data, e.g.,
+// from a go:embed fs.FS, so that an app can ship its own fonts inside the
+// binary, using AddFontData and AddFontFS.  Registered fonts are listed in
+// FontInfo (and thus the font chooser) and are used in font-family resolution
+// just like fonts found on the FontPaths -- a registered font takes
+// precedence over an installed font of the same name.
+
+// fontDataPrefix is the pseudo-path prefix for fonts registered from data
+const fontDataPrefix = "fontdata/"
+
+// fontDataInfo is a font registered from data
+type fontDataInfo struct {
+	name string
+	data []byte
+}
+
+// IsOpenTypeCFF returns true if the font file data is an OpenType font with
+// CFF (PostScript) outlines, as in most .otf files, instead of TrueType
+// outlines
+func IsOpenTypeCFF(data []byte) bool {
+	return len(data) >= 4 && string(data[:4]) == "OTTO"
+}
+
+// AddFontData registers a font from given font file data (.ttf), under given
+// name -- if name is empty, the name is taken from the name table of the
+// font.  The name is regularized (see FixFontMods), and the regularized name
+// is returned.  Any installed font of the same name is replaced.
+func (fl *FontLib) AddFontData(data []byte, name string) (string, error) {
+	if name == "" {
+		name = FontDataName(data)
+		if name == "" {
+			err := errors.New("gi.FontLib AddFontData: no name given and font has no name table")
+			log.Println(err)
+			return "", err
+		}
+	}
+	name = FixFontMods(name)
+	var err error
+	if IsOpenTypeCFF(data) {
+		_, err = sfnt.Parse(data)
+	} else {
+		_, err = truetype.Parse(data)
+	}
+	if err != nil {
+		err = fmt.Errorf("gi.FontLib AddFontData: error parsing font %v: %v", name, err)
+		log.Println(err)
+		return "", err
+	}
+	fl.Init()
+	loadFontMu.Lock()
+	fl.fontData[fontDataPrefix+strings.ToLower(name)] = fontDataInfo{name: name, data: data}
+	fl.addDataFont(name)
+	sort.Slice(fl.FontInfo, func(i, j int) bool {
+		return fl.FontInfo[i].Name < fl.FontInfo[j].Name
+	})
+	loadFontMu.Unlock()
+	fl.deleteOptFaces(strings.ToLower(name))
+	fl.ResetFallbacks()
+	return name, nil
+}
+
+// AddFontFS registers all of the font files (see FontExts) in given directory
+// of given file system (recursively), e.g., a go:embed file system -- use "."
+// for the root.  Fonts are named from their name table, or the file name if
+// not available.
+func (fl *FontLib) AddFontFS(fsys fs.FS, dir string) error {
+	return fs.WalkDir(fsys, dir, func(fpath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			log.Printf("gi.FontLib: error accessing path %q: %v\n", fpath, err)
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if _, ok := FontExts[strings.ToLower(path.Ext(fpath))]; !ok {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, fpath)
+		if err != nil {
+			log.Printf("gi.FontLib: error reading font %q: %v\n", fpath, err)
+			return err
+		}
+		name := FontDataName(data)
+		if name == "" {
+			name = FontFileName(fpath)
+		}
+		_, err = fl.AddFontData(data, name)
+		return err
+	})
+}
+
+// DataFontsAvail adds the fonts registered from data to FontsAvail and
+// FontInfo
+func (fl *FontLib) DataFontsAvail() {
+	for _, fd := range fl.fontData {
+		fl.addDataFont(fd.name)
+	}
+}
+
+// addDataFont adds the registered font of given name to FontsAvail and
+// FontInfo, and clears any cached data for a prior font of that name, except
+// for its FontOpts faces (see deleteOptFaces) -- loadFontMu must be locked
+func (fl *FontLib) addDataFont(name string) {
+	basefn := strings.ToLower(name)
+	if _, has := fl.FontsAvail[basefn]; has {
+		delete(fl.Faces, basefn)
+		delete(fl.TTFonts, basefn)
+		delete(fl.ttErrs, basefn)
+		delete(fl.sfntFonts, basefn)
+		delete(fl.shapers, basefn)
+		delete(fl.fontVars, basefn)
+		for face, fi := range fl.faceInfo { // including those of FontOpts faces
+			if fi.name == basefn {
+				delete(fl.faceInfo, face)
+				fl.capsInfo.Delete(face)
+			}
+		}
+	} else {
+		fi := FontInfo{Name: name, Example: FontInfoExample}
+		_, fi.Stretch, fi.Weight, fi.Style = FontNameToMods(name)
+		fl.FontInfo = append(fl.FontInfo, fi)
+	}
+	fl.FontsAvail[basefn] = fontDataPrefix + basefn
+}
+
+// openFontFace opens the font at given path in FontsAvail, which can be a
+// registered font data path -- loadFontMu must be locked
+func (fl *FontLib) openFontFace(path string, size int) (font.Face, error) {
+	if fd, ok := fl.fontData[path]; ok {
+		return OpenFontData(fd.data, size, 0)
+	}
+	return OpenFontFace(path, size, 0)
+}
+
+// FontDataName returns the full font name (family and subfamily, e.g.,
+// "Roboto Bold") from the name table of given font file data, preferring the
+// typographic names over the legacy ones -- returns "" if not found
+func FontDataName(data []byte) string {
+	nt := otTables(data)["name"]
+	if nt == nil {
+		return ""
+	}
+	fam := otNameString(nt, 16)
+	sub := otNameString(nt, 17)
+	if fam == "" {
+		fam = otNameString(nt, 1)
+		sub = otNameString(nt, 2)
+	}
+	if fam == "" {
+		return ""
+	}
+	if sub == "" || sub == "Regular" {
+		return fam
+	}
+	return fam + " " + sub
+}
+
+// otNameString returns the string for given name id in a name table,
+// preferring the Windows (UTF-16) English names
+func otNameString(nt []byte, id int) string {
+	n := otU16(nt, 2)
+	soff := otU16(nt, 4)
+	mac := ""
+	for i := 0; i < n; i++ {
+		rec := 6 + 12*i
+		if otU16(nt, rec+6) != id {
+			continue
+		}
+		plat := otU16(nt, rec)
+		lang := otU16(nt, rec+4)
+		ln := otU16(nt, rec+8)
+		off := soff + otU16(nt, rec+10)
+		if off+ln > len(nt) {
+			continue
+		}
+		str := nt[off : off+ln]
+		switch {
+		case plat == 3 && lang&0xFF == 0x09: // windows english
+			u := make([]uint16, len(str)/2)
+			for j := range u {
+				u[j] = uint16(otU16(str, 2*j))
+			}
+			return string(utf16.Decode(u))
+		case plat == 1 && lang == 0 && mac == "": // mac roman english
+			mac = string(str)
+		}
+	}
+	return mac
+}
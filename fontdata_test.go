@@ -0,0 +1,219 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"unicode/utf16"
+
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+type testNameRec struct {
+	plat, lang, id int
+	str            string
+}
+
+// testNameTable returns an sfnt name table with given records -- the
+// windows (platform 3) strings are encoded in UTF-16
+func testNameTable(recs ...testNameRec) []byte {
+	nt := make([]byte, 6+12*len(recs))
+	binary.BigEndian.PutUint16(nt[2:], uint16(len(recs)))
+	binary.BigEndian.PutUint16(nt[4:], uint16(len(nt)))
+	var strs []byte
+	for i, r := range recs {
+		str := []byte(r.str)
+		if r.plat == 3 {
+			str = nil
+			for _, u := range utf16.Encode([]rune(r.str)) {
+				str = append(str, byte(u>>8), byte(u))
+			}
+		}
+		rec := nt[6+12*i:]
+		binary.BigEndian.PutUint16(rec, uint16(r.plat))
+		binary.BigEndian.PutUint16(rec[4:], uint16(r.lang))
+		binary.BigEndian.PutUint16(rec[6:], uint16(r.id))
+		binary.BigEndian.PutUint16(rec[8:], uint16(len(str)))
+		binary.BigEndian.PutUint16(rec[10:], uint16(len(strs)))
+		strs = append(strs, str...)
+	}
+	return append(nt, strs...)
+}
+
+// testNameFont returns font file data with only a name table with given
+// records
+func testNameFont(recs ...testNameRec) []byte {
+	nt := testNameTable(recs...)
+	data := make([]byte, 28)
+	binary.BigEndian.PutUint32(data, 0x00010000)
+	binary.BigEndian.PutUint16(data[4:], 1)
+	copy(data[12:], "name")
+	binary.BigEndian.PutUint32(data[20:], 28)
+	binary.BigEndian.PutUint32(data[24:], uint32(len(nt)))
+	return append(data, nt...)
+}
+
+type testFontDataSpec struct {
+	data []byte
+	name string
+}
+
+var testFontDataNames = []testFontDataSpec{
+	{nil, ""},
+	{[]byte("junk"), ""},
+	{testNameFont(), ""},
+	{testNameFont(testNameRec{3, 0x409, 1, "Roboto"}, testNameRec{3, 0x409, 2, "Bold"}), "Roboto Bold"},
+	{testNameFont(testNameRec{3, 0x409, 1, "Roboto"}, testNameRec{3, 0x409, 2, "Regular"}), "Roboto"},
+	{testNameFont(testNameRec{3, 0x409, 1, "Roboto"}), "Roboto"},
+	{testNameFont(testNameRec{3, 0x409, 2, "Bold"}), ""},
+	{testNameFont(testNameRec{3, 0x409, 1, "Roboto Medium"}, testNameRec{3, 0x409, 2, "Regular"}, testNameRec{3, 0x409, 16, "Roboto"}, testNameRec{3, 0x409, 17, "Medium"}), "Roboto Medium"},
+	{testNameFont(testNameRec{1, 0, 1, "Mac Font"}, testNameRec{1, 0, 2, "Italic"}), "Mac Font Italic"},
+	{testNameFont(testNameRec{1, 0, 1, "Mac Font"}, testNameRec{3, 0x409, 1, "Win Font"}), "Win Font"},
+	{testNameFont(testNameRec{3, 0x40C, 1, "Police"}, testNameRec{1, 0, 1, "Font"}), "Font"},
+	{testNameFont(testNameRec{3, 0x809, 1, "Fönt"}), "Fönt"},
+	{goregular.TTF, "Go"},
+	{gobold.TTF, "Go Bold"},
+}
+
+func TestFontDataName(t *testing.T) {
+	for i, ft := range testFontDataNames {
+		if nm := FontDataName(ft.data); nm != ft.name {
+			t.Errorf("FontDataName %v: %v != correct: %v\n", i, nm, ft.name)
+		}
+	}
+	if IsOpenTypeCFF(goregular.TTF) || !IsOpenTypeCFF([]byte("OTTO\x00\x0a")) || IsOpenTypeCFF([]byte("OTT")) {
+		t.Errorf("IsOpenTypeCFF: wrong for .ttf, .otf or short data\n")
+	}
+}
+
+func TestFontFileName(t *testing.T) {
+	tests := []struct {
+		path string
+		name string
+	}{
+		{"/fonts/Roboto-Bold.ttf", "Roboto Bold"},
+		{"fonts/NotoSans_Italic.ttf", "NotoSans Italic"},
+		{"Inter[slnt,wght].ttf", "Inter"},
+		{"Inter-VariableFont_slnt,wght.ttf", "Inter"},
+		{"arialbd.ttf", "Arial Bold"},
+		{"arialbi.ttf", "Arial Bold Italic"},
+		{"ariali.ttf", "Arial Italic"},
+	}
+	for _, ft := range tests {
+		if nm := FontFileName(ft.path); nm != ft.name {
+			t.Errorf("FontFileName(%q): %v != correct: %v\n", ft.path, nm, ft.name)
+		}
+	}
+}
+
+// testFontDataAvail checks that given font is registered from given data in
+// given font library, and listed once in its FontInfo
+func testFontDataAvail(t *testing.T, fl *FontLib, name string, data []byte) {
+	t.Helper()
+	pth := fontDataPrefix + strings.ToLower(name)
+	if fp := fl.FontsAvail[strings.ToLower(name)]; fp != pth {
+		t.Errorf("FontsAvail for %v: %v != correct: %v\n", name, fp, pth)
+	}
+	if fd := fl.fontData[pth]; fd.name != name || !bytes.Equal(fd.data, data) {
+		t.Errorf("font data for %v: name %v, %v bytes != correct: %v bytes\n", name, fd.name, len(fd.data), len(data))
+	}
+	n := 0
+	for _, fi := range fl.FontInfo {
+		if fi.Name == name {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Errorf("FontInfo entries for %v: %v != correct: 1\n", name, n)
+	}
+}
+
+func TestAddFontData(t *testing.T) {
+	tests := []struct {
+		data []byte
+		name string
+		cor  string
+		err  bool
+	}{
+		{goregular.TTF, "", "Go", false},
+		{gobold.TTF, "MyFontBold", "MyFont Bold", false},
+		{goitalic.TTF, "MyFontItalic", "MyFont Italic", false},
+		{[]byte("junk"), "", "", true},
+		{[]byte("junk"), "Junk", "", true},
+		{[]byte("OTTOjunk"), "Junk", "", true},
+	}
+	fl := &FontLib{}
+	for _, ft := range tests {
+		nm, err := fl.AddFontData(ft.data, ft.name)
+		if nm != ft.cor || (err != nil) != ft.err {
+			t.Errorf("AddFontData(%q): %v, %v != correct: %v, error: %v\n", ft.name, nm, err, ft.cor, ft.err)
+		}
+		if err == nil {
+			testFontDataAvail(t, fl, nm, ft.data)
+		}
+	}
+	if len(fl.FontInfo) != 3 {
+		t.Errorf("AddFontData FontInfo: %v != correct: 3 fonts\n", fl.FontInfo)
+	}
+	for i := 1; i < len(fl.FontInfo); i++ {
+		if fl.FontInfo[i-1].Name > fl.FontInfo[i].Name {
+			t.Errorf("AddFontData FontInfo not sorted: %v\n", fl.FontInfo)
+		}
+	}
+	if fi := fl.FontInfo[0]; fi.Name != "Go" || fi.Weight != WeightNormal {
+		t.Errorf("AddFontData FontInfo[0]: %v != correct: Go, normal weight\n", fi)
+	}
+	if fi := fl.FontInfo[1]; fi.Name != "MyFont Bold" || fi.Weight != WeightBold {
+		t.Errorf("AddFontData FontInfo[1]: %v != correct: MyFont Bold, bold weight\n", fi)
+	}
+
+	face, err := fl.Font("myfont bold", 12)
+	if err != nil || face == nil {
+		t.Fatalf("Font of registered font: %v, %v\n", face, err)
+	}
+	if _, err := fl.AddFontData(goregular.TTF, "MyFont Bold"); err != nil {
+		t.Errorf("AddFontData replacing MyFont Bold: %v\n", err)
+	}
+	testFontDataAvail(t, fl, "MyFont Bold", goregular.TTF)
+	if len(fl.FontInfo) != 3 {
+		t.Errorf("AddFontData replacing FontInfo: %v != correct: 3 fonts\n", fl.FontInfo)
+	}
+	if nf, err := fl.Font("myfont bold", 12); err != nil || nf == face {
+		t.Errorf("Font of replaced font: %v, same face: %v != correct: new face\n", err, nf == face)
+	}
+}
+
+func TestAddFontFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"fonts/regular.ttf": {Data: goregular.TTF},
+		"fonts/readme.txt":  {Data: []byte("not a font")},
+		"fonts/bold/b.TTF":  {Data: gobold.TTF},
+		"fonts/it/x.ttf":    {Data: goitalic.TTF},
+	}
+	fl := &FontLib{}
+	if err := fl.AddFontFS(fsys, "fonts"); err != nil {
+		t.Errorf("AddFontFS: %v\n", err)
+	}
+	testFontDataAvail(t, fl, "Go", goregular.TTF)
+	testFontDataAvail(t, fl, "Go Bold", gobold.TTF)
+	testFontDataAvail(t, fl, "Go Italic", goitalic.TTF)
+	if len(fl.FontInfo) != 3 {
+		t.Errorf("AddFontFS FontInfo: %v != correct: 3 fonts\n", fl.FontInfo)
+	}
+
+	bad := fstest.MapFS{"bad.ttf": {Data: []byte("junk")}}
+	if err := (&FontLib{}).AddFontFS(bad, "."); err == nil {
+		t.Errorf("AddFontFS of bad font: no error != correct: error\n")
+	}
+	if err := (&FontLib{}).AddFontFS(fsys, "nodir"); err == nil {
+		t.Errorf("AddFontFS of missing dir: no error != correct: error\n")
+	}
+}
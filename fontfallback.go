@@ -0,0 +1,219 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/image/font"
+)
+
+// FontFallbackFamilies is the ordered list of font families with broad
+// unicode coverage that are searched (if they are available) for characters
+// that are missing from the font being used, after the families in the
+// font-family list -- all other fonts found on the FontPaths are searched
+// after these, as a last resort.
+var FontFallbackFamilies = []string{
+	"NotoSans",
+	"Noto Sans",
+	"DejaVu Sans",
+	"Noto Sans CJK SC",
+	"Noto Sans CJK JP",
+	"Noto Sans CJK KR",
+	"Noto Sans CJK",
+	"Droid Sans Fallback",
+	"WenQuanYi Zen Hei",
+	"Arial Unicode",
+	"Arial Unicode MS",
+	"Segoe UI",
+	"Segoe UI Symbol",
+	"Segoe UI Historic",
+	"Microsoft YaHei",
+	"Malgun Gothic",
+	"MS Gothic",
+	"PingFang SC",
+	"Hiragino Sans GB",
+	"Apple Symbols",
+	"Noto Sans Symbols",
+	"Noto Sans Symbols2",
+	"Noto Sans Math",
+	"Noto Sans Arabic",
+	"Noto Sans Hebrew",
+	"Noto Sans Devanagari",
+	"Noto Sans Thai",
+	"Symbola",
+}
+
+// fontFaceInfo records the font name and size of a face in the FontLib,
+// and its options (see FontOpts)
+type fontFaceInfo struct {
+	name  string
+	size  int
+	feats string    // font-feature-settings
+	caps  font.Face // face for lowercase letters, for synthesized small caps
+}
+
+// fallbackKey is the key for the fallback font cache: the fallback chain,
+// and a range of runes (or one rune for runes not found in any font)
+type fallbackKey struct {
+	chain string
+	r     rune
+}
+
+// fallbackBlock is the size of the range of runes sharing a cached fallback
+// font -- runes in a range usually come from the same script
+const fallbackBlock = 128
+
+// ResetFallbacks clears the cached fallback fonts -- called automatically
+// when the fonts available change
+func (fl *FontLib) ResetFallbacks() {
+	fl.fbMu.Lock()
+	fl.fbChains = nil
+	fl.fbRunes = nil
+	fl.fbMu.Unlock()
+}
+
+// HasRune returns true if the font of given name has a glyph for given rune
+// -- opentype (CFF) fonts, which TrueTypeFont cannot read, are checked
+// through their SfntFont
+func (fl *FontLib) HasRune(fontnm string, r rune) bool {
+	if f, err := fl.TrueTypeFont(fontnm); err == nil {
+		return f.Index(r) != 0
+	}
+	sf := fl.SfntFont(fontnm)
+	if sf == nil {
+		return false
+	}
+	idx, err := sf.GlyphIndex(nil, r)
+	return err == nil && idx != 0
+}
+
+// FallbackChain returns the ordered list of font names to search for runes
+// that are missing from the font of given name: the families in the given
+// font-family list (comma separated, as in css), then FontFallbackFamilies,
+// then all other fonts available -- using the same stretch, weight and style
+// as the given font where available
+func (fl *FontLib) FallbackChain(fontnm, fams string) []string {
+	fontnm = strings.ToLower(fontnm)
+	key := fontnm + "|" + fams
+	fl.fbMu.Lock()
+	if ch, ok := fl.fbChains[key]; ok {
+		fl.fbMu.Unlock()
+		return ch
+	}
+	fl.fbMu.Unlock()
+
+	fnm := fontnm
+	for _, fi := range fl.FontInfo { // get regular case, for mods
+		if strings.ToLower(fi.Name) == fontnm {
+			fnm = fi.Name
+			break
+		}
+	}
+	_, str, wt, sty := FontNameToMods(fnm)
+
+	var bases []string
+	if fams != "" {
+		bases, _, _ = FontAlts(fams)
+	}
+	for _, fb := range FontFallbackFamilies {
+		addUniqueFontRobust(&bases, fb)
+	}
+	for _, fi := range fl.FontInfo {
+		if fi.Stretch == FontStrNormal && fi.Weight == WeightNormal && fi.Style == FontNormal {
+			addUniqueFont(&bases, fi.Name)
+		}
+	}
+
+	ch := make([]string, 0, len(bases))
+	for _, bn := range bases {
+		nm := FontNameFromMods(bn, str, wt, sty)
+		if !fl.FontAvail(nm) {
+			if !fl.FontAvail(bn) {
+				continue
+			}
+			nm = bn
+		}
+		nm = strings.ToLower(nm)
+		if nm == fontnm {
+			continue
+		}
+		addUniqueFont(&ch, nm)
+	}
+
+	fl.fbMu.Lock()
+	if fl.fbChains == nil {
+		fl.fbChains = make(map[string][]string)
+	}
+	fl.fbChains[key] = ch
+	fl.fbMu.Unlock()
+	return ch
+}
+
+// RuneFace returns the face to use for rendering given rune, starting from
+// given face (which must have been obtained from the library, e.g., via
+// FontStyle.OpenFont): if that face has no glyph for the rune, the first
+// font in the FallbackChain (for given font-family list, which can be empty)
+// that has one is used, at the same size.  The chosen font is cached per
+// range of runes, so this is fast for subsequent runes of the same script.
+// The original face is returned if no font has the rune.  For faces with
+// synthesized small caps (see FontOpts), the smaller capitals face is
+// returned for lowercase letters.
+func (fl *FontLib) RuneFace(face font.Face, fams string, r rune) font.Face {
+	if face != nil && unicode.IsLower(r) {
+		if fii, ok := fl.capsInfo.Load(face); ok {
+			if fi := fii.(fontFaceInfo); unicode.ToUpper(r) != r && fl.HasRune(fi.name, unicode.ToUpper(r)) {
+				return fi.caps
+			}
+		}
+	}
+	if r < 0x80 || !unicode.IsGraphic(r) || face == nil {
+		return face
+	}
+	fontnm, size, ok := fl.FaceInfo(face)
+	if !ok || fl.HasRune(fontnm, r) {
+		return face
+	}
+	chain := fontnm + "|" + fams
+	bkey := fallbackKey{chain, r - r%fallbackBlock}
+	rkey := fallbackKey{chain, r}
+	fl.fbMu.Lock()
+	fbnm, hasBlk := fl.fbRunes[bkey]
+	rnm, hasRune := fl.fbRunes[rkey]
+	fl.fbMu.Unlock()
+
+	switch {
+	case hasRune:
+		fbnm = rnm
+	case hasBlk && fl.HasRune(fbnm, r):
+	default:
+		fbnm = ""
+		for _, fnm := range fl.FallbackChain(fontnm, fams) {
+			if fl.HasRune(fnm, r) {
+				fbnm = fnm
+				break
+			}
+		}
+		fl.fbMu.Lock()
+		if fl.fbRunes == nil {
+			fl.fbRunes = make(map[fallbackKey]string)
+		}
+		if fbnm != "" && !hasBlk {
+			fl.fbRunes[bkey] = fbnm
+		} else {
+			fl.fbRunes[rkey] = fbnm // exception within range, or not found at all
+		}
+		fl.fbMu.Unlock()
+	}
+	if fbnm == "" {
+		return face
+	}
+	fbface, err := fl.Font(fbnm, size)
+	if err != nil {
+		return face
+	}
+	return fbface
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// testGoFontsOnly sets the FontLibrary to have only the Go fonts available,
+// so that the fallback chains do not depend on the installed fonts, and
+// returns a function that restores it
+func testGoFontsOnly() func() {
+	FontLibrary.Init()
+	loadFontMu.Lock()
+	avail, info := FontLibrary.FontsAvail, FontLibrary.FontInfo
+	FontLibrary.FontsAvail = make(map[string]string)
+	FontLibrary.FontInfo = nil
+	FontLibrary.GoFontsAvail()
+	sort.Slice(FontLibrary.FontInfo, func(i, j int) bool {
+		return FontLibrary.FontInfo[i].Name < FontLibrary.FontInfo[j].Name
+	})
+	loadFontMu.Unlock()
+	FontLibrary.ResetFallbacks()
+	return func() {
+		loadFontMu.Lock()
+		FontLibrary.FontsAvail, FontLibrary.FontInfo = avail, info
+		loadFontMu.Unlock()
+		FontLibrary.ResetFallbacks()
+	}
+}
+
+type testFallbackSpec struct {
+	fontnm string
+	fams   string
+	chain  []string
+}
+
+var testFallbackChains = []testFallbackSpec{
+	{"Go", "", []string{"go mono", "go small caps"}},
+	{"Go Italic", "", []string{"go mono italic", "go small caps italic"}},
+	{"go italic", "Go Small Caps", []string{"go small caps italic", "go mono italic"}},
+	{"Go Mono Bold Italic", "", []string{"go bold italic", "go small caps"}},
+	{"Go Medium", "", []string{"go mono", "go small caps"}},
+	{"Go Bold", "Go Mono, Go", []string{"go mono bold", "go small caps"}},
+	{"NoSuchFont", "", []string{"go", "go mono", "go small caps"}},
+}
+
+func TestFallbackChain(t *testing.T) {
+	defer testGoFontsOnly()()
+	for _, ft := range testFallbackChains {
+		ch := FontLibrary.FallbackChain(ft.fontnm, ft.fams)
+		if !reflect.DeepEqual(ch, ft.chain) {
+			t.Errorf("FallbackChain(%q, %q): %v != correct: %v\n", ft.fontnm, ft.fams, ch, ft.chain)
+		}
+		if cch := FontLibrary.FallbackChain(ft.fontnm, ft.fams); !reflect.DeepEqual(cch, ch) {
+			t.Errorf("FallbackChain(%q, %q) cached: %v != correct: %v\n", ft.fontnm, ft.fams, cch, ch)
+		}
+	}
+}
+
+func TestHasRune(t *testing.T) {
+	defer testGoFontsOnly()()
+	tests := []struct {
+		fontnm string
+		r      rune
+		has    bool
+	}{
+		{"Go", 'a', true},
+		{"go", 'é', true},
+		{"Go Mono Bold", 'λ', true},
+		{"Go", 'א', false},
+		{"Go", '日', false},
+		{"NoSuchFont", 'a', false},
+	}
+	for _, ft := range tests {
+		if has := FontLibrary.HasRune(ft.fontnm, ft.r); has != ft.has {
+			t.Errorf("HasRune(%q, %q): %v != correct: %v\n", ft.fontnm, ft.r, has, ft.has)
+		}
+	}
+}
+
+func TestRuneFace(t *testing.T) {
+	defer testGoFontsOnly()()
+	face, err := FontLibrary.Font("Go", 12)
+	if err != nil {
+		t.Fatalf("Font(%q): %v\n", "Go", err)
+	}
+	tests := []struct {
+		fams string
+		r    rune
+	}{
+		{"", 'a'},
+		{"", 'é'},
+		{"", '\u200b'}, // not graphic
+		{"", 'א'},      // not in any font
+		{"Go Mono", '日'},
+	}
+	for _, ft := range tests {
+		if rf := FontLibrary.RuneFace(face, ft.fams, ft.r); rf != face {
+			t.Errorf("RuneFace(%q, %q): %v != correct: original face %v\n", ft.fams, ft.r, rf, face)
+		}
+	}
+	FontLibrary.fbMu.Lock()
+	fbnm, has := FontLibrary.fbRunes[fallbackKey{"go|", 'א'}]
+	_, hasBlk := FontLibrary.fbRunes[fallbackKey{"go|", 'א' - 'א'%fallbackBlock}]
+	FontLibrary.fbMu.Unlock()
+	if fbnm != "" || !has || hasBlk {
+		t.Errorf("RuneFace missing rune cache: %q, %v, block: %v != correct: \"\", true, block: false\n", fbnm, has, hasBlk)
+	}
+	if rf := FontLibrary.RuneFace(nil, "", 'א'); rf != nil {
+		t.Errorf("RuneFace of nil face: %v != correct: nil\n", rf)
+	}
+}
+
+func TestTrueTypeFontErrs(t *testing.T) {
+	defer testGoFontsOnly()()
+	loadFontMu.Lock()
+	FontLibrary.FontsAvail["no file"] = "/no/such/dir/nofile.ttf"
+	FontLibrary.FontsAvail["otf font"] = "/no/such/dir/font.otf"
+	loadFontMu.Unlock()
+	for _, fnm := range []string{"No File", "otf font"} {
+		_, err := FontLibrary.TrueTypeFont(fnm)
+		if err == nil {
+			t.Errorf("TrueTypeFont(%q): nil != correct: error\n", fnm)
+			continue
+		}
+		if cerr, ok := FontLibrary.ttErrs[strings.ToLower(fnm)]; !ok || cerr != err {
+			t.Errorf("TrueTypeFont(%q) cached error: %v != correct: %v\n", fnm, cerr, err)
+		}
+		if _, err2 := FontLibrary.TrueTypeFont(fnm); err2 != err {
+			t.Errorf("TrueTypeFont(%q) again: %v != correct: cached %v\n", fnm, err2, err)
+		}
+		if FontLibrary.HasRune(fnm, 'a') {
+			t.Errorf("HasRune(%q): true != correct: false\n", fnm)
+		}
+	}
+	if _, err := FontLibrary.TrueTypeFont("Go"); err != nil {
+		t.Errorf("TrueTypeFont(%q): %v != correct: nil\n", "Go", err)
+	}
+}
@@ -0,0 +1,492 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/goki/freetype/truetype"
+	"github.com/srwiley/rasterx"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// FontFeatures are OpenType feature settings, as in the css
+// font-feature-settings property: the value for each feature tag, where 0
+// turns the feature off and 1 turns it on -- other values select
+// alternates, which are not supported, and are treated as on
+type FontFeatures map[string]int
+
+// ParseFontFeatures parses a css font-feature-settings value, e.g.,
+// `"tnum", "smcp" on, "liga" off, "ss01" 1` -- normal is no settings
+func ParseFontFeatures(str string) FontFeatures {
+	ff := make(FontFeatures)
+	for _, it := range strings.Split(str, ",") {
+		fs := strings.Fields(it)
+		if len(fs) == 0 {
+			continue
+		}
+		tag := strings.Trim(fs[0], `"'`)
+		if len(tag) != 4 {
+			continue
+		}
+		val := 1
+		if len(fs) > 1 {
+			switch fs[1] {
+			case "on":
+			case "off":
+				val = 0
+			default:
+				if v, err := strconv.Atoi(fs[1]); err == nil {
+					val = v
+				}
+			}
+		}
+		ff[tag] = val
+	}
+	return ff
+}
+
+// String returns the features in css form, sorted by tag, which is the
+// canonical form used in FontOpts
+func (ff FontFeatures) String() string {
+	tags := make([]string, 0, len(ff))
+	for tag := range ff {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	for i, tag := range tags {
+		tags[i] = fmt.Sprintf("%q %d", tag, ff[tag])
+	}
+	return strings.Join(tags, ", ")
+}
+
+// IsOff returns true if given feature is turned off
+func (ff FontFeatures) IsOff(tag string) bool {
+	v, has := ff[tag]
+	return has && v == 0
+}
+
+// Apply returns the union of given sets of features, with the features that
+// are turned on added, and those that are turned off removed
+func (ff FontFeatures) Apply(sets ...map[string]bool) map[string]bool {
+	fm := make(map[string]bool)
+	for _, set := range sets {
+		for tag := range set {
+			fm[tag] = true
+		}
+	}
+	for tag, v := range ff {
+		if v == 0 {
+			delete(fm, tag)
+		} else {
+			fm[tag] = true
+		}
+	}
+	return fm
+}
+
+// FontVariations are the values of the variation axes of a variable font,
+// as in the css font-variation-settings property, e.g., wght for weight
+type FontVariations map[string]float32
+
+// ParseFontVariations parses a css font-variation-settings value, e.g.,
+// `"wght" 650, "wdth" 80` -- normal is no settings
+func ParseFontVariations(str string) FontVariations {
+	fvs := make(FontVariations)
+	for _, it := range strings.Split(str, ",") {
+		fs := strings.Fields(it)
+		if len(fs) != 2 {
+			continue
+		}
+		tag := strings.Trim(fs[0], `"'`)
+		if len(tag) != 4 {
+			continue
+		}
+		if v, err := strconv.ParseFloat(fs[1], 32); err == nil {
+			fvs[tag] = float32(v)
+		}
+	}
+	return fvs
+}
+
+// String returns the variations in css form, sorted by tag, which is the
+// canonical form used in FontOpts
+func (fvs FontVariations) String() string {
+	tags := make([]string, 0, len(fvs))
+	for tag := range fvs {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	for i, tag := range tags {
+		tags[i] = fmt.Sprintf("%q %g", tag, fvs[tag])
+	}
+	return strings.Join(tags, ", ")
+}
+
+// SmallCapsScale is the size of the capitals that are used for lowercase
+// letters in synthesized small caps, relative to the font size
+var SmallCapsScale = float32(0.7)
+
+// FontObliqueSlant is the slant angle in degrees used for the oblique style
+// with variable fonts that have a slnt axis (and no separate oblique font)
+var FontObliqueSlant = float32(14)
+
+// FontOpts are the options for a font face, beyond the font and size: see
+// FontLib.FontOpts and FontStyle.FontOpts
+type FontOpts struct {
+	Vars      string `desc:"values of the variation axes, for variable fonts, in the canonical form of FontVariations"`
+	Feats     string `desc:"OpenType features used in shaping text in the face, in the canonical form of FontFeatures"`
+	SmallCaps bool   `desc:"synthesize small caps, by rendering lowercase letters as smaller capitals"`
+}
+
+// fontOptsKey is the key for the cache of faces with FontOpts
+type fontOptsKey struct {
+	name string
+	size int
+	opts FontOpts
+}
+
+// FontOpts gets a face for the font of given name (see FontsAvail list) and
+// integer dots size, with given options, using a cache: a separate face is
+// made for each set of options (Font is used if there are none), so that
+// the options can be looked up from the face (see FaceFeatures).  Variable
+// fonts are set to the given axis values -- these are ignored for other
+// fonts.
+func (fl *FontLib) FontOpts(fontnm string, size int, opts FontOpts) (font.Face, error) {
+	if opts == (FontOpts{}) {
+		return fl.Font(fontnm, size)
+	}
+	fl.optMu.Lock() // held while making the face, so that it is made only once
+	defer fl.optMu.Unlock()
+	return fl.fontOpts(strings.ToLower(fontnm), size, opts)
+}
+
+// fontOpts gets the face for FontOpts, making it if it is not in the cache
+// -- optMu must be locked
+func (fl *FontLib) fontOpts(fontnm string, size int, opts FontOpts) (font.Face, error) {
+	if opts == (FontOpts{}) {
+		return fl.Font(fontnm, size)
+	}
+	key := fontOptsKey{fontnm, size, opts}
+	if face, ok := fl.optFaces[key]; ok {
+		return face, nil
+	}
+	base, err := fl.Font(fontnm, size)
+	if err != nil {
+		return nil, err
+	}
+	var face font.Face
+	if opts.Vars != "" {
+		if fv := fl.FontVar(fontnm); fv != nil {
+			ttf, _ := fl.TrueTypeFont(fontnm)
+			face = newVarFace(fv, ttf, base, fv.NormCoords(ParseFontVariations(opts.Vars)), size)
+		}
+	}
+	if face == nil { // separate copy of the face, for its own options
+		loadFontMu.Lock()
+		face, err = fl.openFontFace(fl.FontsAvail[fontnm], size)
+		loadFontMu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+	fi := fontFaceInfo{name: fontnm, size: size, feats: opts.Feats}
+	if opts.SmallCaps {
+		csz := int(math.Round(float64(float32(size) * SmallCapsScale)))
+		if cbase, err := fl.fontOpts(fontnm, csz, FontOpts{Vars: opts.Vars, Feats: opts.Feats}); err == nil {
+			cf := &capsFace{Face: cbase}
+			fi.caps = cf
+			loadFontMu.Lock()
+			fl.faceInfo[cf] = fontFaceInfo{name: fontnm, size: csz, feats: opts.Feats}
+			loadFontMu.Unlock()
+		}
+	}
+	loadFontMu.Lock()
+	fl.faceInfo[face] = fi
+	loadFontMu.Unlock()
+	if fi.caps != nil {
+		fl.capsInfo.Store(face, fi)
+	}
+	if fl.optFaces == nil {
+		fl.optFaces = make(map[fontOptsKey]font.Face)
+	}
+	fl.optFaces[key] = face
+	return face, nil
+}
+
+// deleteOptFaces deletes the cached FontOpts faces of the font of given
+// (lowercase) name -- loadFontMu must not be locked, as optMu is locked
+// before it in FontOpts
+func (fl *FontLib) deleteOptFaces(fontnm string) {
+	fl.optMu.Lock()
+	for k := range fl.optFaces {
+		if k.name == fontnm {
+			delete(fl.optFaces, k)
+		}
+	}
+	fl.optMu.Unlock()
+}
+
+// lookupFaceInfo returns the info of given face from the library, under
+// loadFontMu
+func (fl *FontLib) lookupFaceInfo(face font.Face) (fontFaceInfo, bool) {
+	loadFontMu.Lock()
+	defer loadFontMu.Unlock()
+	fi, ok := fl.faceInfo[face]
+	return fi, ok
+}
+
+// FontVar returns the variation information for the font of given name, or
+// nil if it is not a variable font (or its outlines cannot be read, see
+// TrueTypeFont)
+func (fl *FontLib) FontVar(fontnm string) *FontVar {
+	if _, err := fl.TrueTypeFont(fontnm); err != nil {
+		return nil
+	}
+	loadFontMu.Lock()
+	defer loadFontMu.Unlock()
+	return fl.fontVars[strings.ToLower(fontnm)]
+}
+
+// IsVarFont returns true if the font of given name is available and is a
+// variable font
+func (fl *FontLib) IsVarFont(fontnm string) bool {
+	return fl.FontAvail(fontnm) && fl.FontVar(fontnm) != nil
+}
+
+// FaceFeatures returns the font-feature-settings of given face from the
+// library (see FontOpts), in the canonical form of FontFeatures -- empty if
+// none
+func (fl *FontLib) FaceFeatures(face font.Face) string {
+	fi, _ := fl.lookupFaceInfo(face)
+	return fi.feats
+}
+
+// HasFeature returns true if the font of given name has given OpenType
+// feature in its layout tables
+func (fl *FontLib) HasFeature(fontnm, tag string) bool {
+	sh := fl.Shaper(fontnm)
+	return sh != nil && sh.HasFeature(tag)
+}
+
+// capsFace renders lowercase letters as the capitals of a smaller face, for
+// synthesized small caps -- it is used for the lowercase letters of a face
+// with the SmallCaps option (see FontLib.RuneFace)
+type capsFace struct {
+	font.Face
+}
+
+func (cf *capsFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	return cf.Face.Glyph(dot, unicode.ToUpper(r))
+}
+
+func (cf *capsFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	return cf.Face.GlyphBounds(unicode.ToUpper(r))
+}
+
+func (cf *capsFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	return cf.Face.GlyphAdvance(unicode.ToUpper(r))
+}
+
+func (cf *capsFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	return cf.Face.Kern(unicode.ToUpper(r0), unicode.ToUpper(r1))
+}
+
+// ttf returns the truetype font and size of the underlying face
+func (cf *capsFace) ttf() (*truetype.Font, float32) {
+	fontnm, size, ok := FontLibrary.FaceInfo(cf.Face)
+	if !ok {
+		return nil, 0
+	}
+	f, err := FontLibrary.TrueTypeFont(fontnm)
+	if err != nil {
+		return nil, 0
+	}
+	return f, float32(size)
+}
+
+func (cf *capsFace) RuneOutline(r rune, pos Vec2D, xf Matrix2D) (rasterx.Path, bool) {
+	r = unicode.ToUpper(r)
+	if of, ok := cf.Face.(OutlineFace); ok {
+		return of.RuneOutline(r, pos, xf)
+	}
+	if f, size := cf.ttf(); f != nil {
+		return GlyphOutline(f, size, r, pos, xf)
+	}
+	return nil, false
+}
+
+func (cf *capsFace) GlyphIndexOutline(idx int, pos Vec2D, xf Matrix2D) (rasterx.Path, bool) {
+	if of, ok := cf.Face.(OutlineFace); ok {
+		return of.GlyphIndexOutline(idx, pos, xf)
+	}
+	if f, size := cf.ttf(); f != nil {
+		return GlyphIndexOutline(f, size, truetype.Index(idx), pos, xf)
+	}
+	return nil, false
+}
+
+func (cf *capsFace) GlyphIndexAdvance(idx int) float32 {
+	if of, ok := cf.Face.(OutlineFace); ok {
+		return of.GlyphIndexAdvance(idx)
+	}
+	if f, size := cf.ttf(); f != nil {
+		return FixedToFloat32(f.HMetric(Float32ToFixed(size), truetype.Index(idx)).AdvanceWidth)
+	}
+	return 0
+}
+
+//////////////////////////////////////////////////////////////////////////////////
+//  FontStyle
+
+// FontOpts returns the face options for the style: the
+// font-feature-settings, with small caps synthesized for the small-caps
+// variant (or the smcp feature) if the font does not have them, and for
+// variable fonts, the axis values for the weight, stretch and style (where
+// these are not provided by the face itself), overridden by any
+// font-variation-settings -- FaceName must already be set (see OpenFont)
+func (fs *FontStyle) FontOpts() FontOpts {
+	var opts FontOpts
+	ff := ParseFontFeatures(fs.Features)
+	if fs.Variant == FontVarSmallCaps {
+		if _, has := ff["smcp"]; !has {
+			ff["smcp"] = 1
+		}
+	}
+	if ff["smcp"] > 0 && !FontLibrary.HasFeature(fs.FaceName, "smcp") {
+		delete(ff, "smcp")
+		opts.SmallCaps = true
+	}
+	if len(ff) > 0 {
+		opts.Feats = ff.String()
+	}
+	fv := FontLibrary.FontVar(fs.FaceName)
+	if fv == nil {
+		return opts
+	}
+	fvs := ParseFontVariations(fs.Variation)
+	setAxis := func(tag string, val float32) {
+		if _, has := fvs[tag]; !has && fv.HasAxis(tag) {
+			fvs[tag] = val
+		}
+	}
+	_, str, wt, sty := FontNameToMods(fs.FaceName)
+	if wt == WeightNormal && fs.Weight != WeightNormal {
+		setAxis("wght", fs.Weight.Numeric())
+	}
+	if str == FontStrNormal && fs.Stretch != FontStrNormal {
+		setAxis("wdth", fs.Stretch.Percent())
+	}
+	if sty == FontNormal && fs.Style != FontNormal {
+		if fs.Style == FontItalic && fv.HasAxis("ital") {
+			setAxis("ital", 1)
+		} else {
+			setAxis("slnt", -FontObliqueSlant)
+		}
+	}
+	if len(fvs) > 0 {
+		opts.Vars = fvs.String()
+	}
+	return opts
+}
+
+// SetRelWeight resolves the bolder and lighter relative weights, relative
+// to the weight of given parent style
+func (fs *FontStyle) SetRelWeight(par *FontStyle) {
+	fs.Weight = fs.Weight.Relative(par.Weight)
+}
+
+// Numeric returns the numeric (css) value of the weight, e.g., 400 for
+// normal and 700 for bold -- bolder and lighter are relative to normal
+func (fw FontWeights) Numeric() float32 {
+	switch fw {
+	case Weight100, WeightThin:
+		return 100
+	case Weight200, WeightExtraLight:
+		return 200
+	case Weight300, WeightLight, WeightLighter:
+		return 300
+	case Weight500, WeightMedium:
+		return 500
+	case Weight600, WeightSemiBold:
+		return 600
+	case Weight700, WeightBold, WeightBolder:
+		return 700
+	case Weight800, WeightExtraBold:
+		return 800
+	case Weight900, WeightBlack:
+		return 900
+	}
+	return 400
+}
+
+// FontWeightFromNumeric returns the weight closest to given numeric (css)
+// value, in the range 1..1000
+func FontWeightFromNumeric(v float32) FontWeights {
+	ws := []FontWeights{Weight100, Weight200, Weight300, Weight400, Weight500, Weight600, Weight700, Weight800, Weight900}
+	n := int(math.Round(float64(v) / 100))
+	if n < 1 {
+		n = 1
+	} else if n > 9 {
+		n = 9
+	}
+	return ws[n-1]
+}
+
+// Relative returns the weight that bolder or lighter results in for given
+// parent weight, as specified in css -- other weights are returned as-is
+func (fw FontWeights) Relative(par FontWeights) FontWeights {
+	pv := par.Numeric()
+	switch fw {
+	case WeightBolder:
+		switch {
+		case pv < 350:
+			return Weight400
+		case pv < 550:
+			return Weight700
+		}
+		return Weight900
+	case WeightLighter:
+		switch {
+		case pv < 550:
+			return Weight100
+		case pv < 750:
+			return Weight400
+		}
+		return Weight700
+	}
+	return fw
+}
+
+// Percent returns the width of the stretch as a percentage of normal, as
+// used for the wdth axis of variable fonts
+func (fs FontStretch) Percent() float32 {
+	switch fs {
+	case FontStrUltraCondensed:
+		return 50
+	case FontStrExtraCondensed:
+		return 62.5
+	case FontStrCondensed:
+		return 75
+	case FontStrSemiCondensed, FontStrNarrower:
+		return 87.5
+	case FontStrSemiExpanded, FontStrWider:
+		return 112.5
+	case FontStrExpanded:
+		return 125
+	case FontStrExtraExpanded:
+		return 150
+	case FontStrUltraExpanded:
+		return 200
+	}
+	return 100
+}
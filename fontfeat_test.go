@@ -0,0 +1,196 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testFontFeatSpec struct {
+	str string
+	ff  FontFeatures
+	cor string
+}
+
+var testFontFeats = []testFontFeatSpec{
+	{"", FontFeatures{}, ""},
+	{"normal", FontFeatures{}, ""},
+	{`"tnum"`, FontFeatures{"tnum": 1}, `"tnum" 1`},
+	{`"smcp" on, "liga" off`, FontFeatures{"smcp": 1, "liga": 0}, `"liga" 0, "smcp" 1`},
+	{`'ss01' 2, "kern" off`, FontFeatures{"ss01": 2, "kern": 0}, `"kern" 0, "ss01" 2`},
+	{`"liga" xx, "toolong" 1, "ab" 1`, FontFeatures{"liga": 1}, `"liga" 1`},
+	{`"dlig",, "onum" 0`, FontFeatures{"dlig": 1, "onum": 0}, `"dlig" 1, "onum" 0`},
+}
+
+func TestParseFontFeatures(t *testing.T) {
+	for _, ft := range testFontFeats {
+		ff := ParseFontFeatures(ft.str)
+		if !reflect.DeepEqual(ff, ft.ff) {
+			t.Errorf("ParseFontFeatures(%q): %v != correct: %v\n", ft.str, ff, ft.ff)
+		}
+		if s := ff.String(); s != ft.cor {
+			t.Errorf("FontFeatures String for %q: %v != correct: %v\n", ft.str, s, ft.cor)
+		}
+		if rf := ParseFontFeatures(ff.String()); !reflect.DeepEqual(rf, ff) {
+			t.Errorf("FontFeatures String round trip for %q: %v != correct: %v\n", ft.str, rf, ff)
+		}
+	}
+}
+
+func TestFontFeaturesApply(t *testing.T) {
+	def := map[string]bool{"liga": true, "kern": true}
+	tests := []struct {
+		str string
+		off []string
+		cor map[string]bool
+	}{
+		{"", nil, map[string]bool{"liga": true, "kern": true}},
+		{`"liga" off`, []string{"liga"}, map[string]bool{"kern": true}},
+		{`"smcp", "kern" 0`, []string{"kern"}, map[string]bool{"liga": true, "smcp": true}},
+		{`"ss01" 3, "dlig" on`, nil, map[string]bool{"liga": true, "kern": true, "ss01": true, "dlig": true}},
+	}
+	for _, ft := range tests {
+		ff := ParseFontFeatures(ft.str)
+		if fm := ff.Apply(def, nil); !reflect.DeepEqual(fm, ft.cor) {
+			t.Errorf("FontFeatures Apply for %q: %v != correct: %v\n", ft.str, fm, ft.cor)
+		}
+		for _, tag := range []string{"liga", "kern", "smcp"} {
+			isOff := false
+			for _, ot := range ft.off {
+				if ot == tag {
+					isOff = true
+				}
+			}
+			if ff.IsOff(tag) != isOff {
+				t.Errorf("FontFeatures IsOff(%v) for %q: %v != correct: %v\n", tag, ft.str, ff.IsOff(tag), isOff)
+			}
+		}
+	}
+	if len(def) != 2 {
+		t.Errorf("FontFeatures Apply modified its default set: %v\n", def)
+	}
+}
+
+type testFontVarsSpec struct {
+	str string
+	fvs FontVariations
+	cor string
+}
+
+var testFontVars = []testFontVarsSpec{
+	{"", FontVariations{}, ""},
+	{"normal", FontVariations{}, ""},
+	{`"wght" 650`, FontVariations{"wght": 650}, `"wght" 650`},
+	{`"wght" 650, 'wdth' 87.5`, FontVariations{"wght": 650, "wdth": 87.5}, `"wdth" 87.5, "wght" 650`},
+	{`"slnt" -12, "wght"`, FontVariations{"slnt": -12}, `"slnt" -12`},
+	{`"opsz" x, "toolong" 1, "wght" 300 1`, FontVariations{}, ""},
+}
+
+func TestParseFontVariations(t *testing.T) {
+	for _, ft := range testFontVars {
+		fvs := ParseFontVariations(ft.str)
+		if !reflect.DeepEqual(fvs, ft.fvs) {
+			t.Errorf("ParseFontVariations(%q): %v != correct: %v\n", ft.str, fvs, ft.fvs)
+		}
+		if s := fvs.String(); s != ft.cor {
+			t.Errorf("FontVariations String for %q: %v != correct: %v\n", ft.str, s, ft.cor)
+		}
+	}
+}
+
+func TestFontWeightNumeric(t *testing.T) {
+	tests := []struct {
+		wt  FontWeights
+		num float32
+		frm FontWeights
+	}{
+		{WeightNormal, 400, Weight400},
+		{WeightThin, 100, Weight100},
+		{WeightExtraLight, 200, Weight200},
+		{WeightLight, 300, Weight300},
+		{WeightMedium, 500, Weight500},
+		{WeightSemiBold, 600, Weight600},
+		{WeightBold, 700, Weight700},
+		{WeightExtraBold, 800, Weight800},
+		{WeightBlack, 900, Weight900},
+		{Weight700, 700, Weight700},
+	}
+	for _, ft := range tests {
+		if num := ft.wt.Numeric(); num != ft.num {
+			t.Errorf("FontWeights Numeric for %v: %v != correct: %v\n", ft.wt, num, ft.num)
+		}
+		if frm := FontWeightFromNumeric(ft.num); frm != ft.frm {
+			t.Errorf("FontWeightFromNumeric(%v): %v != correct: %v\n", ft.num, frm, ft.frm)
+		}
+	}
+	nums := []struct {
+		num float32
+		wt  FontWeights
+	}{
+		{0, Weight100},
+		{1, Weight100},
+		{349, Weight300},
+		{350, Weight400},
+		{650, Weight700},
+		{1000, Weight900},
+	}
+	for _, ft := range nums {
+		if wt := FontWeightFromNumeric(ft.num); wt != ft.wt {
+			t.Errorf("FontWeightFromNumeric(%v): %v != correct: %v\n", ft.num, wt, ft.wt)
+		}
+	}
+}
+
+func TestFontWeightRelative(t *testing.T) {
+	tests := []struct {
+		wt  FontWeights
+		par FontWeights
+		cor FontWeights
+	}{
+		{WeightBolder, WeightThin, Weight400},
+		{WeightBolder, WeightLight, Weight400},
+		{WeightBolder, WeightNormal, Weight700},
+		{WeightBolder, WeightMedium, Weight700},
+		{WeightBolder, WeightSemiBold, Weight900},
+		{WeightBolder, WeightBlack, Weight900},
+		{WeightLighter, WeightThin, Weight100},
+		{WeightLighter, WeightMedium, Weight100},
+		{WeightLighter, WeightSemiBold, Weight400},
+		{WeightLighter, WeightBold, Weight400},
+		{WeightLighter, WeightExtraBold, Weight700},
+		{WeightBold, WeightThin, WeightBold},
+		{WeightNormal, WeightBlack, WeightNormal},
+	}
+	for _, ft := range tests {
+		if rw := ft.wt.Relative(ft.par); rw != ft.cor {
+			t.Errorf("FontWeights Relative for %v of %v: %v != correct: %v\n", ft.wt, ft.par, rw, ft.cor)
+		}
+	}
+}
+
+func TestFontStretchPercent(t *testing.T) {
+	tests := []struct {
+		str FontStretch
+		pct float32
+	}{
+		{FontStrNormal, 100},
+		{FontStrUltraCondensed, 50},
+		{FontStrExtraCondensed, 62.5},
+		{FontStrCondensed, 75},
+		{FontStrSemiCondensed, 87.5},
+		{FontStrNarrower, 87.5},
+		{FontStrSemiExpanded, 112.5},
+		{FontStrWider, 112.5},
+		{FontStrExpanded, 125},
+		{FontStrExtraExpanded, 150},
+		{FontStrUltraExpanded, 200},
+	}
+	for _, ft := range tests {
+		if pct := ft.str.Percent(); pct != ft.pct {
+			t.Errorf("FontStretch Percent for %v: %v != correct: %v\n", ft.str, pct, ft.pct)
+		}
+	}
+}
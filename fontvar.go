@@ -0,0 +1,819 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sync"
+
+	"github.com/goki/freetype/truetype"
+	"github.com/srwiley/rasterx"
+	"github.com/srwiley/scanFT"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// Variable fonts (OpenType font variations) contain a continuous range of
+// styles along one or more variation axes, e.g., weight (wght) and width
+// (wdth), instead of a separate font file for each style.  A variable font is
+// registered in the FontLib under its base name, and then provides all of the
+// weights, stretches and (if it has a slnt or ital axis) styles for that
+// name -- see FontStyle.FontOpts.  Arbitrary axis values can be set with the
+// font-variation-settings style property.  Glyph outlines are varied using
+// the gvar table of TrueType-flavored variable fonts -- glyph advances are
+// varied using the phantom points of gvar, and the HVAR, MVAR and GPOS
+// variations are not used, so the vertical metrics and kerning are those of
+// the default instance.  CFF2 (.otf) variable fonts are not supported.
+
+// FontAxis is a variation axis of a variable font
+type FontAxis struct {
+	Tag     string  `desc:"4-character axis tag, e.g., wght for weight, wdth for width, slnt for slant, ital for italic, opsz for optical size"`
+	Min     float32 `desc:"minimum value of the axis"`
+	Default float32 `desc:"default value of the axis -- the default instance of the font has this value"`
+	Max     float32 `desc:"maximum value of the axis"`
+}
+
+// FontVar has the variation information of a variable font, for generating
+// glyphs at any point in its design space
+type FontVar struct {
+	Axes       []FontAxis `desc:"variation axes of the font"`
+	UnitsPerEm int        `desc:"font design units per em"`
+	avar       [][][2]float32
+	gvar       []byte
+	shared     [][]float32
+	glyf       []byte
+	loca       []byte
+	locFmt     int
+	hmtx       []byte
+	nHMetrics  int
+}
+
+// ParseFontVar returns the variation information for given font file data,
+// or nil if the font is not a TrueType variable font
+func ParseFontVar(data []byte) *FontVar {
+	tbls := otTables(data)
+	fvar := tbls["fvar"]
+	if fvar == nil || tbls["glyf"] == nil {
+		return nil
+	}
+	fv := &FontVar{gvar: tbls["gvar"], glyf: tbls["glyf"], loca: tbls["loca"], hmtx: tbls["hmtx"]}
+	fv.UnitsPerEm = otU16(tbls["head"], 18)
+	if fv.UnitsPerEm == 0 {
+		fv.UnitsPerEm = 1000
+	}
+	fv.locFmt = otI16(tbls["head"], 50)
+	fv.nHMetrics = otU16(tbls["hhea"], 34)
+
+	axoff := otU16(fvar, 4)
+	nax := otU16(fvar, 8)
+	axsz := otU16(fvar, 10)
+	for i := 0; i < nax; i++ {
+		off := axoff + i*axsz
+		fv.Axes = append(fv.Axes, FontAxis{Tag: otTag(fvar, off), Min: otFixed(fvar, off+4), Default: otFixed(fvar, off+8), Max: otFixed(fvar, off+12)})
+	}
+	if len(fv.Axes) == 0 {
+		return nil
+	}
+
+	if avar := tbls["avar"]; avar != nil && otU16(avar, 6) == nax {
+		off := 8
+		fv.avar = make([][][2]float32, nax)
+		for i := 0; i < nax; i++ {
+			nm := otU16(avar, off)
+			off += 2
+			for j := 0; j < nm; j++ {
+				fv.avar[i] = append(fv.avar[i], [2]float32{otF2Dot14(avar, off), otF2Dot14(avar, off+2)})
+				off += 4
+			}
+		}
+	}
+
+	if fv.gvar != nil {
+		ac := otU16(fv.gvar, 4)
+		nst := otU16(fv.gvar, 6)
+		off := otU32(fv.gvar, 8)
+		for i := 0; i < nst; i++ {
+			fv.shared = append(fv.shared, otTuple(fv.gvar, off+i*2*ac, ac))
+		}
+	}
+	return fv
+}
+
+// HasAxis returns true if the font has a variation axis with given tag
+func (fv *FontVar) HasAxis(tag string) bool {
+	for _, ax := range fv.Axes {
+		if ax.Tag == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// NormCoords returns the normalized coordinates (-1..1 relative to the
+// default) for given axis values -- axes not in the map are at their default
+func (fv *FontVar) NormCoords(vals FontVariations) []float32 {
+	coords := make([]float32, len(fv.Axes))
+	for i, ax := range fv.Axes {
+		v, ok := vals[ax.Tag]
+		if !ok {
+			continue
+		}
+		v = InRange32(v, ax.Min, ax.Max)
+		var n float32
+		switch {
+		case v < ax.Default && ax.Default > ax.Min:
+			n = (v - ax.Default) / (ax.Default - ax.Min)
+		case v > ax.Default && ax.Max > ax.Default:
+			n = (v - ax.Default) / (ax.Max - ax.Default)
+		}
+		if i < len(fv.avar) {
+			n = avarMap(fv.avar[i], n)
+		}
+		coords[i] = n
+	}
+	return coords
+}
+
+// avarMap applies an avar segment map to a normalized coordinate
+func avarMap(segs [][2]float32, n float32) float32 {
+	for k := 1; k < len(segs); k++ {
+		if n > segs[k][0] {
+			continue
+		}
+		f0, t0 := segs[k-1][0], segs[k-1][1]
+		f1, t1 := segs[k][0], segs[k][1]
+		if f1 == f0 {
+			return t1
+		}
+		return t0 + (n-f0)*(t1-t0)/(f1-f0)
+	}
+	return n
+}
+
+// varPt is a glyph outline point of a variable font, in font units with y up
+type varPt struct {
+	X, Y float32
+	On   bool
+}
+
+// varGlyph is a glyph outline of a variable font instance, in font units
+// with y up, relative to the origin (left baseline)
+type varGlyph struct {
+	pts  []varPt
+	ends []int // index after the last point of each contour
+	adv  float32
+}
+
+// glyph returns the outline of given glyph at given normalized coordinates
+// (see NormCoords)
+func (fv *FontVar) glyph(gid int, coords []float32) *varGlyph {
+	pts, ends, pp := fv.glyphPoints(gid, coords, 0)
+	for i := range pts {
+		pts[i].X -= pp[0].X
+	}
+	return &varGlyph{pts: pts, ends: ends, adv: pp[1].X - pp[0].X}
+}
+
+// glyphData returns the glyf table data for given glyph, nil if empty
+func (fv *FontVar) glyphData(gid int) []byte {
+	var st, ed int
+	if fv.locFmt == 0 {
+		st, ed = 2*otU16(fv.loca, 2*gid), 2*otU16(fv.loca, 2*gid+2)
+	} else {
+		st, ed = otU32(fv.loca, 4*gid), otU32(fv.loca, 4*gid+4)
+	}
+	if st >= ed || ed > len(fv.glyf) {
+		return nil
+	}
+	return fv.glyf[st:ed]
+}
+
+// hMetric returns the advance width and left side bearing of given glyph,
+// in font units
+func (fv *FontVar) hMetric(gid int) (adv, lsb float32) {
+	if fv.nHMetrics == 0 {
+		return 0, 0
+	}
+	if gid < fv.nHMetrics {
+		return float32(otU16(fv.hmtx, 4*gid)), float32(otI16(fv.hmtx, 4*gid+2))
+	}
+	adv = float32(otU16(fv.hmtx, 4*(fv.nHMetrics-1)))
+	lsb = float32(otI16(fv.hmtx, 4*fv.nHMetrics+2*(gid-fv.nHMetrics)))
+	return
+}
+
+// varComp is a component of a composite glyph
+type varComp struct {
+	gid        int
+	dx, dy     float32
+	a, b, c, d float32 // transform
+}
+
+// glyphPoints returns the points and contour ends of given glyph at given
+// normalized coordinates, in the coordinates of the glyph data, along with
+// the horizontal phantom points (origin and advance)
+func (fv *FontVar) glyphPoints(gid int, coords []float32, depth int) ([]varPt, []int, [2]varPt) {
+	adv, lsb := fv.hMetric(gid)
+	data := fv.glyphData(gid)
+	nc := otI16(data, 0)
+	xmin := float32(otI16(data, 2))
+	if data == nil {
+		xmin = lsb
+	}
+	var pts []varPt
+	var ends []int
+	var comps []varComp
+	if nc >= 0 {
+		pts, ends = parseSimpleGlyph(data, nc)
+	} else if depth < 8 {
+		comps = parseCompositeGlyph(data)
+	}
+	pp := [2]varPt{{X: xmin - lsb}, {X: xmin - lsb + adv}}
+	np := len(pts)
+	if comps != nil {
+		np = len(comps)
+	}
+	var orig []varPt
+	if comps == nil {
+		orig = pts
+	}
+	if dx, dy := fv.deltas(gid, coords, np+4, orig, ends); dx != nil {
+		if comps == nil {
+			for i := range pts {
+				pts[i].X += dx[i]
+				pts[i].Y += dy[i]
+			}
+		} else {
+			for i := range comps {
+				comps[i].dx += dx[i]
+				comps[i].dy += dy[i]
+			}
+		}
+		pp[0].X += dx[np]
+		pp[1].X += dx[np+1]
+	}
+	for _, cp := range comps {
+		cpts, cends, _ := fv.glyphPoints(cp.gid, coords, depth+1)
+		st := len(pts)
+		for _, p := range cpts {
+			pts = append(pts, varPt{X: cp.a*p.X + cp.c*p.Y + cp.dx, Y: cp.b*p.X + cp.d*p.Y + cp.dy, On: p.On})
+		}
+		for _, e := range cends {
+			ends = append(ends, st+e)
+		}
+	}
+	return pts, ends, pp
+}
+
+// parseSimpleGlyph returns the points and contour ends of simple glyph data
+// with given number of contours
+func parseSimpleGlyph(data []byte, nc int) ([]varPt, []int) {
+	if nc == 0 {
+		return nil, nil
+	}
+	ends := make([]int, nc)
+	for i := range ends {
+		ends[i] = otU16(data, 10+2*i) + 1
+		if i > 0 && ends[i] <= ends[i-1] {
+			return nil, nil
+		}
+	}
+	np := ends[nc-1]
+	p := 10 + 2*nc
+	p += 2 + otU16(data, p) // skip instructions
+	flags := make([]byte, 0, np)
+	for len(flags) < np {
+		if p >= len(data) {
+			return nil, nil
+		}
+		f := data[p]
+		p++
+		flags = append(flags, f)
+		if f&0x08 != 0 { // repeat
+			if p >= len(data) {
+				return nil, nil
+			}
+			rep := int(data[p])
+			p++
+			for k := 0; k < rep && len(flags) < np; k++ {
+				flags = append(flags, f)
+			}
+		}
+	}
+	pts := make([]varPt, np)
+	for ax := 0; ax < 2; ax++ {
+		short, same := byte(0x02), byte(0x10)
+		if ax == 1 {
+			short, same = 0x04, 0x20
+		}
+		v := 0
+		for i, f := range flags {
+			switch {
+			case f&short != 0:
+				if p >= len(data) {
+					return nil, nil
+				}
+				d := int(data[p])
+				p++
+				if f&same == 0 {
+					d = -d
+				}
+				v += d
+			case f&same == 0:
+				v += otI16(data, p)
+				p += 2
+			}
+			if ax == 0 {
+				pts[i].X = float32(v)
+				pts[i].On = f&0x01 != 0
+			} else {
+				pts[i].Y = float32(v)
+			}
+		}
+	}
+	return pts, ends
+}
+
+// parseCompositeGlyph returns the components of composite glyph data --
+// components positioned by matching points are placed at the origin
+func parseCompositeGlyph(data []byte) []varComp {
+	var comps []varComp
+	p := 10
+	for {
+		flags := otU16(data, p)
+		cp := varComp{gid: otU16(data, p+2), a: 1, d: 1}
+		p += 4
+		var a1, a2 int
+		if flags&0x0001 != 0 { // words
+			a1, a2 = otI16(data, p), otI16(data, p+2)
+			p += 4
+		} else {
+			if p+2 > len(data) {
+				return comps
+			}
+			a1, a2 = int(int8(data[p])), int(int8(data[p+1]))
+			p += 2
+		}
+		if flags&0x0002 != 0 { // args are xy values
+			cp.dx, cp.dy = float32(a1), float32(a2)
+		}
+		switch {
+		case flags&0x0008 != 0: // scale
+			cp.a = otF2Dot14(data, p)
+			cp.d = cp.a
+			p += 2
+		case flags&0x0040 != 0: // x and y scale
+			cp.a, cp.d = otF2Dot14(data, p), otF2Dot14(data, p+2)
+			p += 4
+		case flags&0x0080 != 0: // 2x2
+			cp.a, cp.b = otF2Dot14(data, p), otF2Dot14(data, p+2)
+			cp.c, cp.d = otF2Dot14(data, p+4), otF2Dot14(data, p+6)
+			p += 8
+		}
+		comps = append(comps, cp)
+		if flags&0x0020 == 0 || p >= len(data) { // no more components
+			return comps
+		}
+	}
+}
+
+// deltas returns the x and y deltas of the n points of given glyph
+// (including the 4 phantom points) at given normalized coordinates, or nil
+// if none -- orig are the points of a simple glyph, used for inferring the
+// deltas of points that are not given
+func (fv *FontVar) deltas(gid int, coords []float32, n int, orig []varPt, ends []int) (dx, dy []float32) {
+	if fv.gvar == nil {
+		return nil, nil
+	}
+	nonzero := false
+	for _, c := range coords {
+		if c != 0 {
+			nonzero = true
+			break
+		}
+	}
+	if !nonzero {
+		return nil, nil
+	}
+	ac := otU16(fv.gvar, 4)
+	dataOff := otU32(fv.gvar, 16)
+	var st, ed int
+	if otU16(fv.gvar, 14)&1 != 0 {
+		st, ed = otU32(fv.gvar, 20+4*gid), otU32(fv.gvar, 24+4*gid)
+	} else {
+		st, ed = 2*otU16(fv.gvar, 20+2*gid), 2*otU16(fv.gvar, 22+2*gid)
+	}
+	st += dataOff
+	ed += dataOff
+	if st >= ed || ed > len(fv.gvar) {
+		return nil, nil
+	}
+	data := fv.gvar[st:ed]
+	tc := otU16(data, 0)
+	ser := otU16(data, 2)
+	var sharedPts []int
+	if tc&0x8000 != 0 {
+		sharedPts, ser = otPackedPoints(data, ser)
+	}
+	h := 4
+	for t := 0; t < tc&0x0FFF; t++ {
+		size := otU16(data, h)
+		tidx := otU16(data, h+2)
+		h += 4
+		var peak, start, end []float32
+		if tidx&0x8000 != 0 {
+			peak = otTuple(data, h, ac)
+			h += 2 * ac
+		} else if ti := tidx & 0x0FFF; ti < len(fv.shared) {
+			peak = fv.shared[ti]
+		}
+		if tidx&0x4000 != 0 {
+			start = otTuple(data, h, ac)
+			end = otTuple(data, h+2*ac, ac)
+			h += 4 * ac
+		}
+		if ser+size > len(data) {
+			break
+		}
+		tdata := data[ser : ser+size]
+		ser += size
+		sc := tupleScalar(coords, peak, start, end)
+		if sc == 0 {
+			continue
+		}
+		pts := sharedPts
+		p := 0
+		if tidx&0x2000 != 0 {
+			pts, p = otPackedPoints(tdata, 0)
+		}
+		np := n
+		if pts != nil {
+			np = len(pts)
+		}
+		xs, p := otPackedDeltas(tdata, p, np)
+		ys, _ := otPackedDeltas(tdata, p, np)
+		if dx == nil {
+			dx = make([]float32, n)
+			dy = make([]float32, n)
+		}
+		if pts == nil {
+			for i := 0; i < n; i++ {
+				dx[i] += sc * xs[i]
+				dy[i] += sc * ys[i]
+			}
+			continue
+		}
+		tdx := make([]float32, n)
+		tdy := make([]float32, n)
+		touched := make([]bool, n)
+		for i, pi := range pts {
+			if pi < n {
+				tdx[pi], tdy[pi] = xs[i], ys[i]
+				touched[pi] = true
+			}
+		}
+		if orig != nil {
+			cst := 0
+			for _, ced := range ends {
+				iupContour(orig, cst, ced, touched, tdx, true)
+				iupContour(orig, cst, ced, touched, tdy, false)
+				cst = ced
+			}
+		}
+		for i := 0; i < n; i++ {
+			dx[i] += sc * tdx[i]
+			dy[i] += sc * tdy[i]
+		}
+	}
+	return dx, dy
+}
+
+// tupleScalar returns the scalar for the deltas of a tuple variation with
+// given peak, and start and end of its intermediate region (nil if none), at
+// given normalized coordinates
+func tupleScalar(coords, peak, start, end []float32) float32 {
+	if peak == nil {
+		return 0
+	}
+	s := float32(1)
+	for i, pk := range peak {
+		if pk == 0 {
+			continue
+		}
+		c := float32(0)
+		if i < len(coords) {
+			c = coords[i]
+		}
+		if c == pk {
+			continue
+		}
+		if c == 0 {
+			return 0
+		}
+		var st, ed float32
+		switch {
+		case start != nil:
+			st, ed = start[i], end[i]
+		case pk < 0:
+			st, ed = pk, 0
+		default:
+			st, ed = 0, pk
+		}
+		if c < st || c > ed {
+			return 0
+		}
+		if c < pk {
+			s *= (c - st) / (pk - st)
+		} else {
+			s *= (ed - c) / (ed - pk)
+		}
+	}
+	return s
+}
+
+// iupContour infers the deltas of the points of a contour (from st to ed,
+// exclusive) that are not touched, by interpolating between the deltas of
+// the nearest touched points on either side, in x (isX) or y
+func iupContour(orig []varPt, st, ed int, touched []bool, d []float32, isX bool) {
+	coord := func(i int) float32 {
+		if isX {
+			return orig[i].X
+		}
+		return orig[i].Y
+	}
+	n := ed - st
+	first := -1
+	for i := st; i < ed; i++ {
+		if touched[i] {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return
+	}
+	next := func(i int) int {
+		return st + (i-st+1)%n
+	}
+	prev := first
+	for k := 1; k <= n; k++ {
+		i := st + (first-st+k)%n
+		if !touched[i] {
+			continue
+		}
+		for j := next(prev); j != i; j = next(j) {
+			d[j] = iupValue(coord(j), coord(prev), coord(i), d[prev], d[i])
+		}
+		prev = i
+	}
+}
+
+// iupValue returns the delta for coordinate c between two touched points
+// with coordinates c1, c2 and deltas d1, d2
+func iupValue(c, c1, c2, d1, d2 float32) float32 {
+	if c1 > c2 {
+		c1, c2 = c2, c1
+		d1, d2 = d2, d1
+	}
+	switch {
+	case c1 == c2:
+		if d1 == d2 {
+			return d1
+		}
+		return 0
+	case c <= c1:
+		return d1
+	case c >= c2:
+		return d2
+	}
+	return d1 + (c-c1)*(d2-d1)/(c2-c1)
+}
+
+// otFixed returns the 16.16 fixed-point number at given offset
+func otFixed(b []byte, off int) float32 {
+	return float32(int32(uint32(otU32(b, off)))) / 65536
+}
+
+// otF2Dot14 returns the 2.14 fixed-point number at given offset
+func otF2Dot14(b []byte, off int) float32 {
+	return float32(otI16(b, off)) / 16384
+}
+
+// otTuple returns the tuple of n 2.14 coordinates at given offset
+func otTuple(b []byte, off, n int) []float32 {
+	t := make([]float32, n)
+	for i := range t {
+		t[i] = otF2Dot14(b, off+2*i)
+	}
+	return t
+}
+
+// otPackedPoints returns the point numbers packed at given offset, nil for
+// all points, and the offset after them
+func otPackedPoints(b []byte, p int) ([]int, int) {
+	if p >= len(b) {
+		return nil, p
+	}
+	cnt := int(b[p])
+	p++
+	if cnt&0x80 != 0 {
+		if p >= len(b) {
+			return nil, p
+		}
+		cnt = (cnt&0x7F)<<8 | int(b[p])
+		p++
+	}
+	if cnt == 0 {
+		return nil, p
+	}
+	pts := make([]int, 0, cnt)
+	last := 0
+	for len(pts) < cnt && p < len(b) {
+		ctl := b[p]
+		p++
+		run := int(ctl&0x7F) + 1
+		for k := 0; k < run && len(pts) < cnt; k++ {
+			if ctl&0x80 != 0 { // words
+				last += otU16(b, p)
+				p += 2
+			} else {
+				if p >= len(b) {
+					break
+				}
+				last += int(b[p])
+				p++
+			}
+			pts = append(pts, last)
+		}
+	}
+	return pts, p
+}
+
+// otPackedDeltas returns n deltas packed at given offset, and the offset
+// after them
+func otPackedDeltas(b []byte, p, n int) ([]float32, int) {
+	ds := make([]float32, 0, n)
+	for len(ds) < n && p < len(b) {
+		ctl := b[p]
+		p++
+		run := int(ctl&0x3F) + 1
+		for k := 0; k < run && len(ds) < n; k++ {
+			switch {
+			case ctl&0x80 != 0: // zero
+				ds = append(ds, 0)
+			case ctl&0x40 != 0: // words
+				ds = append(ds, float32(otI16(b, p)))
+				p += 2
+			default:
+				if p >= len(b) {
+					break
+				}
+				ds = append(ds, float32(int8(b[p])))
+				p++
+			}
+		}
+	}
+	for len(ds) < n {
+		ds = append(ds, 0)
+	}
+	return ds, p
+}
+
+//////////////////////////////////////////////////////////////////////////////////
+//  Faces
+
+// OutlineFace is implemented by the font faces that generate their own
+// glyph outlines (variable font instances and synthesized small caps),
+// which are used instead of the outlines in the font file for substituted
+// glyphs (see RenderGlyph) and vector rendering
+type OutlineFace interface {
+	font.Face
+
+	// RuneOutline returns the outline of the glyph for given rune, with its
+	// origin at pos, transformed by xf around that origin, as in GlyphOutline
+	RuneOutline(r rune, pos Vec2D, xf Matrix2D) (rasterx.Path, bool)
+
+	// GlyphIndexOutline returns the outline of the glyph of given index, as
+	// in RuneOutline
+	GlyphIndexOutline(idx int, pos Vec2D, xf Matrix2D) (rasterx.Path, bool)
+
+	// GlyphIndexAdvance returns the advance of the glyph of given index, in dots
+	GlyphIndexAdvance(idx int) float32
+}
+
+// varFace is a font face for an instance of a variable font
+type varFace struct {
+	fv     *FontVar
+	ttf    *truetype.Font
+	base   font.Face // default instance at same size, for metrics and kerning
+	coords []float32
+	scale  float32 // dots per font unit
+	glyphs map[int]*varGlyph
+	mu     sync.Mutex
+}
+
+// newVarFace returns a face for given variable font at given normalized
+// coordinates, and size in dots
+func newVarFace(fv *FontVar, ttf *truetype.Font, base font.Face, coords []float32, size int) *varFace {
+	return &varFace{fv: fv, ttf: ttf, base: base, coords: coords, scale: float32(size) / float32(fv.UnitsPerEm), glyphs: make(map[int]*varGlyph)}
+}
+
+// glyph returns the (cached) glyph of given index
+func (vf *varFace) glyph(idx int) *varGlyph {
+	vf.mu.Lock()
+	defer vf.mu.Unlock()
+	vg, ok := vf.glyphs[idx]
+	if !ok {
+		vg = vf.fv.glyph(idx, vf.coords)
+		vf.glyphs[idx] = vg
+	}
+	return vg
+}
+
+// outline returns the outline of given glyph, as in GlyphOutline
+func (vf *varFace) outline(vg *varGlyph, pos Vec2D, xf Matrix2D) (rasterx.Path, bool) {
+	pts := make([]truetype.Point, len(vg.pts))
+	for i, p := range vg.pts {
+		pts[i] = truetype.Point{X: Float32ToFixed(p.X * vf.scale), Y: Float32ToFixed(p.Y * vf.scale)}
+		if p.On {
+			pts[i].Flags = 0x01
+		}
+	}
+	return PointsOutline(pts, vg.ends, pos, xf)
+}
+
+// bounds returns the bounds of given glyph in dots, relative to the origin,
+// with y down
+func (vf *varFace) bounds(vg *varGlyph) fixed.Rectangle26_6 {
+	if len(vg.pts) == 0 {
+		return fixed.Rectangle26_6{}
+	}
+	minx, miny := float32(math.MaxFloat32), float32(math.MaxFloat32)
+	maxx, maxy := -minx, -miny
+	for _, p := range vg.pts {
+		x, y := p.X*vf.scale, -p.Y*vf.scale
+		minx, maxx = Min32(minx, x), Max32(maxx, x)
+		miny, maxy = Min32(miny, y), Max32(maxy, y)
+	}
+	return fixed.Rectangle26_6{Min: Vec2D{minx, miny}.Fixed(), Max: Vec2D{maxx, maxy}.Fixed()}
+}
+
+func (vf *varFace) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	idx := vf.ttf.Index(r)
+	vg := vf.glyph(int(idx))
+	advance = Float32ToFixed(vg.adv * vf.scale)
+	bb := vf.bounds(vg)
+	dr = image.Rect((dot.X + bb.Min.X).Floor(), (dot.Y + bb.Min.Y).Floor(), (dot.X + bb.Max.X).Ceil(), (dot.Y + bb.Max.Y).Ceil())
+	img := image.NewRGBA(image.Rectangle{Max: dr.Size()})
+	if !dr.Empty() {
+		org := NewVec2DFmFixed(dot).Sub(NewVec2DFmPoint(dr.Min))
+		if p, has := vf.outline(vg, org, Identity2D()); has {
+			w, h := dr.Dx(), dr.Dy()
+			rf := rasterx.NewFiller(w, h, scanFT.NewScannerFT(w, h, scanFT.NewRGBAPainter(img)))
+			rf.SetWinding(true)
+			p.AddTo(rf)
+			rf.SetColor(color.White)
+			rf.Draw()
+		}
+	}
+	return dr, img, image.ZP, advance, idx != 0 // missing glyph, for fallback
+}
+
+func (vf *varFace) GlyphBounds(r rune) (bounds fixed.Rectangle26_6, advance fixed.Int26_6, ok bool) {
+	idx := vf.ttf.Index(r)
+	vg := vf.glyph(int(idx))
+	return vf.bounds(vg), Float32ToFixed(vg.adv * vf.scale), idx != 0
+}
+
+func (vf *varFace) GlyphAdvance(r rune) (advance fixed.Int26_6, ok bool) {
+	idx := vf.ttf.Index(r)
+	return Float32ToFixed(vf.glyph(int(idx)).adv * vf.scale), idx != 0
+}
+
+func (vf *varFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	return vf.base.Kern(r0, r1)
+}
+
+func (vf *varFace) Metrics() font.Metrics {
+	return vf.base.Metrics()
+}
+
+func (vf *varFace) Close() error {
+	return nil
+}
+
+func (vf *varFace) RuneOutline(r rune, pos Vec2D, xf Matrix2D) (rasterx.Path, bool) {
+	return vf.outline(vf.glyph(int(vf.ttf.Index(r))), pos, xf)
+}
+
+func (vf *varFace) GlyphIndexOutline(idx int, pos Vec2D, xf Matrix2D) (rasterx.Path, bool) {
+	return vf.outline(vf.glyph(idx), pos, xf)
+}
+
+func (vf *varFace) GlyphIndexAdvance(idx int) float32 {
+	return vf.glyph(idx).adv * vf.scale
+}
@@ -0,0 +1,265 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"encoding/binary"
+	"reflect"
+	"sort"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// testSfnt returns font file data with given tables
+func testSfnt(tbls map[string][]byte) []byte {
+	tags := make([]string, 0, len(tbls))
+	for tag := range tbls {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	data := make([]byte, 12+16*len(tags))
+	binary.BigEndian.PutUint32(data, 0x00010000)
+	binary.BigEndian.PutUint16(data[4:], uint16(len(tags)))
+	for i, tag := range tags {
+		rec := data[12+16*i:]
+		copy(rec, tag)
+		binary.BigEndian.PutUint32(rec[8:], uint32(len(data)))
+		binary.BigEndian.PutUint32(rec[12:], uint32(len(tbls[tag])))
+		data = append(data, tbls[tag]...)
+	}
+	return data
+}
+
+// testFvar returns an fvar table with given axes
+func testFvar(axes ...FontAxis) []byte {
+	b := make([]byte, 16+20*len(axes))
+	binary.BigEndian.PutUint16(b, 1)
+	binary.BigEndian.PutUint16(b[4:], 16)
+	binary.BigEndian.PutUint16(b[8:], uint16(len(axes)))
+	binary.BigEndian.PutUint16(b[10:], 20)
+	for i, ax := range axes {
+		a := b[16+20*i:]
+		copy(a, ax.Tag)
+		binary.BigEndian.PutUint32(a[4:], uint32(int32(ax.Min*65536)))
+		binary.BigEndian.PutUint32(a[8:], uint32(int32(ax.Default*65536)))
+		binary.BigEndian.PutUint32(a[12:], uint32(int32(ax.Max*65536)))
+	}
+	return b
+}
+
+// testAvar returns an avar table with given segment maps
+func testAvar(maps ...[][2]float32) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint16(b, 1)
+	binary.BigEndian.PutUint16(b[6:], uint16(len(maps)))
+	for _, segs := range maps {
+		b = append(b, byte(len(segs)>>8), byte(len(segs)))
+		for _, sg := range segs {
+			for _, v := range sg {
+				f := uint16(int16(v * 16384))
+				b = append(b, byte(f>>8), byte(f))
+			}
+		}
+	}
+	return b
+}
+
+var testWghtAxis = FontAxis{"wght", 100, 400, 900}
+var testWdthAxis = FontAxis{"wdth", 50, 100, 100}
+var testWghtAvar = [][2]float32{{-1, -1}, {0, 0}, {0.5, 0.75}, {1, 1}}
+
+func TestParseFontVar(t *testing.T) {
+	head := make([]byte, 54)
+	binary.BigEndian.PutUint16(head[18:], 2048)
+	glyf := []byte{0}
+	tests := []struct {
+		tbls  map[string][]byte
+		axes  []FontAxis
+		upem  int
+		navar int
+	}{
+		{map[string][]byte{}, nil, 0, 0},
+		{map[string][]byte{"glyf": glyf}, nil, 0, 0},
+		{map[string][]byte{"fvar": testFvar(testWghtAxis)}, nil, 0, 0},
+		{map[string][]byte{"fvar": testFvar(), "glyf": glyf}, nil, 0, 0},
+		{map[string][]byte{"fvar": testFvar(testWghtAxis), "glyf": glyf}, []FontAxis{testWghtAxis}, 1000, 0},
+		{map[string][]byte{"fvar": testFvar(testWghtAxis, testWdthAxis), "glyf": glyf, "head": head}, []FontAxis{testWghtAxis, testWdthAxis}, 2048, 0},
+		{map[string][]byte{"fvar": testFvar(testWghtAxis, testWdthAxis), "glyf": glyf, "avar": testAvar(testWghtAvar, nil)}, []FontAxis{testWghtAxis, testWdthAxis}, 1000, 2},
+		{map[string][]byte{"fvar": testFvar(testWghtAxis, testWdthAxis), "glyf": glyf, "avar": testAvar(testWghtAvar)}, []FontAxis{testWghtAxis, testWdthAxis}, 1000, 0}, // axis count mismatch
+	}
+	for i, ft := range tests {
+		fv := ParseFontVar(testSfnt(ft.tbls))
+		if ft.axes == nil {
+			if fv != nil {
+				t.Errorf("ParseFontVar %v: %v != correct: nil\n", i, fv.Axes)
+			}
+			continue
+		}
+		if fv == nil {
+			t.Errorf("ParseFontVar %v: nil != correct: %v\n", i, ft.axes)
+			continue
+		}
+		if !reflect.DeepEqual(fv.Axes, ft.axes) {
+			t.Errorf("ParseFontVar %v Axes: %v != correct: %v\n", i, fv.Axes, ft.axes)
+		}
+		if fv.UnitsPerEm != ft.upem {
+			t.Errorf("ParseFontVar %v UnitsPerEm: %v != correct: %v\n", i, fv.UnitsPerEm, ft.upem)
+		}
+		if len(fv.avar) != ft.navar {
+			t.Errorf("ParseFontVar %v avar: %v != correct: %v maps\n", i, fv.avar, ft.navar)
+		}
+		if !fv.HasAxis("wght") || fv.HasAxis("slnt") {
+			t.Errorf("ParseFontVar %v HasAxis: %v %v != correct: true false\n", i, fv.HasAxis("wght"), fv.HasAxis("slnt"))
+		}
+	}
+	if fv := ParseFontVar(goregular.TTF); fv != nil {
+		t.Errorf("ParseFontVar of Go Regular: %v != correct: nil\n", fv.Axes)
+	}
+}
+
+func TestNormCoords(t *testing.T) {
+	fv := &FontVar{Axes: []FontAxis{testWghtAxis, testWdthAxis}}
+	fva := &FontVar{Axes: fv.Axes, avar: [][][2]float32{testWghtAvar}}
+	tests := []struct {
+		fv     *FontVar
+		vals   FontVariations
+		coords []float32
+	}{
+		{fv, nil, []float32{0, 0}},
+		{fv, FontVariations{"wght": 400, "wdth": 100}, []float32{0, 0}},
+		{fv, FontVariations{"wght": 650}, []float32{0.5, 0}},
+		{fv, FontVariations{"wght": 250}, []float32{-0.5, 0}},
+		{fv, FontVariations{"wght": 1000, "wdth": 10}, []float32{1, -1}},
+		{fv, FontVariations{"wdth": 75, "slnt": -10}, []float32{0, -0.5}},
+		{fv, FontVariations{"wdth": 200}, []float32{0, 0}},
+		{fva, FontVariations{"wght": 650, "wdth": 75}, []float32{0.75, -0.5}},
+		{fva, FontVariations{"wght": 525}, []float32{0.375, 0}},
+	}
+	for _, ft := range tests {
+		if coords := ft.fv.NormCoords(ft.vals); !reflect.DeepEqual(coords, ft.coords) {
+			t.Errorf("NormCoords(%v): %v != correct: %v\n", ft.vals, coords, ft.coords)
+		}
+	}
+}
+
+func TestAvarMap(t *testing.T) {
+	tests := []struct {
+		segs [][2]float32
+		n    float32
+		m    float32
+	}{
+		{nil, 0.3, 0.3},
+		{testWghtAvar, -1, -1},
+		{testWghtAvar, -0.5, -0.5},
+		{testWghtAvar, 0, 0},
+		{testWghtAvar, 0.25, 0.375},
+		{testWghtAvar, 0.5, 0.75},
+		{testWghtAvar, 0.75, 0.875},
+		{testWghtAvar, 1, 1},
+		{[][2]float32{{-1, -1}, {0, 0}, {0, 0.5}, {1, 1}}, 0, 0}, // first matching segment wins
+	}
+	for _, ft := range tests {
+		if m := avarMap(ft.segs, ft.n); m != ft.m {
+			t.Errorf("avarMap(%v, %v): %v != correct: %v\n", ft.segs, ft.n, m, ft.m)
+		}
+	}
+}
+
+func TestTupleScalar(t *testing.T) {
+	tests := []struct {
+		coords, peak, start, end []float32
+		s                        float32
+	}{
+		{[]float32{0.5}, nil, nil, nil, 0},
+		{[]float32{0.5}, []float32{1}, nil, nil, 0.5},
+		{[]float32{1}, []float32{1}, nil, nil, 1},
+		{[]float32{0}, []float32{1}, nil, nil, 0},
+		{nil, []float32{1}, nil, nil, 0},
+		{[]float32{-0.5}, []float32{1}, nil, nil, 0},
+		{[]float32{-0.5}, []float32{-1}, nil, nil, 0.5},
+		{[]float32{0.5, 0.5}, []float32{1, 0}, nil, nil, 0.5},
+		{[]float32{0.5, 0.25}, []float32{1, 0.5}, nil, nil, 0.25},
+		{[]float32{0.75}, []float32{0.5}, []float32{0.25}, []float32{1}, 0.5},
+		{[]float32{0.375}, []float32{0.5}, []float32{0.25}, []float32{1}, 0.5},
+		{[]float32{0.1}, []float32{0.5}, []float32{0.25}, []float32{1}, 0},
+	}
+	for _, ft := range tests {
+		if s := tupleScalar(ft.coords, ft.peak, ft.start, ft.end); s != ft.s {
+			t.Errorf("tupleScalar(%v, %v, %v, %v): %v != correct: %v\n", ft.coords, ft.peak, ft.start, ft.end, s, ft.s)
+		}
+	}
+}
+
+func TestIUP(t *testing.T) {
+	tests := []struct {
+		c, c1, c2, d1, d2 float32
+		d                 float32
+	}{
+		{5, 0, 10, 2, 4, 3},
+		{-1, 0, 10, 2, 4, 2},
+		{11, 0, 10, 2, 4, 4},
+		{5, 10, 0, 4, 2, 3},
+		{5, 3, 3, 2, 2, 2},
+		{5, 3, 3, 2, 4, 0},
+	}
+	for _, ft := range tests {
+		if d := iupValue(ft.c, ft.c1, ft.c2, ft.d1, ft.d2); d != ft.d {
+			t.Errorf("iupValue(%v, %v, %v, %v, %v): %v != correct: %v\n", ft.c, ft.c1, ft.c2, ft.d1, ft.d2, d, ft.d)
+		}
+	}
+
+	// square contour with the corners 0 and 2 touched
+	orig := []varPt{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}, {X: 5, Y: 5}}
+	touched := []bool{true, false, true, false, false}
+	dx := []float32{2, 0, 4, 0, 9}
+	iupContour(orig, 0, 4, touched, dx, true)
+	if cor := []float32{2, 4, 4, 2, 9}; !reflect.DeepEqual(dx, cor) {
+		t.Errorf("iupContour x: %v != correct: %v\n", dx, cor)
+	}
+	dy := []float32{2, 0, 4, 0, 9}
+	iupContour(orig, 0, 4, []bool{false, false, false, false, true}, dy, false)
+	if cor := []float32{2, 0, 4, 0, 9}; !reflect.DeepEqual(dy, cor) {
+		t.Errorf("iupContour untouched: %v != correct: %v\n", dy, cor)
+	}
+}
+
+func TestOTPacked(t *testing.T) {
+	pts := []struct {
+		b   []byte
+		pts []int
+		p   int
+	}{
+		{nil, nil, 0},
+		{[]byte{0}, nil, 1},
+		{[]byte{3, 0x02, 1, 2, 3}, []int{1, 3, 6}, 5},
+		{[]byte{2, 0x81, 0x01, 0x00, 0x00, 0x05}, []int{256, 261}, 6},
+		{[]byte{0x80, 0x02, 0x01, 4, 1}, []int{4, 5}, 5},
+		{[]byte{3, 0x00, 2, 0x00, 1, 0x00, 1}, []int{2, 3, 4}, 7},
+	}
+	for _, ft := range pts {
+		if pt, p := otPackedPoints(ft.b, 0); !reflect.DeepEqual(pt, ft.pts) || p != ft.p {
+			t.Errorf("otPackedPoints(%v): %v %v != correct: %v %v\n", ft.b, pt, p, ft.pts, ft.p)
+		}
+	}
+	dts := []struct {
+		b  []byte
+		n  int
+		ds []float32
+		p  int
+	}{
+		{nil, 2, []float32{0, 0}, 0},
+		{[]byte{0x02, 1, 0xFF, 3}, 3, []float32{1, -1, 3}, 4},
+		{[]byte{0x81}, 2, []float32{0, 0}, 1},
+		{[]byte{0x40, 0x01, 0x00, 0x80}, 3, []float32{256, 0, 0}, 4},
+		{[]byte{0x41, 0xFF, 0xFE, 0x00, 0x10, 0x00, 7}, 3, []float32{-2, 16, 7}, 7},
+		{[]byte{0x03, 1, 2, 3, 4}, 2, []float32{1, 2}, 3},
+	}
+	for _, ft := range dts {
+		if ds, p := otPackedDeltas(ft.b, 0, ft.n); !reflect.DeepEqual(ds, ft.ds) || p != ft.p {
+			t.Errorf("otPackedDeltas(%v, %v): %v %v != correct: %v %v\n", ft.b, ft.n, ds, p, ft.ds, ft.p)
+		}
+	}
+}
@@ -643,6 +643,33 @@ func (a Matrix2D) ToRasterx() rasterx.Matrix2D {
 	return rasterx.Matrix2D{float64(a.XX), float64(a.YX), float64(a.XY), float64(a.YY), float64(a.X0), float64(a.Y0)}
 }
 
+// Matrix2DFromRasterx returns a Matrix2D from the rasterx version
+func Matrix2DFromRasterx(m rasterx.Matrix2D) Matrix2D {
+	return Matrix2D{float32(m.A), float32(m.B), float32(m.C), float32(m.D), float32(m.E), float32(m.F)}
+}
+
+// Inverse returns the inverse of the matrix, which undoes its transform --
+// returns the identity if the matrix is singular (cannot be inverted)
+func (a Matrix2D) Inverse() Matrix2D {
+	det := a.XX*a.YY - a.XY*a.YX
+	if det == 0 {
+		return Identity2D()
+	}
+	idet := 1 / det
+	return Matrix2D{
+		a.YY * idet, -a.YX * idet,
+		-a.XY * idet, a.XX * idet,
+		(a.XY*a.Y0 - a.YY*a.X0) * idet,
+		(a.YX*a.X0 - a.XX*a.Y0) * idet,
+	}
+}
+
+// IsSingular returns true if the matrix cannot be inverted, e.g., because it
+// scales to zero along one dimension
+func (a Matrix2D) IsSingular() bool {
+	return a.XX*a.YY-a.XY*a.YX == 0
+}
+
 // ExtractRot extracts the rotation component from a given matrix
 func (a Matrix2D) ExtractRot() float32 {
 	return math32.Atan2(-a.XY, a.XX)
@@ -778,8 +805,11 @@ func (a *Matrix2D) SetString(str string) error {
 				log.Println(err)
 				return err
 			}
-			*a = Matrix2D{pts[0], pts[1], pts[2], pts[3], pts[4], pts[5]}
+			*a = Matrix2D{pts[0], pts[1], pts[2], pts[3], pts[4], pts[5]}.Multiply(*a)
 		case "translate":
+			if len(pts) == 1 { // ty defaults to 0
+				pts = append(pts, 0)
+			}
 			if err := PointsCheckN(pts, 2, errmsg); err != nil {
 				log.Println(err)
 				return err
@@ -832,19 +862,19 @@ func (a *Matrix2D) SetString(str string) error {
 				log.Println(err)
 				return err
 			}
-			*a = a.Skew(pts[0], pts[1])
+			*a = a.Skew(pts[0]*math32.Pi/180, pts[1]*math32.Pi/180) // degrees, as in svg
 		case "skewx":
 			if err := PointsCheckN(pts, 1, errmsg); err != nil {
 				log.Println(err)
 				return err
 			}
-			*a = a.Skew(pts[0], 0)
+			*a = a.Skew(pts[0]*math32.Pi/180, 0)
 		case "skewy":
 			if err := PointsCheckN(pts, 1, errmsg); err != nil {
 				log.Println(err)
 				return err
 			}
-			*a = a.Skew(0, pts[0])
+			*a = a.Skew(0, pts[0]*math32.Pi/180)
 		}
 		if nxt == "" {
 			break
@@ -5,6 +5,8 @@
 package giv
 
 import (
+	"time"
+
 	"github.com/goki/gi"
 	"github.com/goki/gi/units"
 	"github.com/goki/ki"
@@ -38,6 +40,14 @@ func StructViewDialog(avp *gi.Viewport2D, stru interface{}, opts DlgOpts, recv k
 	sv := frame.InsertNewChild(KiT_StructView, prIdx+1, "struct-view").(*StructView)
 	sv.Viewport = dlg.Embed(gi.KiT_Viewport2D).(*gi.Viewport2D)
 	sv.SetStruct(stru, opts.TmpSave)
+	if opts.Ok && sv.Validating { // block Ok while invalid
+		dlg.ValidFunc = sv.Validate
+		sv.ViewSig.Connect(dlg.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+			ddlg := recv.Embed(gi.KiT_Dialog).(*gi.Dialog)
+			ddlg.UpdateOk()
+		})
+		dlg.UpdateOk()
+	}
 
 	if recv != nil && dlgFunc != nil {
 		dlg.DialogSig.Connect(recv, dlgFunc)
@@ -325,6 +335,41 @@ func ColorViewDialogValue(dlg *gi.Dialog) gi.Color {
 	return gi.Color{}
 }
 
+// TimeViewDialog is for editing a time using a TimeView, which shows a
+// calendar for the date and spinners for the time of day, as determined by
+// the given time layout (as in time.Format) -- min and max limit the times
+// that can be chosen (zero for no limit).  recv and dlgFunc connect to the
+// dialog signal: if signal value is gi.DialogAccepted use TimeViewDialogValue
+// to get the resulting time.
+func TimeViewDialog(avp *gi.Viewport2D, tm time.Time, format string, min, max time.Time, opts DlgOpts, recv ki.Ki, dlgFunc ki.RecvFunc) *gi.Dialog {
+	dlg := gi.NewStdDialog(opts.ToGiOpts(), true, true)
+
+	frame := dlg.Frame()
+	_, prIdx := dlg.PromptWidget(frame)
+
+	tv := frame.InsertNewChild(KiT_TimeView, prIdx+1, "time-view").(*TimeView)
+	tv.Viewport = dlg.Embed(gi.KiT_Viewport2D).(*gi.Viewport2D)
+	tv.Min, tv.Max = min, max
+	tv.SetTime(tm, format, opts.TmpSave)
+
+	if recv != nil && dlgFunc != nil {
+		dlg.DialogSig.Connect(recv, dlgFunc)
+	}
+	dlg.UpdateEndNoSig(true)
+	dlg.Open(0, 0, avp, nil)
+	return dlg
+}
+
+// TimeViewDialogValue gets the time from the dialog
+func TimeViewDialogValue(dlg *gi.Dialog) time.Time {
+	frame := dlg.Frame()
+	tvk, ok := frame.Children().ElemByType(KiT_TimeView, true, 2)
+	if ok {
+		return tvk.(*TimeView).Time
+	}
+	return time.Time{}
+}
+
 // FileViewDialog is for selecting / manipulating files -- ext is one or more
 // (comma separated) extensions -- files with those will be highighted
 // (include the . at the start of the extension).  recv and dlgFunc connect to the
@@ -356,8 +356,12 @@ func (ft *FileTime) FromInt(val int64) {
 	*ft = FileTime(time.Unix(val, 0))
 }
 
+// FileTimeFormat is the time layout (as in time.Format) used for FileTime
+// values
+const FileTimeFormat = "Mon Jan  2 15:04:05 MST 2006"
+
 func (ft FileTime) String() string {
-	return (time.Time)(ft).Format("Mon Jan  2 15:04:05 MST 2006")
+	return (time.Time)(ft).Format(FileTimeFormat)
 }
 
 func (ft FileTime) MarshalBinary() ([]byte, error) {
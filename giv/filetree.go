@@ -39,6 +39,11 @@ var KiT_FileTree = kit.Types.AddType(&FileTree{}, FileTreeProps)
 
 var FileTreeProps = ki.Props{}
 
+// FileTreeTableColumns are the Columns of a TreeTableView showing a FileTree,
+// from the Info of the FileNode's -- e.g., SetInactive on the TreeTableView
+// and SetRootNode(ftree, FileTreeTableColumns)
+var FileTreeTableColumns = []string{"Info.Size", "Info.Kind", "Info.ModTime"}
+
 // OpenPath opens a filetree at given directory path -- reads all the files at
 // given path into this tree -- uses config children to preserve extra info
 // already stored about files.  Only paths listed in OpenDirs will be opened.
@@ -107,6 +112,14 @@ type FileNode struct {
 	FRoot *FileTree   `json:"-" xml:"-" desc:"root of the tree -- has global state"`
 }
 
+// fileNodeFetch is the contents of a directory read in the background by the
+// function from FetchFunc, for LoadFetched
+type fileNodeFetch struct {
+	path   gi.FileName
+	config kit.TypeAndNameList
+	infos  map[string]FileInfo
+}
+
 var KiT_FileNode = kit.Types.AddType(&FileNode{}, nil)
 
 func init() {
@@ -165,6 +178,12 @@ func (fn *FileNode) RelPath() string {
 // root node represents the directory at the given path.  Returns os.Stat
 // error if path cannot be accessed.
 func (fn *FileNode) ReadDir(path string) error {
+	return fn.readDir(path, nil)
+}
+
+// readDir does ReadDir, using the files and their info in fd if non-nil,
+// instead of reading them
+func (fn *FileNode) readDir(path string, fd *fileNodeFetch) error {
 	_, fnm := filepath.Split(path)
 	fn.SetName(fnm)
 	pth, err := filepath.Abs(path)
@@ -180,7 +199,12 @@ func (fn *FileNode) ReadDir(path string) error {
 	fn.SetOpen()
 
 	typ := fn.NodeType()
-	config := fn.ConfigOfFiles(path)
+	var config kit.TypeAndNameList
+	if fd != nil {
+		config = fd.config
+	} else {
+		config = fn.ConfigOfFiles(path)
+	}
 	mods, updt := fn.ConfigChildren(config, true) // unique names
 	// always go through kids, regardless of mods
 	for _, sfk := range fn.Kids {
@@ -188,7 +212,13 @@ func (fn *FileNode) ReadDir(path string) error {
 		sf.FRoot = fn.FRoot
 		sf.SetChildType(typ) // propagate
 		fp := filepath.Join(path, sf.Nm)
-		sf.SetNodePath(fp)
+		if fi, ok := fd.info(sf.Nm); ok {
+			sf.FPath = gi.FileName(fi.Path)
+			sf.Info = fi
+			sf.UpdateDir()
+		} else {
+			sf.SetNodePath(fp)
+		}
 	}
 	if mods {
 		fn.UpdateEnd(updt)
@@ -208,9 +238,15 @@ func (fn *FileNode) NodeType() reflect.Type {
 // ConfigOfFiles returns a type-and-name list for configuring nodes based on
 // files immediately within given path
 func (fn *FileNode) ConfigOfFiles(path string) kit.TypeAndNameList {
+	return FilesConfig(path, fn.NodeType(), fn.FRoot.DirsOnTop)
+}
+
+// FilesConfig returns a type-and-name list for configuring nodes of given
+// type based on files immediately within given path, with the directories
+// first if dirsOnTop
+func FilesConfig(path string, typ reflect.Type, dirsOnTop bool) kit.TypeAndNameList {
 	config1 := kit.TypeAndNameList{}
 	config2 := kit.TypeAndNameList{}
-	typ := fn.NodeType()
 	filepath.Walk(path, func(pth string, info os.FileInfo, err error) error {
 		if err != nil {
 			emsg := fmt.Sprintf("giv.FileNode ConfigFilesIn Path %q: Error: %v", path, err)
@@ -221,7 +257,7 @@ func (fn *FileNode) ConfigOfFiles(path string) kit.TypeAndNameList {
 			return nil
 		}
 		_, fnm := filepath.Split(pth)
-		if fn.FRoot.DirsOnTop {
+		if dirsOnTop {
 			if info.IsDir() {
 				config1.Add(typ, fnm)
 			} else {
@@ -235,7 +271,7 @@ func (fn *FileNode) ConfigOfFiles(path string) kit.TypeAndNameList {
 		}
 		return nil
 	})
-	if fn.FRoot.DirsOnTop {
+	if dirsOnTop {
 		for _, tn := range config2 {
 			config1 = append(config1, tn)
 		}
@@ -261,19 +297,84 @@ func (fn *FileNode) UpdateNode() error {
 		log.Println(emsg)
 		return emsg
 	}
+	fn.UpdateDir()
+	return nil
+}
+
+// UpdateDir reads the files of this node if it is an open directory
+func (fn *FileNode) UpdateDir() {
 	if fn.IsDir() {
 		if fn.FRoot.IsDirOpen(fn.FPath) {
 			fn.ReadDir(string(fn.FPath)) // keep going down..
 		}
 	}
-	return nil
 }
 
 // OpenDir opens given directory node
 func (fn *FileNode) OpenDir() {
+	fn.LoadChildren()
+}
+
+// HasUnloadedChildren returns true if this is a directory whose files have
+// not been read yet, satisfying the TreeViewProvider interface
+func (fn *FileNode) HasUnloadedChildren() bool {
+	return fn.IsDir() && !fn.IsOpen() && !fn.HasChildren()
+}
+
+// LoadChildren opens this directory node, reading its files, satisfying the
+// TreeViewProvider interface
+func (fn *FileNode) LoadChildren() error {
 	fn.SetOpen()
 	fn.FRoot.SetDirOpen(fn.FPath)
-	fn.UpdateNode()
+	return fn.UpdateNode()
+}
+
+// LoadAsync returns true, as directories can be slow to read, e.g., on a
+// network mount, satisfying the TreeViewAsyncProvider interface
+func (fn *FileNode) LoadAsync() bool {
+	return true
+}
+
+// FetchFunc returns a function that reads the files of this directory node,
+// and their file info, in the background, for LoadFetched -- satisfies the
+// TreeViewAsyncProvider interface
+func (fn *FileNode) FetchFunc() func() (interface{}, error) {
+	path := fn.FPath
+	typ := fn.NodeType()
+	dirsOnTop := fn.FRoot.DirsOnTop
+	return func() (interface{}, error) {
+		fd := &fileNodeFetch{path: path, config: FilesConfig(string(path), typ, dirsOnTop), infos: make(map[string]FileInfo)}
+		for _, tn := range fd.config {
+			var fi FileInfo
+			if err := fi.InitFile(filepath.Join(string(path), tn.Name)); err == nil {
+				fd.infos[tn.Name] = fi
+			}
+		}
+		return fd, nil
+	}
+}
+
+// LoadFetched opens this directory node, with the files read by the function
+// from FetchFunc -- reads them again if the node has moved meanwhile --
+// satisfies the TreeViewAsyncProvider interface
+func (fn *FileNode) LoadFetched(fetched interface{}) error {
+	fd, ok := fetched.(*fileNodeFetch)
+	if !ok || fd.path != fn.FPath {
+		return fn.LoadChildren()
+	}
+	fn.SetOpen()
+	fn.FRoot.SetDirOpen(fn.FPath)
+	return fn.readDir(string(fn.FPath), fd)
+}
+
+// info returns the fetched file info for the file of given name -- false if
+// none (nil safe)
+func (fd *fileNodeFetch) info(name string) (FileInfo, bool) {
+	if fd == nil {
+		return FileInfo{}, false
+	}
+	fi, ok := fd.infos[name]
+	return fi, ok
 }
 
 // CloseDir closes given directory node -- updates memory state
@@ -695,7 +796,7 @@ var fnFolderProps = ki.Props{
 func (tv *FileTreeView) Style2D() {
 	fn := tv.SrcNode.Ptr.Embed(KiT_FileNode).(*FileNode)
 	if fn.IsDir() {
-		if fn.HasChildren() {
+		if fn.HasChildren() || fn.HasUnloadedChildren() {
 			tv.Icon = gi.IconName("")
 		} else {
 			tv.Icon = gi.IconName("folder")
@@ -0,0 +1,740 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/goki/gi"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki"
+	"github.com/goki/ki/bitflag"
+	"github.com/goki/ki/kit"
+	"github.com/iancoleman/strcase"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  FuncForm
+
+// FuncForm is a standalone, non-modal form for calling a Go function with
+// args edited in the form, and showing its results -- see SetFunc, and
+// FuncFormWindow for a form in its own window.  The args have defaults and
+// validation from their props, or from the field tags of an arg struct, and
+// Run calls the function in the background, showing a spinner, its progress
+// and a Cancel action while it runs -- the results are then shown below
+// through their ToValueView, and any error returned in the status.
+type FuncForm struct {
+	gi.Frame
+	Func        reflect.Value   `view:"-" json:"-" xml:"-" desc:"the function that is called"`
+	Opts        FuncFormOpts    `desc:"options for the form"`
+	Args        []ArgData       `json:"-" xml:"-" desc:"data for each of the args of the function, as for ArgView -- args of type context.Context and FuncFormProgress are supplied by the form, and are not shown"`
+	ArgStruct   interface{}     `desc:"pointer to the struct whose fields are edited as the args of a function that takes a single struct (or pointer to one) -- initialized from the def tags of its fields and its Defaults() method -- nil otherwise"`
+	Results     []reflect.Value `json:"-" xml:"-" desc:"the results of the last call that completed, without any error result -- shown below the args, where any edits only change these copies"`
+	Err         error           `json:"-" xml:"-" desc:"error returned by the last call that completed (if its last result is an error), or the validation error of the args if Run was blocked by it"`
+	Elapsed     time.Duration   `desc:"duration of the last call that completed"`
+	Progress    float32         `desc:"fraction done (0-1) of the running call, as reported through its FuncFormProgress arg -- negative if not reported"`
+	Running     bool            `desc:"is a call of the function running?"`
+	Canceled    bool            `desc:"was the last call canceled?"`
+	FuncFormSig ki.Signal       `json:"-" xml:"-" desc:"signal for FuncForm -- see FuncFormSignals for the types"`
+	argStruct   int             // index of the arg struct in Args, -1 if none
+	argsInvalid bool            // Err is from validation of the args
+	call        *funcFormCall   // the running call, nil if none
+	spinFrame   int             // current frame of the spinner
+	mu          sync.Mutex      // protects call, spinFrame, and Results, Err, Elapsed, Progress, Running and Canceled, which are set when the call completes in the background
+}
+
+var KiT_FuncForm = kit.Types.AddType(&FuncForm{}, FuncFormProps)
+
+var FuncFormProps = ki.Props{
+	"background-color": &gi.Prefs.Colors.Background,
+	"color":            &gi.Prefs.Colors.Font,
+	"max-width":        -1,
+	"max-height":       -1,
+	"#title": ki.Props{
+		"max-width":      -1,
+		"text-align":     gi.AlignCenter,
+		"vertical-align": gi.AlignTop,
+	},
+	"#status": ki.Props{
+		"max-width": -1,
+	},
+}
+
+// FuncFormOpts are the options for a FuncForm, given to SetFunc
+type FuncFormOpts struct {
+	Title   string       `desc:"title / prompt shown above the args"`
+	Args    ki.PropSlice `desc:"names and props of the args of the function, in order, as for the Args of methods in MethView -- props are desc, default, value (fixed, not shown), and validation tags (see ValidateTags), e.g., {\"Count\", ki.Props{\"default\": 10, \"min\": \"1\"}} -- args without an entry are named Arg 1, Arg 2.. -- if empty, a single struct arg is edited as an ArgStruct, using its field tags"`
+	Results []string     `desc:"names of the results of the function, in order, not counting a last error result -- default Result, or Result 1, Result 2.."`
+	RunText string       `desc:"label of the action that calls the function -- default Run"`
+}
+
+// FuncFormSignals are signals that FuncForm sends about calls of its function
+type FuncFormSignals int64
+
+const (
+	// FuncFormStarted is emitted when a call of the function has started
+	FuncFormStarted FuncFormSignals = iota
+
+	// FuncFormDone is emitted when a call has completed, and its results are
+	// shown -- data is the error returned, if any
+	FuncFormDone
+
+	// FuncFormCanceled is emitted when the running call has been canceled
+	FuncFormCanceled
+
+	FuncFormSignalsN
+)
+
+//go:generate stringer -type=FuncFormSignals
+
+// FuncFormProgress is the type of an arg of a function called by a FuncForm,
+// through which the function reports its progress, as the fraction done
+// (0-1) -- the form supplies it, and shows the progress while the function
+// runs
+type FuncFormProgress func(frac float32)
+
+// An arg of type context.Context is supplied by the FuncForm with the context
+// of the call, which is canceled by the Cancel action -- the function should
+// return when it is done.  A function that does not take one cannot actually
+// be stopped -- it keeps running after Cancel, and its results are discarded.
+var funcFormCtxType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+var funcFormProgressType = reflect.TypeOf(FuncFormProgress(nil))
+
+var funcFormErrType = reflect.TypeOf((*error)(nil)).Elem()
+
+// funcFormCall is a call of the function of a FuncForm
+type funcFormCall struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	start  time.Time
+}
+
+// SetFunc sets the function that is called by the form, with given options,
+// and configures the form for its args -- returns false (and logs the
+// error) if fun is not a function, or is variadic
+func (ff *FuncForm) SetFunc(fun interface{}, opts FuncFormOpts) bool {
+	if !FuncFormCheck(fun) {
+		return false
+	}
+	fval := reflect.ValueOf(fun)
+	updt := ff.UpdateStart()
+	ff.Cancel()
+	ff.Func = fval
+	ff.Opts = opts
+	ff.mu.Lock()
+	ff.Results = nil
+	ff.Err = nil
+	ff.Canceled = false
+	ff.mu.Unlock()
+	ff.argsInvalid = false
+	ff.SetArgs()
+	ff.UpdateFromFunc()
+	ff.UpdateEnd(updt)
+	return true
+}
+
+// FuncFormCheck returns true if fun can be called by a FuncForm -- logs the
+// error otherwise: not a function, or variadic
+func FuncFormCheck(fun interface{}) bool {
+	fval := reflect.ValueOf(fun)
+	if fval.Kind() != reflect.Func || fval.IsNil() {
+		log.Printf("giv.FuncForm: value is not a function: %T\n", fun)
+		return false
+	}
+	if fval.Type().IsVariadic() {
+		log.Printf("giv.FuncForm: variadic functions are not supported: %v\n", fval.Type().String())
+		return false
+	}
+	return true
+}
+
+// SetArgs sets the Args for the function, and the ArgStruct if it takes one
+func (ff *FuncForm) SetArgs() {
+	ftyp := ff.Func.Type()
+	narg := ftyp.NumIn()
+	ff.Args = make([]ArgData, narg)
+	ff.ArgStruct = nil
+	ff.argStruct = -1
+	nshow := 0
+	for ai := 0; ai < narg; ai++ {
+		ad := &ff.Args[ai]
+		atyp := ftyp.In(ai)
+		ad.Val = reflect.New(atyp)
+		ad.Name = fmt.Sprintf("Arg %v", ai+1)
+		if atyp == funcFormCtxType || atyp == funcFormProgressType {
+			bitflag.Set32((*int32)(&ad.Flags), int(ArgDataValSet))
+			continue
+		}
+		if atyp.Kind() == reflect.Ptr && atyp.Elem().Kind() == reflect.Struct && !ki.IsKi(atyp.Elem()) {
+			ad.Val.Elem().Set(reflect.New(atyp.Elem())) // so it has a view
+		}
+		ad.View = ToValueView(ad.Val.Interface())
+		if ad.View == nil {
+			log.Printf("giv.FuncForm SetFunc: no view for arg: %v of type: %v -- it is passed as its zero value\n", ai, atyp.String())
+			bitflag.Set32((*int32)(&ad.Flags), int(ArgDataValSet))
+			continue
+		}
+		nshow++
+		ad.View.SetStandaloneValue(ad.Val)
+		if len(ff.Opts.Args) > 0 {
+			continue
+		}
+		switch ad.View.(type) {
+		case *StructValueView, *StructInlineValueView:
+			ff.argStruct = ai
+		}
+	}
+	if nshow != 1 || ff.argStruct < 0 {
+		ff.argStruct = -1
+	} else {
+		ad := &ff.Args[ff.argStruct]
+		bitflag.Set32((*int32)(&ad.Flags), int(ArgDataValSet)) // edited in the StructView
+		sval := reflect.New(kit.NonPtrType(ad.Val.Type()))
+		StructTagDefaults(sval.Interface())
+		if df, ok := sval.Interface().(interface {
+			Defaults()
+		}); ok {
+			df.Defaults()
+		}
+		ff.ArgStruct = sval.Interface()
+	}
+	for ai, aps := range ff.Opts.Args {
+		if ai >= narg {
+			log.Printf("giv.FuncForm SetFunc: more Args props: %v than args of function: %v\n", len(ff.Opts.Args), ftyp.String())
+			break
+		}
+		ad := &ff.Args[ai]
+		ad.Name = aps.Name
+		if ad.View == nil {
+			continue
+		}
+		switch apv := aps.Value.(type) {
+		case ki.BlankProp:
+		case ki.Props:
+			for pk, pv := range apv {
+				switch pk {
+				case "desc":
+					ad.Desc = kit.ToString(pv)
+					ad.View.SetTag("desc", ad.Desc)
+				case "default":
+					ad.Default = pv
+					ad.SetHasDef()
+				case "value":
+					ad.Default = pv
+					ad.SetHasDef()
+					bitflag.Set32((*int32)(&ad.Flags), int(ArgDataValSet))
+				default:
+					if str, ok := pv.(string); ok {
+						ad.View.SetTag(pk, str)
+					}
+				}
+			}
+		}
+		if ad.HasDef() {
+			ad.View.SetValue(ad.Default)
+		}
+	}
+}
+
+// ResultName returns the name of the result at given index (not counting a
+// last error result), from the Results of the Opts
+func (ff *FuncForm) ResultName(idx int) string {
+	if idx < len(ff.Opts.Results) {
+		return ff.Opts.Results[idx]
+	}
+	ftyp := ff.Func.Type()
+	nout := ftyp.NumOut()
+	if nout > 0 && ftyp.Out(nout-1) == funcFormErrType {
+		nout--
+	}
+	if nout == 1 {
+		return "Result"
+	}
+	return fmt.Sprintf("Result %v", idx+1)
+}
+
+// HasArgsView returns true if the form has args to show
+func (ff *FuncForm) HasArgsView() bool {
+	if ff.ArgStruct != nil {
+		return true
+	}
+	for i := range ff.Args {
+		if !ff.Args[i].HasValSet() {
+			return true
+		}
+	}
+	return false
+}
+
+// StdFrameConfig returns a TypeAndNameList for configuring a standard Frame
+// -- can modify as desired before calling ConfigChildren on Frame using this
+func (ff *FuncForm) StdFrameConfig() kit.TypeAndNameList {
+	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_Label, "title")
+	switch {
+	case ff.ArgStruct != nil:
+		config.Add(KiT_StructView, "args")
+	case ff.HasArgsView():
+		config.Add(KiT_ArgView, "args")
+	}
+	config.Add(gi.KiT_ToolBar, "toolbar")
+	config.Add(gi.KiT_Label, "status")
+	config.Add(gi.KiT_Frame, "results")
+	return config
+}
+
+// StdConfig configures a standard setup of the overall Frame -- returns mods,
+// updt from ConfigChildren and does NOT call UpdateEnd
+func (ff *FuncForm) StdConfig() (mods, updt bool) {
+	ff.Lay = gi.LayoutVert
+	ff.SetProp("spacing", gi.StdDialogVSpaceUnits)
+	config := ff.StdFrameConfig()
+	mods, updt = ff.ConfigChildren(config, false)
+	return
+}
+
+// TitleWidget returns the title label widget
+func (ff *FuncForm) TitleWidget() *gi.Label {
+	return ff.KnownChild(0).(*gi.Label)
+}
+
+// ArgsView returns the view of the args, for a function that does not take an
+// ArgStruct -- nil if none
+func (ff *FuncForm) ArgsView() *ArgView {
+	idx, ok := ff.Children().IndexByName("args", 1)
+	if !ok {
+		return nil
+	}
+	av, _ := ff.KnownChild(idx).(*ArgView)
+	return av
+}
+
+// ArgStructView returns the view of the ArgStruct -- nil if none
+func (ff *FuncForm) ArgStructView() *StructView {
+	idx, ok := ff.Children().IndexByName("args", 1)
+	if !ok {
+		return nil
+	}
+	sv, _ := ff.KnownChild(idx).(*StructView)
+	return sv
+}
+
+// ToolBar returns the toolbar, with the Run and Cancel actions
+func (ff *FuncForm) ToolBar() *gi.ToolBar {
+	idx, _ := ff.Children().IndexByName("toolbar", 2)
+	return ff.KnownChild(idx).(*gi.ToolBar)
+}
+
+// StatusLabel returns the label showing the status of the call: the spinner
+// and progress while running, and any error
+func (ff *FuncForm) StatusLabel() *gi.Label {
+	idx, _ := ff.Children().IndexByName("status", 3)
+	return ff.KnownChild(idx).(*gi.Label)
+}
+
+// ResultsGrid returns the grid showing the results of the last call
+func (ff *FuncForm) ResultsGrid() *gi.Frame {
+	idx, _ := ff.Children().IndexByName("results", 4)
+	return ff.KnownChild(idx).(*gi.Frame)
+}
+
+// UpdateFromFunc updates the full widget layout for the function
+func (ff *FuncForm) UpdateFromFunc() {
+	mods, updt := ff.StdConfig()
+	if !mods {
+		updt = ff.UpdateStart()
+	}
+	ff.TitleWidget().SetText(ff.Opts.Title)
+	if sv := ff.ArgStructView(); sv != nil {
+		sv.Viewport = ff.Viewport
+		sv.SetStruct(ff.ArgStruct, nil)
+		sv.ViewSig.ConnectOnly(ff.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+			ffv, _ := recv.Embed(KiT_FuncForm).(*FuncForm)
+			ffv.ArgsChanged()
+		})
+	}
+	if av := ff.ArgsView(); av != nil {
+		av.Viewport = ff.Viewport
+		av.SetArgs(ff.Args)
+		av.ViewSig.ConnectOnly(ff.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+			ffv, _ := recv.Embed(KiT_FuncForm).(*FuncForm)
+			ffv.ArgsChanged()
+		})
+	}
+	ff.ConfigToolBar()
+	ff.UpdateStatus()
+	ff.ConfigResultsGrid()
+	ff.SetFullReRender()
+	ff.UpdateEnd(updt)
+}
+
+// ConfigToolBar configures the Run and Cancel actions of the toolbar
+func (ff *FuncForm) ConfigToolBar() {
+	tb := ff.ToolBar()
+	tb.SetStretchMaxWidth()
+	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_Action, "run")
+	config.Add(gi.KiT_Action, "cancel")
+	tb.ConfigChildren(config, false)
+	run := tb.KnownChild(0).(*gi.Action)
+	if ff.Opts.RunText != "" {
+		run.SetText(ff.Opts.RunText)
+	} else {
+		run.SetText("Run")
+	}
+	run.SetIcon("update")
+	run.Tooltip = "call the function with the args above, showing its results below"
+	run.ActionSig.ConnectOnly(ff.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+		ffv, _ := recv.Embed(KiT_FuncForm).(*FuncForm)
+		ffv.Run()
+	})
+	run.UpdateFunc = func(act *gi.Action) {
+		act.SetActiveStateUpdt(!ff.IsRunning())
+	}
+	cncl := tb.KnownChild(1).(*gi.Action)
+	cncl.SetText("Cancel")
+	cncl.SetIcon("cancel")
+	cncl.Tooltip = "cancel the running call of the function"
+	cncl.ActionSig.ConnectOnly(ff.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+		ffv, _ := recv.Embed(KiT_FuncForm).(*FuncForm)
+		ffv.Cancel()
+	})
+	cncl.UpdateFunc = func(act *gi.Action) {
+		act.SetActiveStateUpdt(ff.IsRunning())
+	}
+	tb.UpdateActions()
+}
+
+// ConfigResultsGrid configures the grid showing the Results of the last call
+func (ff *FuncForm) ConfigResultsGrid() {
+	sg := ff.ResultsGrid()
+	sg.Lay = gi.LayoutGrid
+	sg.Stripes = gi.RowStripes
+	sg.SetStretchMaxHeight()
+	sg.SetStretchMaxWidth()
+	sg.SetProp("columns", 2)
+	ff.mu.Lock()
+	results := ff.Results
+	ff.mu.Unlock()
+	vvs := make([]ValueView, len(results))
+	config := kit.TypeAndNameList{}
+	for i, rv := range results {
+		val := reflect.New(rv.Type()) // copy, as values are not settable
+		val.Elem().Set(rv)
+		vv := ToValueView(val.Interface())
+		if vv == nil { // e.g., a nil pointer
+			vv = ToValueView(nil)
+		}
+		vv.SetStandaloneValue(val)
+		vvs[i] = vv
+		knm := strcase.ToKebab(ff.ResultName(i))
+		config.Add(gi.KiT_Label, "label-"+knm)
+		config.Add(vv.WidgetType(), "value-"+knm)
+	}
+	mods, updt := sg.ConfigChildren(config, false)
+	if mods {
+		ff.SetFullReRender()
+	} else {
+		updt = sg.UpdateStart()
+	}
+	for i, vv := range vvs {
+		lbl := sg.KnownChild(i * 2).(*gi.Label)
+		lbl.SetText(ff.ResultName(i))
+		widg := sg.KnownChild((i * 2) + 1).(gi.Node2D)
+		widg.SetProp("horizontal-align", gi.AlignLeft)
+		vv.ConfigWidget(widg)
+	}
+	sg.UpdateEnd(updt)
+}
+
+// StatusText returns the text of the status label: the spinner and progress
+// while running, otherwise any error, or the time taken by the last call
+func (ff *FuncForm) StatusText() string {
+	ff.mu.Lock()
+	defer ff.mu.Unlock()
+	switch {
+	case ff.Running:
+		txt := TreeViewSpinnerFrames[ff.spinFrame%len(TreeViewSpinnerFrames)] + " running"
+		if ff.Progress >= 0 {
+			txt += fmt.Sprintf(" %.0f%%", 100*ff.Progress)
+		}
+		return txt
+	case ff.Canceled:
+		return "canceled"
+	case ff.Err != nil:
+		return fmt.Sprintf(`<span style="color:%v">%v</span>`, ValidateErrColor, html.EscapeString(ff.Err.Error()))
+	case ff.Elapsed > 0:
+		return fmt.Sprintf("done in %v", ff.Elapsed.Round(time.Millisecond))
+	}
+	return ""
+}
+
+// IsRunning returns true if a call of the function is running
+func (ff *FuncForm) IsRunning() bool {
+	ff.mu.Lock()
+	defer ff.mu.Unlock()
+	return ff.Running
+}
+
+// UpdateStatus updates the status label, and the active state of the Run
+// and Cancel actions
+func (ff *FuncForm) UpdateStatus() {
+	lbl := ff.StatusLabel()
+	lbl.Redrawable = true
+	if txt := ff.StatusText(); lbl.Text != txt {
+		lbl.SetTextAction(txt)
+	}
+	ff.ToolBar().UpdateActions()
+}
+
+// ValidateArgs validates the args, updating the display of the validation
+// errors of an ArgStruct -- returns the first error, nil if all valid
+func (ff *FuncForm) ValidateArgs() error {
+	if sv := ff.ArgStructView(); sv != nil {
+		if sv.Validate() {
+			return nil
+		}
+		for i, err := range sv.FieldErrs {
+			if err != nil {
+				return fmt.Errorf("%v: %v", sv.FieldViews[i].AsValueViewBase().Field.Name, err)
+			}
+		}
+		return sv.StructErr
+	}
+	for i := range ff.Args {
+		ad := &ff.Args[i]
+		if ad.HasValSet() {
+			continue
+		}
+		if err := ValidateValue(ad.View); err != nil {
+			return fmt.Errorf("%v: %v", ad.Name, err)
+		}
+	}
+	return nil
+}
+
+// ArgsChanged is called when an arg is edited -- clears a validation error
+// that blocked Run, once the args are valid
+func (ff *FuncForm) ArgsChanged() {
+	if !ff.argsInvalid {
+		return
+	}
+	err := ff.ValidateArgs()
+	ff.mu.Lock()
+	ff.Err = err
+	ff.mu.Unlock()
+	if err == nil {
+		ff.argsInvalid = false
+	}
+	ff.UpdateStatus()
+}
+
+// callArgs returns the args for calling the function in given call -- copies
+// of the values edited in the form, so that they can be edited while it runs
+func (ff *FuncForm) callArgs(c *funcFormCall) []reflect.Value {
+	args := make([]reflect.Value, len(ff.Args))
+	for i := range ff.Args {
+		ad := &ff.Args[i]
+		atyp := ad.Val.Type().Elem()
+		switch {
+		case atyp == funcFormCtxType:
+			args[i] = reflect.ValueOf(c.ctx)
+		case atyp == funcFormProgressType:
+			args[i] = reflect.ValueOf(FuncFormProgress(func(frac float32) {
+				ff.setProgress(c, frac)
+			}))
+		case i == ff.argStruct:
+			sval := reflect.ValueOf(ff.ArgStruct)
+			cpy := reflect.New(sval.Elem().Type())
+			cpy.Elem().Set(sval.Elem())
+			if atyp.Kind() == reflect.Ptr {
+				args[i] = cpy
+			} else {
+				args[i] = cpy.Elem()
+			}
+		default:
+			args[i] = ValueUndoCopy(ad.Val.Elem())
+		}
+	}
+	return args
+}
+
+// setProgress sets the Progress reported by given call, if it is still the
+// running one
+func (ff *FuncForm) setProgress(c *funcFormCall, frac float32) {
+	ff.mu.Lock()
+	if ff.call == c {
+		ff.Progress = frac
+	}
+	ff.mu.Unlock()
+}
+
+// Run validates the args, and if they are valid, calls the function with
+// them in the background -- the status shows a spinner and the progress
+// until it completes, and then its results are shown -- does nothing if a
+// call is already running
+func (ff *FuncForm) Run() {
+	if ff.IsRunning() || !ff.Func.IsValid() {
+		return
+	}
+	if err := ff.ValidateArgs(); err != nil {
+		ff.mu.Lock()
+		ff.Err = err
+		ff.mu.Unlock()
+		ff.argsInvalid = true
+		ff.UpdateStatus()
+		return
+	}
+	c := &funcFormCall{start: time.Now()}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	args := ff.callArgs(c)
+	ff.mu.Lock()
+	ff.call = c
+	ff.Progress = -1
+	ff.Running = true
+	ff.Canceled = false
+	ff.Err = nil
+	ff.mu.Unlock()
+	ff.argsInvalid = false
+	ff.UpdateStatus()
+	ff.FuncFormSig.Emit(ff.This, int64(FuncFormStarted), nil)
+	go ff.spin(c)
+	go func() {
+		rv := ff.Func.Call(args)
+		ff.callDone(c, rv)
+	}()
+}
+
+// Cancel cancels the running call, if any -- its context is canceled, and
+// its results are discarded when it returns
+func (ff *FuncForm) Cancel() {
+	if !ff.cancelCall(nil) {
+		return
+	}
+	ff.UpdateStatus()
+	ff.FuncFormSig.Emit(ff.This, int64(FuncFormCanceled), nil)
+}
+
+// cancelCall cancels given call if it is the running one, or the running
+// call if c is nil, marking it as canceled -- returns false if not running
+func (ff *FuncForm) cancelCall(c *funcFormCall) bool {
+	ff.mu.Lock()
+	if ff.call == nil || (c != nil && ff.call != c) {
+		ff.mu.Unlock()
+		return false
+	}
+	c = ff.call
+	ff.call = nil
+	ff.Running = false
+	ff.Canceled = true
+	ff.mu.Unlock()
+	c.cancel()
+	return true
+}
+
+// callDone is called in the background when given call returns its results
+// -- if it is still the running call, they are shown within an update of
+// the window
+func (ff *FuncForm) callDone(c *funcFormCall, rv []reflect.Value) {
+	var err error
+	if n := len(rv); n > 0 && ff.Func.Type().Out(n-1) == funcFormErrType {
+		err, _ = rv[n-1].Interface().(error)
+		rv = rv[:n-1]
+	}
+	ff.mu.Lock()
+	cur := ff.call == c
+	if cur {
+		ff.call = nil
+		ff.Running = false
+		ff.Elapsed = time.Since(c.start)
+		ff.Err = err
+		ff.Results = rv
+	}
+	ff.mu.Unlock()
+	c.cancel() // releases the context, and stops the spinner
+	if !cur || ff.IsDestroyed() || ff.IsDeleted() {
+		return
+	}
+	win := ff.ParentWindow()
+	if win == nil {
+		return
+	}
+	wupdt := win.UpdateStart()
+	ff.UpdateStatus()
+	ff.ConfigResultsGrid()
+	win.UpdateEnd(wupdt)
+	ff.FuncFormSig.Emit(ff.This, int64(FuncFormDone), err)
+}
+
+// spin animates the spinner in the status while given call is running,
+// updating it within an update of the window -- the call is canceled if the
+// form is destroyed
+func (ff *FuncForm) spin(c *funcFormCall) {
+	tick := time.NewTicker(time.Duration(TreeViewSpinnerMSec) * time.Millisecond)
+	defer tick.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-tick.C:
+		}
+		if ff.IsDestroyed() || ff.IsDeleted() {
+			ff.cancelCall(c) // nothing to show, or signal
+			return
+		}
+		ff.mu.Lock()
+		ff.spinFrame++
+		ff.mu.Unlock()
+		win := ff.ParentWindow()
+		if win == nil || win.IsResizing() || win.IsClosed() || win.IsUpdating() {
+			continue
+		}
+		wupdt := win.UpdateStart()
+		ff.UpdateStatus()
+		win.UpdateEnd(wupdt)
+	}
+}
+
+// FuncFormWindow opens a new window with a FuncForm for calling given
+// function with given options -- it is not modal, so any number of them can
+// be open and running at once -- returns nil if fun cannot be called by a
+// FuncForm (see FuncFormCheck)
+func FuncFormWindow(fun interface{}, opts FuncFormOpts) (*FuncForm, *gi.Window) {
+	if !FuncFormCheck(fun) {
+		return nil, nil
+	}
+	width := 800
+	height := 600
+	wnm := "func-form"
+	wti := "Function"
+	if opts.Title != "" {
+		wnm += "-" + strcase.ToKebab(opts.Title)
+		wti = opts.Title
+	}
+
+	win := gi.NewWindow2D(wnm, wti, width, height, true)
+
+	vp := win.WinViewport2D()
+	updt := vp.UpdateStart()
+
+	mfr := win.SetMainFrame()
+	mfr.Lay = gi.LayoutVert
+
+	ff := mfr.AddNewChild(KiT_FuncForm, "func-form").(*FuncForm)
+	ff.Viewport = vp
+	ff.SetProp("min-width", units.NewValue(40, units.Em))
+	ff.SetFunc(fun, opts)
+
+	vp.UpdateEndNoSig(updt)
+	win.GoStartEventLoop() // in a separate goroutine
+	return ff, win
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/goki/ki"
+)
+
+type funcFormTestArgs struct {
+	Name  string `def:"test"`
+	Count int    `def:"3" min:"1"`
+	Scale float32
+}
+
+func (fa *funcFormTestArgs) Defaults() {
+	fa.Scale = 0.5
+}
+
+func TestFuncFormCheck(t *testing.T) {
+	tests := []struct {
+		fun interface{}
+		ok  bool
+	}{
+		{func() {}, true},
+		{func(a int, s string) (int, error) { return a, nil }, true},
+		{func(a int, b ...int) {}, false},
+		{(func())(nil), false},
+		{3, false},
+		{nil, false},
+	}
+	for _, test := range tests {
+		if ok := FuncFormCheck(test.fun); ok != test.ok {
+			t.Errorf("FuncFormCheck(%T): got %v, expected %v\n", test.fun, ok, test.ok)
+		}
+	}
+}
+
+func TestFuncFormArgs(t *testing.T) {
+	tests := []struct {
+		fun    interface{}
+		args   ki.PropSlice
+		names  []string
+		shown  []bool
+		vals   []interface{} // values of the shown args
+		argStr interface{}
+	}{
+		{func(a int, s string) {}, nil, []string{"Arg 1", "Arg 2"}, []bool{true, true}, []interface{}{0, ""}, nil},
+		{func(ctx context.Context, a int, p FuncFormProgress) {}, nil, []string{"Arg 1", "Arg 2", "Arg 3"}, []bool{false, true, false}, []interface{}{nil, 0, nil}, nil},
+		{func(n int, lbl string, x float32) {}, ki.PropSlice{
+			{"Count", ki.Props{"default": 10, "min": "1", "desc": "how many"}},
+			{"Label", ki.Props{"value": "fixed"}},
+		}, []string{"Count", "Label", "Arg 3"}, []bool{true, false, true}, []interface{}{10, "fixed", float32(0)}, nil},
+		{func(fa funcFormTestArgs) error { return nil }, nil, []string{"Arg 1"}, []bool{false}, []interface{}{nil}, &funcFormTestArgs{"test", 3, 0.5}},
+		{func(ctx context.Context, fa *funcFormTestArgs) {}, nil, []string{"Arg 1", "Arg 2"}, []bool{false, false}, []interface{}{nil, nil}, &funcFormTestArgs{"test", 3, 0.5}},
+		{func(fa funcFormTestArgs, n int) {}, nil, []string{"Arg 1", "Arg 2"}, []bool{true, true}, []interface{}{funcFormTestArgs{}, 0}, nil},
+		{func(fa funcFormTestArgs) {}, ki.PropSlice{{"Params", ki.BlankProp{}}}, []string{"Params"}, []bool{true}, []interface{}{funcFormTestArgs{}}, nil},
+	}
+	for i, test := range tests {
+		ff := &FuncForm{}
+		ff.InitName(ff, "funcform-test")
+		ff.Func = reflect.ValueOf(test.fun)
+		ff.Opts.Args = test.args
+		ff.SetArgs()
+		if len(ff.Args) != len(test.names) {
+			t.Errorf("FuncForm %v SetArgs: got %v args, expected %v\n", i, len(ff.Args), len(test.names))
+			continue
+		}
+		for ai := range ff.Args {
+			ad := &ff.Args[ai]
+			if ad.Name != test.names[ai] || ad.HasValSet() == test.shown[ai] {
+				t.Errorf("FuncForm %v arg %v: got %v shown: %v, expected %v shown: %v\n", i, ai, ad.Name, !ad.HasValSet(), test.names[ai], test.shown[ai])
+			}
+			if test.vals[ai] == nil {
+				continue
+			}
+			if val := ad.Val.Elem().Interface(); !reflect.DeepEqual(val, test.vals[ai]) {
+				t.Errorf("FuncForm %v arg %v value: got %v, expected %v\n", i, ai, val, test.vals[ai])
+			}
+		}
+		if !reflect.DeepEqual(ff.ArgStruct, test.argStr) {
+			t.Errorf("FuncForm %v ArgStruct: got %v, expected %v\n", i, ff.ArgStruct, test.argStr)
+		}
+		hasArgs := test.argStr != nil
+		for _, sh := range test.shown {
+			hasArgs = hasArgs || sh
+		}
+		if hav := ff.HasArgsView(); hav != hasArgs {
+			t.Errorf("FuncForm %v HasArgsView: got %v, expected %v\n", i, hav, hasArgs)
+		}
+	}
+}
+
+func TestFuncFormArgProps(t *testing.T) {
+	ff := &FuncForm{}
+	ff.InitName(ff, "funcform-test")
+	ff.Func = reflect.ValueOf(func(n int, lbl string) {})
+	ff.Opts.Args = ki.PropSlice{
+		{"Count", ki.Props{"default": 10, "min": "1", "desc": "how many"}},
+		{"Label", ki.Props{"value": "fixed"}},
+		{"Extra", ki.BlankProp{}}, // more props than args
+	}
+	ff.SetArgs()
+	ad := &ff.Args[0]
+	if ad.Desc != "how many" || !ad.HasDef() || ad.Default != 10 {
+		t.Errorf("FuncForm arg props: got desc %q, has def %v, default %v, expected %q, true, 10\n", ad.Desc, ad.HasDef(), ad.Default, "how many")
+	}
+	if mintag, _ := ad.View.Tag("min"); mintag != "1" {
+		t.Errorf("FuncForm arg min tag: got %q, expected %q\n", mintag, "1")
+	}
+	if err := ff.ValidateArgs(); err != nil {
+		t.Errorf("FuncForm ValidateArgs: got %v, expected nil\n", err)
+	}
+	ad.View.SetValue(0)
+	if err := ff.ValidateArgs(); err == nil {
+		t.Errorf("FuncForm ValidateArgs of Count 0: got nil, expected min error\n")
+	}
+}
+
+func TestFuncFormCallArgs(t *testing.T) {
+	ff := &FuncForm{}
+	ff.InitName(ff, "funcform-test")
+	ff.Func = reflect.ValueOf(func(ctx context.Context, sl []int, p FuncFormProgress) {})
+	ff.SetArgs()
+	sl := []int{1, 2}
+	ff.Args[1].Val.Elem().Set(reflect.ValueOf(sl))
+	c := &funcFormCall{ctx: context.Background()}
+	ff.call = c
+	args := ff.callArgs(c)
+	if len(args) != 3 || args[0].Interface() != c.ctx {
+		t.Fatalf("FuncForm callArgs: got %v, expected the context of the call first\n", args)
+	}
+	args[1].Index(0).SetInt(9) // copy, so edits while running do not change it
+	if sl[0] != 1 {
+		t.Errorf("FuncForm callArgs: slice arg is not a copy\n")
+	}
+	args[2].Interface().(FuncFormProgress)(0.25)
+	if ff.Progress != 0.25 {
+		t.Errorf("FuncForm callArgs progress: got %v, expected 0.25\n", ff.Progress)
+	}
+	ff.call = nil // no longer running: progress is ignored
+	args[2].Interface().(FuncFormProgress)(0.75)
+	if ff.Progress != 0.25 {
+		t.Errorf("FuncForm callArgs progress after done: got %v, expected 0.25\n", ff.Progress)
+	}
+
+	ff.Func = reflect.ValueOf(func(fa *funcFormTestArgs) {})
+	ff.SetArgs()
+	args = ff.callArgs(c)
+	fa := args[0].Interface().(*funcFormTestArgs)
+	if fa == ff.ArgStruct || !reflect.DeepEqual(fa, ff.ArgStruct) {
+		t.Errorf("FuncForm callArgs ArgStruct: got %v, expected a copy of %v\n", fa, ff.ArgStruct)
+	}
+	ff.Func = reflect.ValueOf(func(fa funcFormTestArgs) {})
+	ff.SetArgs()
+	args = ff.callArgs(c)
+	if fv, ok := args[0].Interface().(funcFormTestArgs); !ok || fv != *ff.ArgStruct.(*funcFormTestArgs) {
+		t.Errorf("FuncForm callArgs ArgStruct value: got %v, expected %v\n", args[0], ff.ArgStruct)
+	}
+}
@@ -210,6 +210,7 @@ func (ge *GiEditor) ConfigSplitView() {
 		tvfr := split.AddNewChild(gi.KiT_Frame, "tvfr").(*gi.Frame)
 		tv := tvfr.AddNewChild(KiT_TreeView, "tv").(*TreeView)
 		sv := split.AddNewChild(KiT_StructView, "sv").(*StructView)
+		sv.SetProp("group-structs", true)
 		tv.TreeViewSig.Connect(ge.This, func(recv, send ki.Ki, sig int64, data interface{}) {
 			if data == nil {
 				return
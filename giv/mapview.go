@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 
 	"github.com/goki/gi"
 	"github.com/goki/gi/units"
@@ -20,14 +21,19 @@ import (
 // overall frame.
 type MapView struct {
 	gi.Frame
-	Map        interface{} `desc:"the map that we are a view onto"`
-	Changed    bool        `desc:"has the map been edited?"`
-	Keys       []ValueView `json:"-" xml:"-" desc:"ValueView representations of the map keys"`
-	Values     []ValueView `json:"-" xml:"-" desc:"ValueView representations of the map values"`
-	SortVals   bool        `desc:"sort by values instead of keys"`
-	TmpSave    ValueView   `json:"-" xml:"-" desc:"value view that needs to have SaveTmp called on it whenever a change is made to one of the underlying values -- pass this down to any sub-views created from a parent"`
-	ViewSig    ki.Signal   `json:"-" xml:"-" desc:"signal for valueview -- only one signal sent when a value has been set -- all related value views interconnect with each other to update when others update"`
-	ToolbarMap interface{} `desc:"the map that we successfully set a toolbar for"`
+	Map        interface{}                    `desc:"the map that we are a view onto"`
+	Changed    bool                           `desc:"has the map been edited?"`
+	Keys       []ValueView                    `json:"-" xml:"-" desc:"ValueView representations of the map keys"`
+	Values     []ValueView                    `json:"-" xml:"-" desc:"ValueView representations of the map values"`
+	SortVals   bool                           `desc:"sort by values instead of keys"`
+	TmpSave    ValueView                      `json:"-" xml:"-" desc:"value view that needs to have SaveTmp called on it whenever a change is made to one of the underlying values -- pass this down to any sub-views created from a parent"`
+	ViewSig    ki.Signal                      `json:"-" xml:"-" desc:"signal for valueview -- only one signal sent when a value has been set -- all related value views interconnect with each other to update when others update"`
+	ToolbarMap interface{}                    `desc:"the map that we successfully set a toolbar for"`
+	ShowSearch bool                           `desc:"show the search bar, for filtering the elements -- set from the search property if set, otherwise true if the map has at least PropGridSearchFields elements"`
+	Search     string                         `desc:"only show the elements whose key or value text contains all of these white-space separated terms"`
+	GroupFunc  func(key reflect.Value) string `json:"-" xml:"-" view:"-" desc:"optional function returning the group of each key -- the elements are shown in collapsible groups, e.g., MapKeyPrefixGroup"`
+	GroupOpen  map[string]bool                `json:"-" xml:"-" desc:"open state of the collapsible groups, by name -- open by default"`
+	keyRows    []int                          // row in the grid of each of the Keys
 }
 
 var KiT_MapView = kit.Types.AddType(&MapView{}, MapViewProps)
@@ -53,7 +59,13 @@ var MapViewProps = ki.Props{
 
 // UpdateFromMap does full updating from map
 func (mv *MapView) UpdateFromMap() {
+	if srch, ok := mv.Prop("search"); ok {
+		mv.ShowSearch, _ = kit.ToBool(srch)
+	} else if !kit.IfaceIsNil(mv.Map) {
+		mv.ShowSearch = kit.NonPtrValue(reflect.ValueOf(mv.Map)).Len() >= PropGridSearchFields
+	}
 	mods, updt := mv.StdConfig()
+	mv.ConfigSearchBar()
 	mv.ConfigMapGrid()
 	mv.ConfigToolbar()
 	if mods {
@@ -72,6 +84,9 @@ func (mv *MapView) UpdateValues() {
 func (mv *MapView) StdFrameConfig() kit.TypeAndNameList {
 	config := kit.TypeAndNameList{}
 	config.Add(gi.KiT_ToolBar, "toolbar")
+	if mv.ShowSearch {
+		config.Add(gi.KiT_Layout, "search-bar")
+	}
 	config.Add(gi.KiT_Frame, "map-grid")
 	return config
 }
@@ -95,6 +110,65 @@ func (mv *MapView) MapGrid() (*gi.Frame, int) {
 	return mv.KnownChild(idx).(*gi.Frame), idx
 }
 
+// SearchBar returns the search bar -- nil if not ShowSearch
+func (mv *MapView) SearchBar() *gi.Layout {
+	idx, ok := mv.Children().IndexByName("search-bar", 1)
+	if !ok {
+		return nil
+	}
+	return mv.KnownChild(idx).(*gi.Layout)
+}
+
+// ConfigSearchBar configures the search bar, if ShowSearch
+func (mv *MapView) ConfigSearchBar() {
+	bar := mv.SearchBar()
+	if bar == nil {
+		return
+	}
+	ConfigPropSearchBar(bar, mv.Search, false, false, mv.This, func(recv ki.Ki, search string, modOnly bool) {
+		mvv := recv.Embed(KiT_MapView).(*MapView)
+		mvv.SetSearch(search)
+	})
+}
+
+// SetSearch sets the Search filter on the elements shown
+func (mv *MapView) SetSearch(search string) {
+	if mv.Search == search {
+		return
+	}
+	mv.Search = search
+	mv.ConfigMapGrid()
+}
+
+// GroupIsOpen returns whether the group of given name is open -- open unless
+// closed in GroupOpen
+func (mv *MapView) GroupIsOpen(grp string) bool {
+	if open, ok := mv.GroupOpen[grp]; ok {
+		return open
+	}
+	return true
+}
+
+// ToggleGroup opens or closes the group of given name
+func (mv *MapView) ToggleGroup(grp string) {
+	if mv.GroupOpen == nil {
+		mv.GroupOpen = make(map[string]bool)
+	}
+	mv.GroupOpen[grp] = !mv.GroupIsOpen(grp)
+	mv.ConfigMapGrid()
+}
+
+// MapKeyPrefixGroup is a GroupFunc for MapView that groups the keys by their
+// text up to the first - or . -- e.g., the border-* and font-* style
+// properties in a ki.Props map
+func MapKeyPrefixGroup(key reflect.Value) string {
+	ks := kit.ToString(key.Interface())
+	if i := strings.IndexAny(ks, "-."); i > 0 {
+		return ks[:i]
+	}
+	return ks
+}
+
 // ToolBar returns the toolbar widget
 func (mv *MapView) ToolBar() *gi.ToolBar {
 	idx, ok := mv.Children().IndexByName("toolbar", 0)
@@ -162,14 +236,49 @@ func (mv *MapView) ConfigMapGrid() {
 			return kit.ToString(keys[i]) < kit.ToString(keys[j])
 		})
 	}
+	var grps []string
+	if mv.GroupFunc != nil {
+		gidx := make(map[string]int)
+		for _, key := range keys {
+			grp := mv.GroupFunc(key)
+			if _, has := gidx[grp]; !has {
+				gidx[grp] = len(grps)
+				grps = append(grps, grp)
+			}
+		}
+		sort.SliceStable(keys, func(i, j int) bool {
+			return gidx[mv.GroupFunc(keys[i])] < gidx[mv.GroupFunc(keys[j])]
+		})
+		grps = grps[:0]
+	}
+	mv.keyRows = make([]int, 0)
+	nrow := 0
 	for _, key := range keys {
+		val := mpvnp.MapIndex(key)
+		if !PropSearchMatch(mv.Search, kit.ToString(key.Interface()), kit.ToString(val.Interface())) {
+			continue
+		}
+		if mv.GroupFunc != nil {
+			grp := mv.GroupFunc(key)
+			if len(grps) == 0 || grps[len(grps)-1] != grp {
+				grps = append(grps, grp)
+				grpnm := fmt.Sprintf("group-%v", grp)
+				config.Add(gi.KiT_Action, grpnm)
+				for c := 1; c < ncol; c++ {
+					config.Add(gi.KiT_Label, fmt.Sprintf("%v-%v", grpnm, c))
+				}
+				nrow++
+			}
+			if mv.Search == "" && !mv.GroupIsOpen(grp) {
+				continue
+			}
+		}
 		kv := ToValueView(key.Interface())
 		if kv == nil { // shouldn't happen
 			continue
 		}
 		kv.SetMapKey(key, mv.Map, mv.TmpSave)
 
-		val := mpvnp.MapIndex(key)
 		vv := ToValueView(val.Interface())
 		if vv == nil { // shouldn't happen
 			continue
@@ -190,6 +299,8 @@ func (mv *MapView) ConfigMapGrid() {
 		config.Add(gi.KiT_Action, delnm)
 		mv.Keys = append(mv.Keys, kv)
 		mv.Values = append(mv.Values, vv)
+		mv.keyRows = append(mv.keyRows, nrow)
+		nrow++
 	}
 	mods, updt := sg.ConfigChildren(config, false)
 	if mods {
@@ -197,14 +308,25 @@ func (mv *MapView) ConfigMapGrid() {
 	} else {
 		updt = sg.UpdateStart() // cover rest of updates, which can happen even if same config
 	}
+	for _, kid := range sg.Kids {
+		if act, ok := kid.(*gi.Action); ok && strings.HasPrefix(act.Nm, "group-") {
+			grp := strings.TrimPrefix(act.Nm, "group-")
+			open := mv.Search != "" || mv.GroupIsOpen(grp)
+			ConfigPropGroupHeader(act, grp, "", 0, open, grp, mv.This, func(recv ki.Ki, grp string) {
+				mvv := recv.Embed(KiT_MapView).(*MapView)
+				mvv.ToggleGroup(grp)
+			})
+		}
+	}
 	for i, vv := range mv.Values {
+		ridx := mv.keyRows[i] * ncol
 		vvb := vv.AsValueViewBase()
 		vvb.ViewSig.ConnectOnly(mv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
 			mvv, _ := recv.Embed(KiT_MapView).(*MapView)
 			mvv.SetChanged()
 		})
-		keyw := sg.KnownChild(i * ncol).(gi.Node2D)
-		widg := sg.KnownChild(i*ncol + 1).(gi.Node2D)
+		keyw := sg.KnownChild(ridx).(gi.Node2D)
+		widg := sg.KnownChild(ridx + 1).(gi.Node2D)
 		kv := mv.Keys[i]
 		kvb := kv.AsValueViewBase()
 		kvb.ViewSig.ConnectOnly(mv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
@@ -214,7 +336,7 @@ func (mv *MapView) ConfigMapGrid() {
 		kv.ConfigWidget(keyw)
 		vv.ConfigWidget(widg)
 		if ifaceType {
-			typw := sg.KnownChild(i*ncol + 2).(*gi.ComboBox)
+			typw := sg.KnownChild(ridx + 2).(*gi.ComboBox)
 			typw.ItemsFromTypes(valtypes, false, true, 50)
 			vtyp := kit.NonPtrType(reflect.TypeOf(vv.Val().Interface()))
 			if vtyp == nil {
@@ -230,7 +352,7 @@ func (mv *MapView) ConfigMapGrid() {
 				mvv.MapChangeValueType(idx, typ)
 			})
 		}
-		delact := sg.KnownChild(i*ncol + ncol - 1).(*gi.Action)
+		delact := sg.KnownChild(ridx + ncol - 1).(*gi.Action)
 		delact.SetIcon("minus")
 		delact.Tooltip = "delete item"
 		delact.Data = kv
@@ -298,7 +420,9 @@ func (mv *MapView) MapAdd() {
 	updt := mv.UpdateStart()
 	defer mv.UpdateEnd(updt)
 
+	before := ValueUndoCopy(kit.NonPtrValue(reflect.ValueOf(mv.Map)))
 	kit.MapAdd(mv.Map)
+	mv.SaveUndo("Add", before)
 
 	if mv.TmpSave != nil {
 		mv.TmpSave.SaveTmp()
@@ -315,7 +439,9 @@ func (mv *MapView) MapDelete(key reflect.Value) {
 	updt := mv.UpdateStart()
 	defer mv.UpdateEnd(updt)
 
+	before := ValueUndoCopy(kit.NonPtrValue(reflect.ValueOf(mv.Map)))
 	kit.MapDeleteValue(mv.Map, key)
+	mv.SaveUndo("Delete", before)
 
 	if mv.TmpSave != nil {
 		mv.TmpSave.SaveTmp()
@@ -324,6 +450,18 @@ func (mv *MapView) MapDelete(key reflect.Value) {
 	mv.SetChanged()
 }
 
+// SaveUndo saves the change to the map from before, a ValueUndoCopy of it
+// from before the change, in the ValueUndo of the window
+func (mv *MapView) SaveUndo(desc string, before reflect.Value) {
+	SaveValueUndo(mv.This.(gi.Node2D), desc, kit.NonPtrValue(reflect.ValueOf(mv.Map)), before, func() {
+		if mv.TmpSave != nil {
+			mv.TmpSave.SaveTmp()
+		}
+		mv.ConfigMapGrid()
+		mv.SetChanged()
+	})
+}
+
 // ConfigToolbar configures the toolbar actions
 func (mv *MapView) ConfigToolbar() {
 	if kit.IfaceIsNil(mv.Map) || mv.IsInactive() {
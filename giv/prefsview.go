@@ -32,6 +32,7 @@ func PrefsView(pf *gi.Preferences) (*StructView, *gi.Window) {
 
 	sv := mfr.AddNewChild(KiT_StructView, "sv").(*StructView)
 	sv.Viewport = vp
+	sv.SetProp("group-structs", true)
 	sv.SetStruct(pf, nil)
 	sv.SetStretchMaxWidth()
 	sv.SetStretchMaxHeight()
@@ -0,0 +1,116 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"strings"
+
+	"github.com/goki/gi"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki"
+	"github.com/goki/ki/kit"
+)
+
+// The property-grid presentation shared by StructView and MapView: a search
+// bar that filters the rows, and collapsible groups of rows within the grid,
+// each with a header Action row that opens / closes it.
+
+// PropGridSearchFields is the number of fields (or map elements) at which a
+// StructView or MapView shows its search bar, unless set by its "search"
+// property
+var PropGridSearchFields = 12
+
+// PropSearchMatch returns true if all the white-space separated terms of
+// search are found in any of texts, ignoring case -- true for an empty search
+func PropSearchMatch(search string, texts ...string) bool {
+	for _, term := range strings.Fields(strings.ToLower(search)) {
+		found := false
+		for _, txt := range texts {
+			if strings.Contains(strings.ToLower(txt), term) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// PropIndent returns the indentation of the labels of rows at given depth of
+// nesting in groups -- non-breaking spaces, which are not collapsed
+func PropIndent(depth int) string {
+	return strings.Repeat("\u00a0", 4*depth)
+}
+
+// ConfigPropSearchBar configures bar as the search bar of a StructView or
+// MapView: a "search" TextField, and if showMod, a "modified" CheckBox for
+// showing only the values modified from their defaults -- fun is called on
+// recv when either is changed
+func ConfigPropSearchBar(bar *gi.Layout, search string, showMod, modOnly bool, recv ki.Ki, fun func(recv ki.Ki, search string, modOnly bool)) {
+	bar.Lay = gi.LayoutHoriz
+	bar.SetStretchMaxWidth()
+	bar.SetProp("spacing", units.NewValue(1, units.Ex))
+	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_TextField, "search")
+	if showMod {
+		config.Add(gi.KiT_CheckBox, "modified")
+	}
+	mods, updt := bar.ConfigChildren(config, false)
+	if !mods {
+		updt = bar.UpdateStart()
+	}
+	sf := bar.KnownChild(0).(*gi.TextField)
+	sf.SetStretchMaxWidth()
+	sf.Placeholder = "search"
+	sf.Tooltip = "show only the rows whose name or description contain all of these white-space separated terms"
+	if sf.Txt != search {
+		sf.SetText(search)
+	}
+	sf.TextFieldSig.ConnectOnly(recv, func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.TextFieldDone) {
+			sff := send.(*gi.TextField)
+			mod := false
+			if cb, ok := sff.Par.ChildByName("modified", 1); ok {
+				mod = cb.(*gi.CheckBox).IsChecked()
+			}
+			fun(recv, sff.Txt, mod)
+		}
+	})
+	if showMod {
+		cb := bar.KnownChild(1).(*gi.CheckBox)
+		cb.SetText("Modified")
+		cb.Tooltip = "show only the values that are modified from their defaults"
+		cb.SetChecked(modOnly)
+		cb.ButtonSig.ConnectOnly(recv, func(recv, send ki.Ki, sig int64, data interface{}) {
+			if sig == int64(gi.ButtonToggled) {
+				cbb := send.(*gi.CheckBox)
+				sff := cbb.Par.KnownChild(0).(*gi.TextField)
+				fun(recv, sff.Txt, cbb.IsChecked())
+			}
+		})
+	}
+	bar.UpdateEnd(updt)
+}
+
+// ConfigPropGroupHeader configures act as the header of a collapsible group
+// of rows in a StructView or MapView grid, indented for its depth of nesting
+// -- fun is called on recv with the path of the group when it is clicked
+func ConfigPropGroupHeader(act *gi.Action, label, tooltip string, depth int, open bool, path string, recv ki.Ki, fun func(recv ki.Ki, path string)) {
+	act.SetText(PropIndent(depth) + label)
+	if open {
+		act.SetIcon("widget-wedge-down")
+	} else {
+		act.SetIcon("widget-wedge-right")
+	}
+	act.Tooltip = tooltip
+	act.Data = path
+	act.SetProp("font-weight", gi.WeightBold)
+	act.SetProp("horizontal-align", gi.AlignLeft)
+	act.ActionSig.ConnectOnly(recv, func(recv, send ki.Ki, sig int64, data interface{}) {
+		fun(recv, send.(*gi.Action).Data.(string))
+	})
+}
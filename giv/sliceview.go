@@ -27,6 +27,19 @@ import (
 ////////////////////////////////////////////////////////////////////////////////////////
 //  SliceView
 
+// SliceViewVirtualSize is the length of the slice above which the view is
+// shown in Virtual mode, with widgets only for the visible rows, unless the
+// "virtual" property is set
+var SliceViewVirtualSize = 1000
+
+// SliceViewVirtualRows is the initial number of rows of widgets in Virtual
+// mode, before the number of visible rows is known from the layout
+var SliceViewVirtualRows = 40
+
+// SliceViewVirtualMargin is the number of rows of widgets beyond the visible
+// rows in Virtual mode, so partially-visible rows at the end are shown
+var SliceViewVirtualMargin = 2
+
 // SliceView represents a slice, creating a property editor of the values --
 // constructs Children widgets to show the index / value pairs, within an
 // overall frame. Set to Inactive for select-only mode, which emits WidgetSig
@@ -38,8 +51,13 @@ type SliceView struct {
 	StyleFunc        SliceViewStyleFunc `view:"-" json:"-" xml:"-" desc:"optional styling function"`
 	ShowViewCtxtMenu bool               `desc:"if the type we're viewing has its own CtxtMenu property defined, should we also still show the view's standard context menu?"`
 	Changed          bool               `desc:"has the slice been edited?"`
-	Values           []ValueView        `json:"-" xml:"-" desc:"ValueView representations of the slice values"`
+	Values           []ValueView        `json:"-" xml:"-" desc:"ValueView representations of the slice values -- in Virtual mode, for the rows of widgets, starting at StartIdx"`
 	ShowIndex        bool               `xml:"index" desc:"whether to show index or not -- updated from "index" property (bool)"`
+	Virtual          bool               `xml:"virtual" desc:"only create widgets for the visible rows, which are re-used for other rows as the view is scrolled, for large slices -- updated from "virtual" property (bool) if set, otherwise true if the slice is longer than SliceViewVirtualSize"`
+	StartIdx         int                `json:"-" xml:"-" desc:"in Virtual mode, the index of the first row shown"`
+	VisRows          int                `json:"-" xml:"-" desc:"number of rows of widgets -- all the rows unless in Virtual mode"`
+	NVisible         int                `json:"-" xml:"-" desc:"in Virtual mode, the number of rows that fit within the grid, from the layout"`
+	RowHeight        float32            `json:"-" xml:"-" desc:"in Virtual mode, the height of a row, in dots, from the layout"`
 	InactKeyNav      bool               `xml:"inact-key-nav" desc:"support key navigation when inactive (default true) -- updated from "intact-key-nav" property (bool) -- no focus really plausible in inactive case, so it uses a low-pri capture of up / down events"`
 	SelVal           interface{}        `view:"-" json:"-" xml:"-" desc:"current selection value -- initially select this value if set"`
 	SelectedIdx      int                `json:"-" xml:"-" desc:"index of currently-selected item, in Inactive mode only"`
@@ -77,6 +95,7 @@ func (sv *SliceView) SetSlice(sl interface{}, tmpSave ValueView) {
 		}
 		sv.SelectedRows = make(map[int]struct{}, 10)
 		sv.SelectMode = false
+		sv.StartIdx = 0
 		sv.SetFullReRender()
 	}
 	sv.ShowIndex = true
@@ -130,7 +149,9 @@ func (sv *SliceView) UpdateFromSlice() {
 func (sv *SliceView) UpdateValues() {
 	updt := sv.UpdateStart()
 	for _, vv := range sv.Values {
-		vv.UpdateWidget()
+		if vv != nil { // rows past the end in Virtual mode
+			vv.UpdateWidget()
+		}
 	}
 	sv.UpdateEnd(updt)
 }
@@ -140,7 +161,18 @@ func (sv *SliceView) UpdateValues() {
 func (sv *SliceView) StdFrameConfig() kit.TypeAndNameList {
 	config := kit.TypeAndNameList{}
 	config.Add(gi.KiT_ToolBar, "toolbar")
+	config.Add(gi.KiT_Layout, "grid-lay")
+	return config
+}
+
+// StdGridLayConfig returns a TypeAndNameList for configuring the grid-lay,
+// with the scrollbar for the rows in Virtual mode
+func (sv *SliceView) StdGridLayConfig() kit.TypeAndNameList {
+	config := kit.TypeAndNameList{}
 	config.Add(gi.KiT_Frame, "slice-grid")
+	if sv.Virtual {
+		config.Add(gi.KiT_ScrollBar, "scrollbar")
+	}
 	return config
 }
 
@@ -154,14 +186,39 @@ func (sv *SliceView) StdConfig() (mods, updt bool) {
 	return
 }
 
+// SliceGridLay returns the layout containing the SliceGrid and, in Virtual
+// mode, its ScrollBar
+func (sv *SliceView) SliceGridLay() *gi.Layout {
+	idx, ok := sv.Children().IndexByName("grid-lay", 0)
+	if !ok {
+		return nil
+	}
+	return sv.KnownChild(idx).(*gi.Layout)
+}
+
 // SliceGrid returns the SliceGrid grid frame widget, which contains all the
-// fields and values, and its index, within frame -- nil, -1 if not found
+// fields and values, and its index, within SliceGridLay -- nil, -1 if not
+// found
 func (sv *SliceView) SliceGrid() (*gi.Frame, int) {
-	idx, ok := sv.Children().IndexByName("slice-grid", 0)
+	sgl := sv.SliceGridLay()
+	if sgl == nil {
+		return nil, -1
+	}
+	idx, ok := sgl.Children().IndexByName("slice-grid", 0)
 	if !ok {
 		return nil, -1
 	}
-	return sv.KnownChild(idx).(*gi.Frame), idx
+	return sgl.KnownChild(idx).(*gi.Frame), idx
+}
+
+// ScrollBar returns the scrollbar for the rows in Virtual mode -- nil if not
+// Virtual
+func (sv *SliceView) ScrollBar() *gi.ScrollBar {
+	sgl := sv.SliceGridLay()
+	if sgl == nil || len(sgl.Kids) < 2 {
+		return nil
+	}
+	return sgl.KnownChild(1).(*gi.ScrollBar)
 }
 
 // ToolBar returns the toolbar widget
@@ -202,6 +259,31 @@ func (sv *SliceView) ConfigSliceGrid(forceUpdt bool) {
 	sv.BuiltSlice = sv.Slice
 	sv.BuiltSize = sz
 
+	sv.Virtual = sz > SliceViewVirtualSize
+	if vp, ok := sv.Prop("virtual"); ok {
+		sv.Virtual, _ = kit.ToBool(vp)
+	}
+	if sv.Virtual {
+		if sv.NVisible == 0 {
+			sv.NVisible = SliceViewVirtualRows - SliceViewVirtualMargin
+		}
+		sv.VisRows = ints.MinInt(sv.NVisible+SliceViewVirtualMargin, sz)
+		sv.StartIdx = sv.ClampStartIdx(sv.StartIdx)
+	} else {
+		sv.VisRows = sz
+		sv.StartIdx = 0
+	}
+
+	sgl := sv.SliceGridLay()
+	if sgl == nil {
+		return
+	}
+	sgl.Lay = gi.LayoutHoriz
+	sgl.SetProp("spacing", 0)
+	sgl.SetStretchMaxHeight() // for this to work, ALL layers above need it too
+	sgl.SetStretchMaxWidth()  // for this to work, ALL layers above need it too
+	sgl.ConfigChildren(sv.StdGridLayConfig(), false)
+
 	sg, _ := sv.SliceGrid()
 	if sg == nil {
 		return
@@ -219,18 +301,34 @@ func (sv *SliceView) ConfigSliceGrid(forceUpdt bool) {
 	sg.SetMinPrefWidth(units.NewValue(10, units.Em))
 	sg.SetStretchMaxHeight() // for this to work, ALL layers above need it too
 	sg.SetStretchMaxWidth()  // for this to work, ALL layers above need it too
+	if sv.Virtual {
+		sg.SetProp("overflow", "hidden") // rows are scrolled by our own scrollbar
+		sv.ConfigScrollBar()
+	} else {
+		sg.DeleteProp("overflow")
+	}
 
-	sv.Values = make([]ValueView, sz)
+	sv.Values = make([]ValueView, sv.VisRows)
 
 	sg.DeleteChildren(true)
-	sg.Kids = make(ki.Slice, nWidgPerRow*sz)
+	sg.Kids = make(ki.Slice, nWidgPerRow*sv.VisRows)
 
+	if sv.Virtual { // start with the selected row in view
+		if sv.SelVal != nil {
+			sv.SelectedIdx, _ = SliceRowByValue(sv.Slice, sv.SelVal)
+		}
+		if sv.SelectedIdx >= 0 {
+			sv.StartIdx = sv.StartIdxForRow(sv.SelectedIdx)
+		}
+		sv.UpdateScrollBar()
+	}
 	sv.ConfigSliceGridRows()
 }
 
 // ConfigSliceGridRows configures the SliceGrid rows for the current slice --
 // assumes .Kids is created at the right size -- only call this for a direct
-// re-render e.g., after sorting
+// re-render e.g., after sorting -- in Virtual mode, the rows of widgets are
+// (re)bound to the rows of the slice starting at StartIdx
 func (sv *SliceView) ConfigSliceGridRows() {
 	mv := reflect.ValueOf(sv.Slice)
 	mvnp := kit.NonPtrValue(mv)
@@ -241,16 +339,25 @@ func (sv *SliceView) ConfigSliceGridRows() {
 	updt := sg.UpdateStart()
 	defer sg.UpdateEnd(updt)
 
-	for i := 0; i < sz; i++ {
-		ridx := i * nWidgPerRow
+	for wi := 0; wi < sv.VisRows; wi++ {
+		i := sv.StartIdx + wi
+		if i >= sz {
+			sv.Values[wi] = nil // blank rows, see ConfigBlankRows
+			continue
+		}
+		ridx := wi * nWidgPerRow
 		val := kit.OnePtrValue(mvnp.Index(i)) // deal with pointer lists
 		vv := ToValueView(val.Interface())
 		if vv == nil { // shouldn't happen
 			continue
 		}
 		vv.SetSliceValue(val, sv.Slice, i, sv.TmpSave)
-		sv.Values[i] = vv
+		sv.Values[wi] = vv
 		vtyp := vv.WidgetType()
+		sel := i == sv.SelectedIdx // inactive mode only has one selected
+		if !sv.IsInactive() {
+			sel = sv.RowIsSelected(i)
+		}
 		idxtxt := fmt.Sprintf("%05d", i)
 		labnm := fmt.Sprintf("index-%v", idxtxt)
 		valnm := fmt.Sprintf("value-%v", idxtxt)
@@ -259,13 +366,15 @@ func (sv *SliceView) ConfigSliceGridRows() {
 			var idxlab *gi.Label
 			if sg.Kids[ridx] != nil {
 				idxlab = sg.Kids[ridx].(*gi.Label)
+				idxlab.SetText(idxtxt)
 			} else {
 				idxlab = &gi.Label{}
 				sg.SetChild(idxlab, ridx, labnm)
+				idxlab.Text = idxtxt
 			}
-			idxlab.Text = idxtxt
 			idxlab.SetProp("slv-index", i)
 			idxlab.Selectable = true
+			idxlab.SetSelectedState(sel)
 			idxlab.WidgetSig.ConnectOnly(sv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
 				if sig == int64(gi.WidgetSelected) {
 					wbb := send.(gi.Node2D).AsWidget()
@@ -277,11 +386,12 @@ func (sv *SliceView) ConfigSliceGridRows() {
 		}
 
 		var widg gi.Node2D
-		if sg.Kids[ridx+idxOff] != nil {
+		if sg.Kids[ridx+idxOff] != nil && sg.Kids[ridx+idxOff].Type() == vtyp {
 			widg = sg.Kids[ridx+idxOff].(gi.Node2D)
-		} else {
+		} else { // new, or a re-used row needs a different type of widget
 			widg = ki.NewOfType(vtyp).(gi.Node2D)
 			sg.SetChild(widg, ridx+idxOff, valnm)
+			sg.SetFullReRender()
 		}
 		vv.ConfigWidget(widg)
 
@@ -332,6 +442,7 @@ func (sv *SliceView) ConfigSliceGridRows() {
 				})
 			}
 		}
+		widg.AsNode2D().SetSelectedState(sel)
 		if sv.StyleFunc != nil {
 			sv.StyleFunc(sv, mvnp.Interface(), widg, i, vv)
 		}
@@ -343,6 +454,168 @@ func (sv *SliceView) ConfigSliceGridRows() {
 	if sv.IsInactive() && sv.SelectedIdx >= 0 {
 		sv.SelectRowWidgets(sv.SelectedIdx, true)
 	}
+	if sv.Virtual {
+		sv.ConfigBlankRows()
+	}
+}
+
+//////////////////////////////////////////////////////////////////////////////
+//  Virtual mode
+
+// ConfigBlankRows makes the rows of widgets past the end of the slice
+// invisible in Virtual mode, filling any that have no widgets with blank
+// labels
+func (sv *SliceView) ConfigBlankRows() {
+	sg, _ := sv.SliceGrid()
+	nWidgPerRow, _ := sv.RowWidgetNs()
+	nw := nWidgPerRow * ints.MaxInt(sv.BuiltSize-sv.StartIdx, 0)
+	for ci, kid := range sg.Kids {
+		if kid == nil {
+			kid = &gi.Label{}
+			sg.SetChild(kid, ci, fmt.Sprintf("blank-%v", ci))
+		}
+		kid.(gi.Node2D).AsNode2D().SetInvisibleState(ci >= nw)
+	}
+}
+
+// ClampStartIdx returns given StartIdx limited to the range where the
+// visible rows are within the slice
+func (sv *SliceView) ClampStartIdx(idx int) int {
+	return ints.MaxInt(ints.MinInt(idx, sv.BuiltSize-sv.NVisible), 0)
+}
+
+// StartIdxForRow returns the StartIdx needed for given row to be visible in
+// Virtual mode, changing the current StartIdx as little as possible
+func (sv *SliceView) StartIdxForRow(row int) int {
+	st := sv.StartIdx
+	if row < st {
+		st = row
+	} else if row >= st+sv.NVisible {
+		st = row - sv.NVisible + 1
+	}
+	return sv.ClampStartIdx(st)
+}
+
+// SetStartIdx sets the first row shown in Virtual mode, re-using the rows of
+// widgets for the rows starting there -- returns true if changed
+func (sv *SliceView) SetStartIdx(idx int) bool {
+	idx = sv.ClampStartIdx(idx)
+	if !sv.Virtual || idx == sv.StartIdx {
+		return false
+	}
+	sv.StartIdx = idx
+	sv.UpdateScrollBar()
+	sv.ConfigSliceGridRows()
+	sv.ConfigBlankRows()
+	return true
+}
+
+// ConfigScrollBar configures the ScrollBar for the rows in Virtual mode,
+// whose values are row indexes
+func (sv *SliceView) ConfigScrollBar() {
+	sb := sv.ScrollBar()
+	if sb == nil {
+		return
+	}
+	sb.Defaults()
+	sb.Dim = gi.Y
+	sb.Tracking = true
+	sb.TrackThr = 1
+	sb.Step = 1
+	sb.SetFixedWidth(sv.Sty.Layout.ScrollBarWidth)
+	sb.SetStretchMaxHeight()
+	sv.UpdateScrollBar()
+	sb.SliderSig.ConnectOnly(sv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig != int64(gi.SliderValueChanged) {
+			return
+		}
+		svv := recv.Embed(KiT_SliceView).(*SliceView)
+		svv.SetStartIdx(int(data.(float32) + 0.5))
+	})
+}
+
+// UpdateScrollBar updates the range, thumb and value of the ScrollBar from
+// the number of rows, NVisible and StartIdx
+func (sv *SliceView) UpdateScrollBar() {
+	sb := sv.ScrollBar()
+	if sb == nil {
+		return
+	}
+	updt := sb.UpdateStart()
+	sb.Min = 0
+	sb.Max = float32(sv.BuiltSize)
+	sb.PageStep = float32(ints.MaxInt(sv.NVisible-1, 1))
+	sb.SetThumbValue(float32(sv.NVisible))
+	sb.SetValue(float32(sv.StartIdx))
+	sb.UpdateEnd(updt)
+}
+
+// LayoutVirtual updates NVisible from the row height and the size of the
+// grid in Virtual mode, and reconfigures the rows of widgets if a different
+// number is needed, or re-binds them if StartIdx changes -- returns true if
+// the layout needs to be redone
+func (sv *SliceView) LayoutVirtual() bool {
+	sg, _ := sv.SliceGrid()
+	if sg == nil || len(sg.GridData[gi.Row]) == 0 {
+		return false
+	}
+	rht := sg.GridData[gi.Row][0].AllocSize
+	if rht <= 0 {
+		return false
+	}
+	sv.RowHeight = rht
+	avail := sg.LayData.AllocSize.Y - 2*sg.Sty.BoxSpace()
+	nvis := ints.MaxInt(int(avail/rht), 1)
+	if nvis == sv.NVisible {
+		return false
+	}
+	sv.NVisible = nvis
+	stidx := sv.StartIdx
+	sv.StartIdx = sv.ClampStartIdx(sv.StartIdx)
+	sv.UpdateScrollBar()
+	visRows := ints.MinInt(nvis+SliceViewVirtualMargin, sv.BuiltSize)
+	if visRows == sv.VisRows {
+		if sv.StartIdx != stidx {
+			sv.ConfigSliceGridRows()
+			sv.ConfigBlankRows()
+		}
+		return false
+	}
+	sv.VisRows = visRows
+	nWidgPerRow, _ := sv.RowWidgetNs()
+	nw := nWidgPerRow * visRows
+	if nw < len(sg.Kids) {
+		for _, kid := range sg.Kids[nw:] {
+			kid.Destroy()
+		}
+		sg.Kids = sg.Kids[:nw]
+	} else {
+		sg.Kids = append(sg.Kids, make(ki.Slice, nw-len(sg.Kids))...)
+	}
+	sv.Values = make([]ValueView, visRows)
+	sv.ConfigSliceGridRows()
+	sg.Init2DTree()
+	sg.Style2DTree()
+	sv.ConfigBlankRows()
+	return true
+}
+
+// ScrollVirtual processes a mouse scroll event in Virtual mode, scrolling by
+// rows according to the vertical delta
+func (sv *SliceView) ScrollVirtual(me *mouse.ScrollEvent) {
+	del := me.Delta.Y
+	if del == 0 || sv.RowHeight <= 0 {
+		return
+	}
+	nr := int(float32(del) / sv.RowHeight)
+	if nr == 0 {
+		nr = 1
+		if del < 0 {
+			nr = -1
+		}
+	}
+	sv.SetStartIdx(sv.StartIdx + nr)
+	me.SetProcessed()
 }
 
 // SetChanged sets the Changed flag and emits the ViewSig signal for the
@@ -370,6 +643,7 @@ func (sv *SliceView) SliceNewAt(idx int, reconfig bool) {
 
 	svl := reflect.ValueOf(sv.Slice)
 	svnp := kit.NonPtrValue(svl)
+	before := ValueUndoCopy(svnp)
 
 	nval := reflect.New(kit.NonPtrType(sltyp)) // make the concrete el
 	if !slptr {
@@ -382,6 +656,7 @@ func (sv *SliceView) SliceNewAt(idx int, reconfig bool) {
 		svnp.Index(idx).Set(nval)
 	}
 	svl.Elem().Set(svnp)
+	sv.SaveUndo("Insert", before)
 	if sv.TmpSave != nil {
 		sv.TmpSave.SaveTmp()
 	}
@@ -400,7 +675,9 @@ func (sv *SliceView) SliceDeleteAt(idx int, reconfig bool) {
 	updt := sv.UpdateStart()
 	defer sv.UpdateEnd(updt)
 
+	before := ValueUndoCopy(kit.NonPtrValue(reflect.ValueOf(sv.Slice)))
 	kit.SliceDeleteAt(sv.Slice, idx)
+	sv.SaveUndo("Delete", before)
 
 	if sv.TmpSave != nil {
 		sv.TmpSave.SaveTmp()
@@ -411,6 +688,18 @@ func (sv *SliceView) SliceDeleteAt(idx int, reconfig bool) {
 	}
 }
 
+// SaveUndo saves the change to the slice from before, a ValueUndoCopy of it
+// from before the change, in the ValueUndo of the window
+func (sv *SliceView) SaveUndo(desc string, before reflect.Value) {
+	SaveValueUndo(sv.This.(gi.Node2D), desc, kit.NonPtrValue(reflect.ValueOf(sv.Slice)), before, func() {
+		if sv.TmpSave != nil {
+			sv.TmpSave.SaveTmp()
+		}
+		sv.ConfigSliceGrid(true)
+		sv.SetChanged()
+	})
+}
+
 // ConfigToolbar configures the toolbar actions
 func (sv *SliceView) ConfigToolbar() {
 	if kit.IfaceIsNil(sv.Slice) || sv.IsInactive() {
@@ -453,6 +742,14 @@ func (sv *SliceView) Style2D() {
 	sv.Frame.Style2D()
 }
 
+func (sv *SliceView) Layout2D(parBBox image.Rectangle, iter int) bool {
+	redo := sv.Frame.Layout2D(parBBox, iter)
+	if sv.Virtual && iter == 0 && sv.LayoutVirtual() {
+		redo = true
+	}
+	return redo
+}
+
 func (sv *SliceView) Render2D() {
 	sv.ToolBar().UpdateActions()
 	if win := sv.ParentWindow(); win != nil {
@@ -469,7 +766,7 @@ func (sv *SliceView) Render2D() {
 		sv.RenderScrolls()
 		sv.Render2DChildren()
 		sv.PopBounds()
-		if sv.SelectedIdx > -1 {
+		if sv.SelectedIdx > -1 && !sv.Virtual { // Virtual keeps it in view at config
 			sv.ScrollToRow(sv.SelectedIdx)
 		}
 	} else {
@@ -501,8 +798,28 @@ func (sv *SliceView) RowVal(row int) interface{} {
 	return vali
 }
 
+// RowWidgetIdx returns the index within the SliceGrid of the first widget
+// for given row -- false if out of range, or the row has no widgets because
+// it is not shown in Virtual mode
+func (sv *SliceView) RowWidgetIdx(row int) (int, bool) {
+	wr := row - sv.StartIdx
+	if row < 0 || row >= sv.BuiltSize || wr < 0 || wr >= sv.VisRows {
+		return -1, false
+	}
+	nWidgPerRow, _ := sv.RowWidgetNs()
+	sg, _ := sv.SliceGrid()
+	if sg == nil {
+		return -1, false
+	}
+	ridx := wr * nWidgPerRow
+	if !sg.Kids.IsValidIndex(ridx + nWidgPerRow - 1) {
+		return -1, false
+	}
+	return ridx, true
+}
+
 // RowFirstWidget returns the first widget for given row (could be index or
-// not) -- false if out of range
+// not) -- false if out of range, or not shown in Virtual mode
 func (sv *SliceView) RowFirstWidget(row int) (*gi.WidgetBase, bool) {
 	if !sv.ShowIndex {
 		return nil, false
@@ -510,12 +827,12 @@ func (sv *SliceView) RowFirstWidget(row int) (*gi.WidgetBase, bool) {
 	if sv.RowVal(row) == nil { // range check
 		return nil, false
 	}
-	nWidgPerRow, _ := sv.RowWidgetNs()
-	sg, _ := sv.SliceGrid()
-	if sg == nil {
+	ridx, ok := sv.RowWidgetIdx(row)
+	if !ok {
 		return nil, false
 	}
-	widg := sg.Kids[row*nWidgPerRow].(gi.Node2D).AsWidget()
+	sg, _ := sv.SliceGrid()
+	widg := sg.Kids[ridx].(gi.Node2D).AsWidget()
 	return widg, true
 }
 
@@ -526,12 +843,12 @@ func (sv *SliceView) RowGrabFocus(row int) *gi.WidgetBase {
 	if sv.RowVal(row) == nil || sv.inFocusGrab { // range check
 		return nil
 	}
-	nWidgPerRow, idxOff := sv.RowWidgetNs()
-	sg, _ := sv.SliceGrid()
-	if sg == nil {
+	_, idxOff := sv.RowWidgetNs()
+	ridx, ok := sv.RowWidgetIdx(row)
+	if !ok {
 		return nil
 	}
-	ridx := nWidgPerRow * row
+	sg, _ := sv.SliceGrid()
 	widg := sg.KnownChild(ridx + idxOff).(gi.Node2D).AsWidget()
 	if widg.HasFocus() {
 		return widg
@@ -558,7 +875,7 @@ func (sv *SliceView) RowPos(row int) image.Point {
 // RowFromPos returns the row that contains given vertical position, false if not found
 func (sv *SliceView) RowFromPos(posY int) (int, bool) {
 	// todo: could optimize search to approx loc, and search up / down from there
-	for rw := 0; rw < sv.BuiltSize; rw++ {
+	for rw := sv.StartIdx; rw < sv.StartIdx+sv.VisRows; rw++ {
 		widg, ok := sv.RowFirstWidget(rw)
 		if ok {
 			if widg.WinBBox.Min.Y < posY && posY < widg.WinBBox.Max.Y {
@@ -573,6 +890,9 @@ func (sv *SliceView) RowFromPos(posY int) (int, bool) {
 // -- returns true if any scrolling was performed
 func (sv *SliceView) ScrollToRow(row int) bool {
 	row = ints.MinInt(row, sv.BuiltSize-1)
+	if sv.Virtual {
+		return sv.SetStartIdx(sv.StartIdxForRow(row))
+	}
 	sg, _ := sv.SliceGrid()
 	if widg, ok := sv.RowFirstWidget(row); ok {
 		return sg.ScrollToItem(widg)
@@ -677,9 +997,12 @@ func (sv *SliceView) MoveUpAction(selMode mouse.SelectModes) int {
 
 // SelectRowWidgets sets the selection state of given row of widgets
 func (sv *SliceView) SelectRowWidgets(idx int, sel bool) {
+	rowidx, ok := sv.RowWidgetIdx(idx)
+	if !ok { // not shown in Virtual mode -- selection is set when it is
+		return
+	}
 	sg, _ := sv.SliceGrid()
-	nWidgPerRow, idxOff := sv.RowWidgetNs()
-	rowidx := idx * nWidgPerRow
+	_, idxOff := sv.RowWidgetNs()
 	if sv.ShowIndex {
 		if sg.Kids.IsValidIndex(rowidx) {
 			widg := sg.KnownChild(rowidx).(gi.Node2D).AsNode2D()
@@ -1020,7 +1343,9 @@ func (sv *SliceView) PasteAssign(md mimedata.Mimes, row int) {
 		return
 	}
 	ns := sl[0]
+	before := ValueUndoCopy(tvnp)
 	tvnp.Index(row).Set(reflect.ValueOf(ns).Elem())
+	sv.SaveUndo("Paste", before)
 	if sv.TmpSave != nil {
 		sv.TmpSave.SaveTmp()
 	}
@@ -1036,6 +1361,7 @@ func (sv *SliceView) PasteAtRow(md mimedata.Mimes, row int) {
 
 	sl := sv.RowsFromMimeData(md)
 	updt := sv.UpdateStart()
+	before := ValueUndoCopy(tvnp)
 	for _, ns := range sl {
 		sz := tvnp.Len()
 		tvnp = reflect.Append(tvnp, reflect.ValueOf(ns).Elem())
@@ -1047,6 +1373,7 @@ func (sv *SliceView) PasteAtRow(md mimedata.Mimes, row int) {
 		}
 		row++
 	}
+	sv.SaveUndo("Paste", before)
 	if sv.TmpSave != nil {
 		sv.TmpSave.SaveTmp()
 	}
@@ -1085,7 +1412,13 @@ func (sv *SliceView) DragNDropStart() {
 		sv.MimeDataRow(&md, r)
 	}
 	rws := sv.SelectedRowsList(true) // descending sort
-	widg, ok := sv.RowFirstWidget(rws[0])
+	var widg *gi.WidgetBase
+	ok := false
+	for _, r := range rws { // first one shown, in Virtual mode
+		if widg, ok = sv.RowFirstWidget(r); ok {
+			break
+		}
+	}
 	if ok {
 		bi := &gi.Bitmap{}
 		bi.InitName(bi, sv.UniqueName())
@@ -1353,6 +1686,13 @@ func (sv *SliceView) KeyInputInactive(kt *key.ChordEvent) {
 }
 
 func (sv *SliceView) SliceViewEvents() {
+	if sv.Virtual {
+		sv.ConnectEvent(oswin.MouseScrollEvent, gi.LowPri, func(recv, send ki.Ki, sig int64, d interface{}) {
+			me := d.(*mouse.ScrollEvent)
+			svv := recv.Embed(KiT_SliceView).(*SliceView)
+			svv.ScrollVirtual(me)
+		})
+	}
 	if sv.IsInactive() {
 		if sv.InactKeyNav {
 			sv.ConnectEvent(oswin.KeyChordEvent, gi.LowPri, func(recv, send ki.Ki, sig int64, d interface{}) {
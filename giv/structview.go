@@ -6,6 +6,8 @@ package giv
 
 import (
 	"fmt"
+	"html"
+	"log"
 	"reflect"
 
 	"github.com/goki/gi"
@@ -19,17 +21,31 @@ import (
 // each field, within an overall frame.
 type StructView struct {
 	gi.Frame
-	Struct      interface{}    `desc:"the struct that we are a view onto"`
-	Changed     bool           `desc:"has the value of any field changed?  updated by the ViewSig signals from fields"`
-	ChangeFlag  *reflect.Value `json:"-" xml:"-" desc:"ValueView for a field marked with changeflag struct tag, which must be a bool type, which is updated when changes are registered in field values."`
-	FieldViews  []ValueView    `json:"-" xml:"-" desc:"ValueView representations of the fields"`
-	TmpSave     ValueView      `json:"-" xml:"-" desc:"value view that needs to have SaveTmp called on it whenever a change is made to one of the underlying values -- pass this down to any sub-views created from a parent"`
-	ViewSig     ki.Signal      `json:"-" xml:"-" desc:"signal for valueview -- only one signal sent when a value has been set -- all related value views interconnect with each other to update when others update"`
-	ToolbarStru interface{}    `desc:"the struct that we successfully set a toolbar for"`
+	Struct       interface{}     `desc:"the struct that we are a view onto"`
+	Changed      bool            `desc:"has the value of any field changed?  updated by the ViewSig signals from fields"`
+	ChangeFlag   *reflect.Value  `json:"-" xml:"-" desc:"ValueView for a field marked with changeflag struct tag, which must be a bool type, which is updated when changes are registered in field values."`
+	FieldViews   []ValueView     `json:"-" xml:"-" desc:"ValueView representations of the fields"`
+	TmpSave      ValueView       `json:"-" xml:"-" desc:"value view that needs to have SaveTmp called on it whenever a change is made to one of the underlying values -- pass this down to any sub-views created from a parent"`
+	ViewSig      ki.Signal       `json:"-" xml:"-" desc:"signal for valueview -- only one signal sent when a value has been set -- all related value views interconnect with each other to update when others update"`
+	ToolbarStru  interface{}     `desc:"the struct that we successfully set a toolbar for"`
+	Validating   bool            `json:"-" xml:"-" desc:"does the struct have any validation (see HasValidation)?  if so, the struct grid has a third column showing field validation errors, and struct-level errors are shown below it"`
+	FieldErrs    []error         `json:"-" xml:"-" desc:"validation errors for each of the FieldViews -- nil if valid"`
+	StructErr    error           `json:"-" xml:"-" desc:"validation error from the Validator interface on the struct itself -- nil if valid"`
+	GroupStructs bool            `desc:"show the fields of nested struct fields, which are otherwise edited in a dialog, in collapsible groups -- set from the group-structs property"`
+	ShowSearch   bool            `desc:"show the search bar, for filtering the fields -- set from the search property if set, otherwise true if the struct has at least PropGridSearchFields fields"`
+	Search       string          `desc:"only show the fields whose label or description (desc tag) contains all of these white-space separated terms"`
+	ModifiedOnly bool            `desc:"only show the fields whose values differ from their defaults -- from the def tags of its fields and the Defaults() method of the struct if it has one, otherwise the zero values"`
+	GroupOpen    map[string]bool `json:"-" xml:"-" desc:"open state of the collapsible groups, by path -- groups of fields with the same category tag are open by default, and nested structs closed, except while filtering by Search or ModifiedOnly, to show their matching fields"`
+	rows         []structViewRow
+	fieldRows    []int // row in the grid of each of the FieldViews
 }
 
 var KiT_StructView = kit.Types.AddType(&StructView{}, StructViewProps)
 
+// ValidateErrColor is the color used for highlighting fields that fail
+// validation, and their error messages
+var ValidateErrColor = "#d00000"
+
 var StructViewProps = ki.Props{
 	"background-color": &gi.Prefs.Colors.Background,
 	"color":            &gi.Prefs.Colors.Font,
@@ -45,11 +61,13 @@ func (sv *StructView) SetStruct(st interface{}, tmpSave ValueView) {
 		sv.Changed = false
 		updt = sv.UpdateStart()
 		sv.Struct = st
+		sv.Validating = HasValidation(st)
 		if k, ok := st.(ki.Ki); ok {
 			k.NodeSignal().Connect(sv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
 				// todo: check for delete??
 				svv, _ := recv.Embed(KiT_StructView).(*StructView)
 				svv.UpdateFields()
+				svv.Validate()
 				svv.ViewSig.Emit(svv.This, 0, nil)
 			})
 		}
@@ -66,7 +84,21 @@ func (sv *StructView) UpdateFromStruct() {
 			return
 		}
 	}
+	if gs, ok := sv.Prop("group-structs"); ok {
+		sv.GroupStructs, _ = kit.ToBool(gs)
+	}
+	if srch, ok := sv.Prop("search"); ok {
+		sv.ShowSearch, _ = kit.ToBool(srch)
+	} else {
+		nfld := 0
+		kit.FlatFieldsValueFunc(sv.Struct, func(fval interface{}, typ reflect.Type, field reflect.StructField, fieldVal reflect.Value) bool {
+			nfld++
+			return true
+		})
+		sv.ShowSearch = nfld >= PropGridSearchFields
+	}
 	mods, updt := sv.StdConfig()
+	sv.ConfigSearchBar()
 	sv.ConfigStructGrid()
 	sv.ConfigToolbar()
 	if mods {
@@ -89,7 +121,13 @@ func (sv *StructView) UpdateFields() {
 func (sv *StructView) StdFrameConfig() kit.TypeAndNameList {
 	config := kit.TypeAndNameList{}
 	config.Add(gi.KiT_ToolBar, "toolbar")
+	if sv.ShowSearch {
+		config.Add(gi.KiT_Layout, "search-bar")
+	}
 	config.Add(gi.KiT_Frame, "struct-grid")
+	if sv.Validating {
+		config.Add(gi.KiT_Label, "struct-err")
+	}
 	return config
 }
 
@@ -113,6 +151,25 @@ func (sv *StructView) StructGrid() (*gi.Frame, int) {
 	return sv.KnownChild(idx).(*gi.Frame), idx
 }
 
+// SearchBar returns the search bar -- nil if not ShowSearch
+func (sv *StructView) SearchBar() *gi.Layout {
+	idx, ok := sv.Children().IndexByName("search-bar", 1)
+	if !ok {
+		return nil
+	}
+	return sv.KnownChild(idx).(*gi.Layout)
+}
+
+// StructErrLabel returns the label showing struct-level validation errors --
+// nil if the struct does not have validation
+func (sv *StructView) StructErrLabel() *gi.Label {
+	idx, ok := sv.Children().IndexByName("struct-err", 3)
+	if !ok {
+		return nil
+	}
+	return sv.KnownChild(idx).(*gi.Label)
+}
+
 // ToolBar returns the toolbar widget
 func (sv *StructView) ToolBar() *gi.ToolBar {
 	idx, ok := sv.Children().IndexByName("toolbar", 1)
@@ -141,6 +198,182 @@ func (sv *StructView) ConfigToolbar() {
 	sv.ToolbarStru = sv.Struct
 }
 
+// structViewRow is one row of the StructView grid: the header of a group of
+// rows, or a field
+type structViewRow struct {
+	group   string    // label of the group, for a header row -- "" for a field
+	tooltip string    // tooltip of the group header
+	path    string    // path of the group or field, for unique names
+	depth   int       // depth of nesting in groups
+	open    bool      // is the group open
+	vv      ValueView // ValueView of the field
+}
+
+// GroupIsOpen returns whether the group at given path is open, from
+// GroupOpen, with dflt if not set there
+func (sv *StructView) GroupIsOpen(path string, dflt bool) bool {
+	if open, ok := sv.GroupOpen[path]; ok {
+		return open
+	}
+	return dflt
+}
+
+// ToggleGroup opens or closes the group at given path
+func (sv *StructView) ToggleGroup(path string) {
+	if sv.GroupOpen == nil {
+		sv.GroupOpen = make(map[string]bool)
+	}
+	open := false
+	for _, row := range sv.rows {
+		if row.group != "" && row.path == path {
+			open = row.open
+			break
+		}
+	}
+	sv.GroupOpen[path] = !open
+	sv.ConfigStructGrid()
+}
+
+// SetSearch sets the Search and ModifiedOnly filters on the fields shown
+func (sv *StructView) SetSearch(search string, modOnly bool) {
+	if sv.Search == search && sv.ModifiedOnly == modOnly {
+		return
+	}
+	sv.Search = search
+	sv.ModifiedOnly = modOnly
+	sv.ConfigStructGrid()
+}
+
+// StructDefaults returns a value of the type of the struct with its default
+// values, for ModifiedOnly -- from the def tags of its fields (see
+// StructTagDefaults), and then its Defaults() method if it has one, so that
+// fields left at the default documented in their def tag are not modified
+func (sv *StructView) StructDefaults() reflect.Value {
+	typ := kit.NonPtrType(reflect.TypeOf(sv.Struct))
+	if typ.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	dp := reflect.New(typ)
+	StructTagDefaults(dp.Interface())
+	if df, ok := dp.Interface().(interface {
+		Defaults()
+	}); ok {
+		df.Defaults()
+	}
+	return dp.Elem()
+}
+
+// StructTagDefaults sets the fields of stru (a pointer to a struct) that
+// have a def tag to the value given in the tag, e.g., `def:"0.5"` --
+// recursively for nested struct fields -- logs fields that cannot be set
+// from their tag
+func StructTagDefaults(stru interface{}) {
+	kit.FlatFieldsValueFunc(stru, func(fval interface{}, typ reflect.Type, field reflect.StructField, fieldVal reflect.Value) bool {
+		if !fieldVal.CanAddr() {
+			return true
+		}
+		deftag, ok := field.Tag.Lookup("def")
+		if !ok {
+			if fieldVal.Kind() == reflect.Struct {
+				StructTagDefaults(fieldVal.Addr().Interface())
+			}
+			return true
+		}
+		if !kit.SetRobust(fieldVal.Addr().Interface(), deftag) {
+			log.Printf("giv.StructTagDefaults: could not set field: %v of type: %v to default: %v\n", field.Name, typ.Name(), deftag)
+		}
+		return true
+	})
+}
+
+// structRows returns the rows of the grid for the fields of stru (a pointer
+// to a struct), given the value of the struct with its default values (for
+// ModifiedOnly, invalid otherwise), and the path and depth of the group that
+// it is in -- fields with a category tag are grouped by category after the
+// others, and nested struct fields are shown as groups if GroupStructs
+func (sv *StructView) structRows(stru interface{}, def reflect.Value, prefix string, depth int) []structViewRow {
+	var rows []structViewRow
+	var cats []string
+	catRows := make(map[string][]structViewRow)
+	filtering := sv.Search != "" || sv.ModifiedOnly
+	kit.FlatFieldsValueFunc(stru, func(fval interface{}, typ reflect.Type, field reflect.StructField, fieldVal reflect.Value) bool {
+		if depth == 0 {
+			if _, got := field.Tag.Lookup("changeflag"); got && field.Type.Kind() == reflect.Bool {
+				sv.ChangeFlag = &fieldVal
+			}
+		}
+		vwtag := field.Tag.Get("view")
+		if vwtag == "-" {
+			return true
+		}
+		vv := FieldToValueView(stru, field.Name, fval)
+		if vv == nil { // shouldn't happen
+			return true
+		}
+		var dfv reflect.Value
+		if def.IsValid() {
+			dfv = def.FieldByName(field.Name)
+		}
+		pth := prefix + field.Name
+		var frows []structViewRow
+		if _, isStru := vv.(*StructValueView); isStru && sv.GroupStructs && fieldVal.Kind() == reflect.Struct {
+			sub := sv.structRows(fieldVal.Addr().Interface(), dfv, pth+".", depth+1)
+			if filtering && len(sub) == 0 {
+				return true
+			}
+			open := sv.GroupIsOpen(pth, filtering) // open to show matches, unless closed
+			frows = append(frows, structViewRow{group: sv.FieldLabel(&field), tooltip: field.Tag.Get("desc"), path: pth, depth: depth, open: open})
+			if open {
+				frows = append(frows, sub...)
+			}
+		} else {
+			if !PropSearchMatch(sv.Search, sv.FieldLabel(&field), field.Tag.Get("desc")) {
+				return true
+			}
+			if sv.ModifiedOnly && dfv.IsValid() && reflect.DeepEqual(fieldVal.Interface(), dfv.Interface()) {
+				return true
+			}
+			vv.SetStructValue(fieldVal.Addr(), stru, &field, sv.TmpSave)
+			frows = append(frows, structViewRow{path: pth, depth: depth, vv: vv})
+		}
+		cat := field.Tag.Get("category")
+		if cat == "" {
+			rows = append(rows, frows...)
+			return true
+		}
+		if _, has := catRows[cat]; !has {
+			cats = append(cats, cat)
+		}
+		catRows[cat] = append(catRows[cat], frows...)
+		return true
+	})
+	for _, cat := range cats {
+		cpth := prefix + "category-" + cat
+		open := sv.GroupIsOpen(cpth, true)
+		rows = append(rows, structViewRow{group: cat, path: cpth, depth: depth, open: open})
+		if !open {
+			continue
+		}
+		for _, row := range catRows[cat] {
+			row.depth++
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// ConfigSearchBar configures the search bar, if ShowSearch
+func (sv *StructView) ConfigSearchBar() {
+	bar := sv.SearchBar()
+	if bar == nil {
+		return
+	}
+	ConfigPropSearchBar(bar, sv.Search, true, sv.ModifiedOnly, sv.This, func(recv ki.Ki, search string, modOnly bool) {
+		svv := recv.Embed(KiT_StructView).(*StructView)
+		svv.SetSearch(search, modOnly)
+	})
+}
+
 // ConfigStructGrid configures the StructGrid for the current struct
 func (sv *StructView) ConfigStructGrid() {
 	if kit.IfaceIsNil(sv.Struct) {
@@ -157,53 +390,75 @@ func (sv *StructView) ConfigStructGrid() {
 	sg.SetMinPrefWidth(units.NewValue(10, units.Em))
 	sg.SetStretchMaxHeight() // for this to work, ALL layers above need it too
 	sg.SetStretchMaxWidth()  // for this to work, ALL layers above need it too
-	sg.SetProp("columns", 2)
+	ncol := 2
+	if sv.Validating {
+		ncol = 3
+	}
+	sg.SetProp("columns", ncol)
 	config := kit.TypeAndNameList{}
 	// always start fresh!
 	sv.FieldViews = make([]ValueView, 0)
-	kit.FlatFieldsValueFunc(sv.Struct, func(fval interface{}, typ reflect.Type, field reflect.StructField, fieldVal reflect.Value) bool {
-		// todo: check tags, skip various etc
-		_, got := field.Tag.Lookup("changeflag")
-		if got {
-			if field.Type.Kind() == reflect.Bool {
-				sv.ChangeFlag = &fieldVal
+	sv.fieldRows = make([]int, 0)
+	def := reflect.Value{}
+	if sv.ModifiedOnly {
+		def = sv.StructDefaults()
+	}
+	sv.rows = sv.structRows(sv.Struct, def, "", 0)
+	for ri, row := range sv.rows {
+		if row.group != "" {
+			grpnm := fmt.Sprintf("group-%v", row.path)
+			config.Add(gi.KiT_Action, grpnm)
+			for c := 1; c < ncol; c++ {
+				config.Add(gi.KiT_Label, fmt.Sprintf("%v-%v", grpnm, c))
 			}
+			continue
 		}
-		vwtag := field.Tag.Get("view")
-		if vwtag == "-" {
-			return true
-		}
-		vv := FieldToValueView(sv.Struct, field.Name, fval)
-		if vv == nil { // shouldn't happen
-			return true
-		}
-		vvp := fieldVal.Addr()
-		vv.SetStructValue(vvp, sv.Struct, &field, sv.TmpSave)
-		vtyp := vv.WidgetType()
+		vtyp := row.vv.WidgetType()
 		// todo: other things with view tag..
-		labnm := fmt.Sprintf("label-%v", field.Name)
-		valnm := fmt.Sprintf("value-%v", field.Name)
+		labnm := fmt.Sprintf("label-%v", row.path)
+		valnm := fmt.Sprintf("value-%v", row.path)
 		config.Add(gi.KiT_Label, labnm)
 		config.Add(vtyp, valnm) // todo: extend to diff types using interface..
-		sv.FieldViews = append(sv.FieldViews, vv)
-		return true
-	})
+		if sv.Validating {
+			config.Add(gi.KiT_Label, fmt.Sprintf("error-%v", row.path))
+		}
+		sv.FieldViews = append(sv.FieldViews, row.vv)
+		sv.fieldRows = append(sv.fieldRows, ri)
+	}
 	mods, updt := sg.ConfigChildren(config, false)
 	if mods {
 		sg.SetFullReRender()
 	} else {
 		updt = sg.UpdateStart()
 	}
+	for ri, row := range sv.rows {
+		if row.group == "" {
+			continue
+		}
+		act := sg.KnownChild(ri * ncol).(*gi.Action)
+		ConfigPropGroupHeader(act, row.group, row.tooltip, row.depth, row.open, row.path, sv.This, func(recv ki.Ki, path string) {
+			svv := recv.Embed(KiT_StructView).(*StructView)
+			svv.ToggleGroup(path)
+		})
+	}
+	sv.ValidateFields()
 	for i, vv := range sv.FieldViews {
-		lbl := sg.KnownChild(i * 2).(*gi.Label)
+		ridx := sv.fieldRows[i] * ncol
+		lbl := sg.KnownChild(ridx).(*gi.Label)
 		vvb := vv.AsValueViewBase()
 		vvb.ViewSig.ConnectOnly(sv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
 			svv, _ := recv.Embed(KiT_StructView).(*StructView)
+			if pv, ok := send.(ParseErrValueView); ok && pv.ValueParseErr() != nil {
+				svv.Validate()                     // not changed -- just shows the error
+				svv.ViewSig.Emit(svv.This, 0, nil) // e.g., for StructViewDialog to block Ok
+				return
+			}
 			// note: updating vv here is redundant -- relevant field will have already updated
 			svv.Changed = true
 			if svv.ChangeFlag != nil {
 				svv.ChangeFlag.SetBool(true)
 			}
+			svv.Validate()
 			tb := svv.ToolBar()
 			if tb != nil {
 				tb.UpdateActions()
@@ -212,24 +467,122 @@ func (sv *StructView) ConfigStructGrid() {
 			// vvv, _ := send.Embed(KiT_ValueViewBase).(*ValueViewBase)
 			// fmt.Printf("sview got edit from vv %v field: %v\n", vvv.Nm, vvv.Field.Name)
 		})
-		lbltag := vvb.Field.Tag.Get("label")
-		if lbltag != "" {
-			lbl.Text = lbltag
-		} else {
-			lbl.Text = vvb.Field.Name
-		}
+		lbl.Text, _ = sv.validationText(i)
 		lbl.Redrawable = true
 		lbl.Tooltip = vvb.Field.Tag.Get("desc")
-		widg := sg.KnownChild((i * 2) + 1).(gi.Node2D)
+		if sv.Validating {
+			elbl := sg.KnownChild(ridx + 2).(*gi.Label)
+			_, elbl.Text = sv.validationText(i)
+			elbl.Redrawable = true
+			elbl.SetProp("color", ValidateErrColor)
+		}
+		widg := sg.KnownChild(ridx + 1).(gi.Node2D)
 		widg.SetProp("horizontal-align", gi.AlignLeft)
 		if sv.IsInactive() {
 			widg.AsNode2D().SetInactive()
 		}
 		vv.ConfigWidget(widg)
 	}
+	if slbl := sv.StructErrLabel(); slbl != nil {
+		slbl.Text = sv.structErrText()
+		slbl.Redrawable = true
+		slbl.SetProp("color", ValidateErrColor)
+	}
 	sg.UpdateEnd(updt)
 }
 
+// FieldLabel returns the label text for given field -- from the label tag if
+// set, otherwise the field name
+func (sv *StructView) FieldLabel(field *reflect.StructField) string {
+	if lbltag := field.Tag.Get("label"); lbltag != "" {
+		return lbltag
+	}
+	return field.Name
+}
+
+// ValidateFields validates all of the fields, and the struct itself, saving
+// the errors in FieldErrs and StructErr, without updating the display --
+// returns true if all valid
+func (sv *StructView) ValidateFields() bool {
+	valid := true
+	sv.FieldErrs = make([]error, len(sv.FieldViews))
+	for i, vv := range sv.FieldViews {
+		sv.FieldErrs[i] = ValidateValue(vv)
+		if sv.FieldErrs[i] != nil {
+			valid = false
+		}
+	}
+	sv.StructErr = ValidateStruct(sv.Struct)
+	return valid && sv.StructErr == nil
+}
+
+// Validate validates all of the fields, and the struct itself, and updates
+// the display of any validation errors -- returns true if all valid
+func (sv *StructView) Validate() bool {
+	valid := sv.ValidateFields()
+	if !sv.Validating {
+		return valid
+	}
+	sg, _ := sv.StructGrid()
+	if sg == nil || len(sg.Kids) < 3*len(sv.rows) {
+		return valid
+	}
+	for i := range sv.FieldViews {
+		ltxt, etxt := sv.validationText(i)
+		ridx := sv.fieldRows[i] * 3
+		if lbl := sg.KnownChild(ridx).(*gi.Label); lbl.Text != ltxt {
+			lbl.SetTextAction(ltxt)
+		}
+		if elbl := sg.KnownChild(ridx + 2).(*gi.Label); elbl.Text != etxt {
+			elbl.SetTextAction(etxt)
+		}
+	}
+	if slbl := sv.StructErrLabel(); slbl != nil {
+		if stxt := sv.structErrText(); slbl.Text != stxt {
+			slbl.SetTextAction(stxt)
+		}
+	}
+	return valid
+}
+
+// IsValid returns true if there were no validation errors as of the last
+// Validate call
+func (sv *StructView) IsValid() bool {
+	if sv.StructErr != nil {
+		return false
+	}
+	for _, err := range sv.FieldErrs {
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// validationText returns the field label and error text for given field,
+// with the label highlighted if the field is invalid, and indented for its
+// depth in groups
+func (sv *StructView) validationText(idx int) (lbl, errs string) {
+	lbl = sv.FieldLabel(sv.FieldViews[idx].AsValueViewBase().Field)
+	indent := ""
+	if idx < len(sv.fieldRows) {
+		indent = PropIndent(sv.rows[sv.fieldRows[idx]].depth)
+	}
+	if idx >= len(sv.FieldErrs) || sv.FieldErrs[idx] == nil {
+		return indent + lbl, ""
+	}
+	lbl = fmt.Sprintf(`%v<span style="color:%v">%v</span>`, indent, ValidateErrColor, lbl)
+	return lbl, html.EscapeString(sv.FieldErrs[idx].Error())
+}
+
+// structErrText returns the text for the struct-level validation error
+func (sv *StructView) structErrText() string {
+	if sv.StructErr == nil {
+		return ""
+	}
+	return html.EscapeString(sv.StructErr.Error())
+}
+
 func (sv *StructView) Style2D() {
 	if sv.Viewport != nil && sv.Viewport.IsDoingFullRender() {
 		sv.UpdateFromStruct()
@@ -11,7 +11,9 @@ import (
 	"log"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/goki/gi"
 	"github.com/goki/gi/oswin"
@@ -37,6 +39,19 @@ import (
 // cursor will be displayed while updating the table
 var TableViewWaitCursorSize = 5000
 
+// TableViewVirtualSize is the length of the slice above which the table is
+// shown in Virtual mode, with widgets only for the visible rows, unless the
+// "virtual" property is set
+var TableViewVirtualSize = 1000
+
+// TableViewVirtualRows is the initial number of rows of widgets in Virtual
+// mode, before the number of visible rows is known from the layout
+var TableViewVirtualRows = 40
+
+// TableViewVirtualMargin is the number of rows of widgets beyond the visible
+// rows in Virtual mode, so partially-visible rows at the end are shown
+var TableViewVirtualMargin = 2
+
 // TableView represents a slice-of-structs as a table, where the fields are
 // the columns, within an overall frame.  It has two modes, determined by
 // Inactive flag: if Inactive, it functions as a mutually-exclusive item
@@ -47,23 +62,34 @@ var TableViewWaitCursorSize = 5000
 // taken using the TableViewSig signals
 type TableView struct {
 	gi.Frame
-	Slice            interface{}        `view:"-" json:"-" xml:"-" desc:"the slice that we are a view onto -- must be a pointer to that slice"`
-	StyleFunc        TableViewStyleFunc `view:"-" json:"-" xml:"-" desc:"optional styling function"`
-	ShowViewCtxtMenu bool               `desc:"if the object we're viewing has its own CtxtMenu property defined, should we also still show the view's standard context menu?"`
-	Changed          bool               `desc:"has the table been edited?"`
-	Values           [][]ValueView      `json:"-" xml:"-" desc:"ValueView representations of the slice field values -- outer dimension is fields, inner is rows (generally more rows than fields, so this minimizes number of slices allocated)"`
-	ShowIndex        bool               `xml:"index" desc:"whether to show index or not (default true) -- updated from "index" property (bool)"`
-	InactKeyNav      bool               `xml:"inact-key-nav" desc:"support key navigation when inactive (default true) -- updated from "intact-key-nav" property (bool) -- no focus really plausible in inactive case, so it uses a low-pri capture of up / down events"`
-	SelField         string             `view:"-" json:"-" xml:"-" desc:"current selection field -- initially select value in this field"`
-	SelVal           interface{}        `view:"-" json:"-" xml:"-" desc:"current selection value -- initially select this value in SelField"`
-	SelectedIdx      int                `json:"-" xml:"-" desc:"index (row) of currently-selected item (-1 if none) -- see SelectedRows for full set of selected rows in active editing mode"`
-	SortIdx          int                `desc:"current sort index"`
-	SortDesc         bool               `desc:"whether current sort order is descending"`
-	SelectMode       bool               `desc:"editing-mode select rows mode"`
-	SelectedRows     map[int]bool       `desc:"list of currently-selected rows"`
-	DraggedRows      []int              `desc:"list of currently-dragged rows"`
-	TableViewSig     ki.Signal          `json:"-" xml:"-" desc:"table view interactive editing signals"`
-	ViewSig          ki.Signal          `json:"-" xml:"-" desc:"signal for valueview -- only one signal sent when a value has been set -- all related value views interconnect with each other to update when others update"`
+	Slice            interface{}         `view:"-" json:"-" xml:"-" desc:"the slice that we are a view onto -- must be a pointer to that slice"`
+	StyleFunc        TableViewStyleFunc  `view:"-" json:"-" xml:"-" desc:"optional styling function"`
+	ShowViewCtxtMenu bool                `desc:"if the object we're viewing has its own CtxtMenu property defined, should we also still show the view's standard context menu?"`
+	Changed          bool                `desc:"has the table been edited?"`
+	Values           [][]ValueView       `json:"-" xml:"-" desc:"ValueView representations of the slice field values -- outer dimension is fields, inner is rows (generally more rows than fields, so this minimizes number of slices allocated) -- in Virtual mode, inner is the rows of widgets, starting at StartIdx"`
+	ShowIndex        bool                `xml:"index" desc:"whether to show index or not (default true) -- updated from "index" property (bool)"`
+	Virtual          bool                `xml:"virtual" desc:"only create widgets for the visible rows, which are re-used for other rows as the table is scrolled, for large slices -- updated from "virtual" property (bool) if set, otherwise true if the slice is longer than TableViewVirtualSize"`
+	StartIdx         int                 `json:"-" xml:"-" desc:"in Virtual mode, the index of the first row shown"`
+	VisRows          int                 `json:"-" xml:"-" desc:"number of rows of widgets -- all the rows unless in Virtual mode"`
+	NVisible         int                 `json:"-" xml:"-" desc:"in Virtual mode, the number of rows that fit within the grid, from the layout"`
+	RowHeight        float32             `json:"-" xml:"-" desc:"in Virtual mode, the height of a row, in dots, from the layout"`
+	InactKeyNav      bool                `xml:"inact-key-nav" desc:"support key navigation when inactive (default true) -- updated from "intact-key-nav" property (bool) -- no focus really plausible in inactive case, so it uses a low-pri capture of up / down events"`
+	SelField         string              `view:"-" json:"-" xml:"-" desc:"current selection field -- initially select value in this field"`
+	SelVal           interface{}         `view:"-" json:"-" xml:"-" desc:"current selection value -- initially select this value in SelField"`
+	SelectedIdx      int                 `json:"-" xml:"-" desc:"index (row) of currently-selected item (-1 if none) -- see SelectedRows for full set of selected rows in active editing mode"`
+	SortIdx          int                 `desc:"current sort index"`
+	SortDesc         bool                `desc:"whether current sort order is descending"`
+	SortKeys         []TableViewSortKey  `desc:"fields to sort by, in order of priority -- shift-click on a header adds its field -- the first is also in SortIdx, SortDesc"`
+	Filter           string              `desc:"filter expression for the rows shown -- white-space separated terms that must all match: Field:text matches rows with text in that field, Field:>n (or <, >=, <=, =, !=) compares the field to n, and other text matches any field -- case insensitive"`
+	FilterFunc       TableViewFilterFunc `view:"-" json:"-" xml:"-" desc:"optional function that rows must also pass to be shown"`
+	Indexes          []int               `json:"-" xml:"-" desc:"indexes in the slice of the rows shown, in order, when filtered -- nil if all rows are shown -- the rows used in all other methods are slice indexes"`
+	ShowFilter       bool                `xml:"filter" desc:"whether to show the filter field (default true unless Inactive) -- updated from "filter" property (bool)"`
+	SaveState        bool                `xml:"save-state" desc:"save the sort and filter state in Prefs.TableViews for the type of struct shown, and restore it when the slice is set -- updated from "save-state" property (bool)"`
+	SelectMode       bool                `desc:"editing-mode select rows mode"`
+	SelectedRows     map[int]bool        `desc:"list of currently-selected rows"`
+	DraggedRows      []int               `desc:"list of currently-dragged rows"`
+	TableViewSig     ki.Signal           `json:"-" xml:"-" desc:"table view interactive editing signals"`
+	ViewSig          ki.Signal           `json:"-" xml:"-" desc:"signal for valueview -- only one signal sent when a value has been set -- all related value views interconnect with each other to update when others update"`
 
 	TmpSave      ValueView   `json:"-" xml:"-" desc:"value view that needs to have SaveTmp called on it whenever a change is made to one of the underlying values -- pass this down to any sub-views created from a parent"`
 	BuiltSlice   interface{} `view:"-" json:"-" xml:"-" desc:"the built slice"`
@@ -72,7 +98,10 @@ type TableView struct {
 	StruType     reflect.Type
 	NVisFields   int
 	VisFields    []reflect.StructField `view:"-" json:"-" xml:"-" desc:"the visible fields"`
+	AllFields    []reflect.StructField `view:"-" json:"-" xml:"-" desc:"all the fields that can be shown, including those hidden by the user, in the order of the struct"`
+	Cols         *TableViewCols        `view:"-" json:"-" xml:"-" desc:"the configuration of the columns -- shared by all TableViews of the type of struct shown, and saved in TableViewColsPrefs"`
 	inFocusGrab  bool
+	colDrag      *tableViewColDrag
 }
 
 var KiT_TableView = kit.Types.AddType(&TableView{}, TableViewProps)
@@ -84,6 +113,16 @@ var KiT_TableView = kit.Types.AddType(&TableView{}, TableViewProps)
 // configuration of elements in the view
 type TableViewStyleFunc func(tv *TableView, slice interface{}, widg gi.Node2D, row, col int, vv ValueView)
 
+// TableViewFilterFunc is a filtering function that returns true if given row
+// of the slice is shown
+type TableViewFilterFunc func(tv *TableView, row int) bool
+
+// TableViewSortKey is one of the fields that a TableView is sorted by
+type TableViewSortKey struct {
+	FieldIdx int  `desc:"index of the field in VisFields"`
+	Desc     bool `desc:"whether to sort in descending order"`
+}
+
 // SetSlice sets the source slice that we are viewing -- rebuilds the children
 // to represent this slice
 func (tv *TableView) SetSlice(sl interface{}, tmpSave ValueView) {
@@ -91,12 +130,17 @@ func (tv *TableView) SetSlice(sl interface{}, tmpSave ValueView) {
 	if kit.IfaceIsNil(sl) {
 		return
 	}
-	if tv.Slice != sl {
+	newSlice := tv.Slice != sl
+	if newSlice {
 		if !tv.IsInactive() {
 			tv.SelectedIdx = -1
 		}
 		tv.SortIdx = -1
 		tv.SortDesc = false
+		tv.SortKeys = nil
+		tv.Filter = ""
+		tv.Indexes = nil
+		tv.StartIdx = 0
 		slpTyp := reflect.TypeOf(sl)
 		if slpTyp.Kind() != reflect.Ptr {
 			log.Printf("TableView requires that you pass a pointer to a slice of struct elements -- type is not a Ptr: %v\n", slpTyp.String())
@@ -112,6 +156,7 @@ func (tv *TableView) SetSlice(sl interface{}, tmpSave ValueView) {
 			log.Printf("TableView requires that you pass a slice of struct elements -- type is not a Struct: %v\n", struTyp.String())
 			return
 		}
+		OpenTableViewColsPrefs()
 		updt = tv.UpdateStart()
 		tv.SelectedRows = make(map[int]bool, 10)
 		tv.SelectMode = false
@@ -125,6 +170,17 @@ func (tv *TableView) SetSlice(sl interface{}, tmpSave ValueView) {
 	if siknp, ok := tv.Prop("inact-key-nav"); ok {
 		tv.InactKeyNav, _ = kit.ToBool(siknp)
 	}
+	tv.ShowFilter = !tv.IsInactive()
+	if sfp, ok := tv.Prop("filter"); ok {
+		tv.ShowFilter, _ = kit.ToBool(sfp)
+	}
+	tv.SaveState = false
+	if ssp, ok := tv.Prop("save-state"); ok {
+		tv.SaveState, _ = kit.ToBool(ssp)
+	}
+	if newSlice && tv.SaveState {
+		tv.OpenState()
+	}
 	tv.TmpSave = tmpSave
 	tv.UpdateFromSlice()
 	tv.UpdateEnd(updt)
@@ -170,7 +226,9 @@ func (tv *TableView) UpdateValues() {
 	updt := tv.UpdateStart()
 	for _, vv := range tv.Values {
 		for _, vvf := range vv {
-			vvf.UpdateWidget()
+			if vvf != nil { // rows past the end in Virtual mode
+				vvf.UpdateWidget()
+			}
 		}
 	}
 	tv.UpdateEnd(updt)
@@ -184,10 +242,11 @@ func (tv *TableView) StructType() reflect.Type {
 }
 
 // CacheVisFields computes the number of visible fields in nVisFields and
-// caches those to skip in fieldSkip
+// caches those to skip in fieldSkip -- the fields are in the order of the
+// Cols, without those it hides
 func (tv *TableView) CacheVisFields() {
 	styp := tv.StructType()
-	tv.VisFields = make([]reflect.StructField, 0, 20)
+	tv.AllFields = make([]reflect.StructField, 0, 20)
 	kit.FlatFieldsTypeFunc(styp, func(typ reflect.Type, fld reflect.StructField) bool {
 		tvtag := fld.Tag.Get("tableview")
 		add := true
@@ -201,10 +260,27 @@ func (tv *TableView) CacheVisFields() {
 			}
 		}
 		if add {
-			tv.VisFields = append(tv.VisFields, fld)
+			tv.AllFields = append(tv.AllFields, fld)
 		}
 		return true
 	})
+	tv.Cols = TableViewColsPrefs.Cols(styp.String())
+	tv.VisFields = make([]reflect.StructField, 0, len(tv.AllFields))
+	for _, nm := range tv.Cols.Order {
+		for _, fld := range tv.AllFields {
+			if fld.Name == nm {
+				if !tv.Cols.IsHidden(fld) {
+					tv.VisFields = append(tv.VisFields, fld)
+				}
+				break
+			}
+		}
+	}
+	for _, fld := range tv.AllFields {
+		if !tv.Cols.InOrder(fld.Name) && !tv.Cols.IsHidden(fld) {
+			tv.VisFields = append(tv.VisFields, fld)
+		}
+	}
 	tv.NVisFields = len(tv.VisFields)
 }
 
@@ -213,7 +289,10 @@ func (tv *TableView) CacheVisFields() {
 func (tv *TableView) StdFrameConfig() kit.TypeAndNameList {
 	config := kit.TypeAndNameList{}
 	config.Add(gi.KiT_ToolBar, "toolbar")
-	config.Add(gi.KiT_Frame, "slice-frame")
+	if tv.ShowFilter {
+		config.Add(gi.KiT_TextField, "filter")
+	}
+	config.Add(KiT_TableViewFrame, "slice-frame")
 	return config
 }
 
@@ -234,17 +313,37 @@ func (tv *TableView) SliceFrame() (*gi.Frame, int) {
 	if !ok {
 		return nil, -1
 	}
-	return tv.KnownChild(idx).(*gi.Frame), idx
+	return &tv.KnownChild(idx).(*TableViewFrame).Frame, idx
+}
+
+// SliceGridLay returns the layout containing the SliceGrid and, in Virtual
+// mode, its ScrollBar, within SliceFrame
+func (tv *TableView) SliceGridLay() *gi.Layout {
+	sf, _ := tv.SliceFrame()
+	if sf == nil {
+		return nil
+	}
+	return sf.KnownChild(1).(*gi.Layout)
 }
 
 // SliceGrid returns the SliceGrid grid frame widget, which contains all the
 // fields and values, within SliceFrame
 func (tv *TableView) SliceGrid() *gi.Frame {
-	sf, _ := tv.SliceFrame()
-	if sf == nil {
+	sgl := tv.SliceGridLay()
+	if sgl == nil {
 		return nil
 	}
-	return sf.KnownChild(1).(*gi.Frame)
+	return &sgl.KnownChild(0).(*TableViewFrame).Frame
+}
+
+// ScrollBar returns the scrollbar for the rows in Virtual mode -- nil if not
+// Virtual
+func (tv *TableView) ScrollBar() *gi.ScrollBar {
+	sgl := tv.SliceGridLay()
+	if sgl == nil || len(sgl.Kids) < 2 {
+		return nil
+	}
+	return sgl.KnownChild(1).(*gi.ScrollBar)
 }
 
 // SliceHeader returns the Toolbar header for slice grid
@@ -256,6 +355,15 @@ func (tv *TableView) SliceHeader() *gi.ToolBar {
 	return sf.KnownChild(0).(*gi.ToolBar)
 }
 
+// FilterField returns the filter text field -- nil if not ShowFilter
+func (tv *TableView) FilterField() *gi.TextField {
+	idx, ok := tv.Children().IndexByName("filter", 0)
+	if !ok {
+		return nil
+	}
+	return tv.KnownChild(idx).(*gi.TextField)
+}
+
 // ToolBar returns the toolbar widget
 func (tv *TableView) ToolBar() *gi.ToolBar {
 	idx, ok := tv.Children().IndexByName("toolbar", 0)
@@ -269,7 +377,18 @@ func (tv *TableView) ToolBar() *gi.ToolBar {
 func (tv *TableView) StdSliceFrameConfig() kit.TypeAndNameList {
 	config := kit.TypeAndNameList{}
 	config.Add(gi.KiT_ToolBar, "header")
-	config.Add(gi.KiT_Frame, "grid")
+	config.Add(gi.KiT_Layout, "grid-lay")
+	return config
+}
+
+// StdGridLayConfig returns a TypeAndNameList for configuring the grid-lay,
+// with the scrollbar for the rows in Virtual mode
+func (tv *TableView) StdGridLayConfig() kit.TypeAndNameList {
+	config := kit.TypeAndNameList{}
+	config.Add(KiT_TableViewFrame, "grid")
+	if tv.Virtual {
+		config.Add(gi.KiT_ScrollBar, "scrollbar")
+	}
 	return config
 }
 
@@ -303,13 +422,31 @@ func (tv *TableView) ConfigSliceGrid(forceUpdt bool) {
 	tv.BuiltSize = sz
 
 	tv.CacheVisFields()
+	tv.SortSlice()
+	tv.FilterRows()
+	nd := tv.NDispRows()
+
+	tv.Virtual = nd > TableViewVirtualSize
+	if vp, ok := tv.Prop("virtual"); ok {
+		tv.Virtual, _ = kit.ToBool(vp)
+	}
+	if tv.Virtual {
+		if tv.NVisible == 0 {
+			tv.NVisible = TableViewVirtualRows - TableViewVirtualMargin
+		}
+		tv.VisRows = ints.MinInt(tv.NVisible+TableViewVirtualMargin, nd)
+		tv.StartIdx = tv.ClampStartIdx(tv.StartIdx)
+	} else {
+		tv.VisRows = nd
+		tv.StartIdx = 0
+	}
 
 	nWidgPerRow, idxOff := tv.RowWidgetNs()
 
 	// always start fresh!
 	tv.Values = make([][]ValueView, tv.NVisFields)
 	for fli := 0; fli < tv.NVisFields; fli++ {
-		tv.Values[fli] = make([]ValueView, sz)
+		tv.Values[fli] = make([]ValueView, tv.VisRows)
 	}
 
 	sg, _ := tv.SliceFrame()
@@ -340,6 +477,13 @@ func (tv *TableView) ConfigSliceGrid(forceUpdt bool) {
 	sgh.SetProp("spacing", 0)
 	// sgh.SetStretchMaxWidth()
 
+	sgl := tv.SliceGridLay()
+	sgl.Lay = gi.LayoutHoriz
+	sgl.SetProp("spacing", 0)
+	sgl.SetStretchMaxHeight() // for this to work, ALL layers above need it too
+	sgl.SetStretchMaxWidth()  // for this to work, ALL layers above need it too
+	sgl.ConfigChildren(tv.StdGridLayConfig(), false)
+
 	sgf := tv.SliceGrid()
 	sgf.Lay = gi.LayoutGrid
 	sgf.Stripes = gi.RowStripes
@@ -349,6 +493,12 @@ func (tv *TableView) ConfigSliceGrid(forceUpdt bool) {
 	sgf.SetStretchMaxHeight() // for this to work, ALL layers above need it too
 	sgf.SetStretchMaxWidth()  // for this to work, ALL layers above need it too
 	sgf.SetProp("columns", nWidgPerRow)
+	if tv.Virtual {
+		sgf.SetProp("overflow", "hidden") // rows are scrolled by our own scrollbar
+		tv.ConfigScrollBar()
+	} else {
+		sgf.DeleteProp("overflow")
+	}
 
 	// Configure Header
 	hcfg := kit.TypeAndNameList{}
@@ -379,26 +529,23 @@ func (tv *TableView) ConfigSliceGrid(forceUpdt bool) {
 		fld := tv.VisFields[fli]
 		hdr := sgh.KnownChild(idxOff + fli).(*gi.Action)
 		hdr.SetText(fld.Name)
-		if fli == tv.SortIdx {
-			if tv.SortDesc {
-				hdr.SetIcon("widget-wedge-down")
-			} else {
-				hdr.SetIcon("widget-wedge-up")
-			}
-		}
 		hdr.Data = fli
-		hdr.Tooltip = "click to sort / toggle sort direction by this column"
+		hdr.Tooltip = "click to sort / toggle sort direction by this column, shift-click to also sort by it"
 		dsc := fld.Tag.Get("desc")
 		if dsc != "" {
 			hdr.Tooltip += ": " + dsc
 		}
 		hdr.ActionSig.ConnectOnly(tv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
 			tvv := recv.Embed(KiT_TableView).(*TableView)
+			if tvv.colDrag != nil && tvv.colDrag.moved { // end of resizing or moving the column
+				return
+			}
 			act := send.(*gi.Action)
 			fldIdx := act.Data.(int)
 			tvv.SortSliceAction(fldIdx)
 		})
 	}
+	tv.UpdateSortIcons()
 	if !tv.IsInactive() {
 		lbl := sgh.KnownChild(tv.NVisFields + idxOff).(*gi.Label)
 		lbl.Text = "+"
@@ -407,13 +554,19 @@ func (tv *TableView) ConfigSliceGrid(forceUpdt bool) {
 		lbl.Text = "-"
 		lbl.Tooltip = "delete row"
 	}
+	tv.ConfigFilterField()
 
 	sgf.DeleteChildren(true)
-	sgf.Kids = make(ki.Slice, nWidgPerRow*sz)
+	sgf.Kids = make(ki.Slice, nWidgPerRow*tv.VisRows)
 
-	if tv.SortIdx >= 0 {
-		rawIdx := tv.VisFields[tv.SortIdx].Index
-		kit.StructSliceSort(tv.Slice, rawIdx, !tv.SortDesc)
+	if tv.Virtual { // start with the selected row in view
+		if tv.SelField != "" && tv.SelVal != nil {
+			tv.SelectedIdx, _ = StructSliceRowByValue(tv.Slice, tv.SelField, tv.SelVal)
+		}
+		if di, ok := tv.DispIdx(tv.SelectedIdx); ok {
+			tv.StartIdx = tv.StartIdxForRow(di)
+		}
+		tv.UpdateScrollBar()
 	}
 	tv.ConfigSliceGridRows()
 
@@ -424,13 +577,14 @@ func (tv *TableView) ConfigSliceGrid(forceUpdt bool) {
 
 // ConfigSliceGridRows configures the SliceGrid rows for the current slice --
 // assumes .Kids is created at the right size -- only call this for a direct
-// re-render e.g., after sorting
+// re-render e.g., after sorting -- in Virtual mode, the rows of widgets are
+// (re)bound to the rows of the slice starting at StartIdx
 func (tv *TableView) ConfigSliceGridRows() {
 	mv := reflect.ValueOf(tv.Slice)
 	mvnp := kit.NonPtrValue(mv)
-	sz := mvnp.Len()
+	nd := tv.NDispRows()
 
-	if sz > TableViewWaitCursorSize {
+	if tv.VisRows > TableViewWaitCursorSize {
 		oswin.TheApp.Cursor(tv.Viewport.Win.OSWin).Push(cursor.Wait)
 		defer oswin.TheApp.Cursor(tv.Viewport.Win.OSWin).Pop()
 	}
@@ -441,23 +595,37 @@ func (tv *TableView) ConfigSliceGridRows() {
 	updt := sgf.UpdateStart()
 	defer sgf.UpdateEnd(updt)
 
-	for i := 0; i < sz; i++ {
-		ridx := i * nWidgPerRow
+	for wi := 0; wi < tv.VisRows; wi++ {
+		di := tv.StartIdx + wi
+		if di >= nd {
+			for fli := range tv.Values {
+				tv.Values[fli][wi] = nil // blank rows, see ConfigBlankRows
+			}
+			continue
+		}
+		i := tv.SliceIdx(di)
+		ridx := wi * nWidgPerRow
 		val := kit.OnePtrValue(mvnp.Index(i)) // deal with pointer lists
 		stru := val.Interface()
+		sel := i == tv.SelectedIdx // inactive mode only has one selected
+		if !tv.IsInactive() {
+			sel = tv.RowIsSelected(i)
+		}
 		idxtxt := fmt.Sprintf("%05d", i)
 		labnm := fmt.Sprintf("index-%v", idxtxt)
 		if tv.ShowIndex {
 			var idxlab *gi.Label
 			if sgf.Kids[ridx] != nil {
 				idxlab = sgf.Kids[ridx].(*gi.Label)
+				idxlab.SetText(idxtxt)
 			} else {
 				idxlab = &gi.Label{}
 				sgf.SetChild(idxlab, ridx, labnm)
+				idxlab.Text = idxtxt
 			}
-			idxlab.Text = idxtxt
 			idxlab.SetProp("tv-index", i)
 			idxlab.Selectable = true
+			idxlab.SetSelectedState(sel)
 			idxlab.WidgetSig.ConnectOnly(tv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
 				if sig == int64(gi.WidgetSelected) {
 					wbb := send.(gi.Node2D).AsWidget()
@@ -476,22 +644,33 @@ func (tv *TableView) ConfigSliceGridRows() {
 				continue
 			}
 			vv.SetStructValue(fval.Addr(), stru, &field, tv.TmpSave)
+			slc, row, fidx := tv.Slice, i, field.Index[0]
+			vv.AsValueViewBase().UndoLoc = func() reflect.Value { return TableViewFieldLoc(slc, row, fidx) }
+			tv.Values[fli][wi] = vv
 			vtyp := vv.WidgetType()
 			valnm := fmt.Sprintf("value-%v.%v", fli, idxtxt)
 			cidx := ridx + idxOff + fli
 			var widg gi.Node2D
-			if sgf.Kids[cidx] != nil {
+			if sgf.Kids[cidx] != nil && sgf.Kids[cidx].Type() == vtyp {
 				widg = sgf.Kids[cidx].(gi.Node2D)
-			} else {
-				tv.Values[fli][i] = vv
+			} else { // new, or a re-used row needs a different type of widget
 				widg = ki.NewOfType(vtyp).(gi.Node2D)
 				sgf.SetChild(widg, cidx, valnm)
+				sgf.SetFullReRender()
 			}
 			vv.ConfigWidget(widg)
 			wb := widg.AsWidget()
 			if wb != nil {
 				wb.SetProp("tv-index", i)
+				wb.SetProp("text-overflow", "ellipsis") // long values end in … instead of being cut off
+				if wd, ok := tv.Cols.Widths[field.Name]; ok {
+					wb.SetFixedWidth(units.NewValue(wd, units.Dot))
+				}
+				if k := field.Type.Kind(); k >= reflect.Int && k <= reflect.Float64 {
+					wb.SetProp("font-feature-settings", `"tnum"`) // digits line up in columns
+				}
 				wb.ClearSelected()
+				wb.SetSelectedState(sel)
 				wb.WidgetSig.ConnectOnly(tv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
 					if sig == int64(gi.WidgetSelected) || sig == int64(gi.WidgetFocused) {
 						wbb := send.(gi.Node2D).AsWidget()
@@ -512,35 +691,14 @@ func (tv *TableView) ConfigSliceGridRows() {
 						tvv, _ := recv.Embed(KiT_TableView).(*TableView)
 						tvv.SetChanged()
 					})
-
-				addnm := fmt.Sprintf("add-%v", idxtxt)
-				delnm := fmt.Sprintf("del-%v", idxtxt)
-				addact := gi.Action{}
-				delact := gi.Action{}
-				sgf.SetChild(&addact, ridx+1+tv.NVisFields, addnm)
-				sgf.SetChild(&delact, ridx+1+tv.NVisFields+1, delnm)
-
-				addact.SetIcon("plus")
-				addact.Tooltip = "insert a new element at this index"
-				addact.Data = i
-				addact.ActionSig.ConnectOnly(tv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
-					act := send.(*gi.Action)
-					tvv := recv.Embed(KiT_TableView).(*TableView)
-					tvv.SliceNewAt(act.Data.(int)+1, true)
-				})
-				delact.SetIcon("minus")
-				delact.Tooltip = "delete this element"
-				delact.Data = i
-				delact.ActionSig.ConnectOnly(tv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
-					act := send.(*gi.Action)
-					tvv := recv.Embed(KiT_TableView).(*TableView)
-					tvv.SliceDelete(act.Data.(int), true)
-				})
 			}
 			if tv.StyleFunc != nil {
 				tv.StyleFunc(tv, mvnp.Interface(), widg, i, fli, vv)
 			}
 		}
+		if !tv.IsInactive() {
+			tv.ConfigRowActions(ridx+idxOff+tv.NVisFields, i, idxtxt)
+		}
 	}
 	if tv.SelField != "" && tv.SelVal != nil {
 		tv.SelectedIdx, _ = StructSliceRowByValue(tv.Slice, tv.SelField, tv.SelVal)
@@ -548,6 +706,217 @@ func (tv *TableView) ConfigSliceGridRows() {
 	if tv.IsInactive() && tv.SelectedIdx >= 0 {
 		tv.SelectRow(tv.SelectedIdx)
 	}
+	if tv.Virtual {
+		tv.ConfigBlankRows()
+	}
+}
+
+// ConfigRowActions configures the insert and delete actions at given index
+// of the SliceGrid children, for slice index i -- they are made once per row
+// of widgets, and a row that is re-bound to another element only updates
+// their Data
+func (tv *TableView) ConfigRowActions(cidx, i int, idxtxt string) {
+	sgf := tv.SliceGrid()
+	if addact, ok := sgf.Kids[cidx].(*gi.Action); ok {
+		addact.Data = i
+		sgf.Kids[cidx+1].(*gi.Action).Data = i
+		return
+	}
+	for ci := cidx; ci < cidx+2; ci++ { // e.g., blank labels, see ConfigBlankRows
+		if sgf.Kids[ci] != nil {
+			sgf.Kids[ci].Destroy()
+			sgf.Kids[ci] = nil
+		}
+	}
+	addact := gi.Action{}
+	delact := gi.Action{}
+	sgf.SetChild(&addact, cidx, fmt.Sprintf("add-%v", idxtxt))
+	sgf.SetChild(&delact, cidx+1, fmt.Sprintf("del-%v", idxtxt))
+	sgf.SetFullReRender()
+
+	addact.SetIcon("plus")
+	addact.Tooltip = "insert a new element at this index"
+	addact.Data = i
+	addact.ActionSig.ConnectOnly(tv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+		act := send.(*gi.Action)
+		tvv := recv.Embed(KiT_TableView).(*TableView)
+		tvv.SliceNewAt(act.Data.(int)+1, true)
+	})
+	delact.SetIcon("minus")
+	delact.Tooltip = "delete this element"
+	delact.Data = i
+	delact.ActionSig.ConnectOnly(tv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+		act := send.(*gi.Action)
+		tvv := recv.Embed(KiT_TableView).(*TableView)
+		tvv.SliceDelete(act.Data.(int), true)
+	})
+	if sgf.Viewport != nil { // re-bound after the grid was initialized, e.g., when scrolling
+		addact.Init2DTree()
+		addact.Style2DTree()
+		delact.Init2DTree()
+		delact.Style2DTree()
+	}
+}
+
+//////////////////////////////////////////////////////////////////////////////
+//  Virtual mode
+
+// ConfigBlankRows makes the rows of widgets past the end of the slice
+// invisible in Virtual mode, filling any that have no widgets with blank
+// labels
+func (tv *TableView) ConfigBlankRows() {
+	sgf := tv.SliceGrid()
+	nWidgPerRow, _ := tv.RowWidgetNs()
+	nw := nWidgPerRow * ints.MaxInt(tv.NDispRows()-tv.StartIdx, 0)
+	for ci, kid := range sgf.Kids {
+		if kid == nil {
+			kid = &gi.Label{}
+			sgf.SetChild(kid, ci, fmt.Sprintf("blank-%v", ci))
+		}
+		kid.(gi.Node2D).AsNode2D().SetInvisibleState(ci >= nw)
+	}
+}
+
+// ClampStartIdx returns given StartIdx limited to the range where the
+// visible rows are within the rows shown
+func (tv *TableView) ClampStartIdx(idx int) int {
+	return ints.MaxInt(ints.MinInt(idx, tv.NDispRows()-tv.NVisible), 0)
+}
+
+// StartIdxForRow returns the StartIdx needed for given displayed row (see
+// DispIdx) to be visible in Virtual mode, changing the current StartIdx as
+// little as possible
+func (tv *TableView) StartIdxForRow(di int) int {
+	st := tv.StartIdx
+	if di < st {
+		st = di
+	} else if di >= st+tv.NVisible {
+		st = di - tv.NVisible + 1
+	}
+	return tv.ClampStartIdx(st)
+}
+
+// SetStartIdx sets the first row shown in Virtual mode, re-using the rows of
+// widgets for the rows starting there -- returns true if changed
+func (tv *TableView) SetStartIdx(idx int) bool {
+	idx = tv.ClampStartIdx(idx)
+	if !tv.Virtual || idx == tv.StartIdx {
+		return false
+	}
+	tv.StartIdx = idx
+	tv.UpdateScrollBar()
+	tv.ConfigSliceGridRows()
+	tv.ConfigBlankRows()
+	return true
+}
+
+// ConfigScrollBar configures the ScrollBar for the rows in Virtual mode,
+// whose values are row indexes
+func (tv *TableView) ConfigScrollBar() {
+	sb := tv.ScrollBar()
+	if sb == nil {
+		return
+	}
+	sb.Defaults()
+	sb.Dim = gi.Y
+	sb.Tracking = true
+	sb.TrackThr = 1
+	sb.Step = 1
+	sb.SetFixedWidth(tv.Sty.Layout.ScrollBarWidth)
+	sb.SetStretchMaxHeight()
+	tv.UpdateScrollBar()
+	sb.SliderSig.ConnectOnly(tv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig != int64(gi.SliderValueChanged) {
+			return
+		}
+		tvv := recv.Embed(KiT_TableView).(*TableView)
+		tvv.SetStartIdx(int(data.(float32) + 0.5))
+	})
+}
+
+// UpdateScrollBar updates the range, thumb and value of the ScrollBar from
+// the number of rows, NVisible and StartIdx
+func (tv *TableView) UpdateScrollBar() {
+	sb := tv.ScrollBar()
+	if sb == nil {
+		return
+	}
+	updt := sb.UpdateStart()
+	sb.Min = 0
+	sb.Max = float32(tv.NDispRows())
+	sb.PageStep = float32(ints.MaxInt(tv.NVisible-1, 1))
+	sb.SetThumbValue(float32(tv.NVisible))
+	sb.SetValue(float32(tv.StartIdx))
+	sb.UpdateEnd(updt)
+}
+
+// LayoutVirtual updates NVisible from the row height and the size of the
+// grid in Virtual mode, and reconfigures the rows of widgets if a different
+// number is needed -- returns true if the layout needs to be redone
+func (tv *TableView) LayoutVirtual() bool {
+	sgf := tv.SliceGrid()
+	if len(sgf.GridData[gi.Row]) == 0 {
+		return false
+	}
+	rht := sgf.GridData[gi.Row][0].AllocSize
+	if rht <= 0 {
+		return false
+	}
+	tv.RowHeight = rht
+	avail := sgf.LayData.AllocSize.Y - 2*sgf.Sty.BoxSpace()
+	nvis := ints.MaxInt(int(avail/rht), 1)
+	if nvis == tv.NVisible {
+		return false
+	}
+	tv.NVisible = nvis
+	stidx := tv.StartIdx
+	tv.StartIdx = tv.ClampStartIdx(tv.StartIdx)
+	tv.UpdateScrollBar()
+	visRows := ints.MinInt(nvis+TableViewVirtualMargin, tv.NDispRows())
+	if visRows == tv.VisRows {
+		if tv.StartIdx != stidx {
+			tv.ConfigSliceGridRows()
+			tv.ConfigBlankRows()
+		}
+		return false
+	}
+	tv.VisRows = visRows
+	nWidgPerRow, _ := tv.RowWidgetNs()
+	nw := nWidgPerRow * visRows
+	if nw < len(sgf.Kids) {
+		for _, kid := range sgf.Kids[nw:] {
+			kid.Destroy()
+		}
+		sgf.Kids = sgf.Kids[:nw]
+	} else {
+		sgf.Kids = append(sgf.Kids, make(ki.Slice, nw-len(sgf.Kids))...)
+	}
+	for fli := range tv.Values {
+		tv.Values[fli] = make([]ValueView, visRows)
+	}
+	tv.ConfigSliceGridRows()
+	sgf.Init2DTree()
+	sgf.Style2DTree()
+	tv.ConfigBlankRows()
+	return true
+}
+
+// ScrollVirtual processes a mouse scroll event in Virtual mode, scrolling by
+// rows according to the vertical delta
+func (tv *TableView) ScrollVirtual(me *mouse.ScrollEvent) {
+	del := me.Delta.Y
+	if del == 0 || tv.RowHeight <= 0 {
+		return
+	}
+	nr := int(float32(del) / tv.RowHeight)
+	if nr == 0 {
+		nr = 1
+		if del < 0 {
+			nr = -1
+		}
+	}
+	tv.SetStartIdx(tv.StartIdx + nr)
+	me.SetProcessed()
 }
 
 // SetChanged sets the Changed flag and emits the ViewSig signal for the
@@ -566,7 +935,9 @@ func (tv *TableView) SliceNewAt(idx int, reconfig bool) {
 	updt := tv.UpdateStart()
 	defer tv.UpdateEnd(updt)
 
+	before := ValueUndoCopy(kit.NonPtrValue(reflect.ValueOf(tv.Slice)))
 	kit.SliceNewAt(tv.Slice, idx)
+	tv.SaveUndo("Insert", before)
 
 	if tv.TmpSave != nil {
 		tv.TmpSave.SaveTmp()
@@ -587,7 +958,9 @@ func (tv *TableView) SliceDelete(idx int, reconfig bool) {
 	updt := tv.UpdateStart()
 	defer tv.UpdateEnd(updt)
 
+	before := ValueUndoCopy(kit.NonPtrValue(reflect.ValueOf(tv.Slice)))
 	kit.SliceDeleteAt(tv.Slice, idx)
+	tv.SaveUndo("Delete", before)
 
 	if tv.TmpSave != nil {
 		tv.TmpSave.SaveTmp()
@@ -599,48 +972,72 @@ func (tv *TableView) SliceDelete(idx int, reconfig bool) {
 	tv.ViewSig.Emit(tv.This, 0, nil)
 }
 
+// TableViewFieldLoc returns the current location of the field at given index
+// in the element at given row of given slice (a pointer to it), e.g., for
+// undo of edits of the field, which moves when the slice is re-allocated --
+// invalid if the row no longer exists
+func TableViewFieldLoc(slc interface{}, row, fidx int) reflect.Value {
+	sv := kit.NonPtrValue(reflect.ValueOf(slc))
+	if row >= sv.Len() {
+		return reflect.Value{}
+	}
+	return kit.OnePtrValue(sv.Index(row)).Elem().Field(fidx)
+}
+
+// SaveUndo saves the change to the slice from before, a ValueUndoCopy of it
+// from before the change, in the ValueUndo of the window
+func (tv *TableView) SaveUndo(desc string, before reflect.Value) {
+	SaveValueUndo(tv.This.(gi.Node2D), desc, kit.NonPtrValue(reflect.ValueOf(tv.Slice)), before, func() {
+		if tv.TmpSave != nil {
+			tv.TmpSave.SaveTmp()
+		}
+		tv.ConfigSliceGrid(true)
+		tv.SetChanged()
+	})
+}
+
 // SortSliceAction sorts the slice for given field index -- toggles ascending
-// vs. descending if already sorting on this dimension
+// vs. descending if already sorting on this dimension -- if shift is held
+// down (the last select mode is ExtendContinuous), the field is added to the
+// SortKeys, or its direction toggled if already there, instead of replacing
+// them
 func (tv *TableView) SortSliceAction(fldIdx int) {
 	oswin.TheApp.Cursor(tv.Viewport.Win.OSWin).Push(cursor.Wait)
 	defer oswin.TheApp.Cursor(tv.Viewport.Win.OSWin).Pop()
 
-	sgh := tv.SliceHeader()
-	sgh.SetFullReRender()
-	idxOff := 1
-	if !tv.ShowIndex {
-		idxOff = 0
-	}
-
-	ascending := true
-
-	for fli := 0; fli < tv.NVisFields; fli++ {
-		hdr := sgh.KnownChild(idxOff + fli).(*gi.Action)
-		if fli == fldIdx {
-			if tv.SortIdx == fli {
-				tv.SortDesc = !tv.SortDesc
-				ascending = !tv.SortDesc
-			} else {
-				tv.SortDesc = false
-			}
-			if ascending {
-				hdr.SetIcon("widget-wedge-up")
-			} else {
-				hdr.SetIcon("widget-wedge-down")
-			}
-		} else {
-			hdr.SetIcon("none")
+	extend := false
+	if win := tv.ParentWindow(); win != nil {
+		extend = win.LastSelMode == mouse.ExtendContinuous
+	}
+	keys := append([]TableViewSortKey(nil), tv.SortKeys...)
+	ski := -1
+	for i, sk := range tv.SortKeys {
+		if sk.FieldIdx == fldIdx {
+			ski = i
+			break
 		}
 	}
+	switch {
+	case extend && ski >= 0:
+		tv.SortKeys[ski].Desc = !tv.SortKeys[ski].Desc
+	case extend:
+		tv.SortKeys = append(tv.SortKeys, TableViewSortKey{FieldIdx: fldIdx})
+	default:
+		desc := ski == 0 && !tv.SortKeys[0].Desc
+		tv.SortKeys = []TableViewSortKey{{FieldIdx: fldIdx, Desc: desc}}
+	}
+	tv.saveSortKeysUndo(keys)
 
-	tv.SortIdx = fldIdx
-	rawIdx := tv.VisFields[fldIdx].Index
-
+	sgh := tv.SliceHeader()
+	sgh.SetFullReRender()
 	sgf := tv.SliceGrid()
 	sgf.SetFullReRender()
 
-	kit.StructSliceSort(tv.Slice, rawIdx, !tv.SortDesc)
+	tv.SortSlice()
+	tv.UpdateSortIcons()
+	tv.FilterRows() // rows have moved
 	tv.ConfigSliceGridRows()
+	tv.SaveStatePrefs()
 }
 
 // ConfigToolbar configures the toolbar actions
@@ -673,18 +1070,23 @@ func (tv *TableView) ConfigToolbar() {
 }
 
 // SortFieldName returns the name of the field being sorted, along with :up or
-// :down depending on descending
+// :down depending on descending -- for multiple SortKeys, the fields are
+// separated by commas
 func (tv *TableView) SortFieldName() string {
-	if tv.SortIdx >= 0 && tv.SortIdx < tv.NVisFields {
-		nm := tv.VisFields[tv.SortIdx].Name
-		if tv.SortDesc {
+	var nms []string
+	for _, sk := range tv.SortKeys {
+		if sk.FieldIdx < 0 || sk.FieldIdx >= tv.NVisFields {
+			continue
+		}
+		nm := tv.VisFields[sk.FieldIdx].Name
+		if sk.Desc {
 			nm += ":down"
 		} else {
 			nm += ":up"
 		}
-		return nm
+		nms = append(nms, nm)
 	}
-	return ""
+	return strings.Join(nms, ",")
 }
 
 // SetSortField sets sorting to happen on given field and direction -- see
@@ -693,20 +1095,457 @@ func (tv *TableView) SetSortFieldName(nm string) {
 	if nm == "" {
 		return
 	}
-	spnm := strings.Split(nm, ":")
+	if tv.NVisFields == 0 {
+		tv.CacheVisFields()
+	}
+	tv.SortKeys = nil
+	for _, fnm := range strings.Split(nm, ",") {
+		spnm := strings.Split(strings.TrimSpace(fnm), ":")
+		for fli := 0; fli < tv.NVisFields; fli++ {
+			fld := tv.VisFields[fli]
+			if fld.Name == spnm[0] {
+				sk := TableViewSortKey{FieldIdx: fli}
+				if len(spnm) == 2 && spnm[1] == "down" {
+					sk.Desc = true
+				}
+				tv.SortKeys = append(tv.SortKeys, sk)
+				break
+			}
+		}
+	}
+	tv.SortIdx = -1
+	tv.SortDesc = false
+	if len(tv.SortKeys) > 0 {
+		tv.SortIdx = tv.SortKeys[0].FieldIdx
+		tv.SortDesc = tv.SortKeys[0].Desc
+	}
+}
+
+//////////////////////////////////////////////////////////////////////////////
+//  Sorting and filtering
+
+// NDispRows returns the number of rows shown, which is less than the length
+// of the slice when filtered
+func (tv *TableView) NDispRows() int {
+	if tv.Indexes != nil {
+		return len(tv.Indexes)
+	}
+	return tv.BuiltSize
+}
+
+// SliceIdx returns the index in the slice of given displayed row, which is
+// the position among the rows shown
+func (tv *TableView) SliceIdx(di int) int {
+	if tv.Indexes != nil {
+		return tv.Indexes[di]
+	}
+	return di
+}
+
+// DispIdx returns the displayed row, i.e., the position among the rows
+// shown, of given row of the slice -- false if out of range or filtered out,
+// in which case the position of the next row shown is returned
+func (tv *TableView) DispIdx(row int) (int, bool) {
+	if tv.Indexes == nil {
+		return row, row >= 0 && row < tv.BuiltSize
+	}
+	di := sort.SearchInts(tv.Indexes, row) // in order, as the slice is sorted
+	return di, di < len(tv.Indexes) && tv.Indexes[di] == row
+}
+
+// NextDispRow returns the row of the slice that is shown given number of
+// rows after (or before, if negative) given row -- -1 if none
+func (tv *TableView) NextDispRow(row, n int) int {
+	di, ok := tv.DispIdx(row)
+	if !ok && n > 0 {
+		di-- // di is the next row shown
+	}
+	di += n
+	if di < 0 || di >= tv.NDispRows() {
+		return -1
+	}
+	return tv.SliceIdx(di)
+}
+
+// SortSlice sorts the slice according to the SortKeys, in a stable way so
+// rows equal in all the fields keep their order -- the selected rows are
+// updated to where their elements have moved -- a change of the order is
+// saved for undo, so that the rows of other changes stay valid, and the
+// slice is not sorted during undo / redo, which restore the order
+func (tv *TableView) SortSlice() {
+	if len(tv.SortKeys) == 0 && tv.SortIdx >= 0 { // set directly
+		tv.SortKeys = []TableViewSortKey{{FieldIdx: tv.SortIdx, Desc: tv.SortDesc}}
+	}
+	tv.SortIdx = -1
+	tv.SortDesc = false
+	if len(tv.SortKeys) == 0 {
+		return
+	}
+	tv.SortIdx = tv.SortKeys[0].FieldIdx
+	tv.SortDesc = tv.SortKeys[0].Desc
+	if vu := ValueUndoFor(tv.This.(gi.Node2D)); vu != nil && vu.applying {
+		return
+	}
+	mvnp := kit.NonPtrValue(reflect.ValueOf(tv.Slice))
+	sz := mvnp.Len()
+	perm := make([]int, sz)
+	for i := range perm {
+		perm[i] = i
+	}
+	sort.SliceStable(perm, func(i, j int) bool {
+		vi := kit.NonPtrValue(mvnp.Index(perm[i]))
+		vj := kit.NonPtrValue(mvnp.Index(perm[j]))
+		for _, sk := range tv.SortKeys {
+			if sk.FieldIdx < 0 || sk.FieldIdx >= tv.NVisFields {
+				continue
+			}
+			fi := tv.VisFields[sk.FieldIdx].Index[0]
+			cmp := CompareValues(vi.Field(fi), vj.Field(fi))
+			if cmp != 0 {
+				return (cmp < 0) != sk.Desc
+			}
+		}
+		return false
+	})
+	moved := false
+	for i, pi := range perm {
+		if pi != i {
+			moved = true
+			break
+		}
+	}
+	if !moved {
+		return
+	}
+	before := ValueUndoCopy(mvnp)
+	srt := reflect.MakeSlice(mvnp.Type(), sz, sz)
+	newIdx := make([]int, sz)
+	for i, pi := range perm {
+		srt.Index(i).Set(mvnp.Index(pi))
+		newIdx[pi] = i
+	}
+	reflect.Copy(mvnp, srt)
+	tv.SaveUndo("Sort", before)
+	if tv.SelectedIdx >= 0 && tv.SelectedIdx < sz {
+		tv.SelectedIdx = newIdx[tv.SelectedIdx]
+	}
+	selRows := make(map[int]bool, len(tv.SelectedRows))
+	for r := range tv.SelectedRows {
+		if r < sz {
+			selRows[newIdx[r]] = true
+		}
+	}
+	tv.SelectedRows = selRows
+}
+
+// saveSortKeysUndo saves the change of the SortKeys from keys for undo, in
+// the same group as the sort of the slice that follows it
+func (tv *TableView) saveSortKeysUndo(keys []TableViewSortKey) {
+	after := append([]TableViewSortKey(nil), tv.SortKeys...)
+	ValueUndoFor(tv.This.(gi.Node2D)).SaveUndo(&ValueUndoRec{
+		Desc: "Sort",
+		Undo: func() {
+			tv.SortKeys = keys
+			tv.SortIdx = -1 // from SortKeys, see SortSlice
+		},
+		Redo: func() {
+			tv.SortKeys = after
+			tv.SortIdx = -1
+		},
+		Update: func() {
+			tv.UpdateSortIcons()
+			tv.SaveStatePrefs()
+		},
+	})
+}
+
+// UpdateSortIcons updates the header icons showing the SortKeys and their
+// directions
+func (tv *TableView) UpdateSortIcons() {
+	sgh := tv.SliceHeader()
+	if sgh == nil {
+		return
+	}
+	_, idxOff := tv.RowWidgetNs()
 	for fli := 0; fli < tv.NVisFields; fli++ {
-		fld := tv.VisFields[fli]
-		if fld.Name == spnm[0] {
-			tv.SortIdx = fli
+		hdr := sgh.KnownChild(idxOff + fli).(*gi.Action)
+		icnm := gi.IconName("none")
+		for _, sk := range tv.SortKeys {
+			if sk.FieldIdx == fli {
+				if sk.Desc {
+					icnm = "widget-wedge-down"
+				} else {
+					icnm = "widget-wedge-up"
+				}
+				break
+			}
 		}
+		hdr.SetIcon(string(icnm))
 	}
-	if len(spnm) == 2 {
-		if spnm[1] == "down" {
-			tv.SortDesc = true
-		} else {
-			tv.SortDesc = false
+}
+
+// tableViewFilterTerm is one term of a TableView Filter expression
+type tableViewFilterTerm struct {
+	fldIdx int    // index in VisFields, -1 for any field
+	op     string // comparison operator, or "" to match text within the field
+	val    string
+}
+
+// tableViewFilterOps are the comparison operators in Filter terms, longest
+// first
+var tableViewFilterOps = []string{">=", "<=", "!=", ">", "<", "="}
+
+// FilterTerms parses the Filter expression into its terms
+func (tv *TableView) FilterTerms() []tableViewFilterTerm {
+	var terms []tableViewFilterTerm
+	for _, fs := range strings.Fields(tv.Filter) {
+		term := tableViewFilterTerm{fldIdx: -1, val: fs}
+		if ci := strings.Index(fs, ":"); ci > 0 {
+			for fli := 0; fli < tv.NVisFields; fli++ {
+				if strings.EqualFold(tv.VisFields[fli].Name, fs[:ci]) {
+					term.fldIdx = fli
+					term.val = fs[ci+1:]
+					break
+				}
+			}
+		}
+		if term.fldIdx >= 0 {
+			for _, op := range tableViewFilterOps {
+				if strings.HasPrefix(term.val, op) {
+					term.op = op
+					term.val = term.val[len(op):]
+					break
+				}
+			}
+		}
+		if term.op == "" {
+			term.val = strings.ToLower(term.val)
+		}
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// RowPassesFilter returns true if given row of the slice matches all of the
+// Filter terms, and passes the FilterFunc if set
+func (tv *TableView) RowPassesFilter(row int, terms []tableViewFilterTerm) bool {
+	val := kit.NonPtrValue(kit.NonPtrValue(reflect.ValueOf(tv.Slice)).Index(row))
+	if !val.IsValid() {
+		return len(terms) == 0 && (tv.FilterFunc == nil || tv.FilterFunc(tv, row))
+	}
+	for _, term := range terms {
+		if term.fldIdx < 0 {
+			match := false
+			for fli := 0; fli < tv.NVisFields; fli++ {
+				fval := val.Field(tv.VisFields[fli].Index[0])
+				if strings.Contains(strings.ToLower(kit.ToString(fval.Interface())), term.val) {
+					match = true
+					break
+				}
+			}
+			if !match {
+				return false
+			}
+			continue
+		}
+		fval := val.Field(tv.VisFields[term.fldIdx].Index[0])
+		if term.op == "" {
+			if !strings.Contains(strings.ToLower(kit.ToString(fval.Interface())), term.val) {
+				return false
+			}
+			continue
+		}
+		cmp := CompareValueString(fval, term.val)
+		var match bool
+		switch term.op {
+		case ">=":
+			match = cmp >= 0
+		case "<=":
+			match = cmp <= 0
+		case "!=":
+			match = cmp != 0
+		case ">":
+			match = cmp > 0
+		case "<":
+			match = cmp < 0
+		case "=":
+			match = cmp == 0
+		}
+		if !match {
+			return false
+		}
+	}
+	return tv.FilterFunc == nil || tv.FilterFunc(tv, row)
+}
+
+// FilterRows updates the Indexes of the rows shown, from the Filter and
+// FilterFunc -- selected rows that are no longer shown are unselected
+func (tv *TableView) FilterRows() {
+	tv.Indexes = nil
+	terms := tv.FilterTerms()
+	if len(terms) == 0 && tv.FilterFunc == nil {
+		return
+	}
+	sz := kit.NonPtrValue(reflect.ValueOf(tv.Slice)).Len()
+	idxs := make([]int, 0, sz)
+	for row := 0; row < sz; row++ {
+		if tv.RowPassesFilter(row, terms) {
+			idxs = append(idxs, row)
+		}
+	}
+	tv.Indexes = idxs
+	for r := range tv.SelectedRows {
+		if _, ok := tv.DispIdx(r); !ok {
+			delete(tv.SelectedRows, r)
+		}
+	}
+	if _, ok := tv.DispIdx(tv.SelectedIdx); !ok {
+		tv.SelectedIdx = -1
+	}
+}
+
+// SetFilter sets the Filter expression and updates the rows shown
+func (tv *TableView) SetFilter(filt string) {
+	updt := tv.UpdateStart()
+	tv.Filter = filt
+	if ff := tv.FilterField(); ff != nil && ff.Txt != filt {
+		ff.SetText(filt)
+	}
+	tv.StartIdx = 0
+	tv.SaveStatePrefs()
+	tv.ConfigSliceGrid(true)
+	tv.SetFullReRender()
+	tv.UpdateEnd(updt)
+}
+
+// ConfigFilterField configures the filter text field
+func (tv *TableView) ConfigFilterField() {
+	ff := tv.FilterField()
+	if ff == nil {
+		return
+	}
+	ff.SetStretchMaxWidth()
+	ff.Placeholder = "filter"
+	ff.Tooltip = "filter the rows shown: white-space separated terms that must all match -- Field:text matches text in the field, Field:>n (or <, >=, <=, =, !=) compares the field to n, and other text matches any field"
+	if ff.Txt != tv.Filter {
+		ff.SetText(tv.Filter)
+	}
+	ff.TextFieldSig.ConnectOnly(tv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.TextFieldDone) {
+			tvv := recv.Embed(KiT_TableView).(*TableView)
+			tff := send.(*gi.TextField)
+			if tff.Txt != tvv.Filter {
+				tvv.SetFilter(tff.Txt)
+			}
+		}
+	})
+}
+
+// StateTypeName returns the name of the type of struct shown, used for
+// saving the state in Prefs.TableViews
+func (tv *TableView) StateTypeName() string {
+	return tv.StructType().String()
+}
+
+// OpenState restores the sort and filter state from Prefs.TableViews, for
+// the type of struct shown (see SaveState)
+func (tv *TableView) OpenState() {
+	tp, ok := gi.Prefs.TableViews[tv.StateTypeName()]
+	if !ok {
+		return
+	}
+	tv.CacheVisFields()
+	tv.SetSortFieldName(tp.Sort)
+	tv.Filter = tp.Filter
+}
+
+// SaveStatePrefs records the sort and filter state in Prefs.TableViews, for
+// the type of struct shown, if SaveState is set -- it is saved with the
+// Prefs, not here, as this is called on each edit of the filter
+func (tv *TableView) SaveStatePrefs() {
+	if !tv.SaveState || kit.IfaceIsNil(tv.Slice) {
+		return
+	}
+	tp := gi.Prefs.TableViews.Prefs(tv.StateTypeName())
+	tp.Sort = tv.SortFieldName()
+	tp.Filter = tv.Filter
+}
+
+// CompareValues compares two values, for sorting: numbers numerically, times
+// and FileTimes in time order, and other values by their strings -- returns
+// -1, 0 or 1 if a is less than, equal to, or greater than b
+func CompareValues(a, b reflect.Value) int {
+	a, b = kit.NonPtrValue(a), kit.NonPtrValue(b)
+	switch {
+	case !a.IsValid() && !b.IsValid():
+		return 0
+	case !a.IsValid():
+		return -1
+	case !b.IsValid():
+		return 1
+	}
+	if at, ok := valueTime(a); ok {
+		if bt, ok := valueTime(b); ok {
+			switch {
+			case at.Before(bt):
+				return -1
+			case at.After(bt):
+				return 1
+			}
+			return 0
+		}
+	}
+	ak, bk := a.Kind(), b.Kind()
+	if ak >= reflect.Int && ak <= reflect.Float64 && bk >= reflect.Int && bk <= reflect.Float64 {
+		af, _ := kit.ToFloat(a.Interface())
+		bf, _ := kit.ToFloat(b.Interface())
+		switch {
+		case af < bf:
+			return -1
+		case af > bf:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(kit.ToString(a.Interface()), kit.ToString(b.Interface()))
+}
+
+// CompareValueString compares a value to a string, as for filtering: the
+// string is parsed as a number, duration or time if the value is one --
+// otherwise the strings are compared, case insensitive -- returns -1, 0 or 1
+// if the value is less than, equal to, or greater than the string
+func CompareValueString(a reflect.Value, str string) int {
+	a = kit.NonPtrValue(a)
+	if !a.IsValid() {
+		return -1
+	}
+	if at, ok := valueTime(a); ok {
+		if bt, err := ParseTime(str, DefaultTimeFormat); err == nil {
+			return CompareValues(reflect.ValueOf(at), reflect.ValueOf(bt))
+		}
+	}
+	if ad, ok := a.Interface().(time.Duration); ok {
+		if bd, err := ParseDuration(str, 0); err == nil {
+			return CompareValues(reflect.ValueOf(ad), reflect.ValueOf(bd))
+		}
+	}
+	if ak := a.Kind(); ak >= reflect.Int && ak <= reflect.Float64 {
+		if bf, err := strconv.ParseFloat(str, 64); err == nil {
+			return CompareValues(a, reflect.ValueOf(bf))
 		}
 	}
+	return strings.Compare(strings.ToLower(kit.ToString(a.Interface())), strings.ToLower(str))
+}
+
+// valueTime returns the time of a time.Time or FileTime value
+func valueTime(v reflect.Value) (time.Time, bool) {
+	switch tv := v.Interface().(type) {
+	case time.Time:
+		return tv, true
+	case FileTime:
+		return time.Time(tv), true
+	}
+	return time.Time{}, false
 }
 
 func (tv *TableView) Style2D() {
@@ -738,6 +1577,11 @@ func (tv *TableView) Layout2D(parBBox image.Rectangle, iter int) bool {
 			lbl := sgh.KnownChild(fli).(gi.Node2D).AsWidget()
 			wd := sgf.GridData[gi.Col][fli].AllocSize
 			lbl.SetMinPrefWidth(units.NewValue(wd-sgf.Spacing.Dots, units.Dot))
+			if fli >= idxOff {
+				if _, ok := tv.Cols.Widths[tv.VisFields[fli-idxOff].Name]; ok {
+					lbl.SetProp("max-width", units.NewValue(wd-sgf.Spacing.Dots, units.Dot))
+				}
+			}
 			sumwd += wd
 		}
 		if !tv.IsInactive() {
@@ -751,6 +1595,9 @@ func (tv *TableView) Layout2D(parBBox image.Rectangle, iter int) bool {
 		sgh.SetMinPrefWidth(units.NewValue(sumwd, units.Dot))
 		sgh.Layout2D(parBBox, iter)
 	}
+	if tv.Virtual && iter == 0 && tv.LayoutVirtual() {
+		redo = true
+	}
 	return redo
 }
 
@@ -770,7 +1617,7 @@ func (tv *TableView) Render2D() {
 		tv.RenderScrolls()
 		tv.Render2DChildren()
 		tv.PopBounds()
-		if tv.SelectedIdx > -1 {
+		if tv.SelectedIdx > -1 && !tv.Virtual { // Virtual keeps it in view at config
 			tv.ScrollToRow(tv.SelectedIdx)
 		}
 	} else {
@@ -802,19 +1649,39 @@ func (tv *TableView) RowStruct(row int) interface{} {
 	return stru
 }
 
+// RowWidgetIdx returns the index within the SliceGrid of the first widget
+// for given row -- false if out of range, or the row has no widgets because
+// it is filtered out, or not shown in Virtual mode
+func (tv *TableView) RowWidgetIdx(row int) (int, bool) {
+	di, ok := tv.DispIdx(row)
+	wr := di - tv.StartIdx
+	if !ok || wr < 0 || wr >= tv.VisRows {
+		return -1, false
+	}
+	nWidgPerRow, _ := tv.RowWidgetNs()
+	sgf := tv.SliceGrid()
+	if sgf == nil {
+		return -1, false
+	}
+	ridx := wr * nWidgPerRow
+	if !sgf.Kids.IsValidIndex(ridx + nWidgPerRow - 1) {
+		return -1, false
+	}
+	return ridx, true
+}
+
 // RowFirstWidget returns the first widget for given row (could be index or
-// not) -- false if out of range
+// not) -- false if out of range, or not shown in Virtual mode
 func (tv *TableView) RowFirstWidget(row int) (*gi.WidgetBase, bool) {
 	if tv.RowStruct(row) == nil { // range check
 		return nil, false
 	}
-	nWidgPerRow, _ := tv.RowWidgetNs()
-	sg, _ := tv.SliceFrame()
-	if sg == nil {
+	ridx, ok := tv.RowWidgetIdx(row)
+	if !ok {
 		return nil, false
 	}
 	sgf := tv.SliceGrid()
-	widg := sgf.Kids[row*nWidgPerRow].(gi.Node2D).AsWidget()
+	widg := sgf.Kids[ridx].(gi.Node2D).AsWidget()
 	return widg, true
 }
 
@@ -824,17 +1691,16 @@ func (tv *TableView) RowFirstVisWidget(row int) (*gi.WidgetBase, bool) {
 	if tv.RowStruct(row) == nil { // range check
 		return nil, false
 	}
-	nWidgPerRow, idxOff := tv.RowWidgetNs()
-	sg, _ := tv.SliceFrame()
-	if sg == nil {
+	_, idxOff := tv.RowWidgetNs()
+	ridx, ok := tv.RowWidgetIdx(row)
+	if !ok {
 		return nil, false
 	}
 	sgf := tv.SliceGrid()
-	widg := sgf.Kids[row*nWidgPerRow].(gi.Node2D).AsWidget()
+	widg := sgf.Kids[ridx].(gi.Node2D).AsWidget()
 	if widg.VpBBox != image.ZR {
 		return widg, true
 	}
-	ridx := nWidgPerRow * row
 	for fli := 0; fli < tv.NVisFields; fli++ {
 		widg := sgf.KnownChild(ridx + idxOff + fli).(gi.Node2D).AsWidget()
 		if widg.VpBBox != image.ZR {
@@ -852,12 +1718,11 @@ func (tv *TableView) RowGrabFocus(row int) *gi.WidgetBase {
 		return nil
 	}
 	// fmt.Printf("grab row focus: %v\n", row)
-	nWidgPerRow, idxOff := tv.RowWidgetNs()
-	sg, _ := tv.SliceFrame()
-	if sg == nil {
+	_, idxOff := tv.RowWidgetNs()
+	ridx, ok := tv.RowWidgetIdx(row)
+	if !ok {
 		return nil
 	}
-	ridx := nWidgPerRow * row
 	sgf := tv.SliceGrid()
 	// first check if we already have focus
 	for fli := 0; fli < tv.NVisFields; fli++ {
@@ -891,7 +1756,9 @@ func (tv *TableView) RowPos(row int) image.Point {
 // RowFromPos returns the row that contains given vertical position, false if not found
 func (tv *TableView) RowFromPos(posY int) (int, bool) {
 	// todo: could optimize search to approx loc, and search up / down from there
-	for rw := 0; rw < tv.BuiltSize; rw++ {
+	nd := tv.NDispRows()
+	for di := tv.StartIdx; di < tv.StartIdx+tv.VisRows && di < nd; di++ {
+		rw := tv.SliceIdx(di)
 		widg, ok := tv.RowFirstWidget(rw)
 		if ok {
 			if widg.ObjBBox.Min.Y < posY && posY < widg.ObjBBox.Max.Y {
@@ -906,6 +1773,13 @@ func (tv *TableView) RowFromPos(posY int) (int, bool) {
 // -- returns true if any scrolling was performed
 func (tv *TableView) ScrollToRow(row int) bool {
 	row = ints.MinInt(row, tv.BuiltSize-1)
+	if tv.Virtual {
+		di, ok := tv.DispIdx(row)
+		if !ok {
+			return false
+		}
+		return tv.SetStartIdx(tv.StartIdxForRow(di))
+	}
 	sgf := tv.SliceGrid()
 	if widg, ok := tv.RowFirstWidget(row); ok {
 		return sgf.ScrollToItem(widg)
@@ -965,11 +1839,11 @@ func (tv *TableView) MoveDown(selMode mouse.SelectModes) int {
 			selMode = mouse.ExtendContinuous
 		}
 	}
-	if tv.SelectedIdx >= tv.BuiltSize-1 {
-		tv.SelectedIdx = tv.BuiltSize - 1
+	nrow := tv.NextDispRow(tv.SelectedIdx, 1)
+	if nrow < 0 {
 		return -1
 	}
-	tv.SelectedIdx++
+	tv.SelectedIdx = nrow
 	tv.SelectRowAction(tv.SelectedIdx, selMode)
 	return tv.SelectedIdx
 }
@@ -994,11 +1868,11 @@ func (tv *TableView) MoveUp(selMode mouse.SelectModes) int {
 			selMode = mouse.ExtendContinuous
 		}
 	}
-	if tv.SelectedIdx <= 0 {
-		tv.SelectedIdx = 0
+	nrow := tv.NextDispRow(tv.SelectedIdx, -1)
+	if nrow < 0 {
 		return -1
 	}
-	tv.SelectedIdx--
+	tv.SelectedIdx = nrow
 	tv.SelectRowAction(tv.SelectedIdx, selMode)
 	return tv.SelectedIdx
 }
@@ -1020,7 +1894,8 @@ func (tv *TableView) MoveUpAction(selMode mouse.SelectModes) int {
 
 // SelectRowWidgets sets the selection state of given row of widgets
 func (tv *TableView) SelectRowWidgets(idx int, sel bool) {
-	if idx < 0 {
+	ridx, ok := tv.RowWidgetIdx(idx)
+	if !ok { // not shown in Virtual mode -- selection is set when it is
 		return
 	}
 	var win *gi.Window
@@ -1032,8 +1907,7 @@ func (tv *TableView) SelectRowWidgets(idx int, sel bool) {
 		updt = win.UpdateStart()
 	}
 	sgf := tv.SliceGrid()
-	nWidgPerRow, idxOff := tv.RowWidgetNs()
-	ridx := idx * nWidgPerRow
+	_, idxOff := tv.RowWidgetNs()
 	for fli := 0; fli < tv.NVisFields; fli++ {
 		seldx := ridx + idxOff + fli
 		if sgf.Kids.IsValidIndex(seldx) {
@@ -1147,8 +2021,10 @@ func (tv *TableView) SelectAllRows() {
 		updt = win.UpdateStart()
 	}
 	tv.UnselectAllRows()
-	tv.SelectedRows = make(map[int]bool, tv.BuiltSize)
-	for row := 0; row < tv.BuiltSize; row++ {
+	nd := tv.NDispRows()
+	tv.SelectedRows = make(map[int]bool, nd)
+	for di := 0; di < nd; di++ {
+		row := tv.SliceIdx(di)
 		tv.SelectedRows[row] = true
 		tv.SelectRowWidgets(row, true)
 	}
@@ -1381,7 +2257,9 @@ func (tv *TableView) PasteAssign(md mimedata.Mimes, row int) {
 		return
 	}
 	ns := sl[0]
+	before := ValueUndoCopy(tvnp)
 	tvnp.Index(row).Set(reflect.ValueOf(ns).Elem())
+	tv.SaveUndo("Paste", before)
 	if tv.TmpSave != nil {
 		tv.TmpSave.SaveTmp()
 	}
@@ -1397,6 +2275,7 @@ func (tv *TableView) PasteAtRow(md mimedata.Mimes, row int) {
 
 	sl := tv.RowsFromMimeData(md)
 	updt := tv.UpdateStart()
+	before := ValueUndoCopy(tvnp)
 	for _, ns := range sl {
 		sz := tvnp.Len()
 		tvnp = reflect.Append(tvnp, reflect.ValueOf(ns).Elem())
@@ -1408,6 +2287,7 @@ func (tv *TableView) PasteAtRow(md mimedata.Mimes, row int) {
 		}
 		row++
 	}
+	tv.SaveUndo("Paste", before)
 	if tv.TmpSave != nil {
 		tv.TmpSave.SaveTmp()
 	}
@@ -1438,6 +2318,9 @@ func (tv *TableView) Duplicate() int {
 // DragNDropStart starts a drag-n-drop
 func (tv *TableView) DragNDropStart() {
 	nitms := len(tv.SelectedRows)
+	if tv.colDrag != nil { // dragging in the header resizes or moves columns
+		return
+	}
 	if nitms == 0 {
 		return
 	}
@@ -1446,7 +2329,13 @@ func (tv *TableView) DragNDropStart() {
 		tv.MimeDataRow(&md, r)
 	}
 	rws := tv.SelectedRowsList(true) // descending sort
-	widg, ok := tv.RowFirstVisWidget(rws[0])
+	var widg *gi.WidgetBase
+	ok := false
+	for _, r := range rws { // first one shown, in Virtual mode
+		if widg, ok = tv.RowFirstVisWidget(r); ok {
+			break
+		}
+	}
 	if ok {
 		bi := &gi.Bitmap{}
 		bi.InitName(bi, tv.UniqueName())
@@ -1697,14 +2586,14 @@ func (tv *TableView) KeyInputInactive(kt *key.ChordEvent) {
 	row := tv.SelectedIdx
 	switch {
 	case kf == gi.KeyFunMoveDown:
-		nr := row + 1
-		if nr < tv.BuiltSize {
+		nr := tv.NextDispRow(row, 1)
+		if nr >= 0 {
 			tv.ScrollToRow(nr)
 			tv.UpdateSelect(nr, true)
 			kt.SetProcessed()
 		}
 	case kf == gi.KeyFunMoveUp:
-		nr := row - 1
+		nr := tv.NextDispRow(row, -1)
 		if nr >= 0 {
 			tv.ScrollToRow(nr)
 			tv.UpdateSelect(nr, true)
@@ -1717,6 +2606,20 @@ func (tv *TableView) KeyInputInactive(kt *key.ChordEvent) {
 }
 
 func (tv *TableView) TableViewEvents() {
+	if sgh := tv.SliceHeader(); sgh != nil {
+		sgh.ConnectEvent(oswin.MouseDragEvent, gi.RegPri, func(recv, send ki.Ki, sig int64, d interface{}) {
+			me := d.(*mouse.DragEvent)
+			me.SetProcessed()
+			tv.ColDragEvent(me)
+		})
+	}
+	if tv.Virtual {
+		tv.ConnectEvent(oswin.MouseScrollEvent, gi.LowPri, func(recv, send ki.Ki, sig int64, d interface{}) {
+			me := d.(*mouse.ScrollEvent)
+			tvv := recv.Embed(KiT_TableView).(*TableView)
+			tvv.ScrollVirtual(me)
+		})
+	}
 	if tv.IsInactive() {
 		if tv.InactKeyNav {
 			tv.ConnectEvent(oswin.KeyChordEvent, gi.LowPri, func(recv, send ki.Ki, sig int64, d interface{}) {
@@ -1728,6 +2631,9 @@ func (tv *TableView) TableViewEvents() {
 		tv.ConnectEvent(oswin.MouseEvent, gi.LowRawPri, func(recv, send ki.Ki, sig int64, d interface{}) {
 			me := d.(*mouse.Event)
 			tvv := recv.Embed(KiT_TableView).(*TableView)
+			if tvv.HeaderMouseEvent(me) {
+				return
+			}
 			if me.Button == mouse.Left && me.Action == mouse.DoubleClick {
 				tvv.TableViewSig.Emit(tvv.This, int64(TableViewDoubleClicked), tvv.SelectedIdx)
 				me.SetProcessed()
@@ -1741,6 +2647,9 @@ func (tv *TableView) TableViewEvents() {
 		tv.ConnectEvent(oswin.MouseEvent, gi.LowRawPri, func(recv, send ki.Ki, sig int64, d interface{}) {
 			me := d.(*mouse.Event)
 			tvv := recv.Embed(KiT_TableView).(*TableView)
+			if tvv.HeaderMouseEvent(me) {
+				return
+			}
 			if me.Button == mouse.Right && me.Action == mouse.Release {
 				tvv.ItemCtxtMenu(tvv.SelectedIdx)
 				me.SetProcessed()
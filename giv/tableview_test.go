@@ -0,0 +1,200 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type tableTestRow struct {
+	Name  string
+	Age   int
+	Score float64
+	Dur   time.Duration
+}
+
+var tableTestRows = []tableTestRow{
+	{"Alice", 30, 1.5, 2 * time.Minute},
+	{"bob", 25, 3, 90 * time.Second},
+	{"Carol", 30, 2.5, time.Hour},
+	{"dave", 40, 1.5, 30 * time.Second},
+	{"Eve", 25, 2.5, 2 * time.Minute},
+}
+
+// tableTestView returns a TableView of a copy of tableTestRows, with all the
+// fields visible, without configuring its widgets
+func tableTestView() *TableView {
+	rows := make([]tableTestRow, len(tableTestRows))
+	copy(rows, tableTestRows)
+	tv := &TableView{}
+	tv.Slice = &rows
+	tv.SortIdx = -1
+	tv.SelectedIdx = -1
+	tv.SelectedRows = make(map[int]bool, 10)
+	tv.CacheVisFields()
+	return tv
+}
+
+func tableTestNames(tv *TableView) []string {
+	rows := *tv.Slice.(*[]tableTestRow)
+	nms := make([]string, len(rows))
+	for i, r := range rows {
+		nms[i] = r.Name
+	}
+	return nms
+}
+
+func TestFilterTerms(t *testing.T) {
+	tests := []struct {
+		filter string
+		terms  []tableViewFilterTerm
+	}{
+		{"", nil},
+		{"Bob", []tableViewFilterTerm{{-1, "", "bob"}}},
+		{"age:>=30", []tableViewFilterTerm{{1, ">=", "30"}}},
+		{"AGE:<=30 score:!=2.5", []tableViewFilterTerm{{1, "<=", "30"}, {2, "!=", "2.5"}}},
+		{"age:>30 age:<40 name:=Eve", []tableViewFilterTerm{{1, ">", "30"}, {1, "<", "40"}, {0, "=", "Eve"}}},
+		{"name:Al", []tableViewFilterTerm{{0, "", "al"}}},
+		{"height:>3", []tableViewFilterTerm{{-1, "", "height:>3"}}},
+		{">3", []tableViewFilterTerm{{-1, "", ">3"}}},
+	}
+	tv := tableTestView()
+	for _, test := range tests {
+		tv.Filter = test.filter
+		terms := tv.FilterTerms()
+		if !reflect.DeepEqual(terms, test.terms) {
+			t.Errorf("FilterTerms(%q): got %v, expected %v\n", test.filter, terms, test.terms)
+		}
+	}
+}
+
+func TestRowPassesFilter(t *testing.T) {
+	tests := []struct {
+		filter string
+		rows   []int
+	}{
+		{"", []int{0, 1, 2, 3, 4}},
+		{"e", []int{0, 3, 4}},
+		{"BOB", []int{1}},
+		{"name:a", []int{0, 2, 3}},
+		{"age:=30", []int{0, 2}},
+		{"age:!=30", []int{1, 3, 4}},
+		{"age:>25 age:<40", []int{0, 2}},
+		{"age:>=30", []int{0, 2, 3}},
+		{"score:<=1.5", []int{0, 3}},
+		{"dur:>1m", []int{0, 1, 2, 4}},
+		{"dur:<=90", []int{1, 3}},
+		{"name:>=c", []int{2, 3, 4}},
+		{"name:=alice", []int{0}},
+		{"age:=30 e", []int{0}},
+		{"age:>100", nil},
+	}
+	tv := tableTestView()
+	for _, test := range tests {
+		tv.Filter = test.filter
+		terms := tv.FilterTerms()
+		var rows []int
+		for r := range tableTestRows {
+			if tv.RowPassesFilter(r, terms) {
+				rows = append(rows, r)
+			}
+		}
+		if !reflect.DeepEqual(rows, test.rows) {
+			t.Errorf("RowPassesFilter(%q): got %v, expected %v\n", test.filter, rows, test.rows)
+		}
+	}
+	tv.Filter = "age:=25"
+	tv.FilterFunc = func(tv *TableView, row int) bool { return row != 1 }
+	terms := tv.FilterTerms()
+	if tv.RowPassesFilter(1, terms) || !tv.RowPassesFilter(4, terms) {
+		t.Errorf("RowPassesFilter(%q) with FilterFunc: got %v %v, expected false true\n", tv.Filter, tv.RowPassesFilter(1, terms), tv.RowPassesFilter(4, terms))
+	}
+}
+
+func TestCompareValueString(t *testing.T) {
+	tm := time.Date(2020, 3, 4, 10, 30, 0, 0, time.Local)
+	tests := []struct {
+		val interface{}
+		str string
+		cmp int
+	}{
+		{10, "9", 1},
+		{10, "10", 0},
+		{10, "10.5", -1},
+		{float32(1.5), "1.5", 0},
+		{uint8(3), "12", -1},
+		{10, "abc", -1}, // not a number: compared as strings
+		{90 * time.Second, "1m30s", 0},
+		{90 * time.Second, "2m", -1},
+		{90 * time.Second, "60", 1},
+		{tm, tm.Format(DefaultTimeFormat), 0},
+		{tm, tm.Add(time.Hour).Format(DefaultTimeFormat), -1},
+		{FileTime(tm), tm.Add(-time.Hour).Format(DefaultTimeFormat), 1},
+		{"Bob", "bob", 0},
+		{"alice", "Bob", -1},
+		{"Carol", "bob", 1},
+		{true, "true", 0},
+	}
+	for _, test := range tests {
+		cmp := CompareValueString(reflect.ValueOf(test.val), test.str)
+		if cmp != test.cmp {
+			t.Errorf("CompareValueString(%v, %q): got %v, expected %v\n", test.val, test.str, cmp, test.cmp)
+		}
+	}
+	var np *int
+	if cmp := CompareValueString(reflect.ValueOf(np), "1"); cmp != -1 {
+		t.Errorf("CompareValueString(nil, %q): got %v, expected -1\n", "1", cmp)
+	}
+}
+
+func TestSortSlice(t *testing.T) {
+	tests := []struct {
+		keys   []TableViewSortKey
+		names  []string
+		selIdx int
+		selRow []int
+	}{
+		{nil, []string{"Alice", "bob", "Carol", "dave", "Eve"}, 2, []int{0, 2}},
+		{[]TableViewSortKey{{FieldIdx: 1}}, []string{"bob", "Eve", "Alice", "Carol", "dave"}, 3, []int{2, 3}},
+		{[]TableViewSortKey{{FieldIdx: 1, Desc: true}}, []string{"dave", "Alice", "Carol", "bob", "Eve"}, 2, []int{1, 2}},
+		{[]TableViewSortKey{{FieldIdx: 1}, {FieldIdx: 2, Desc: true}}, []string{"bob", "Eve", "Carol", "Alice", "dave"}, 2, []int{2, 3}},
+		{[]TableViewSortKey{{FieldIdx: 2}, {FieldIdx: 3}}, []string{"dave", "Alice", "Eve", "Carol", "bob"}, 3, []int{1, 3}},
+		{[]TableViewSortKey{{FieldIdx: 3, Desc: true}, {FieldIdx: 1}}, []string{"Carol", "Eve", "Alice", "bob", "dave"}, 0, []int{0, 2}},
+		{[]TableViewSortKey{{FieldIdx: 9}, {FieldIdx: 2}}, []string{"Alice", "dave", "Carol", "Eve", "bob"}, 2, []int{0, 2}},
+	}
+	for _, test := range tests {
+		tv := tableTestView()
+		tv.SortKeys = test.keys
+		tv.SelectedIdx = 2        // Carol
+		tv.SelectedRows[0] = true // Alice
+		tv.SelectedRows[2] = true
+		tv.SortSlice()
+		if nms := tableTestNames(tv); !reflect.DeepEqual(nms, test.names) {
+			t.Errorf("SortSlice(%v): got %v, expected %v\n", test.keys, nms, test.names)
+		}
+		if tv.SelectedIdx != test.selIdx {
+			t.Errorf("SortSlice(%v) SelectedIdx: got %v, expected %v\n", test.keys, tv.SelectedIdx, test.selIdx)
+		}
+		selRows := make(map[int]bool, len(test.selRow))
+		for _, r := range test.selRow {
+			selRows[r] = true
+		}
+		if !reflect.DeepEqual(tv.SelectedRows, selRows) {
+			t.Errorf("SortSlice(%v) SelectedRows: got %v, expected %v\n", test.keys, tv.SelectedRows, selRows)
+		}
+	}
+	tv := tableTestView()
+	tv.SortIdx = 0
+	tv.SortDesc = true
+	tv.SortSlice()
+	if nms, exp := tableTestNames(tv), []string{"dave", "bob", "Eve", "Carol", "Alice"}; !reflect.DeepEqual(nms, exp) {
+		t.Errorf("SortSlice(SortIdx 0 Desc): got %v, expected %v\n", nms, exp)
+	}
+	if len(tv.SortKeys) != 1 || tv.SortIdx != 0 || !tv.SortDesc {
+		t.Errorf("SortSlice(SortIdx 0 Desc) SortKeys: got %v %v %v, expected one key for field 0 desc\n", tv.SortKeys, tv.SortIdx, tv.SortDesc)
+	}
+}
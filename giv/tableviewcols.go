@@ -0,0 +1,503 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	"github.com/goki/gi"
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/mouse"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki"
+	"github.com/goki/ki/ints"
+	"github.com/goki/ki/kit"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  TableViewCols
+
+// TableViewCols is the configuration of the columns of the TableViews showing
+// a given type of struct, as set by the user -- resizing, moving, hiding and
+// freezing columns -- and saved in TableViewColsPrefs
+type TableViewCols struct {
+	Order  []string           `desc:"names of the fields in the order shown -- fields not listed are shown after these, in the order of the struct"`
+	Hidden map[string]bool    `desc:"fields that the user has hidden (true) or shown (false) -- fields not listed are hidden if they have a view:\"-\" tag"`
+	Widths map[string]float32 `desc:"widths of the columns resized by the user, in dots, by field name"`
+	Frozen int                `desc:"number of leading columns that stay in place when the table is scrolled horizontally"`
+}
+
+// IsHidden returns true if given field is hidden
+func (tc *TableViewCols) IsHidden(fld reflect.StructField) bool {
+	if hid, ok := tc.Hidden[fld.Name]; ok {
+		return hid
+	}
+	return fld.Tag.Get("view") == "-"
+}
+
+// InOrder returns true if given field name is in the Order
+func (tc *TableViewCols) InOrder(nm string) bool {
+	for _, onm := range tc.Order {
+		if onm == nm {
+			return true
+		}
+	}
+	return false
+}
+
+// TableViewColsPrefs are the column configurations of TableViews, by the
+// type of struct they show -- opened when the first TableView is set to a
+// slice (see OpenTableViewColsPrefs), and saved whenever the user changes the
+// columns
+var TableViewColsPrefs = TableViewColsPrefsMap{}
+
+// TableViewColsPrefsMap records the column configuration of TableViews by
+// the type of struct they show
+type TableViewColsPrefsMap map[string]*TableViewCols
+
+// TableViewColsPrefsFileName is the name of the preferences file in GoGi
+// prefs directory
+var TableViewColsPrefsFileName = "table_view_cols_prefs.json"
+
+// tableViewColsPrefsOnce opens TableViewColsPrefs only once
+var tableViewColsPrefsOnce sync.Once
+
+// OpenTableViewColsPrefs opens TableViewColsPrefs the first time it is
+// called, when a TableView is set to a slice -- they are cached after that,
+// so that laying out TableViews (see CacheVisFields) does no file I/O
+func OpenTableViewColsPrefs() {
+	tableViewColsPrefsOnce.Do(func() {
+		TableViewColsPrefs.Open()
+	})
+}
+
+// tableViewColsPrefsFile returns the path of the TableViewColsPrefs file in
+// the GoGi prefs directory -- empty if there is no app, e.g., in tests
+func tableViewColsPrefsFile() string {
+	if oswin.TheApp == nil {
+		return ""
+	}
+	return filepath.Join(oswin.TheApp.GoGiPrefsDir(), TableViewColsPrefsFileName)
+}
+
+// Open TableView column preferences from GoGi standard prefs directory
+func (tm *TableViewColsPrefsMap) Open() error {
+	if *tm == nil {
+		*tm = make(TableViewColsPrefsMap, 100)
+	}
+	pnm := tableViewColsPrefsFile()
+	if pnm == "" {
+		return nil
+	}
+	b, err := ioutil.ReadFile(pnm)
+	if err != nil {
+		return err // normal if the user hasn't changed any columns yet
+	}
+	err = json.Unmarshal(b, tm)
+	if err != nil {
+		log.Println(err)
+	}
+	return err
+}
+
+// Save TableView column preferences to GoGi standard prefs directory
+func (tm *TableViewColsPrefsMap) Save() error {
+	pnm := tableViewColsPrefsFile()
+	if *tm == nil || pnm == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(tm, "", "  ")
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	err = ioutil.WriteFile(pnm, b, 0644)
+	if err != nil {
+		log.Println(err)
+	}
+	return err
+}
+
+// Cols returns the column configuration for given struct type name, creating
+// it if it does not exist yet
+func (tm *TableViewColsPrefsMap) Cols(typNm string) *TableViewCols {
+	if *tm == nil {
+		*tm = make(TableViewColsPrefsMap, 100)
+	}
+	tc, ok := (*tm)[typNm]
+	if !ok {
+		tc = &TableViewCols{}
+		(*tm)[typNm] = tc
+	}
+	return tc
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  TableViewFrame
+
+// TableViewFrame is the Frame used for the slice frame and the grid of a
+// TableView -- it keeps the frozen leading columns in place when the table
+// is scrolled horizontally
+type TableViewFrame struct {
+	gi.Frame
+}
+
+var KiT_TableViewFrame = kit.Types.AddType(&TableViewFrame{}, gi.FrameProps)
+
+// ParentTableView returns the TableView that this frame is within
+func (tf *TableViewFrame) ParentTableView() *TableView {
+	tvk, ok := tf.ParentByType(KiT_TableView, true)
+	if !ok {
+		return nil
+	}
+	return tvk.Embed(KiT_TableView).(*TableView)
+}
+
+func (tf *TableViewFrame) Layout2D(parBBox image.Rectangle, iter int) bool {
+	redo := tf.Frame.Layout2D(parBBox, iter)
+	if !redo || iter == 1 { // children have been moved for scrolling
+		if tv := tf.ParentTableView(); tv != nil {
+			tv.MoveFrozenCols()
+		}
+	}
+	return redo
+}
+
+func (tf *TableViewFrame) Move2D(delta image.Point, parBBox image.Rectangle) {
+	tf.Frame.Move2D(delta, parBBox)
+	if tv := tf.ParentTableView(); tv != nil {
+		tv.MoveFrozenCols()
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  TableView columns
+
+// TableViewColBorder is the distance in dots from the border between two
+// columns in the header within which dragging resizes the column to the left
+var TableViewColBorder = 4
+
+// TableViewColDragPix is the distance in dots that the mouse must be dragged
+// in the header before resizing or moving a column -- less is a click
+var TableViewColDragPix = 3
+
+// TableViewColMinWidth is the minimum width in dots that a column can be
+// resized to
+var TableViewColMinWidth = float32(16)
+
+// tableViewColDrag is the state of dragging a column in the header
+type tableViewColDrag struct {
+	fli    int         // index in VisFields of the column resized or moved
+	resize bool        // resizing the column, else moving it
+	start  image.Point // where the drag started
+	last   image.Point // where the last drag event was
+	width  float32     // width of the column at the start of resizing
+	moved  bool        // has it been dragged far enough to not be a click
+}
+
+// HeaderFieldAt returns the index in VisFields of the column of the header at
+// given window position -- border is true if the position is on the border
+// to the right of the column, where dragging resizes it -- -1 if none
+func (tv *TableView) HeaderFieldAt(pos image.Point) (fli int, border bool) {
+	sgh := tv.SliceHeader()
+	if sgh == nil {
+		return -1, false
+	}
+	_, idxOff := tv.RowWidgetNs()
+	for fli := 0; fli < tv.NVisFields && idxOff+fli < len(sgh.Kids); fli++ {
+		hdr := sgh.KnownChild(idxOff + fli).(*gi.Action)
+		bb := hdr.WinBBox
+		if pos.Y < bb.Min.Y || pos.Y >= bb.Max.Y {
+			continue
+		}
+		if pos.X >= bb.Max.X-TableViewColBorder && pos.X < bb.Max.X+TableViewColBorder {
+			return fli, true
+		}
+		if pos.X >= bb.Min.X && pos.X < bb.Max.X {
+			return fli, false
+		}
+	}
+	return -1, false
+}
+
+// ColDragEvent processes a mouse drag event in the header: dragging the
+// border to the right of a column resizes it, and dragging a column moves it
+// to where it is dragged
+func (tv *TableView) ColDragEvent(me *mouse.DragEvent) {
+	cd := tv.colDrag
+	if cd == nil || me.From != cd.last { // new drag
+		if cd != nil { // missed the end of the last one
+			tv.EndColDrag()
+		}
+		fli, border := tv.HeaderFieldAt(me.From)
+		if fli < 0 {
+			return
+		}
+		cd = &tableViewColDrag{fli: fli, resize: border, start: me.From}
+		if border {
+			_, idxOff := tv.RowWidgetNs()
+			if sgf := tv.SliceGrid(); idxOff+fli < len(sgf.GridData[gi.Col]) {
+				cd.width = sgf.GridData[gi.Col][idxOff+fli].AllocSize - sgf.Spacing.Dots
+			}
+		}
+		tv.colDrag = cd
+	}
+	cd.last = me.Where
+	dx := me.Where.X - cd.start.X
+	if !cd.moved {
+		if dx < TableViewColDragPix && dx > -TableViewColDragPix {
+			return
+		}
+		cd.moved = true
+	}
+	if cd.resize {
+		tv.SetColWidth(cd.fli, cd.width+float32(dx))
+		return
+	}
+	tfli, _ := tv.HeaderFieldAt(image.Point{me.Where.X, cd.start.Y})
+	if tfli >= 0 && tfli != cd.fli {
+		tv.MoveCol(cd.fli, tfli)
+		cd.fli = tfli
+	}
+}
+
+// EndColDrag ends the resizing or moving of a column, saving the columns
+func (tv *TableView) EndColDrag() {
+	cd := tv.colDrag
+	tv.colDrag = nil
+	if cd != nil && cd.moved {
+		TableViewColsPrefs.Save()
+	}
+}
+
+// HeaderMouseEvent processes mouse events in the header: the end of dragging
+// a column, and the header context menu -- returns true if processed
+func (tv *TableView) HeaderMouseEvent(me *mouse.Event) bool {
+	if me.Action != mouse.Release {
+		return false
+	}
+	if tv.colDrag != nil {
+		tv.EndColDrag()
+		return true
+	}
+	sgh := tv.SliceHeader()
+	if sgh == nil || me.Button != mouse.Right || !me.Where.In(sgh.WinBBox) {
+		return false
+	}
+	fli, _ := tv.HeaderFieldAt(me.Where)
+	tv.HeaderCtxtMenu(fli, me.Where)
+	me.SetProcessed()
+	return true
+}
+
+// HeaderCtxtMenu pops up the context menu of the header, at given window
+// position over the column of given field in VisFields (-1 if none): it
+// shows or hides each of the fields, and freezes the columns
+func (tv *TableView) HeaderCtxtMenu(fli int, pos image.Point) {
+	var men gi.Menu
+	for _, fld := range tv.AllFields {
+		act := men.AddAction(gi.ActOpts{Label: fld.Name, Data: fld.Name, Tooltip: "show or hide this field"},
+			tv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+				tvv := recv.Embed(KiT_TableView).(*TableView)
+				tvv.ToggleColHidden(data.(string))
+			})
+		act.SetCheckable(true)
+		act.SetChecked(!tv.Cols.IsHidden(fld))
+	}
+	men.AddSeparator("sep-cols")
+	if fli >= 0 {
+		men.AddAction(gi.ActOpts{Label: fmt.Sprintf("Freeze Columns Through %v", tv.VisFields[fli].Name), Data: fli + 1},
+			tv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+				tvv := recv.Embed(KiT_TableView).(*TableView)
+				tvv.SetFrozenCols(data.(int))
+			})
+	}
+	if tv.Cols.Frozen > 0 {
+		men.AddAction(gi.ActOpts{Label: "Unfreeze Columns"},
+			tv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+				tvv := recv.Embed(KiT_TableView).(*TableView)
+				tvv.SetFrozenCols(0)
+			})
+	}
+	men.AddAction(gi.ActOpts{Label: "Reset Columns", Tooltip: "show the default fields, in the order of the struct, at their natural widths"},
+		tv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+			tvv := recv.Embed(KiT_TableView).(*TableView)
+			tvv.ResetCols()
+		})
+	gi.PopupMenu(men, pos.X, pos.Y, tv.Viewport, tv.Nm+"-header-menu")
+}
+
+// UpdateCols updates the table after a change to the Cols, keeping it sorted
+// by the same fields
+func (tv *TableView) UpdateCols() {
+	snm := tv.SortFieldName()
+	tv.CacheVisFields()
+	tv.SortKeys = nil
+	tv.SortIdx = -1
+	tv.SetSortFieldName(snm)
+	updt := tv.UpdateStart()
+	tv.SetFullReRender()
+	tv.ConfigSliceGrid(true)
+	tv.UpdateEnd(updt)
+}
+
+// SetColWidth sets the width of the column of given field in VisFields, in
+// dots, updating the table
+func (tv *TableView) SetColWidth(fli int, wd float32) {
+	if wd < TableViewColMinWidth {
+		wd = TableViewColMinWidth
+	}
+	if tv.Cols.Widths == nil {
+		tv.Cols.Widths = make(map[string]float32)
+	}
+	tv.Cols.Widths[tv.VisFields[fli].Name] = wd
+	updt := tv.UpdateStart()
+	nWidgPerRow, idxOff := tv.RowWidgetNs()
+	sgf := tv.SliceGrid()
+	for ci := idxOff + fli; ci < len(sgf.Kids); ci += nWidgPerRow {
+		if wb := sgf.Kids[ci].(gi.Node2D).AsWidget(); wb != nil {
+			wb.SetFixedWidth(units.NewValue(wd, units.Dot))
+		}
+	}
+	tv.SetFullReRender()
+	tv.UpdateEnd(updt)
+}
+
+// MoveCol moves the column of given field in VisFields to the position of
+// another one, updating the table
+func (tv *TableView) MoveCol(fli, toFli int) {
+	order := make([]string, 0, len(tv.AllFields))
+	for _, fld := range tv.VisFields {
+		order = append(order, fld.Name)
+	}
+	for _, fld := range tv.AllFields { // hidden fields go after, if shown again
+		if tv.Cols.IsHidden(fld) {
+			order = append(order, fld.Name)
+		}
+	}
+	nm := order[fli]
+	order = append(order[:fli], order[fli+1:]...)
+	order = append(order[:toFli], append([]string{nm}, order[toFli:]...)...)
+	tv.Cols.Order = order
+	tv.UpdateCols()
+}
+
+// ToggleColHidden shows or hides the column of given field name, saving the
+// columns -- the last column shown cannot be hidden
+func (tv *TableView) ToggleColHidden(nm string) {
+	for _, fld := range tv.AllFields {
+		if fld.Name != nm {
+			continue
+		}
+		hide := !tv.Cols.IsHidden(fld)
+		if hide && tv.NVisFields <= 1 {
+			return
+		}
+		if tv.Cols.Hidden == nil {
+			tv.Cols.Hidden = make(map[string]bool)
+		}
+		tv.Cols.Hidden[nm] = hide
+		tv.UpdateCols()
+		TableViewColsPrefs.Save()
+		return
+	}
+}
+
+// SetFrozenCols sets the number of leading columns that stay in place when
+// the table is scrolled horizontally, saving the columns
+func (tv *TableView) SetFrozenCols(n int) {
+	tv.Cols.Frozen = n
+	TableViewColsPrefs.Save()
+	updt := tv.UpdateStart()
+	tv.SetFullReRender()
+	tv.UpdateEnd(updt)
+}
+
+// ResetCols resets the columns to the default fields, in the order of the
+// struct, at their natural widths, saving the columns
+func (tv *TableView) ResetCols() {
+	*tv.Cols = TableViewCols{}
+	tv.UpdateCols()
+	TableViewColsPrefs.Save()
+}
+
+// NFrozenCols returns the number of columns of widgets, including the index,
+// that are frozen in place when scrolling horizontally -- 0 if none
+func (tv *TableView) NFrozenCols() int {
+	if tv.Cols == nil || tv.Cols.Frozen <= 0 {
+		return 0
+	}
+	_, idxOff := tv.RowWidgetNs()
+	return idxOff + ints.MinInt(tv.Cols.Frozen, tv.NVisFields)
+}
+
+// MoveFrozenCols moves the frozen columns of the header and the grid back to
+// where they are without horizontal scrolling, and clips the other columns
+// to the right of them -- called whenever the frames are moved
+func (tv *TableView) MoveFrozenCols() {
+	nfrz := tv.NFrozenCols()
+	if nfrz == 0 {
+		return
+	}
+	sg, _ := tv.SliceFrame()
+	sgh := tv.SliceHeader()
+	sgf := tv.SliceGrid()
+	if sg == nil || sgh == nil || sgf == nil {
+		return
+	}
+	off := 0
+	if sg.HasScroll[gi.X] {
+		off += int(sg.Scrolls[gi.X].Value)
+	}
+	hoff := off
+	if sgf.HasScroll[gi.X] {
+		off += int(sgf.Scrolls[gi.X].Value)
+	}
+	nWidgPerRow, _ := tv.RowWidgetNs()
+	if hoff > 0 {
+		moveFrozenKids(&sgh.Layout, len(sgh.Kids), nfrz, hoff)
+	}
+	if off > 0 {
+		moveFrozenKids(&sgf.Layout, nWidgPerRow, nfrz, off)
+	}
+}
+
+// moveFrozenKids moves the first nfrz children in each row of ncol children
+// of given layout back by off dots of horizontal scrolling, and clips the
+// other children to the right of them
+func moveFrozenKids(ly *gi.Layout, ncol, nfrz, off int) {
+	delta := ly.LayData.AllocPos.Sub(ly.LayData.AllocPosOrig).ToPoint()
+	delta = ly.Move2DDelta(delta)
+	cbb := ly.This.(gi.Node2D).ChildrenBBox2D()
+	fdelta := delta
+	fdelta.X += off
+	frzMax := cbb.Min.X
+	for i, kid := range ly.Kids {
+		if i%ncol >= nfrz {
+			continue
+		}
+		if nii, ni := gi.KiToNode2D(kid); nii != nil {
+			nii.Move2D(fdelta, cbb)
+			frzMax = ints.MaxInt(frzMax, ni.ObjBBox.Max.X)
+		}
+	}
+	ccbb := cbb
+	ccbb.Min.X = ints.MinInt(frzMax, cbb.Max.X)
+	for i, kid := range ly.Kids {
+		if i%ncol < nfrz {
+			continue
+		}
+		if nii, _ := gi.KiToNode2D(kid); nii != nil {
+			nii.Move2D(delta, ccbb)
+		}
+	}
+}
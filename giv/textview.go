@@ -754,6 +754,38 @@ func (tv *TextView) CursorForward(steps int) {
 	tv.CursorSelect(org)
 }
 
+// CursorVisual moves the cursor by given number of positions to the right
+// (steps > 0) or left (steps < 0) as displayed, within a line of text that
+// has right-to-left parts -- returns false if the line has no such bidi text
+// or the cursor is already at the visual edge of the line, in which case the
+// cursor should be moved forward or backward instead
+func (tv *TextView) CursorVisual(steps int) bool {
+	tv.ValidateCursor()
+	ln := tv.CursorPos.Ln
+	if ln >= len(tv.Renders) || len(tv.Renders[ln].Spans) == 0 {
+		return false
+	}
+	si, ri, ok := tv.Renders[ln].RuneSpanPos(tv.CursorPos.Ch)
+	if !ok { // end of line
+		si = len(tv.Renders[ln].Spans) - 1
+		ri = len(tv.Renders[ln].Spans[si].Text)
+	}
+	sr := &tv.Renders[ln].Spans[si]
+	if !sr.IsBidi() {
+		return false
+	}
+	nri := sr.VisualMoveLR(ri, steps)
+	switch {
+	case nri > ri:
+		tv.CursorForward(nri - ri)
+	case nri < ri:
+		tv.CursorBackward(ri - nri)
+	default:
+		return false
+	}
+	return true
+}
+
 // CursorDown moves the cursor down line(s)
 func (tv *TextView) CursorDown(steps int) {
 	updt := tv.Viewport.Win.UpdateStart()
@@ -2013,7 +2045,10 @@ func (tv *TextView) CharStartPos(pos TextPos) gi.Vec2D {
 	}
 	if len(tv.Renders[pos.Ln].Spans) > 0 {
 		// note: Y from rune pos is baseline
-		rrp, _, _, _ := tv.Renders[pos.Ln].RuneRelPos(pos.Ch)
+		rrp, si, ri, _ := tv.Renders[pos.Ln].RuneRelPos(pos.Ch)
+		if sr := &tv.Renders[pos.Ln].Spans[si]; sr.IsBidi() { // cursor pos, not rune pos
+			rrp.X = sr.RelPos.X + sr.CaretPosLR(ri)
+		}
 		spos.X += rrp.X
 		spos.Y += rrp.Y - tv.Renders[pos.Ln].Spans[0].RelPos.Y // relative
 	}
@@ -2199,6 +2234,10 @@ func (tv *TextView) UpdateHighlights(prev []TextRegion) {
 
 // RenderRegionBox renders a region in background color according to given state style
 func (tv *TextView) RenderRegionBox(reg TextRegion, state TextViewStates) {
+	if tv.RegionHasBidi(reg) {
+		tv.RenderRegionRunes(reg, state)
+		return
+	}
 	st := reg.Start
 	ed := reg.End
 	spos := tv.CharStartPos(st)
@@ -2245,6 +2284,53 @@ func (tv *TextView) RenderRegionBox(reg TextRegion, state TextViewStates) {
 	pc.FillBox(rs, sed, epos.Sub(sed), &sty.Font.BgColor)
 }
 
+// RegionHasBidi returns true if any of the lines in given region have
+// right-to-left text that has been reordered for display, so the region
+// may not be contiguous on screen
+func (tv *TextView) RegionHasBidi(reg TextRegion) bool {
+	for ln := reg.Start.Ln; ln <= reg.End.Ln && ln < len(tv.Renders); ln++ {
+		for si := range tv.Renders[ln].Spans {
+			if tv.Renders[ln].Spans[si].IsBidi() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RenderRegionRunes renders a region in background color according to given
+// state style, rune by rune -- used for regions with bidi text, where the
+// runes in the region are not necessarily adjacent on screen
+func (tv *TextView) RenderRegionRunes(reg TextRegion, state TextViewStates) {
+	rs := &tv.Viewport.Render
+	pc := &rs.Paint
+	sty := &tv.StateStyles[state]
+	sx := tv.RenderStartPos().X + tv.LineNoOff
+	for ln := reg.Start.Ln; ln <= reg.End.Ln && ln < tv.NLines; ln++ {
+		tr := &tv.Renders[ln]
+		stc := 0
+		if ln == reg.Start.Ln {
+			stc = reg.Start.Ch
+		}
+		edc := len(tv.Buf.Lines[ln])
+		if ln == reg.End.Ln {
+			edc = ints.MinInt(edc, reg.End.Ch)
+		}
+		for ch := stc; ch < edc; ch++ {
+			rp, _, _, ok := tr.RuneRelPos(ch)
+			if !ok {
+				break
+			}
+			ep, _, _, _ := tr.RuneEndPos(ch)
+			top := tv.CharStartPos(TextPos{Ln: ln, Ch: ch}).Y
+			if int(math32.Ceil(top+tv.LineHeight)) < tv.VpBBox.Min.Y || int(math32.Floor(top)) > tv.VpBBox.Max.Y {
+				continue
+			}
+			pc.FillBox(rs, gi.Vec2D{sx + rp.X, top}, gi.Vec2D{ep.X - rp.X, tv.LineHeight}, &sty.Font.BgColor)
+		}
+	}
+}
+
 // RenderStartPos is absolute rendering start position from our allocpos
 func (tv *TextView) RenderStartPos() gi.Vec2D {
 	st := &tv.Sty
@@ -2573,6 +2659,10 @@ func (tv *TextView) PixelToCursor(pt image.Point) TextPos {
 	if rsz == 0 {
 		return TextPos{Ln: cln, Ch: spoff}
 	}
+	if sr := &tv.Renders[cln].Spans[si]; sr.IsBidi() { // positions not in logical order
+		lx := tv.RenderStartPos().X + tv.LineNoOff + sr.RelPos.X - xoff
+		return TextPos{Ln: cln, Ch: spoff + sr.CaretIdxLR(float32(pt.X)-lx)}
+	}
 	// fmt.Printf("sc: %v  rsz: %v\n", sc, rsz)
 
 	c, _ := tv.Renders[cln].SpanPosToRuneIdx(si, rsz-1) // end
@@ -2718,13 +2808,17 @@ func (tv *TextView) KeyInput(kt *key.ChordEvent) {
 		tv.ISearchCancel() // note: may need to generalize to cancel more stuff
 		kt.SetProcessed()
 		tv.ShiftSelect(kt)
-		tv.CursorForward(1)
+		if !tv.CursorVisual(1) {
+			tv.CursorForward(1)
+		}
 		tv.OfferComplete(dontforce)
 	case gi.KeyFunMoveLeft:
 		tv.ISearchCancel()
 		kt.SetProcessed()
 		tv.ShiftSelect(kt)
-		tv.CursorBackward(1)
+		if !tv.CursorVisual(-1) {
+			tv.CursorBackward(1)
+		}
 		tv.OfferComplete(dontforce)
 	case gi.KeyFunMoveUp:
 		tv.ISearchCancel()
@@ -0,0 +1,710 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goki/gi"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki"
+	"github.com/goki/ki/kit"
+)
+
+// DefaultTimeFormat is the time layout (as in time.Format) used for
+// time.Time values that do not have a format tag
+var DefaultTimeFormat = "2006-01-02 15:04:05"
+
+// FirstWeekday is the day shown first in each week of the DateView calendar
+var FirstWeekday = time.Sunday
+
+// TimeFormatParts returns whether values formatted with given time layout
+// show the date, the time of day, and the seconds
+func TimeFormatParts(format string) (date, tod, secs bool) {
+	ref := time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+	rs := ref.Format(format)
+	date = ref.AddDate(1, 1, 1).Format(format) != rs
+	tod = ref.Add(time.Hour+time.Minute).Format(format) != rs
+	secs = ref.Add(time.Second).Format(format) != rs
+	return
+}
+
+// ParseTime parses given string as a time, using given time layout, and
+// falling back on RFC3339, DefaultTimeFormat, and a plain date
+func ParseTime(str, format string) (time.Time, error) {
+	str = strings.TrimSpace(str)
+	var err error
+	for _, lay := range []string{format, time.RFC3339, DefaultTimeFormat, "2006-01-02"} {
+		if lay == "" {
+			continue
+		}
+		var tm time.Time
+		tm, err = time.ParseInLocation(lay, str, time.Local)
+		if err == nil {
+			return tm, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// ClampTime returns given time limited to the range between min and max,
+// where a zero min or max means no limit
+func ClampTime(tm, min, max time.Time) time.Time {
+	if !min.IsZero() && tm.Before(min) {
+		return min
+	}
+	if !max.IsZero() && tm.After(max) {
+		return max
+	}
+	return tm
+}
+
+// TimeTagLimits returns the limits for the time value of given ValueView,
+// from its min and max tags, parsed with given time layout (see ParseTime) --
+// zero times if not set
+func TimeTagLimits(vv ValueView, format string) (min, max time.Time) {
+	if mintag, ok := vv.Tag("min"); ok {
+		min, _ = ParseTime(mintag, format)
+	}
+	if maxtag, ok := vv.Tag("max"); ok {
+		max, _ = ParseTime(maxtag, format)
+	}
+	return
+}
+
+// DurationUnits are the units that can be used in the format tag of
+// time.Duration values, which sets the unit for plain numbers and the
+// rounding of the displayed value
+var DurationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+}
+
+// ParseDuration parses given string as a duration, either with units as in
+// time.ParseDuration (e.g., "1h30m"), or a plain number in given unit
+// (seconds if 0)
+func ParseDuration(str string, unit time.Duration) (time.Duration, error) {
+	str = strings.TrimSpace(str)
+	if unit == 0 {
+		unit = time.Second
+	}
+	if fv, err := strconv.ParseFloat(str, 64); err == nil {
+		return time.Duration(fv * float64(unit)), nil
+	}
+	return time.ParseDuration(strings.Replace(str, " ", "", -1))
+}
+
+// FormatDuration returns the string representation of given duration,
+// rounded to given unit if > 0
+func FormatDuration(d, unit time.Duration) string {
+	if unit > 0 {
+		d = d.Round(unit)
+	}
+	return d.String()
+}
+
+// DurationTagLimits returns the limits for the duration value of given
+// ValueView, from its min and max tags, parsed with given unit (see
+// ParseDuration)
+func DurationTagLimits(vv ValueView, unit time.Duration) (min, max time.Duration, hasMin, hasMax bool) {
+	var err error
+	if mintag, ok := vv.Tag("min"); ok {
+		min, err = ParseDuration(mintag, unit)
+		hasMin = err == nil
+	}
+	if maxtag, ok := vv.Tag("max"); ok {
+		max, err = ParseDuration(maxtag, unit)
+		hasMax = err == nil
+	}
+	return
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  DateView
+
+// DateView shows a calendar month for choosing a date, with actions for
+// going to the previous and next months
+type DateView struct {
+	gi.Frame
+	Time    time.Time `desc:"the time whose date we view -- the time of day is preserved when the date is changed"`
+	Month   time.Time `desc:"first day of the month currently shown"`
+	Min     time.Time `desc:"earliest time that can be chosen -- zero for no limit"`
+	Max     time.Time `desc:"latest time that can be chosen -- zero for no limit"`
+	ViewSig ki.Signal `json:"-" xml:"-" desc:"signal for valueview -- only one signal sent when a date has been chosen -- data is the new Time"`
+}
+
+var KiT_DateView = kit.Types.AddType(&DateView{}, DateViewProps)
+
+var DateViewProps = ki.Props{
+	"background-color": &gi.Prefs.Colors.Background,
+	"color":            &gi.Prefs.Colors.Font,
+}
+
+// SetTime sets the time whose date we view, and shows its month
+func (dv *DateView) SetTime(tm time.Time) {
+	dv.Time = tm
+	dv.Month = time.Date(tm.Year(), tm.Month(), 1, 0, 0, 0, 0, tm.Location())
+	dv.Config()
+	dv.UpdateDays()
+}
+
+// Config configures a standard setup of entire view
+func (dv *DateView) Config() {
+	dv.Lay = gi.LayoutVert
+	dv.SetProp("spacing", gi.StdDialogVSpaceUnits)
+	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_Layout, "nav")
+	config.Add(gi.KiT_Layout, "days")
+	mods, updt := dv.ConfigChildren(config, false)
+	if mods {
+		dv.ConfigNav()
+		dv.ConfigDays()
+	} else {
+		updt = dv.UpdateStart()
+	}
+	dv.UpdateEnd(updt)
+}
+
+// Nav returns the layout with the month navigation actions
+func (dv *DateView) Nav() *gi.Layout {
+	idx, ok := dv.Children().IndexByName("nav", 0)
+	if !ok {
+		return nil
+	}
+	return dv.KnownChild(idx).(*gi.Layout)
+}
+
+// DaysGrid returns the grid layout of the days of the month
+func (dv *DateView) DaysGrid() *gi.Layout {
+	idx, ok := dv.Children().IndexByName("days", 1)
+	if !ok {
+		return nil
+	}
+	return dv.KnownChild(idx).(*gi.Layout)
+}
+
+// ConfigNav configures the month navigation actions
+func (dv *DateView) ConfigNav() {
+	nav := dv.Nav()
+	nav.Lay = gi.LayoutHoriz
+	nav.SetStretchMaxWidth()
+	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_Action, "prev")
+	config.Add(gi.KiT_Stretch, "str1")
+	config.Add(gi.KiT_Label, "month")
+	config.Add(gi.KiT_Stretch, "str2")
+	config.Add(gi.KiT_Action, "next")
+	mods, updt := nav.ConfigChildren(config, false)
+	if !mods {
+		updt = nav.UpdateStart()
+	}
+	for i, nm := range []string{"prev", "next"} {
+		ac := nav.KnownChildByName(nm, 0).(*gi.Action)
+		ac.Data = 2*i - 1 // months to add
+		if i == 0 {
+			ac.SetIcon("widget-wedge-left")
+			ac.Tooltip = "previous month"
+		} else {
+			ac.SetIcon("widget-wedge-right")
+			ac.Tooltip = "next month"
+		}
+		ac.ActionSig.ConnectOnly(dv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+			dvv, _ := recv.Embed(KiT_DateView).(*DateView)
+			dvv.Month = dvv.Month.AddDate(0, data.(int), 0)
+			dvv.UpdateDays()
+		})
+	}
+	lbl := nav.KnownChildByName("month", 0).(*gi.Label)
+	lbl.Redrawable = true
+	lbl.SetProp("min-width", units.NewValue(10, units.Em))
+	lbl.SetProp("text-align", gi.AlignCenter)
+	nav.UpdateEnd(updt)
+}
+
+// ConfigDays configures the grid of weekday labels and day actions, for six
+// weeks, which covers any month
+func (dv *DateView) ConfigDays() {
+	g := dv.DaysGrid()
+	g.Lay = gi.LayoutGrid
+	g.SetProp("columns", 7)
+	config := kit.TypeAndNameList{}
+	for i := 0; i < 7; i++ {
+		config.Add(gi.KiT_Label, fmt.Sprintf("wd-%v", i))
+	}
+	for i := 0; i < 42; i++ {
+		config.Add(gi.KiT_Action, fmt.Sprintf("day-%v", i))
+	}
+	mods, updt := g.ConfigChildren(config, false)
+	if !mods {
+		updt = g.UpdateStart()
+	}
+	for i := 0; i < 7; i++ {
+		lbl := g.KnownChild(i).(*gi.Label)
+		lbl.Text = time.Weekday((int(FirstWeekday) + i) % 7).String()[:2]
+		lbl.SetProp("text-align", gi.AlignCenter)
+	}
+	for i := 0; i < 42; i++ {
+		ac := g.KnownChild(7 + i).(*gi.Action)
+		ac.SetProp("min-width", units.NewValue(2, units.Em))
+		ac.ActionSig.ConnectOnly(dv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+			dvv, _ := recv.Embed(KiT_DateView).(*DateView)
+			if day, ok := data.(time.Time); ok {
+				dvv.SetDate(day)
+			}
+		})
+	}
+	g.UpdateEnd(updt)
+}
+
+// UpdateDays updates the month label and day actions for the current Month
+func (dv *DateView) UpdateDays() {
+	nav := dv.Nav()
+	g := dv.DaysGrid()
+	if nav == nil || g == nil {
+		return
+	}
+	updt := dv.UpdateStart()
+	lbl := nav.KnownChildByName("month", 0).(*gi.Label)
+	lbl.SetText(dv.Month.Format("January 2006"))
+	off := (int(dv.Month.Weekday()) - int(FirstWeekday) + 7) % 7
+	st := dv.Month.AddDate(0, 0, -off)
+	for i := 0; i < 42; i++ {
+		day := st.AddDate(0, 0, i)
+		ac := g.KnownChild(7 + i).(*gi.Action)
+		ac.Data = day
+		ac.SetText(strconv.Itoa(day.Day()))
+		ac.Tooltip = day.Format("Monday, January 2, 2006")
+		ac.SetInactiveState(!dv.DateInRange(day))
+		ac.SetSelectedState(SameDate(day, dv.Time))
+		ac.UpdateButtonStyle()
+	}
+	dv.SetFullReRender()
+	dv.UpdateEnd(updt)
+}
+
+// DateInRange returns true if given day is within the Min and Max limits,
+// on a whole-day basis
+func (dv *DateView) DateInRange(day time.Time) bool {
+	if !dv.Min.IsZero() && day.Before(dv.Min) && !SameDate(day, dv.Min) {
+		return false
+	}
+	if !dv.Max.IsZero() && day.After(dv.Max) && !SameDate(day, dv.Max) {
+		return false
+	}
+	return true
+}
+
+// SetDate sets the date of the Time to that of given day, preserving the
+// time of day (within the limits), shows its month, and emits the ViewSig
+func (dv *DateView) SetDate(day time.Time) {
+	tm := dv.Time
+	nt := time.Date(day.Year(), day.Month(), day.Day(), tm.Hour(), tm.Minute(), tm.Second(), tm.Nanosecond(), tm.Location())
+	dv.Time = ClampTime(nt, dv.Min, dv.Max)
+	dv.Month = time.Date(dv.Time.Year(), dv.Time.Month(), 1, 0, 0, 0, 0, dv.Time.Location())
+	dv.UpdateDays()
+	dv.ViewSig.Emit(dv.This, 0, dv.Time)
+}
+
+// SameDate returns true if the two times fall on the same calendar date
+func SameDate(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  TimeView
+
+// TimeView edits a time, using a DateView calendar for the date and spinners
+// for the time of day, as determined by its Format
+type TimeView struct {
+	gi.Frame
+	Time    time.Time `desc:"the time that we view and edit"`
+	Format  string    `desc:"time layout (as in time.Format) of the value being edited -- determines whether the date, the time of day, and the seconds are shown"`
+	Min     time.Time `desc:"earliest time that can be chosen -- zero for no limit"`
+	Max     time.Time `desc:"latest time that can be chosen -- zero for no limit"`
+	TmpSave ValueView `json:"-" xml:"-" desc:"value view that needs to have SaveTmp called on it whenever a change is made to one of the underlying values -- pass this down to any sub-views created from a parent"`
+	ViewSig ki.Signal `json:"-" xml:"-" desc:"signal for valueview -- only one signal sent when a value has been set -- data is the new Time"`
+}
+
+var KiT_TimeView = kit.Types.AddType(&TimeView{}, TimeViewProps)
+
+var TimeViewProps = ki.Props{
+	"background-color": &gi.Prefs.Colors.Background,
+	"color":            &gi.Prefs.Colors.Font,
+}
+
+// SetTime sets the time to edit, with given time layout (DefaultTimeFormat if
+// empty), and rebuilds the view
+func (tv *TimeView) SetTime(tm time.Time, format string, tmpSave ValueView) {
+	if format == "" {
+		format = DefaultTimeFormat
+	}
+	tv.Time = ClampTime(tm, tv.Min, tv.Max)
+	tv.Format = format
+	tv.TmpSave = tmpSave
+	tv.Config()
+	tv.Update()
+}
+
+// Config configures a standard setup of entire view
+func (tv *TimeView) Config() {
+	tv.Lay = gi.LayoutVert
+	tv.SetProp("spacing", gi.StdDialogVSpaceUnits)
+	date, tod, secs := TimeFormatParts(tv.Format)
+	if !date && !tod { // nothing to edit -- show everything
+		date, tod = true, true
+	}
+	config := kit.TypeAndNameList{}
+	if date {
+		config.Add(KiT_DateView, "date")
+	}
+	if tod {
+		config.Add(gi.KiT_Layout, "tod")
+	}
+	mods, updt := tv.ConfigChildren(config, false)
+	if !mods {
+		updt = tv.UpdateStart()
+	}
+	if dv := tv.DateView(); dv != nil {
+		dv.Min, dv.Max = tv.Min, tv.Max
+		dv.SetTime(tv.Time)
+		dv.ViewSig.ConnectOnly(tv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+			tvv, _ := recv.Embed(KiT_TimeView).(*TimeView)
+			dvv, _ := send.Embed(KiT_DateView).(*DateView)
+			tvv.SetTimeAction(dvv.Time)
+		})
+	}
+	if tod {
+		tv.ConfigTimeOfDay(secs)
+	}
+	tv.UpdateEnd(updt)
+}
+
+// DateView returns the DateView calendar -- nil if the date is not shown
+func (tv *TimeView) DateView() *DateView {
+	idx, ok := tv.Children().IndexByName("date", 0)
+	if !ok {
+		return nil
+	}
+	return tv.KnownChild(idx).(*DateView)
+}
+
+// TimeOfDay returns the layout with the time of day spinners -- nil if the
+// time of day is not shown
+func (tv *TimeView) TimeOfDay() *gi.Layout {
+	idx, ok := tv.Children().IndexByName("tod", 1)
+	if !ok {
+		return nil
+	}
+	return tv.KnownChild(idx).(*gi.Layout)
+}
+
+// ConfigTimeOfDay configures the hour, minute, and optionally second spinners
+func (tv *TimeView) ConfigTimeOfDay(secs bool) {
+	tl := tv.TimeOfDay()
+	tl.Lay = gi.LayoutHoriz
+	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_SpinBox, "hour")
+	config.Add(gi.KiT_Label, "hm-sep")
+	config.Add(gi.KiT_SpinBox, "min")
+	if secs {
+		config.Add(gi.KiT_Label, "ms-sep")
+		config.Add(gi.KiT_SpinBox, "sec")
+	}
+	mods, updt := tl.ConfigChildren(config, false)
+	if !mods {
+		updt = tl.UpdateStart()
+	}
+	for i, nm := range []string{"hour", "min", "sec"} {
+		sbk, ok := tl.ChildByName(nm, 2*i)
+		if !ok {
+			continue
+		}
+		sb := sbk.(*gi.SpinBox)
+		sb.Defaults()
+		sb.Step = 1
+		sb.PageStep = 10
+		sb.Prec = 2
+		sb.SetMin(0)
+		if i == 0 {
+			sb.SetMax(23)
+		} else {
+			sb.SetMax(59)
+		}
+		sb.SpinBoxSig.ConnectOnly(tv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+			tvv, _ := recv.Embed(KiT_TimeView).(*TimeView)
+			tvv.SetTimeOfDay()
+		})
+	}
+	for _, nm := range []string{"hm-sep", "ms-sep"} {
+		if lbk, ok := tl.ChildByName(nm, 1); ok {
+			lbk.(*gi.Label).Text = ":"
+		}
+	}
+	tl.UpdateEnd(updt)
+}
+
+// SetTimeOfDay sets the time of day from the spinners
+func (tv *TimeView) SetTimeOfDay() {
+	tl := tv.TimeOfDay()
+	if tl == nil {
+		return
+	}
+	var hms [3]int
+	for i, nm := range []string{"hour", "min", "sec"} {
+		if sbk, ok := tl.ChildByName(nm, 2*i); ok {
+			hms[i] = int(sbk.(*gi.SpinBox).Value)
+		} else {
+			hms[i] = tv.Time.Second()
+		}
+	}
+	tm := tv.Time
+	tv.SetTimeAction(time.Date(tm.Year(), tm.Month(), tm.Day(), hms[0], hms[1], hms[2], tm.Nanosecond(), tm.Location()))
+}
+
+// SetTimeAction sets the time (within the limits), updates the view, and
+// emits the ViewSig
+func (tv *TimeView) SetTimeAction(tm time.Time) {
+	tv.Time = ClampTime(tm, tv.Min, tv.Max)
+	tv.Update()
+	tv.ViewSig.Emit(tv.This, 0, tv.Time)
+}
+
+// Update updates the calendar and spinners from the current Time
+func (tv *TimeView) Update() {
+	updt := tv.UpdateStart()
+	if dv := tv.DateView(); dv != nil {
+		if !dv.Time.Equal(tv.Time) {
+			dv.SetTime(tv.Time)
+		}
+	}
+	if tl := tv.TimeOfDay(); tl != nil {
+		hms := [3]int{tv.Time.Hour(), tv.Time.Minute(), tv.Time.Second()}
+		for i, nm := range []string{"hour", "min", "sec"} {
+			if sbk, ok := tl.ChildByName(nm, 2*i); ok {
+				sbk.(*gi.SpinBox).SetValue(float32(hms[i]))
+			}
+		}
+	}
+	tv.UpdateEnd(updt)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  TimeValueView
+
+// TimeValueView presents an action showing a time.Time or FileTime value in
+// the layout of its format tag, which pulls up a TimeViewDialog for editing
+// it -- the min and max tags limit the times that can be chosen
+type TimeValueView struct {
+	ValueViewBase
+}
+
+var KiT_TimeValueView = kit.Types.AddType(&TimeValueView{}, nil)
+
+// TimeVal returns the current value as a time.Time
+func (vv *TimeValueView) TimeVal() time.Time {
+	switch tv := kit.NonPtrValue(vv.Value).Interface().(type) {
+	case time.Time:
+		return tv
+	case FileTime:
+		return time.Time(tv)
+	}
+	return time.Time{}
+}
+
+// SetTime sets the value from given time, converting to the value type
+func (vv *TimeValueView) SetTime(tm time.Time) bool {
+	if _, ok := kit.NonPtrValue(vv.Value).Interface().(FileTime); ok {
+		return vv.SetValue(FileTime(tm))
+	}
+	return vv.SetValue(tm)
+}
+
+// Format returns the time layout for the value, from its format tag, or the
+// default for its type
+func (vv *TimeValueView) Format() string {
+	if ftag, ok := vv.Tag("format"); ok {
+		return ftag
+	}
+	if _, ok := kit.NonPtrValue(vv.Value).Interface().(FileTime); ok {
+		return FileTimeFormat
+	}
+	return DefaultTimeFormat
+}
+
+func (vv *TimeValueView) WidgetType() reflect.Type {
+	vv.WidgetTyp = gi.KiT_Action
+	return vv.WidgetTyp
+}
+
+func (vv *TimeValueView) UpdateWidget() {
+	if vv.Widget == nil {
+		return
+	}
+	ac := vv.Widget.(*gi.Action)
+	tm := vv.TimeVal()
+	if tm.IsZero() {
+		ac.SetText("(none)")
+	} else {
+		ac.SetText(tm.Format(vv.Format()))
+	}
+}
+
+func (vv *TimeValueView) ConfigWidget(widg gi.Node2D) {
+	vv.Widget = widg
+	ac := vv.Widget.(*gi.Action)
+	ac.Tooltip, _ = vv.Tag("desc")
+	ac.SetInactiveState(vv.This.(ValueView).IsInactive())
+	ac.SetProp("border-radius", units.NewValue(4, units.Px))
+	ac.ActionSig.ConnectOnly(vv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+		vvv, _ := recv.Embed(KiT_TimeValueView).(*TimeValueView)
+		ac := vvv.Widget.(*gi.Action)
+		vvv.Activate(ac.Viewport, nil, nil)
+	})
+	vv.UpdateWidget()
+}
+
+func (vv *TimeValueView) HasAction() bool {
+	return true
+}
+
+func (vv *TimeValueView) Activate(vp *gi.Viewport2D, dlgRecv ki.Ki, dlgFunc ki.RecvFunc) {
+	if vv.IsInactive() {
+		return
+	}
+	format := vv.Format()
+	min, max := TimeTagLimits(vv, format)
+	tm := vv.TimeVal()
+	if tm.IsZero() {
+		tm = time.Now()
+	}
+	desc, _ := vv.Tag("desc")
+	TimeViewDialog(vp, tm, format, min, max, DlgOpts{Title: vv.Name(), Prompt: desc, TmpSave: vv.TmpSave},
+		vv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+			if sig == int64(gi.DialogAccepted) {
+				ddlg := send.Embed(gi.KiT_Dialog).(*gi.Dialog)
+				vv.SetTime(TimeViewDialogValue(ddlg))
+				vv.UpdateWidget()
+			}
+			if dlgRecv != nil && dlgFunc != nil {
+				dlgFunc(dlgRecv, send, sig, data)
+			}
+		})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  DurationValueView
+
+// DurationValueView presents a text field for editing a time.Duration, which
+// accepts durations with units (e.g., "1h30m") or plain numbers in the unit
+// of the format tag (seconds by default) -- see DurationUnits -- and enforces
+// the min and max tags -- text that does not parse is kept, without setting
+// the value, and its error is shown in place (see UpdateParseErr), and as a
+// validation error where enabled (see ParseErrValueView)
+type DurationValueView struct {
+	ValueViewBase
+	ParseErr error `json:"-" xml:"-" desc:"error parsing the text of the last edit -- nil if it parsed"`
+}
+
+var KiT_DurationValueView = kit.Types.AddType(&DurationValueView{}, nil)
+
+// Unit returns the unit from the format tag -- 0 if not set
+func (vv *DurationValueView) Unit() time.Duration {
+	if ftag, ok := vv.Tag("format"); ok {
+		return DurationUnits[ftag]
+	}
+	return 0
+}
+
+// ValueParseErr satisfies the ParseErrValueView interface
+func (vv *DurationValueView) ValueParseErr() error {
+	return vv.ParseErr
+}
+
+func (vv *DurationValueView) WidgetType() reflect.Type {
+	vv.WidgetTyp = gi.KiT_TextField
+	return vv.WidgetTyp
+}
+
+func (vv *DurationValueView) UpdateWidget() {
+	if vv.Widget == nil {
+		return
+	}
+	tf := vv.Widget.(*gi.TextField)
+	npv := kit.NonPtrValue(vv.Value)
+	d := time.Duration(npv.Int())
+	if vv.ParseErr != nil { // the text is replaced
+		vv.ParseErr = nil
+		vv.UpdateParseErr()
+	}
+	tf.SetText(FormatDuration(d, vv.Unit()))
+}
+
+// UpdateParseErr shows ParseErr in place, in the text field: its border is
+// in ValidateErrColor, and its tooltip is the error -- the desc tag otherwise
+func (vv *DurationValueView) UpdateParseErr() {
+	tf, ok := vv.Widget.(*gi.TextField)
+	if !ok {
+		return
+	}
+	updt := tf.UpdateStart()
+	if vv.ParseErr != nil {
+		tf.SetProp("border-color", ValidateErrColor)
+		tf.Tooltip = vv.ParseErr.Error()
+	} else {
+		tf.DeleteProp("border-color")
+		tf.Tooltip, _ = vv.Tag("desc")
+	}
+	if tf.Viewport != nil { // already styled
+		tf.Style2D()
+	}
+	tf.UpdateEnd(updt)
+}
+
+func (vv *DurationValueView) ConfigWidget(widg gi.Node2D) {
+	vv.Widget = widg
+	tf := vv.Widget.(*gi.TextField)
+	tf.Tooltip, _ = vv.Tag("desc")
+	tf.SetInactiveState(vv.This.(ValueView).IsInactive())
+	tf.SetProp("min-width", units.NewValue(10, units.Ch))
+	tf.TextFieldSig.ConnectOnly(vv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.TextFieldDone) {
+			vvv, _ := recv.Embed(KiT_DurationValueView).(*DurationValueView)
+			tf := send.(*gi.TextField)
+			unit := vvv.Unit()
+			d, err := ParseDuration(tf.Text(), unit)
+			if err != nil {
+				vvv.ParseErr = fmt.Errorf("invalid duration: %v", tf.Text())
+				vvv.UpdateParseErr()
+				vvv.ViewSig.Emit(vvv.This, 0, nil) // value not set, but views can show the error
+				return
+			}
+			if vvv.ParseErr != nil {
+				vvv.ParseErr = nil
+				vvv.UpdateParseErr()
+			}
+			min, max, hasMin, hasMax := DurationTagLimits(vvv, unit)
+			if hasMin && d < min {
+				d = min
+			}
+			if hasMax && d > max {
+				d = max
+			}
+			vvv.SetValue(d)
+			vvv.UpdateWidget() // always update, to show the standard format
+		}
+	})
+	vv.UpdateWidget()
+}
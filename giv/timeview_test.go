@@ -0,0 +1,223 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		str  string
+		unit time.Duration
+		d    time.Duration
+		ok   bool
+	}{
+		{"1h30m", 0, 90 * time.Minute, true},
+		{"90", 0, 90 * time.Second, true},
+		{"1.5", time.Minute, 90 * time.Second, true},
+		{"250", time.Millisecond, 250 * time.Millisecond, true},
+		{" 2 m ", 0, 2 * time.Minute, true},
+		{"-3s", time.Hour, -3 * time.Second, true},
+		{"abc", 0, 0, false},
+		{"", 0, 0, false},
+	}
+	for _, test := range tests {
+		d, err := ParseDuration(test.str, test.unit)
+		if (err == nil) != test.ok {
+			t.Errorf("ParseDuration(%q, %v): got error: %v, expected ok: %v\n", test.str, test.unit, err, test.ok)
+			continue
+		}
+		if test.ok && d != test.d {
+			t.Errorf("ParseDuration(%q, %v): got %v, expected %v\n", test.str, test.unit, d, test.d)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		unit time.Duration
+		str  string
+	}{
+		{90*time.Second + 400*time.Millisecond, time.Second, "1m30s"},
+		{1500 * time.Millisecond, 0, "1.5s"},
+		{89 * time.Second, time.Minute, "1m0s"},
+	}
+	for _, test := range tests {
+		if str := FormatDuration(test.d, test.unit); str != test.str {
+			t.Errorf("FormatDuration(%v, %v): got %q, expected %q\n", test.d, test.unit, str, test.str)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		str    string
+		format string
+		tm     time.Time
+		ok     bool
+	}{
+		{"2020-03-04 05:06:07", DefaultTimeFormat, time.Date(2020, 3, 4, 5, 6, 7, 0, time.Local), true},
+		{" 2020-03-04 ", "", time.Date(2020, 3, 4, 0, 0, 0, 0, time.Local), true},
+		{"03/04/2020", "01/02/2006", time.Date(2020, 3, 4, 0, 0, 0, 0, time.Local), true},
+		{"2020-03-04T05:06:07Z", "01/02/2006", time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC), true},
+		{"2020-03-04 05:06:07", "01/02/2006", time.Date(2020, 3, 4, 5, 6, 7, 0, time.Local), true},
+		{"nope", DefaultTimeFormat, time.Time{}, false},
+	}
+	for _, test := range tests {
+		tm, err := ParseTime(test.str, test.format)
+		if (err == nil) != test.ok {
+			t.Errorf("ParseTime(%q, %q): got error: %v, expected ok: %v\n", test.str, test.format, err, test.ok)
+			continue
+		}
+		if test.ok && !tm.Equal(test.tm) {
+			t.Errorf("ParseTime(%q, %q): got %v, expected %v\n", test.str, test.format, tm, test.tm)
+		}
+	}
+}
+
+func TestTimeFormatParts(t *testing.T) {
+	tests := []struct {
+		format          string
+		date, tod, secs bool
+	}{
+		{DefaultTimeFormat, true, true, true},
+		{"2006-01-02", true, false, false},
+		{"Jan 2", true, false, false},
+		{"15:04", false, true, false},
+		{"15:04:05", false, true, true},
+		{time.Kitchen, false, true, false},
+		{time.RFC3339, true, true, true},
+	}
+	for _, test := range tests {
+		date, tod, secs := TimeFormatParts(test.format)
+		if date != test.date || tod != test.tod || secs != test.secs {
+			t.Errorf("TimeFormatParts(%q): got %v, %v, %v, expected %v, %v, %v\n", test.format, date, tod, secs, test.date, test.tod, test.secs)
+		}
+	}
+}
+
+func TestClampTime(t *testing.T) {
+	min := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	max := time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC)
+	mid := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	after := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		tm, min, max, exp time.Time
+	}{
+		{mid, min, max, mid},
+		{before, min, max, min},
+		{after, min, max, max},
+		{before, time.Time{}, max, before},
+		{after, min, time.Time{}, after},
+		{after, time.Time{}, time.Time{}, after},
+	}
+	for _, test := range tests {
+		if tm := ClampTime(test.tm, test.min, test.max); !tm.Equal(test.exp) {
+			t.Errorf("ClampTime(%v, %v, %v): got %v, expected %v\n", test.tm, test.min, test.max, tm, test.exp)
+		}
+	}
+}
+
+// tagsValueView returns a ValueView for given pointer to a value, with given
+// tags
+func tagsValueView(ptr interface{}, tags map[string]string) ValueView {
+	vv := ToValueView(ptr)
+	vv.SetStandaloneValue(reflect.ValueOf(ptr))
+	vv.SetTags(tags)
+	return vv
+}
+
+func TestDurationTagLimits(t *testing.T) {
+	tests := []struct {
+		tags           map[string]string
+		unit           time.Duration
+		min, max       time.Duration
+		hasMin, hasMax bool
+	}{
+		{map[string]string{"min": "1m", "max": "90"}, time.Minute, time.Minute, 90 * time.Minute, true, true},
+		{map[string]string{"min": "0.5"}, 0, 500 * time.Millisecond, 0, true, false},
+		{map[string]string{"max": "2h"}, time.Second, 0, 2 * time.Hour, false, true},
+		{map[string]string{"min": "bad"}, 0, 0, 0, false, false},
+		{nil, 0, 0, 0, false, false},
+	}
+	for _, test := range tests {
+		var d time.Duration
+		vv := tagsValueView(&d, test.tags)
+		min, max, hasMin, hasMax := DurationTagLimits(vv, test.unit)
+		if hasMin != test.hasMin || hasMax != test.hasMax || (hasMin && min != test.min) || (hasMax && max != test.max) {
+			t.Errorf("DurationTagLimits(%v, %v): got %v, %v, %v, %v, expected %v, %v, %v, %v\n", test.tags, test.unit, min, max, hasMin, hasMax, test.min, test.max, test.hasMin, test.hasMax)
+		}
+	}
+}
+
+func TestTimeTagLimits(t *testing.T) {
+	var tm time.Time
+	vv := tagsValueView(&tm, map[string]string{"min": "2020-01-01", "max": "bad"})
+	min, max := TimeTagLimits(vv, "2006-01-02")
+	if exp := time.Date(2020, 1, 1, 0, 0, 0, 0, time.Local); !min.Equal(exp) {
+		t.Errorf("TimeTagLimits: got min %v, expected %v\n", min, exp)
+	}
+	if !max.IsZero() {
+		t.Errorf("TimeTagLimits: got max %v for an invalid tag, expected zero\n", max)
+	}
+}
+
+func TestValidateTimeLimits(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		tags map[string]string
+		ok   bool
+	}{
+		{30 * time.Second, map[string]string{"min": "1m", "max": "1h"}, false},
+		{2 * time.Hour, map[string]string{"min": "1m", "max": "1h"}, false},
+		{time.Hour, map[string]string{"min": "1m", "max": "1h"}, true},
+		{10 * time.Second, map[string]string{"min": "5", "format": "s"}, true},
+		{10 * time.Second, map[string]string{"min": "5", "format": "m"}, false},
+	}
+	for _, test := range tests {
+		d := test.d
+		vv := tagsValueView(&d, test.tags)
+		if err := ValidateValue(vv); (err == nil) != test.ok {
+			t.Errorf("ValidateValue(%v) with tags %v: got error: %v, expected ok: %v\n", test.d, test.tags, err, test.ok)
+		}
+	}
+	var tm time.Time
+	vv := tagsValueView(&tm, map[string]string{"min": "2020-01-01", "format": "2006-01-02"})
+	tm = time.Date(2019, 12, 31, 0, 0, 0, 0, time.Local)
+	if err := ValidateValue(vv); err == nil {
+		t.Errorf("ValidateValue(%v) with min 2020-01-01: expected error\n", tm)
+	}
+}
+
+func TestDurationParseErr(t *testing.T) {
+	var d time.Duration
+	vv := tagsValueView(&d, nil)
+	dvv, ok := vv.(*DurationValueView)
+	if !ok {
+		t.Fatalf("ToValueView(*time.Duration): got %T, expected *DurationValueView\n", vv)
+	}
+	if err := ValidateValue(vv); err != nil {
+		t.Errorf("ValidateValue: got error: %v, expected nil\n", err)
+	}
+	dvv.ParseErr = errors.New("invalid duration: 1x")
+	if err := ValidateValue(vv); err != dvv.ParseErr {
+		t.Errorf("ValidateValue: got error: %v, expected the ParseErr: %v\n", err, dvv.ParseErr)
+	}
+	// parse errors are shown in place, and do not enable validation
+	if HasValidation(&struct{ D time.Duration }{}) {
+		t.Errorf("HasValidation with a time.Duration field: got true, expected false\n")
+	}
+	if !HasValidation(&struct {
+		D time.Duration `required:"+"`
+	}{}) {
+		t.Errorf("HasValidation with a required time.Duration field: got false, expected true\n")
+	}
+}
@@ -0,0 +1,510 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"fmt"
+	"image"
+	"reflect"
+	"strings"
+
+	"github.com/goki/gi"
+	"github.com/goki/gi/oswin/mouse"
+	"github.com/goki/gi/units"
+	"github.com/goki/ki"
+	"github.com/goki/ki/kit"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  TreeTableView
+
+// TreeTableView is a TreeView with TableView-style columns showing fields of
+// the source nodes after their names: the tree is expanded and selected as in
+// a TreeView, clicking on a column header sorts the siblings by that column
+// (only the views are sorted -- the source tree is not changed), and the
+// values are edited in place through ValueViews unless the view is Inactive.
+// The nodes of the tree are TreeTableNode's, in the "tree-frame" Frame under
+// the "header" ToolBar.
+type TreeTableView struct {
+	gi.Frame
+	Columns   []string  `desc:"the fields of the source nodes shown in the columns after the name -- field names, with periods separating the names of fields within struct fields, e.g., Info.Size -- source nodes that do not have a field show nothing in its column"`
+	SortIdx   int       `desc:"the column that the siblings are sorted by: 0 for the name, 1.. for the Columns -- -1 shows them in the order of the source tree"`
+	SortDesc  bool      `desc:"whether the sort is descending"`
+	Changed   bool      `desc:"has a value been edited?"`
+	NameWidth float32   `json:"-" xml:"-" desc:"width of the name column, in dots, from the layout -- includes the indentation of the deepest open node"`
+	ColWidths []float32 `json:"-" xml:"-" desc:"widths of the Columns, in dots, from the layout"`
+	TmpSave   ValueView `json:"-" xml:"-" desc:"value view that needs to have SaveTmp called on it whenever a change is made to one of the underlying values -- pass this down to any sub-views created from a parent"`
+	ViewSig   ki.Signal `json:"-" xml:"-" desc:"signal for valueview -- only one signal sent when a value has been set -- all related value views interconnect with each other to update when others update"`
+}
+
+var KiT_TreeTableView = kit.Types.AddType(&TreeTableView{}, TreeTableViewProps)
+
+var TreeTableViewProps = ki.Props{
+	"background-color": &gi.Prefs.Colors.Background,
+	"color":            &gi.Prefs.Colors.Font,
+	"max-width":        -1,
+	"max-height":       -1,
+}
+
+// SetRootNode sets the root of the source tree that we are viewing, showing
+// the given fields of its nodes in the columns (see Columns) -- builds-out
+// the view of the tree
+func (ttv *TreeTableView) SetRootNode(sk ki.Ki, cols []string) {
+	mods, updt := ttv.StdConfig()
+	if !mods {
+		updt = ttv.UpdateStart()
+	}
+	if !sliceEqualStrings(ttv.Columns, cols) || ttv.Tree().SrcNode.Ptr != sk {
+		ttv.Columns = cols
+		ttv.SortIdx = -1
+		ttv.SortDesc = false
+		ttv.ColWidths = nil
+		ttv.SetFullReRender()
+	}
+	ttv.ConfigHeader()
+	ttv.SortTree()
+	ttv.Tree().SetRootNode(sk)
+	ttv.UpdateEnd(updt)
+}
+
+// sliceEqualStrings returns true if the two slices have the same strings
+func sliceEqualStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// StdConfig configures a standard setup of the overall Frame -- returns mods,
+// updt from ConfigChildren and does NOT call UpdateEnd
+func (ttv *TreeTableView) StdConfig() (mods, updt bool) {
+	ttv.Lay = gi.LayoutVert
+	ttv.SetProp("spacing", units.NewValue(0, units.Px))
+	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_ToolBar, "header")
+	config.Add(gi.KiT_Frame, "tree-frame")
+	mods, updt = ttv.ConfigChildren(config, false)
+	if mods {
+		tf := ttv.TreeFrame()
+		tf.Lay = gi.LayoutVert
+		tf.SetStretchMaxWidth()
+		tf.SetStretchMaxHeight()
+		tf.SetReRenderAnchor()
+		tf.AddNewChild(KiT_TreeTableNode, "tree")
+	}
+	return
+}
+
+// Header returns the ToolBar with the column headers
+func (ttv *TreeTableView) Header() *gi.ToolBar {
+	return ttv.KnownChild(0).(*gi.ToolBar)
+}
+
+// TreeFrame returns the Frame that holds (and scrolls) the tree
+func (ttv *TreeTableView) TreeFrame() *gi.Frame {
+	return ttv.KnownChild(1).(*gi.Frame)
+}
+
+// Tree returns the root TreeTableNode of the tree
+func (ttv *TreeTableView) Tree() *TreeTableNode {
+	return ttv.TreeFrame().KnownChild(0).(*TreeTableNode)
+}
+
+// ColumnName returns the header text for given column: 0 is the name, 1.. the
+// Columns, which show the last field name in their path
+func (ttv *TreeTableView) ColumnName(col int) string {
+	if col == 0 {
+		return "Name"
+	}
+	pth := ttv.Columns[col-1]
+	if li := strings.LastIndex(pth, "."); li >= 0 {
+		return pth[li+1:]
+	}
+	return pth
+}
+
+// ConfigHeader configures the header Actions, which sort the tree by their
+// column when clicked
+func (ttv *TreeTableView) ConfigHeader() {
+	hdr := ttv.Header()
+	hdr.Lay = gi.LayoutHoriz
+	hdr.SetStretchMaxWidth()
+	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_Action, "head-name")
+	for i := range ttv.Columns {
+		config.Add(gi.KiT_Action, fmt.Sprintf("head-%v", i))
+	}
+	mods, updt := hdr.ConfigChildren(config, false)
+	if !mods {
+		updt = hdr.UpdateStart()
+	}
+	for i, kid := range hdr.Kids {
+		act := kid.(*gi.Action)
+		act.SetText(ttv.ColumnName(i))
+		act.Data = i
+		act.Tooltip = "click to sort the siblings by this column / toggle sort direction"
+		act.ActionSig.ConnectOnly(ttv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+			ttvv := recv.Embed(KiT_TreeTableView).(*TreeTableView)
+			act := send.(*gi.Action)
+			ttvv.SortAction(act.Data.(int))
+		})
+	}
+	ttv.UpdateSortIcons()
+	hdr.UpdateEnd(updt)
+}
+
+// UpdateSortIcons shows the sort direction on the header of the sort column
+func (ttv *TreeTableView) UpdateSortIcons() {
+	for i, kid := range ttv.Header().Kids {
+		act := kid.(*gi.Action)
+		icnm := "none"
+		if i == ttv.SortIdx {
+			if ttv.SortDesc {
+				icnm = "widget-wedge-down"
+			} else {
+				icnm = "widget-wedge-up"
+			}
+		}
+		act.SetIcon(icnm)
+	}
+}
+
+// SortAction sorts the siblings of the tree by given column: 0 for the name,
+// 1.. for the Columns -- toggles the direction if already sorted by it
+func (ttv *TreeTableView) SortAction(col int) {
+	if ttv.SortIdx == col {
+		ttv.SortDesc = !ttv.SortDesc
+	} else {
+		ttv.SortIdx = col
+		ttv.SortDesc = false
+	}
+	updt := ttv.UpdateStart()
+	ttv.SetFullReRender()
+	ttv.UpdateSortIcons()
+	ttv.SortTree()
+	tn := ttv.Tree()
+	if tn.SrcNode.Ptr != nil {
+		tvIdx := 0
+		tn.SyncToSrc(&tvIdx)
+	}
+	ttv.UpdateEnd(updt)
+}
+
+// SortTree sets the SortLess ordering of the root of the tree from SortIdx
+// and SortDesc -- the views are re-ordered on the next SyncToSrc
+func (ttv *TreeTableView) SortTree() {
+	tn := ttv.Tree()
+	if ttv.SortIdx < 0 || ttv.SortIdx > len(ttv.Columns) {
+		tn.SortLess = nil
+		return
+	}
+	col, desc := ttv.SortIdx, ttv.SortDesc
+	tn.SortLess = func(a, b ki.Ki) bool {
+		var cmp int
+		if col == 0 {
+			cmp = strings.Compare(a.Name(), b.Name())
+		} else {
+			pth := ttv.Columns[col-1]
+			av, _, _, _ := TreeTableFieldByPath(a, pth)
+			bv, _, _, _ := TreeTableFieldByPath(b, pth)
+			cmp = CompareValues(av, bv)
+		}
+		if desc {
+			return cmp > 0
+		}
+		return cmp < 0
+	}
+}
+
+// SetChanged sets the Changed flag and emits the ViewSig signal for the
+// TreeTableView, indicating that some kind of edit / change has taken place
+// to the values of the source nodes
+func (ttv *TreeTableView) SetChanged() {
+	ttv.Changed = true
+	ttv.ViewSig.Emit(ttv.This, 0, nil)
+}
+
+// ColumnWidths computes the width of the name column, including the
+// indentation, and of each of the Columns, from the sizes of the header and
+// of the parts of the open nodes
+func (ttv *TreeTableView) ColumnWidths() (nmw float32, cws []float32) {
+	cws = make([]float32, len(ttv.Columns))
+	hdr := ttv.Header()
+	hspc := hdr.Spacing.Dots
+	for i, kid := range hdr.Kids {
+		wd := kid.(gi.Node2D).AsWidget().LayData.AllocSize.X + hspc
+		if i == 0 {
+			nmw = gi.Max32(nmw, wd)
+		} else if i-1 < len(cws) {
+			cws[i-1] = gi.Max32(cws[i-1], wd)
+		}
+	}
+	var walk func(tn *TreeTableNode, off float32)
+	walk = func(tn *TreeTableNode, off float32) {
+		ncol := len(tn.Parts.Kids) - len(tn.Values)
+		if len(tn.Values) != len(cws) { // parts not configured for the columns yet
+			ncol = len(tn.Parts.Kids)
+		}
+		spc := tn.Parts.Spacing.Dots
+		ext := off + tn.Sty.BoxSpace()
+		for i, kid := range tn.Parts.Kids {
+			wb := kid.(gi.Node2D).AsWidget()
+			if wb == nil {
+				continue
+			}
+			wd := wb.LayData.AllocSize.X + spc
+			if i < ncol {
+				ext += wd
+			} else {
+				cws[i-ncol] = gi.Max32(cws[i-ncol], wd)
+			}
+		}
+		nmw = gi.Max32(nmw, ext)
+		if tn.IsClosed() {
+			return
+		}
+		for _, kid := range tn.Kids {
+			if ktn, ok := kid.(*TreeTableNode); ok {
+				walk(ktn, off+tn.Indent.Dots)
+			}
+		}
+	}
+	walk(ttv.Tree(), 0)
+	return
+}
+
+func (ttv *TreeTableView) Layout2D(parBBox image.Rectangle, iter int) bool {
+	nmw, cws := ttv.ColumnWidths()
+	redo := false
+	if nmw != ttv.NameWidth || !sliceEqualFloat32s(cws, ttv.ColWidths) {
+		ttv.NameWidth = nmw
+		ttv.ColWidths = cws
+		redo = iter == 0 // sizes of the tree depend on the column widths
+	}
+	sumwd := nmw
+	for _, wd := range cws {
+		sumwd += wd
+	}
+	hdr := ttv.Header()
+	hdr.SetMinPrefWidth(units.NewValue(sumwd, units.Dot))
+	if ttv.Frame.Layout2D(parBBox, iter) {
+		redo = true
+	}
+	ttv.LayoutHeader(iter)
+	return redo
+}
+
+// LayoutHeader positions the header Actions over the columns of the tree
+func (ttv *TreeTableView) LayoutHeader(iter int) {
+	hdr := ttv.Header()
+	tn := ttv.Tree()
+	hbb := hdr.ChildrenBBox2D()
+	x := tn.LayData.AllocPosOrig.X
+	for i, kid := range hdr.Kids {
+		wd := ttv.NameWidth
+		if i > 0 {
+			if i-1 >= len(ttv.ColWidths) {
+				break
+			}
+			wd = ttv.ColWidths[i-1]
+		}
+		ni := kid.(gi.Node2D)
+		wb := ni.AsWidget()
+		wb.LayData.AllocPosRel.X = x - hdr.LayData.AllocPosOrig.X
+		wb.LayData.AllocSize.X = gi.Max32(wd-hdr.Spacing.Dots, 0)
+		ni.Layout2D(hbb, iter)
+		x += wd
+	}
+}
+
+// sliceEqualFloat32s returns true if the two slices have the same values
+func sliceEqualFloat32s(a, b []float32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TreeTableFieldByPath returns the value of the field at given path within
+// the struct of source node k -- field names, with periods separating the
+// names of fields within struct fields -- along with the field and its owner
+// (k itself for fields of the node) -- ok is false if k has no such field
+func TreeTableFieldByPath(k ki.Ki, path string) (fval reflect.Value, field reflect.StructField, owner interface{}, ok bool) {
+	if kit.IfaceIsNil(k) {
+		return
+	}
+	owner = k
+	stru := kit.NonPtrValue(reflect.ValueOf(k))
+	flds := strings.Split(path, ".")
+	for i, fnm := range flds {
+		if stru.Kind() != reflect.Struct {
+			return fval, field, owner, false
+		}
+		field, ok = stru.Type().FieldByName(fnm)
+		if !ok {
+			return
+		}
+		fval = stru.FieldByIndex(field.Index)
+		if i < len(flds)-1 {
+			stru = kit.NonPtrValue(fval)
+			if stru.CanAddr() {
+				owner = stru.Addr().Interface()
+			}
+		}
+	}
+	return
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  TreeTableNode
+
+// TreeTableNode is the TreeView for the nodes of a TreeTableView, with
+// ValueView widgets for the values of the Columns after its label, which are
+// aligned across the tree at the column widths of the TreeTableView
+type TreeTableNode struct {
+	TreeView
+	Values []ValueView `json:"-" xml:"-" desc:"ValueViews of the values of the Columns -- nil for those the source node does not have"`
+}
+
+var KiT_TreeTableNode = kit.Types.AddType(&TreeTableNode{}, TreeViewProps)
+
+// TreeTableView returns the TreeTableView that we are part of -- nil if none
+func (tn *TreeTableNode) TreeTableView() *TreeTableView {
+	ttvi, ok := tn.ParentByType(KiT_TreeTableView, true)
+	if !ok {
+		return nil
+	}
+	return ttvi.Embed(KiT_TreeTableView).(*TreeTableView)
+}
+
+// ColumnsConfig adds the widgets for the values of the Columns to the parts
+// config -- see TreeViewColumns
+func (tn *TreeTableNode) ColumnsConfig(config *kit.TypeAndNameList) {
+	tn.Values = nil
+	ttv := tn.TreeTableView()
+	if ttv == nil || tn.SrcNode.Ptr == nil {
+		return
+	}
+	tn.Values = make([]ValueView, len(ttv.Columns))
+	for i, pth := range ttv.Columns {
+		nm := fmt.Sprintf("col-%v", i)
+		fval, field, owner, ok := TreeTableFieldByPath(tn.SrcNode.Ptr, pth)
+		if !ok {
+			config.Add(gi.KiT_Label, nm)
+			continue
+		}
+		vv := ToValueView(fval.Interface())
+		if vv == nil { // shouldn't happen
+			config.Add(gi.KiT_Label, nm)
+			continue
+		}
+		vv.SetStructValue(fval.Addr(), owner, &field, ttv.TmpSave)
+		tn.Values[i] = vv
+		config.Add(vv.WidgetType(), nm)
+	}
+}
+
+// ConfigColumns configures the widgets for the values of the Columns -- see
+// TreeViewColumns
+func (tn *TreeTableNode) ConfigColumns(mods bool) {
+	ttv := tn.TreeTableView()
+	if ttv == nil {
+		return
+	}
+	ncol := len(tn.Parts.Kids) - len(tn.Values)
+	for i, vv := range tn.Values {
+		widg := tn.Parts.KnownChild(ncol + i).(gi.Node2D)
+		if vv == nil {
+			widg.(*gi.Label).SetText("")
+			continue
+		}
+		vv.ConfigWidget(widg)
+		if ttv.IsInactive() {
+			widg.AsNode2D().SetInactive()
+		} else {
+			vvb := vv.AsValueViewBase()
+			vvb.ViewSig.ConnectOnly(ttv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+				ttvv := recv.Embed(KiT_TreeTableView).(*TreeTableView)
+				ttvv.SetChanged()
+			})
+		}
+		if wb := widg.AsWidget(); wb != nil {
+			wb.SetProp("text-overflow", "ellipsis")
+			wb.WidgetSig.ConnectOnly(tn.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+				if sig == int64(gi.WidgetFocused) {
+					tnn := recv.Embed(KiT_TreeTableNode).(*TreeTableNode)
+					if !tnn.IsSelected() {
+						tnn.SelectAction(mouse.NoSelectMode)
+					}
+				}
+			})
+		}
+		if mods {
+			tn.StylePart(widg)
+		}
+	}
+}
+
+func (tn *TreeTableNode) Size2D(iter int) {
+	tn.TreeView.Size2D(iter)
+	if tn.RootView != &tn.TreeView {
+		return
+	}
+	if ttv := tn.TreeTableView(); ttv != nil {
+		w := ttv.NameWidth
+		for _, wd := range ttv.ColWidths {
+			w += wd
+		}
+		tn.LayData.AllocSize.X = gi.Max32(tn.LayData.AllocSize.X, w)
+		tn.WidgetSize.X = tn.LayData.AllocSize.X
+	}
+}
+
+func (tn *TreeTableNode) Layout2D(parBBox image.Rectangle, iter int) bool {
+	redo := tn.TreeView.Layout2D(parBBox, iter)
+	if !tn.HasClosedParent() {
+		tn.LayoutColumns(iter)
+	}
+	return redo
+}
+
+// LayoutColumns limits the label to the name column and positions the value
+// widgets in the columns, which start at the same X for all the nodes
+func (tn *TreeTableNode) LayoutColumns(iter int) {
+	ttv := tn.TreeTableView()
+	if ttv == nil || len(ttv.ColWidths) != len(tn.Values) || tn.RootView == nil {
+		return
+	}
+	pbb := tn.Parts.ChildrenBBox2D()
+	ppos := tn.Parts.LayData.AllocPosOrig.X
+	spc := tn.Parts.Spacing.Dots
+	x := tn.RootView.LayData.AllocPosOrig.X + ttv.NameWidth
+	if lbl, ok := tn.LabelPart(); ok {
+		lbl.LayData.AllocSize.X = gi.Max32(x-lbl.LayData.AllocPos.X-spc, 0)
+		lbl.Layout2D(pbb, iter)
+	}
+	ncol := len(tn.Parts.Kids) - len(tn.Values)
+	for i, wd := range ttv.ColWidths {
+		ni := tn.Parts.KnownChild(ncol + i).(gi.Node2D)
+		wb := ni.AsWidget()
+		if wb == nil {
+			continue
+		}
+		wb.LayData.AllocPosRel.X = x - ppos
+		wb.LayData.AllocSize.X = gi.Max32(wd-spc, 0)
+		ni.Layout2D(pbb, iter)
+		x += wd
+	}
+}
@@ -11,6 +11,9 @@ import (
 	"image/color"
 	"log"
 	"reflect"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/chewxy/math32"
 	"github.com/goki/gi"
@@ -39,12 +42,21 @@ type TreeView struct {
 	SrcNode          ki.Ptr                    `desc:"Ki Node that this widget is viewing in the tree -- the source"`
 	ShowViewCtxtMenu bool                      `desc:"if the object we're viewing has its own CtxtMenu property defined, should we also still show the view's own context menu?"`
 	ViewIdx          int                       `desc:"linear index of this node within the entire tree -- updated on full rebuilds and may sometimes be off, but close enough for expected uses"`
+	RowIdx           int                       `json:"-" xml:"-" desc:"index of the source node of this view within the children (fields first) of the source node of the parent view -- a virtual parent only has views for some of its children (see TreeViewVirtualMin)"`
 	Indent           units.Value               `xml:"indent" desc:"styled amount to indent children relative to this node"`
 	TreeViewSig      ki.Signal                 `json:"-" xml:"-" desc:"signal for TreeView -- all are emitted from the root tree view widget, with data = affected node -- see TreeViewSignals for the types"`
 	StateStyles      [TreeViewStatesN]gi.Style `json:"-" xml:"-" desc:"styles for different states of the widget -- everything inherits from the base Style which is styled first according to the user-set styles, and then subsequent style settings can override that"`
 	WidgetSize       gi.Vec2D                  `desc:"just the size of our widget -- our alloc includes all of our children, but we only draw us"`
 	Icon             gi.IconName               `json:"-" xml:"icon" view:"show-name" desc:"optional icon, displayed to the the left of the text label"`
 	RootView         *TreeView                 `json:"-" xml:"-" desc:"cached root of the view"`
+	SortLess         func(a, b ki.Ki) bool     `json:"-" xml:"-" view:"-" desc:"optional ordering of the children of each source node, set on the root view -- only the views are sorted, not the source tree -- nil shows the children in source order"`
+	kidsDisconnected bool
+	virtRows         ki.Slice // source nodes of all the children of a virtual node -- nil if not virtual
+	virtStart        int      // first row of the children in view of a virtual node
+	virtN            int      // number of rows of the children in view of a virtual node, from virtStart
+	virtRowHt        float32  // height of the views of the closed children of a virtual node
+	virtRelayout     bool     // views of the children of a virtual node were re-bound since the last layout
+	virtBinding      bool     // views of the children of a virtual node are being re-bound within Render2D
 }
 
 var KiT_TreeView = kit.Types.AddType(&TreeView{}, TreeViewProps)
@@ -72,6 +84,9 @@ func (tv *TreeView) SetSrcNode(sk ki.Ki, tvIdx *int) {
 	updt := false
 	if tv.SrcNode.Ptr != sk {
 		updt = tv.UpdateStart()
+		if tv.SrcNode.Ptr != nil { // re-used for another node by a virtual parent
+			tv.SrcNode.Ptr.NodeSignal().Disconnect(tv.This)
+		}
 		tv.SrcNode.Ptr = sk
 		sk.NodeSignal().Connect(tv.This, SrcNodeSignal) // we recv signals from source
 	}
@@ -80,7 +95,8 @@ func (tv *TreeView) SetSrcNode(sk ki.Ki, tvIdx *int) {
 }
 
 // SyncToSrc updates the view tree to match the source tree, using
-// ConfigChildren to maximally preserve existing tree elements
+// ConfigChildren to maximally preserve existing tree elements -- or
+// SyncVirtual for a node with at least TreeViewVirtualMin children
 func (tv *TreeView) SyncToSrc(tvIdx *int) {
 	pr := prof.Start("TreeView.SyncToSrc")
 	sk := tv.SrcNode.Ptr
@@ -93,8 +109,19 @@ func (tv *TreeView) SyncToSrc(tvIdx *int) {
 	if tvPar != nil {
 		tv.RootView = tvPar.RootView
 	}
+	if tv.IsClosed() && tv != tv.RootView && !tv.HasChildren() {
+		pr.End()
+		return // views of children are only made when opened -- see Open
+	}
 	vcprop := "view-closed"
 	skids := *sk.Children()
+	if tv.RootView != nil && tv.RootView.SortLess != nil && len(skids) > 1 {
+		skids = append(ki.Slice{}, skids...)
+		less := tv.RootView.SortLess
+		sort.SliceStable(skids, func(i, j int) bool {
+			return less(skids[i], skids[j])
+		})
+	}
 	tnl := make(kit.TypeAndNameList, 0, len(skids))
 	typ := tv.This.Type() // always make our type
 	flds := make([]ki.Ki, 0)
@@ -116,6 +143,12 @@ func (tv *TreeView) SyncToSrc(tvIdx *int) {
 		fldClosed = append(fldClosed, cls)
 		return true
 	})
+	if len(flds)+len(skids) >= TreeViewVirtualMin {
+		tv.SyncVirtual(append(ki.Slice(flds), skids...), tvIdx)
+		pr.End()
+		return
+	}
+	tv.virtRows = nil
 	for _, skid := range skids {
 		tnl.Add(typ, "tv_"+skid.UniqueName())
 	}
@@ -127,22 +160,27 @@ func (tv *TreeView) SyncToSrc(tvIdx *int) {
 	idx := 0
 	for i, fld := range flds {
 		vk := tv.Kids[idx].Embed(KiT_TreeView).(*TreeView)
-		vk.SetSrcNode(fld, tvIdx)
-		if mods {
-			vk.SetClosedState(fldClosed[i])
+		if mods { // before setting the source, so closed ones don't make views of their children
+			vk.SetClosedState(fldClosed[i] || SrcHasUnloadedChildren(fld))
 		}
+		vk.RowIdx = idx
+		vk.SetSrcNode(fld, tvIdx)
 		idx++
 	}
-	for _, skid := range *sk.Children() {
+	for _, skid := range skids {
 		vk := tv.Kids[idx].Embed(KiT_TreeView).(*TreeView)
-		vk.SetSrcNode(skid, tvIdx)
 		if mods {
 			if vcp, ok := skid.PropInherit(vcprop, false, true); ok {
 				if vc, ok := kit.ToBool(vcp); vc && ok {
 					vk.SetClosed()
 				}
 			}
+			if SrcHasUnloadedChildren(skid) {
+				vk.SetClosed()
+			}
 		}
+		vk.RowIdx = idx
+		vk.SetSrcNode(skid, tvIdx)
 		idx++
 	}
 	if !sk.HasChildren() {
@@ -433,12 +471,15 @@ func (tv *TreeView) SelectAll() {
 	if win != nil {
 		updt = win.UpdateStart()
 	}
+	rupdt := tv.RootView.UpdateStart() // views re-bound by virtual nodes render once
 	tv.UnselectAll()
 	nn := tv.RootView
 	nn.Select()
 	for nn != nil {
 		nn = nn.MoveDown(mouse.SelectModesN) // just select
 	}
+	tv.RootView.SetFullReRender()
+	tv.RootView.UpdateEnd(rupdt)
 	if win != nil {
 		win.UpdateEnd(updt)
 	}
@@ -476,6 +517,7 @@ func (tv *TreeView) SelectAction(mode mouse.SelectModes) {
 			}
 			cidx := tv.ViewIdx
 			nn := tv
+			rupdt := tv.RootView.UpdateStart() // views re-bound by virtual nodes render once
 			tv.Select()
 			if tv.ViewIdx < minIdx {
 				for cidx < minIdx {
@@ -488,6 +530,8 @@ func (tv *TreeView) SelectAction(mode mouse.SelectModes) {
 					cidx = nn.ViewIdx
 				}
 			}
+			tv.RootView.SetFullReRender()
+			tv.RootView.UpdateEnd(rupdt)
 		}
 	case mouse.ExtendOne:
 		if tv.IsSelected() {
@@ -546,7 +590,7 @@ func (tv *TreeView) MoveDown(selMode mouse.SelectModes) *TreeView {
 		return tv.MoveDownSibling(selMode)
 	} else {
 		if tv.HasChildren() {
-			nn := tv.KnownChild(0).Embed(KiT_TreeView).(*TreeView)
+			nn := tv.RowView(0)
 			if nn != nil {
 				nn.SelectAction(selMode)
 				return nn
@@ -577,15 +621,16 @@ func (tv *TreeView) MoveDownSibling(selMode mouse.SelectModes) *TreeView {
 	if tv == tv.RootView {
 		return nil
 	}
-	myidx, ok := tv.IndexInParent()
-	if ok && myidx < len(*tv.Par.Children())-1 {
-		nn := tv.Par.KnownChild(myidx + 1).Embed(KiT_TreeView).(*TreeView)
+	ptv := tv.Par.Embed(KiT_TreeView).(*TreeView)
+	myidx, ok := tv.RowInParent()
+	if ok && myidx < ptv.NRows()-1 {
+		nn := ptv.RowView(myidx + 1)
 		if nn != nil {
 			nn.SelectAction(selMode)
 			return nn
 		}
 	} else {
-		return tv.Par.Embed(KiT_TreeView).(*TreeView).MoveDownSibling(selMode) // try up
+		return ptv.MoveDownSibling(selMode) // try up
 	}
 	return nil
 }
@@ -601,9 +646,9 @@ func (tv *TreeView) MoveUp(selMode mouse.SelectModes) *TreeView {
 			selMode = mouse.ExtendContinuous
 		}
 	}
-	myidx, ok := tv.IndexInParent()
+	myidx, ok := tv.RowInParent()
 	if ok && myidx > 0 {
-		nn := tv.Par.KnownChild(myidx - 1).Embed(KiT_TreeView).(*TreeView)
+		nn := tv.Par.Embed(KiT_TreeView).(*TreeView).RowView(myidx - 1)
 		if nn != nil {
 			return nn.MoveToLastChild(selMode)
 		}
@@ -638,9 +683,8 @@ func (tv *TreeView) MoveToLastChild(selMode mouse.SelectModes) *TreeView {
 		return nil
 	}
 	if !tv.IsClosed() && tv.HasChildren() {
-		nnk, ok := tv.Children().ElemFromEnd(0)
-		if ok {
-			nn := nnk.Embed(KiT_TreeView).(*TreeView)
+		nn := tv.RowView(tv.NRows() - 1)
+		if nn != nil {
 			return nn.MoveToLastChild(selMode)
 		}
 	} else {
@@ -663,20 +707,39 @@ func (tv *TreeView) Close() {
 	}
 }
 
-// Open opens the given node and updates the view accordingly (if it is not already opened)
+// Open opens the given node and updates the view accordingly (if it is not
+// already opened) -- makes the views of its children if not yet made, and
+// loads them first if the source is a TreeViewProvider that has not loaded
+// them yet, fetching them in the background if it is a
+// TreeViewAsyncProvider
 func (tv *TreeView) Open() {
-	if tv.IsClosed() {
-		updt := tv.UpdateStart()
-		if tv.HasChildren() {
-			tv.SetFullReRender()
+	if !tv.IsClosed() || tv.IsLoading() {
+		return
+	}
+	loaded := false
+	if SrcHasUnloadedChildren(tv.SrcNode.Ptr) {
+		prov := tv.SrcNode.Ptr.(TreeViewProvider)
+		if aprov, ok := prov.(TreeViewAsyncProvider); ok && aprov.LoadAsync() {
+			tv.LoadChildrenAsync(aprov)
+			return
 		}
-		if tv.HasChildren() {
-			tv.SetClosedState(false)
+		if err := prov.LoadChildren(); err != nil {
+			log.Printf("giv.TreeView Open: error loading children of: %v err: %v\n", tv.SrcNode.Ptr.PathUnique(), err)
 		}
-		// send signal in any case -- dynamic trees can open a node here!
-		tv.RootView.TreeViewSig.Emit(tv.RootView.This, int64(TreeViewOpened), tv.This)
-		tv.UpdateEnd(updt)
+		loaded = true
+	}
+	updt := tv.UpdateStart()
+	if loaded || tv.HasBranch() { // if nothing was loaded, the branch goes away
+		tv.SetFullReRender()
 	}
+	if tv.HasBranch() {
+		tv.SetClosedState(false)
+		tvIdx := tv.ViewIdx
+		tv.SyncToSrc(&tvIdx)
+	}
+	// send signal in any case -- dynamic trees can open a node here!
+	tv.RootView.TreeViewSig.Emit(tv.RootView.This, int64(TreeViewOpened), tv.This)
+	tv.UpdateEnd(updt)
 }
 
 // ToggleClose toggles the close / open status: if closed, opens, and vice-versa
@@ -688,6 +751,525 @@ func (tv *TreeView) ToggleClose() {
 	}
 }
 
+//////////////////////////////////////////////////////////////////////////////
+//    Lazy loading
+
+// For large trees, views are made only for the nodes that are open (see
+// Open), and the views of rows that are out of view are not rendered.  A
+// node with many children is virtual (see TreeViewVirtualMin): it only has
+// views for the rows of its children that are in view, which are re-used as
+// it is scrolled.
+
+// TreeViewProvider is an interface for source nodes that load their children
+// only when they are opened in a TreeView -- for large trees, or slow
+// backends such as a directory on a network mount
+type TreeViewProvider interface {
+	// HasUnloadedChildren returns true if the node may have children that
+	// have not been loaded yet -- the TreeView then shows it as a closed
+	// branch, even though it has no children
+	HasUnloadedChildren() bool
+
+	// LoadChildren loads the children of the node, adding them as its Ki
+	// children within an UpdateStart / UpdateEnd -- always called on the GUI
+	// thread (within an update of the window)
+	LoadChildren() error
+}
+
+// TreeViewAsyncProvider is a TreeViewProvider whose children can be slow to
+// load: the slow part (e.g., I/O) is done by the function from FetchFunc in
+// the background, while the TreeView shows a spinner, and then LoadFetched
+// adds the fetched children within an update of the window
+type TreeViewAsyncProvider interface {
+	TreeViewProvider
+
+	// LoadAsync returns true if the children should be fetched in the
+	// background, e.g., only for a slow backend
+	LoadAsync() bool
+
+	// FetchFunc returns a function that does the slow part of loading the
+	// children -- FetchFunc is called on the GUI thread, but the function
+	// is called in the background, so it must not use the node or anything
+	// else that is shown, which can change meanwhile -- what it returns is
+	// passed to LoadFetched
+	FetchFunc() func() (interface{}, error)
+
+	// LoadFetched loads the children of the node from what was returned by
+	// the function from FetchFunc, as LoadChildren does -- always called on
+	// the GUI thread (within an update of the window)
+	LoadFetched(fetched interface{}) error
+}
+
+// SrcHasUnloadedChildren returns true if given source node is a
+// TreeViewProvider with children that have not been loaded yet
+func SrcHasUnloadedChildren(sk ki.Ki) bool {
+	if prov, ok := sk.(TreeViewProvider); ok {
+		return prov.HasUnloadedChildren()
+	}
+	return false
+}
+
+// HasBranch returns true if this node has children to show when opened:
+// views of children, source children or fields that do not have views yet
+// (which are only made when opened), or children of a TreeViewProvider
+// source that have not been loaded yet
+func (tv *TreeView) HasBranch() bool {
+	if tv.HasChildren() {
+		return true
+	}
+	sk := tv.SrcNode.Ptr
+	if sk == nil {
+		return false
+	}
+	if sk.HasChildren() {
+		return true
+	}
+	hasFlds := false
+	sk.FuncFields(0, nil, func(k ki.Ki, level int, d interface{}) bool {
+		hasFlds = true
+		return false
+	})
+	return hasFlds || SrcHasUnloadedChildren(sk)
+}
+
+// IsLoading returns true if the children of this node are being loaded in
+// the background
+func (tv *TreeView) IsLoading() bool {
+	treeViewLoadingMu.Lock()
+	_, loading := treeViewLoading[tv]
+	treeViewLoadingMu.Unlock()
+	return loading
+}
+
+// LabelText returns the text shown in the label: the Label, after a spinner
+// while loading
+func (tv *TreeView) LabelText() string {
+	treeViewLoadingMu.Lock()
+	_, loading := treeViewLoading[tv]
+	frame := treeViewSpinFrame
+	treeViewLoadingMu.Unlock()
+	if loading {
+		return TreeViewSpinnerFrames[frame%len(TreeViewSpinnerFrames)] + " " + tv.Label()
+	}
+	return tv.Label()
+}
+
+// TreeViewSpinnerFrames are the frames of the spinner shown before the label
+// of a node while its children are being loaded
+var TreeViewSpinnerFrames = []string{"◐", "◓", "◑", "◒"}
+
+// TreeViewSpinnerMSec is the number of milliseconds between frames of the
+// spinner
+var TreeViewSpinnerMSec = 150
+
+// treeViewSpinner is the time.Ticker for the spinners of loading nodes --
+// only runs while any are loading
+var treeViewSpinner *time.Ticker
+
+// treeViewSpinFrame is the current frame of the spinners
+var treeViewSpinFrame int
+
+// treeViewLoading are the nodes that are loading their children -- it is
+// their loading state, so that it is only changed under treeViewLoadingMu
+var treeViewLoading = map[*TreeView]struct{}{}
+
+// treeViewLoadingMu is the mutex for treeViewLoading, treeViewSpinner and
+// treeViewSpinFrame
+var treeViewLoadingMu sync.Mutex
+
+// setLoading sets whether this node is loading its children
+func (tv *TreeView) setLoading(loading bool) {
+	treeViewLoadingMu.Lock()
+	if loading {
+		treeViewLoading[tv] = struct{}{}
+	} else {
+		delete(treeViewLoading, tv)
+	}
+	treeViewLoadingMu.Unlock()
+}
+
+// LoadChildrenAsync fetches the children of the node from given provider in
+// the background, showing a spinner, and then loads them and opens the node,
+// within an update of the window -- nothing but the fetching is done in the
+// background
+func (tv *TreeView) LoadChildrenAsync(prov TreeViewAsyncProvider) {
+	fetch := prov.FetchFunc()
+	src := tv.SrcNode.Ptr
+	treeViewLoadingMu.Lock()
+	treeViewLoading[tv] = struct{}{}
+	if treeViewSpinner == nil {
+		treeViewSpinner = time.NewTicker(time.Duration(TreeViewSpinnerMSec) * time.Millisecond)
+		go TreeViewSpin(treeViewSpinner)
+	}
+	treeViewLoadingMu.Unlock()
+	tv.UpdateSig()
+	go func() {
+		fetched, ferr := fetch() // only the slow part -- uses nothing shown
+		win := tv.ParentWindow()
+		if win == nil { // not shown, so nothing to update -- fetched again when opened
+			tv.setLoading(false)
+			return
+		}
+		wupdt := win.UpdateStart()
+		defer win.UpdateEnd(wupdt)
+		tv.setLoading(false)
+		if tv.IsDestroyed() || tv.IsDeleted() || tv.SrcNode.Ptr != src {
+			return
+		}
+		err := ferr
+		if err == nil {
+			err = prov.LoadFetched(fetched)
+		}
+		if err != nil {
+			log.Printf("giv.TreeView Open: error loading children of: %v err: %v\n", tv.SrcNode.Ptr.PathUnique(), err)
+		}
+		if SrcHasUnloadedChildren(tv.SrcNode.Ptr) { // failed -- Open would try again
+			tv.UpdateSig()
+		} else {
+			tv.SetFullReRender() // in case nothing was loaded, so the branch goes away
+			tv.Open()
+		}
+	}()
+}
+
+// TreeViewSpin is the function that animates the spinners of the nodes that
+// are loading, until there are none, updating them within an update of the
+// window
+func TreeViewSpin(tick *time.Ticker) {
+	for {
+		<-tick.C
+		treeViewLoadingMu.Lock()
+		if len(treeViewLoading) == 0 {
+			tick.Stop()
+			treeViewSpinner = nil
+			treeViewLoadingMu.Unlock()
+			return
+		}
+		treeViewSpinFrame++
+		tvs := make([]*TreeView, 0, len(treeViewLoading))
+		for tv := range treeViewLoading {
+			tvs = append(tvs, tv)
+		}
+		treeViewLoadingMu.Unlock()
+		for _, tv := range tvs {
+			if tv.IsDestroyed() || tv.IsDeleted() || tv.Viewport == nil {
+				continue
+			}
+			win := tv.ParentWindow()
+			if win == nil || win.IsResizing() || win.IsClosed() || win.IsUpdating() {
+				continue
+			}
+			wupdt := win.UpdateStart()
+			tv.UpdateSig()
+			win.UpdateEnd(wupdt)
+		}
+	}
+}
+
+//////////////////////////////////////////////////////////////////////////////
+//    Virtual rows
+
+// TreeViewVirtualMin is the number of children (fields included) at which a
+// node is virtual: it only has views for the children in view, plus those
+// that are open, selected, focused or loading, and the views of the others
+// are re-used for the rows that come into view when scrolling -- children of
+// a virtual node start out closed
+var TreeViewVirtualMin = 500
+
+// TreeViewVirtualMargin is the number of rows of a virtual node that have
+// views beyond those in view, so that scrolling a little does not re-bind
+var TreeViewVirtualMargin = 20
+
+// IsVirtual returns true if this node only has views for some of its children
+// -- see TreeViewVirtualMin
+func (tv *TreeView) IsVirtual() bool {
+	return tv.virtRows != nil
+}
+
+// NRows returns the number of children of this node, including those of a
+// virtual node that do not have views
+func (tv *TreeView) NRows() int {
+	if tv.IsVirtual() {
+		return len(tv.virtRows)
+	}
+	return len(tv.Kids)
+}
+
+// RowView returns the view of the child in given row (see RowIdx) -- the
+// views of a virtual node are re-bound to the rows around it if it has none
+// -- nil if out of range
+func (tv *TreeView) RowView(row int) *TreeView {
+	if row < 0 || row >= tv.NRows() {
+		return nil
+	}
+	if !tv.IsVirtual() {
+		return tv.KnownChild(row).Embed(KiT_TreeView).(*TreeView)
+	}
+	if vk := tv.rowView(row); vk != nil {
+		return vk
+	}
+	updt := tv.UpdateStart()
+	tv.BindVirtual(row-tv.virtN/2, tv.virtN, nil)
+	tv.virtRelayout = true
+	tv.SetFullReRender()
+	tv.UpdateEnd(updt)
+	return tv.rowView(row)
+}
+
+// rowView returns the view of the child in given row of a virtual node, if it
+// has one
+func (tv *TreeView) rowView(row int) *TreeView {
+	i := sort.Search(len(tv.Kids), func(i int) bool {
+		return tv.Kids[i].Embed(KiT_TreeView).(*TreeView).RowIdx >= row
+	})
+	if i < len(tv.Kids) {
+		if vk := tv.Kids[i].Embed(KiT_TreeView).(*TreeView); vk.RowIdx == row {
+			return vk
+		}
+	}
+	return nil
+}
+
+// RowInParent returns the row of this view within the children of its
+// parent view -- see RowIdx
+func (tv *TreeView) RowInParent() (int, bool) {
+	if ptv, ok := tv.Par.Embed(KiT_TreeView).(*TreeView); ok && ptv.IsVirtual() {
+		return tv.RowIdx, true
+	}
+	return tv.IndexInParent()
+}
+
+// KeepView returns true if the view of this child of a virtual node must not
+// be re-used for another row: it is open, selected, focused or loading
+func (tv *TreeView) KeepView() bool {
+	return !tv.IsClosed() || tv.IsSelected() || tv.HasFocus() || tv.IsLoading()
+}
+
+// SyncVirtual updates the views of the children of this virtual node for
+// given source nodes of all of its children -- the views that are kept (see
+// KeepView) stay with their source nodes, and the others are re-used for the
+// rows in view (see BindVirtual)
+func (tv *TreeView) SyncVirtual(rows ki.Slice, tvIdx *int) {
+	updt := tv.UpdateStart()
+	tv.virtRows = rows
+	srcViews := make(map[ki.Ki]*TreeView, len(tv.Kids))
+	for _, kid := range tv.Kids {
+		vk := kid.Embed(KiT_TreeView).(*TreeView)
+		vk.RowIdx = -1
+		if vk.SrcNode.Ptr != nil {
+			srcViews[vk.SrcNode.Ptr] = vk
+		}
+	}
+	if len(srcViews) > 0 {
+		for r, sk := range rows {
+			if vk, ok := srcViews[sk]; ok {
+				vk.RowIdx = r
+			}
+		}
+	}
+	if tv.virtN == 0 {
+		tv.virtN = TreeViewVirtualMargin
+	}
+	tv.BindVirtual(tv.virtStart, tv.virtN, tvIdx)
+	tv.SetFullReRender()
+	tv.UpdateEnd(updt)
+}
+
+// BindVirtual binds the views of the children of this virtual node to the n
+// rows from start, in addition to the views that are kept (see KeepView) --
+// the views of other rows are re-used for them, or destroyed if not needed.
+// If tvIdx is non-nil, all the views are synced to their source nodes, as in
+// SyncToSrc, otherwise only the re-used ones are -- must be called within an
+// UpdateStart / End of this node
+func (tv *TreeView) BindVirtual(start, n int, tvIdx *int) {
+	nr := len(tv.virtRows)
+	n = ints.MinInt(n, nr)
+	start = ints.MaxInt(ints.MinInt(start, nr-n), 0)
+	tv.virtStart, tv.virtN = start, n
+	views := make(map[int]*TreeView, len(tv.Kids))
+	var free []*TreeView
+	for _, kid := range tv.Kids {
+		vk := kid.Embed(KiT_TreeView).(*TreeView)
+		r := vk.RowIdx
+		if r >= 0 && r < nr && (vk.KeepView() || (r >= start && r < start+n)) {
+			views[r] = vk
+		} else {
+			free = append(free, vk)
+		}
+	}
+	rows := make([]int, 0, len(views)+n)
+	for r := range views {
+		if r < start || r >= start+n {
+			rows = append(rows, r)
+		}
+	}
+	for r := start; r < start+n; r++ {
+		rows = append(rows, r)
+	}
+	sort.Ints(rows)
+
+	kids := make(ki.Slice, len(rows))
+	bind := make([]bool, len(rows))
+	var newKids []int
+	for i, r := range rows {
+		if vk, ok := views[r]; ok {
+			kids[i] = vk.This
+			continue
+		}
+		bind[i] = true
+		if len(free) > 0 {
+			vk := free[len(free)-1]
+			free = free[:len(free)-1]
+			vk.resetVirtual()
+			vk.RowIdx = r
+			kids[i] = vk.This
+		} else {
+			newKids = append(newKids, i)
+		}
+	}
+	for _, vk := range free {
+		vk.disconnectSrcTree()
+		vk.Destroy()
+	}
+	tv.Kids = kids
+	typ := tv.This.Type()
+	for _, i := range newKids {
+		nk := ki.NewOfType(typ)
+		tv.SetChild(nk, i, fmt.Sprintf("tv_%v", rows[i]))
+		vk := nk.Embed(KiT_TreeView).(*TreeView)
+		vk.SetClosed()
+		vk.RowIdx = rows[i]
+	}
+
+	idx := tv.ViewIdx + 1
+	if tvIdx != nil {
+		idx = *tvIdx
+	}
+	prev := 0
+	for i, kid := range tv.Kids {
+		vk := kid.Embed(KiT_TreeView).(*TreeView)
+		idx += vk.RowIdx - prev
+		prev = vk.RowIdx + 1
+		if tvIdx != nil || bind[i] {
+			vk.SetSrcNode(tv.virtRows[vk.RowIdx], &idx)
+		} else {
+			vk.ViewIdx = idx
+			idx = vk.lastViewIdx() + 1
+		}
+	}
+	if tvIdx != nil {
+		*tvIdx = idx + nr - prev
+	}
+}
+
+// resetVirtual resets this view of a child of a virtual node, to re-use it
+// for another row: it is closed, without views of its own children
+func (tv *TreeView) resetVirtual() {
+	if tv.HasChildren() {
+		for _, kid := range tv.Kids {
+			kid.Embed(KiT_TreeView).(*TreeView).disconnectSrcTree()
+		}
+		tv.DeleteChildren(true)
+	}
+	tv.SetClosed()
+	tv.kidsDisconnected = false
+	tv.virtRows = nil
+	tv.virtStart, tv.virtN = 0, 0
+}
+
+// disconnectSrcTree disconnects this view and all the views below it from
+// the signals of their source nodes, when they are destroyed
+func (tv *TreeView) disconnectSrcTree() {
+	tv.FuncDownMeFirst(0, tv.This, func(k ki.Ki, level int, d interface{}) bool {
+		kv, ok := k.Embed(KiT_TreeView).(*TreeView)
+		if !ok {
+			return false
+		}
+		if kv.SrcNode.Ptr != nil {
+			kv.SrcNode.Ptr.NodeSignal().Disconnect(kv.This)
+		}
+		return true
+	})
+}
+
+// lastViewIdx returns the ViewIdx of the last view below this one, counting
+// the children of virtual nodes that do not have views
+func (tv *TreeView) lastViewIdx() int {
+	if tv.IsClosed() || tv.NRows() == 0 {
+		return tv.ViewIdx
+	}
+	if tv.IsVirtual() {
+		vk, ok := tv.Kids.ElemFromEnd(0)
+		if !ok {
+			return tv.ViewIdx + len(tv.virtRows)
+		}
+		kv := vk.Embed(KiT_TreeView).(*TreeView)
+		return kv.lastViewIdx() + len(tv.virtRows) - 1 - kv.RowIdx
+	}
+	return tv.KnownChild(len(tv.Kids) - 1).Embed(KiT_TreeView).(*TreeView).lastViewIdx()
+}
+
+// VirtualRowHeight returns the height of the views of closed children of
+// this virtual node, from the first one that has a size
+func (tv *TreeView) VirtualRowHeight() float32 {
+	for _, kid := range tv.Kids {
+		vk := kid.Embed(KiT_TreeView).(*TreeView)
+		if vk.IsClosed() && vk.LayData.AllocSize.Y > 0 {
+			return math32.Ceil(vk.LayData.AllocSize.Y)
+		}
+	}
+	return math32.Ceil(tv.WidgetSize.Y)
+}
+
+// VirtualRowAt returns the row of the child of this virtual node at given
+// vertical offset from its top, counting rows that do not have views
+func (tv *TreeView) VirtualRowAt(y float32) int {
+	h := math32.Ceil(tv.WidgetSize.Y)
+	if y < h {
+		return 0
+	}
+	prev := 0
+	for _, kid := range tv.Kids {
+		vk := kid.Embed(KiT_TreeView).(*TreeView)
+		gap := float32(vk.RowIdx-prev) * tv.virtRowHt
+		if y < h+gap {
+			return prev + int((y-h)/tv.virtRowHt)
+		}
+		h += gap
+		kh := math32.Ceil(vk.LayData.AllocSize.Y)
+		if y < h+kh {
+			return vk.RowIdx
+		}
+		h += kh
+		prev = vk.RowIdx + 1
+	}
+	return ints.MinInt(prev+int((y-h)/tv.virtRowHt), len(tv.virtRows)-1)
+}
+
+// VirtualRebindIfNeeded re-binds the views of the children of this virtual
+// node if the rows in view do not all have views -- returns true if so, and
+// it then needs to be laid out and rendered again
+func (tv *TreeView) VirtualRebindIfNeeded() bool {
+	if !tv.IsVirtual() || tv.IsClosed() || tv.virtRowHt <= 0 {
+		return false
+	}
+	vis := tv.ChildrenBBox2D()
+	if vis.Empty() {
+		return false
+	}
+	first := tv.VirtualRowAt(float32(vis.Min.Y) - tv.LayData.AllocPos.Y)
+	last := tv.VirtualRowAt(float32(vis.Max.Y) - tv.LayData.AllocPos.Y)
+	if first >= tv.virtStart && last < tv.virtStart+tv.virtN {
+		return false
+	}
+	if first == last && tv.rowView(first) != nil { // within a kept view
+		return false
+	}
+	updt := tv.UpdateStart()
+	tv.BindVirtual(first-TreeViewVirtualMargin/2, last-first+1+TreeViewVirtualMargin, nil)
+	tv.UpdateEndNoSig(updt)
+	return true
+}
+
 //////////////////////////////////////////////////////////////////////////////
 //    Modifying Source Tree
 
@@ -1317,7 +1899,7 @@ func (tv *TreeView) TreeViewEvents() {
 			tvv.Open()
 		}
 	})
-	if tv.HasChildren() {
+	if tv.HasBranch() {
 		if wb, ok := tv.BranchPart(); ok {
 			wb.ButtonSig.ConnectOnly(tv.This, func(recv, send ki.Ki, sig int64, data interface{}) {
 				if sig == int64(gi.ButtonToggled) {
@@ -1391,16 +1973,20 @@ func (tv *TreeView) LabelPart() (*gi.Label, bool) {
 func (tv *TreeView) ConfigParts() {
 	tv.Parts.Lay = gi.LayoutHoriz
 	config := kit.TypeAndNameList{}
-	if tv.HasChildren() {
+	if tv.HasBranch() {
 		config.Add(gi.KiT_CheckBox, "branch")
 	}
 	if tv.Icon.IsValid() {
 		config.Add(gi.KiT_Icon, "icon")
 	}
 	config.Add(gi.KiT_Label, "label")
+	tvc, hasCols := tv.This.(TreeViewColumns)
+	if hasCols {
+		tvc.ColumnsConfig(&config)
+	}
 	mods, updt := tv.Parts.ConfigChildren(config, false) // not unique names
 	// if mods {
-	if tv.HasChildren() {
+	if tv.HasBranch() {
 		if wb, ok := tv.BranchPart(); ok {
 			wb.SetProp("#icon0", TVBranchProps)
 			wb.SetProp("#icon1", TVBranchProps)
@@ -1437,23 +2023,38 @@ func (tv *TreeView) ConfigParts() {
 		}
 	}
 	if lbl, ok := tv.LabelPart(); ok {
-		lbl.SetText(tv.Label())
+		lbl.SetText(tv.LabelText())
 		if mods {
 			tv.StylePart(gi.Node2D(lbl))
 		}
 	}
+	if hasCols {
+		tvc.ConfigColumns(mods)
+	}
 	tv.Parts.UpdateEnd(updt)
 }
 
+// TreeViewColumns is an interface for types of TreeView that show columns of
+// values after the label in their parts, e.g., TreeTableNode -- ConfigParts
+// calls it on This
+type TreeViewColumns interface {
+	// ColumnsConfig adds the parts for the columns to the parts config
+	ColumnsConfig(config *kit.TypeAndNameList)
+
+	// ConfigColumns configures the column parts once made -- mods is true if
+	// the parts were changed
+	ConfigColumns(mods bool)
+}
+
 func (tv *TreeView) ConfigPartsIfNeeded() {
 	if !tv.Parts.HasChildren() {
 		tv.ConfigParts()
 	}
 	if lbl, ok := tv.LabelPart(); ok {
-		lbl.SetText(tv.Label())
+		lbl.SetText(tv.LabelText())
 		lbl.Sty.Font.Color = tv.Sty.Font.Color
 	}
-	if tv.HasChildren() {
+	if tv.HasBranch() {
 		if wb, ok := tv.BranchPart(); ok {
 			wb.SetChecked(!tv.IsClosed())
 		}
@@ -1601,7 +2202,7 @@ func (tv *TreeView) Init2D() {
 }
 
 func (tv *TreeView) StyleTreeView() {
-	if !tv.HasChildren() {
+	if !tv.HasBranch() {
 		tv.SetClosed()
 	}
 	if tv.HasClosedParent() {
@@ -1653,6 +2254,10 @@ func (tv *TreeView) Size2D(iter int) {
 			h += math32.Ceil(gis.LayData.AllocSize.Y)
 			w = gi.Max32(w, tv.Indent.Dots+gis.LayData.AllocSize.X)
 		}
+		if tv.IsVirtual() { // rows without views
+			tv.virtRowHt = tv.VirtualRowHeight()
+			h += float32(len(tv.virtRows)-len(tv.Kids)) * tv.virtRowHt
+		}
 	}
 	tv.LayData.AllocSize = gi.Vec2D{w, h}
 	tv.WidgetSize.X = w // stretch
@@ -1694,12 +2299,19 @@ func (tv *TreeView) Layout2D(parBBox image.Rectangle, iter int) bool {
 
 	tv.Layout2DParts(parBBox, iter) // use OUR version
 	h := math32.Ceil(tv.WidgetSize.Y)
+	tv.virtRelayout = false
 	if !tv.IsClosed() {
+		prev := 0
 		for _, kid := range tv.Kids {
 			ni := kid.(gi.Node2D).AsWidget()
 			if ni == nil {
 				continue
 			}
+			if tv.IsVirtual() { // skip rows without views
+				r := kid.Embed(KiT_TreeView).(*TreeView).RowIdx
+				h += float32(r-prev) * tv.virtRowHt
+				prev = r + 1
+			}
 			ni.LayData.AllocPosRel.Y = h
 			ni.LayData.AllocPosRel.X = tv.Indent.Dots
 			h += math32.Ceil(ni.LayData.AllocSize.Y)
@@ -1732,6 +2344,12 @@ func (tv *TreeView) Render2D() {
 	// if tv.FullReRenderIfNeeded() { // custom stuff here
 	// 	return
 	// }
+	if !tv.virtBinding && (tv.virtRelayout || tv.VirtualRebindIfNeeded()) {
+		tv.virtBinding = true // views of children were re-bound: layout again
+		tv.ReRender2DTree()
+		tv.virtBinding = false
+		return
+	}
 	if tv.PushBounds() {
 		if tv.IsSelected() {
 			tv.Sty = tv.StateStyles[TreeViewSel]
@@ -1760,10 +2378,33 @@ func (tv *TreeView) Render2D() {
 	} else {
 		tv.DisconnectAllEvents(gi.AllPris)
 	}
-	// we always have to render our kids b/c we could be out of scope but they could be in!
+	// we have to render our kids even if we are out of view, as they could be
+	// in view -- but not if all of us including them is out of view, which
+	// keeps large trees responsive
+	if tv.ChildrenBBox2D().Empty() {
+		tv.DisconnectKidsEvents()
+		return
+	}
+	tv.kidsDisconnected = false
 	tv.Render2DChildren()
 }
 
+// DisconnectKidsEvents disconnects the events of all the views below this
+// one, when they are not rendered because they are out of view
+func (tv *TreeView) DisconnectKidsEvents() {
+	if tv.kidsDisconnected {
+		return
+	}
+	tv.kidsDisconnected = true
+	for _, kid := range tv.Kids {
+		if kt, ok := kid.(gi.Node2D); ok && kid.TypeEmbeds(KiT_TreeView) {
+			kv := kid.Embed(KiT_TreeView).(*TreeView)
+			kt.AsNode2D().DisconnectAllEvents(gi.AllPris)
+			kv.DisconnectKidsEvents()
+		}
+	}
+}
+
 func (tv *TreeView) FocusChanged2D(change gi.FocusChanges) {
 	switch change {
 	case gi.FocusLost:
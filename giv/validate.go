@@ -0,0 +1,286 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goki/ki/kit"
+)
+
+// Field values in a StructView are validated after each edit, according to
+// the following struct tags, and the Validator interface if the struct or the
+// field type implements it -- failing fields are highlighted with the error
+// message, and StructViewDialog blocks Ok while the struct is invalid -- this
+// is only enabled for structs with a Validator or any of the
+// HasValidationTags, so min / max alone, which SpinBox already enforces, do
+// not add the error column to existing views -- text that fails to parse is
+// shown in place by its ValueView either way (see ParseErrValueView):
+//
+// * required:"+" -- value must be non-zero (non-empty for strings, slices, maps)
+// * min:"n", max:"n" -- numerical value must be within range (as used for
+//   SpinBox) -- durations and times use ParseDuration and ParseTime
+// * len:"n" or len:"min:max" -- length of strings (in runes), slices, or maps
+//   must be n, or within range, where either end of the range can be empty
+// * regex:"expr" -- string value must fully match the regular expression
+// * oneof:"a b c" -- string representation of value must be one of the
+//   space-separated values
+
+// Validator is an optional interface for structs, and the types of their
+// fields, that validates their current values -- returns an error describing
+// the problem if invalid, nil if valid.
+type Validator interface {
+	Validate() error
+}
+
+// ParseErrValueView is an optional interface for ValueViews that edit their
+// value as text that can fail to parse, e.g., DurationValueView -- the value
+// is not set then, the ValueView shows the error in place, and ValueParseErr
+// returns it, as does ValidateValue, so that it is also shown as a
+// validation error where enabled (see HasValidation) -- nil if it parsed
+type ParseErrValueView interface {
+	ValueParseErr() error
+}
+
+// ValidateTags are the struct field tags used for validation
+var ValidateTags = []string{"required", "min", "max", "len", "regex", "oneof"}
+
+// HasValidationTags are the validation tags that enable validation of the
+// fields of a struct in a StructView (see HasValidation) -- all but min and
+// max, which predate validation as SpinBox limits
+var HasValidationTags = []string{"required", "len", "regex", "oneof"}
+
+// validateRegexps caches compiled regex tags
+var validateRegexps = map[string]*regexp.Regexp{}
+
+// validateMu protects validateRegexps
+var validateMu sync.Mutex
+
+// ValidateValue validates the value of given ValueView, according to its
+// validation tags (see ValidateTags), and the Validator interface if its
+// value implements it -- returns nil if valid
+func ValidateValue(vv ValueView) error {
+	if pv, ok := vv.(ParseErrValueView); ok {
+		if err := pv.ValueParseErr(); err != nil {
+			return err
+		}
+	}
+	vvb := vv.AsValueViewBase()
+	if !vvb.Value.IsValid() {
+		return nil
+	}
+	npv := kit.NonPtrValue(vvb.Value)
+	if _, ok := vv.Tag("required"); ok {
+		if validateIsEmpty(npv) {
+			return errors.New("required")
+		}
+	}
+	if !npv.IsValid() {
+		return nil
+	}
+	if err := validateTimeLimits(vv, npv); err != nil {
+		return err
+	}
+	if mintag, ok := vv.Tag("min"); ok {
+		min, err := strconv.ParseFloat(mintag, 64)
+		if fv, isnum := validateFloat(npv); isnum && err == nil && fv < min {
+			return fmt.Errorf("must be at least %v", mintag)
+		}
+	}
+	if maxtag, ok := vv.Tag("max"); ok {
+		max, err := strconv.ParseFloat(maxtag, 64)
+		if fv, isnum := validateFloat(npv); isnum && err == nil && fv > max {
+			return fmt.Errorf("must be at most %v", maxtag)
+		}
+	}
+	if lentag, ok := vv.Tag("len"); ok {
+		if err := validateLen(npv, lentag); err != nil {
+			return err
+		}
+	}
+	if retag, ok := vv.Tag("regex"); ok && npv.Kind() == reflect.String {
+		re := validateRegexp(retag)
+		if re != nil && !re.MatchString(npv.String()) {
+			return fmt.Errorf("must match the pattern: %v", retag)
+		}
+	}
+	if oftag, ok := vv.Tag("oneof"); ok {
+		str := kit.ToString(npv.Interface())
+		ofs := strings.Fields(oftag)
+		has := false
+		for _, of := range ofs {
+			if str == of {
+				has = true
+				break
+			}
+		}
+		if !has {
+			return fmt.Errorf("must be one of: %v", strings.Join(ofs, ", "))
+		}
+	}
+	if vd, ok := vvb.Value.Interface().(Validator); ok {
+		return vd.Validate()
+	}
+	if npv.CanInterface() {
+		if vd, ok := npv.Interface().(Validator); ok {
+			return vd.Validate()
+		}
+	}
+	return nil
+}
+
+// ValidateStruct validates given struct using its Validator interface, if it
+// implements it -- returns nil if valid
+func ValidateStruct(st interface{}) error {
+	if kit.IfaceIsNil(st) {
+		return nil
+	}
+	if vd, ok := st.(Validator); ok {
+		return vd.Validate()
+	}
+	return nil
+}
+
+// HasValidation returns true if given struct has any validation, either
+// through the Validator interface on the struct or its field types, or
+// validation tags on its fields (see HasValidationTags) -- all of the
+// ValidateTags are then checked, including min and max
+func HasValidation(st interface{}) bool {
+	if kit.IfaceIsNil(st) {
+		return false
+	}
+	if _, ok := st.(Validator); ok {
+		return true
+	}
+	vtyp := reflect.TypeOf((*Validator)(nil)).Elem()
+	has := false
+	kit.FlatFieldsValueFunc(st, func(fval interface{}, typ reflect.Type, field reflect.StructField, fieldVal reflect.Value) bool {
+		for _, tag := range HasValidationTags {
+			if _, ok := field.Tag.Lookup(tag); ok {
+				has = true
+				return false
+			}
+		}
+		if reflect.PtrTo(field.Type).Implements(vtyp) {
+			has = true
+			return false
+		}
+		return true
+	})
+	return has
+}
+
+// validateIsEmpty returns true if given non-pointer value is empty, for the
+// required tag
+func validateIsEmpty(npv reflect.Value) bool {
+	switch npv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return npv.Len() == 0
+	}
+	return kit.ValueIsZero(npv)
+}
+
+// validateFloat returns the value as a float64 if it is a number
+func validateFloat(npv reflect.Value) (float64, bool) {
+	vk := npv.Kind()
+	if vk < reflect.Int || vk > reflect.Float64 || npv.Type() == reflect.TypeOf(time.Duration(0)) {
+		return 0, false
+	}
+	return kit.ToFloat(npv.Interface())
+}
+
+// validateTimeLimits checks time.Duration, time.Time, and FileTime values
+// against the min and max tags, which are parsed as durations or times (see
+// DurationTagLimits, TimeTagLimits)
+func validateTimeLimits(vv ValueView, npv reflect.Value) error {
+	ftag, _ := vv.Tag("format")
+	var tm time.Time
+	switch tv := npv.Interface().(type) {
+	case time.Duration:
+		min, max, hasMin, hasMax := DurationTagLimits(vv, DurationUnits[ftag])
+		if hasMin && tv < min {
+			return fmt.Errorf("must be at least %v", min)
+		}
+		if hasMax && tv > max {
+			return fmt.Errorf("must be at most %v", max)
+		}
+		return nil
+	case time.Time:
+		tm = tv
+	case FileTime:
+		tm = time.Time(tv)
+	default:
+		return nil
+	}
+	if ftag == "" {
+		ftag = DefaultTimeFormat
+	}
+	min, max := TimeTagLimits(vv, ftag)
+	if !min.IsZero() && tm.Before(min) {
+		return fmt.Errorf("must be no earlier than %v", min.Format(ftag))
+	}
+	if !max.IsZero() && tm.After(max) {
+		return fmt.Errorf("must be no later than %v", max.Format(ftag))
+	}
+	return nil
+}
+
+// validateLen checks the length of given non-pointer value against a len tag
+func validateLen(npv reflect.Value, lentag string) error {
+	var ln int
+	switch npv.Kind() {
+	case reflect.String:
+		ln = len([]rune(npv.String()))
+	case reflect.Slice, reflect.Map, reflect.Array:
+		ln = npv.Len()
+	default:
+		return nil
+	}
+	mins, maxs := lentag, lentag
+	if ci := strings.Index(lentag, ":"); ci >= 0 {
+		mins, maxs = lentag[:ci], lentag[ci+1:]
+	}
+	if mins != "" {
+		if min, err := strconv.Atoi(mins); err == nil && ln < min {
+			if mins == maxs {
+				return fmt.Errorf("length must be %v", min)
+			}
+			return fmt.Errorf("length must be at least %v", min)
+		}
+	}
+	if maxs != "" {
+		if max, err := strconv.Atoi(maxs); err == nil && ln > max {
+			if mins == maxs {
+				return fmt.Errorf("length must be %v", max)
+			}
+			return fmt.Errorf("length must be at most %v", max)
+		}
+	}
+	return nil
+}
+
+// validateRegexp returns the compiled regexp for given regex tag, which must
+// match the full string -- nil if invalid
+func validateRegexp(expr string) *regexp.Regexp {
+	validateMu.Lock()
+	defer validateMu.Unlock()
+	if re, ok := validateRegexps[expr]; ok {
+		return re
+	}
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		log.Printf("giv.ValidateValue: invalid regex tag %q: %v\n", expr, err)
+	}
+	validateRegexps[expr] = re
+	return re
+}
@@ -0,0 +1,251 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"reflect"
+
+	"github.com/goki/gi"
+	"github.com/goki/ki"
+	"github.com/goki/ki/kit"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  ValueUndo
+
+// ValueUndoMax is the maximum number of records kept in the ValueUndo of a
+// window -- the oldest are dropped beyond that
+var ValueUndoMax = 1000
+
+// ValueUndoRec is one change to Go values made through the views, e.g., the
+// setting of a value by a ValueView, or an insert / delete in a SliceView
+type ValueUndoRec struct {
+	Desc   string `desc:"description of the change"`
+	Group  int64  `desc:"EventSeq of the window when the change was made -- all the changes made in response to one user action (event) are undone / redone together"`
+	Undo   func() `desc:"restores the values from before the change"`
+	Redo   func() `desc:"re-applies the change"`
+	Update func() `desc:"updates the views after an undo or redo, emitting their normal ViewSig signal so that other views of the values also update"`
+}
+
+// ValueUndo is the undo / redo history of the changes to Go values made
+// through the StructView, TableView, SliceView and MapView views in a window,
+// which binds KeyFunUndo and KeyFunRedo to Undo and Redo -- see
+// WindowValueUndo
+type ValueUndo struct {
+	Win      *gi.Window      `desc:"the window that the changes are made in"`
+	Recs     []*ValueUndoRec `desc:"the records of the changes, oldest first"`
+	Pos      int             `desc:"undo position: number of Recs that are done -- those after are undone, and can be redone"`
+	applying bool
+}
+
+// WindowValueUndo returns the ValueUndo of given window, stored on it as the
+// "value-undo" property -- makes it if needed -- nil if win is nil
+func WindowValueUndo(win *gi.Window) *ValueUndo {
+	if win == nil {
+		return nil
+	}
+	if vui, ok := win.Prop("value-undo"); ok {
+		if vu, ok := vui.(*ValueUndo); ok {
+			return vu
+		}
+	}
+	vu := &ValueUndo{Win: win}
+	win.SetProp("value-undo", vu)
+	return vu
+}
+
+// ValueUndoFor returns the ValueUndo of the window of given view node -- nil
+// if it is not in a window
+func ValueUndoFor(nd gi.Node2D) *ValueUndo {
+	if kit.IfaceIsNil(nd) {
+		return nil
+	}
+	return WindowValueUndo(nd.AsNode2D().ParentWindow())
+}
+
+// SaveUndo saves given record of a change, in the group of the current event
+// of the window -- discards any undone records, which can no longer be
+// redone -- nil safe, and does nothing during Undo / Redo
+func (vu *ValueUndo) SaveUndo(rec *ValueUndoRec) {
+	if vu == nil || vu.applying {
+		return
+	}
+	rec.Group = vu.Win.EventSeq
+	if vu.Pos < len(vu.Recs) {
+		vu.Recs = vu.Recs[:vu.Pos]
+	}
+	vu.Recs = append(vu.Recs, rec)
+	if len(vu.Recs) > ValueUndoMax {
+		vu.Recs = vu.Recs[len(vu.Recs)-ValueUndoMax:]
+	}
+	vu.Pos = len(vu.Recs)
+}
+
+// Undo undoes the last group of changes, and updates their views -- returns
+// false if there is nothing to undo
+func (vu *ValueUndo) Undo() bool {
+	if vu == nil || vu.Pos == 0 {
+		return false
+	}
+	grp := vu.Recs[vu.Pos-1].Group
+	var recs []*ValueUndoRec
+	vu.applying = true
+	for vu.Pos > 0 && vu.Recs[vu.Pos-1].Group == grp {
+		vu.Pos--
+		rec := vu.Recs[vu.Pos]
+		rec.Undo()
+		recs = append(recs, rec)
+	}
+	vu.applying = false
+	vu.update(recs)
+	return true
+}
+
+// Redo redoes the next group of undone changes, and updates their views --
+// returns false if there is nothing to redo
+func (vu *ValueUndo) Redo() bool {
+	if vu == nil || vu.Pos >= len(vu.Recs) {
+		return false
+	}
+	grp := vu.Recs[vu.Pos].Group
+	var recs []*ValueUndoRec
+	vu.applying = true
+	for vu.Pos < len(vu.Recs) && vu.Recs[vu.Pos].Group == grp {
+		rec := vu.Recs[vu.Pos]
+		rec.Redo()
+		recs = append(recs, rec)
+		vu.Pos++
+	}
+	vu.applying = false
+	vu.update(recs)
+	return true
+}
+
+// update calls the Update functions of the records, within one update of
+// the window
+func (vu *ValueUndo) update(recs []*ValueUndoRec) {
+	updt := vu.Win.UpdateStart()
+	vu.applying = true // updates can set values, which are not new changes
+	for _, rec := range recs {
+		if rec.Update != nil {
+			rec.Update()
+		}
+	}
+	vu.applying = false
+	vu.Win.UpdateEnd(updt)
+}
+
+// ValueUndoOwnerView returns the view (a StructView, MapView, SliceView or
+// TableView) that has given widget of a value view -- such views re-make
+// their value views when they are re-configured, so the current one for a
+// value is looked up in it for undo / redo (see ValueUndoViews) -- nil if none
+func ValueUndoOwnerView(widg gi.Node2D) ki.Ki {
+	if kit.IfaceIsNil(widg) {
+		return nil
+	}
+	for p := widg.Parent(); p != nil; p = p.Parent() {
+		if p.TypeEmbeds(KiT_StructView) || p.TypeEmbeds(KiT_MapView) || p.TypeEmbeds(KiT_SliceView) || p.TypeEmbeds(KiT_TableView) {
+			return p
+		}
+	}
+	return nil
+}
+
+// ValueUndoViews returns the current value views of given view from
+// ValueUndoOwnerView -- some can be nil, e.g., for blank rows
+func ValueUndoViews(ownv ki.Ki) []ValueView {
+	switch {
+	case ownv.TypeEmbeds(KiT_TableView):
+		var vvs []ValueView
+		for _, fvs := range ownv.Embed(KiT_TableView).(*TableView).Values {
+			vvs = append(vvs, fvs...)
+		}
+		return vvs
+	case ownv.TypeEmbeds(KiT_SliceView):
+		return ownv.Embed(KiT_SliceView).(*SliceView).Values
+	case ownv.TypeEmbeds(KiT_MapView):
+		mv := ownv.Embed(KiT_MapView).(*MapView)
+		return append(append([]ValueView(nil), mv.Keys...), mv.Values...)
+	case ownv.TypeEmbeds(KiT_StructView):
+		return ownv.Embed(KiT_StructView).(*StructView).FieldViews
+	}
+	return nil
+}
+
+// ValueUndoCopy returns a copy of given value, for restoring it later in
+// Undo / Redo -- slices and maps are copied element by element (not deeply)
+// so that later changes to their elements do not change the copy
+func ValueUndoCopy(v reflect.Value) reflect.Value {
+	if !v.IsValid() {
+		return v
+	}
+	cpy := reflect.New(v.Type()).Elem()
+	switch {
+	case v.Kind() == reflect.Slice && !v.IsNil():
+		cpy.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+		reflect.Copy(cpy, v)
+	case v.Kind() == reflect.Map && !v.IsNil():
+		cpy.Set(reflect.MakeMap(v.Type()))
+		for _, k := range v.MapKeys() {
+			cpy.SetMapIndex(k, v.MapIndex(k))
+		}
+	default:
+		cpy.Set(v)
+	}
+	return cpy
+}
+
+// SaveValueUndo saves the change to val from before, a ValueUndoCopy of it
+// from before the change, in the ValueUndo of the window of view nd -- val
+// must be settable, except for maps, which are restored in place -- update is
+// called after undo / redo -- nothing is saved if the value did not change
+func SaveValueUndo(nd gi.Node2D, desc string, val, before reflect.Value, update func()) {
+	SaveValueUndoAt(nd, desc, func() reflect.Value { return val }, before, update)
+}
+
+// SaveValueUndoAt is SaveValueUndo for a value whose location is returned by
+// loc, which is called again on each undo / redo, for values that can move,
+// e.g., the elements of a slice, which are re-allocated by inserts and
+// deletes -- an invalid location (e.g., the element no longer exists) is
+// skipped
+func SaveValueUndoAt(nd gi.Node2D, desc string, loc func() reflect.Value, before reflect.Value, update func()) {
+	vu := ValueUndoFor(nd)
+	if vu == nil || vu.applying {
+		return
+	}
+	after := ValueUndoCopy(loc())
+	if reflect.DeepEqual(before.Interface(), after.Interface()) {
+		return
+	}
+	vu.SaveUndo(&ValueUndoRec{
+		Desc: desc,
+		Undo: func() {
+			if val := loc(); val.IsValid() {
+				valueUndoSet(val, before)
+			}
+		},
+		Redo: func() {
+			if val := loc(); val.IsValid() {
+				valueUndoSet(val, after)
+			}
+		},
+		Update: update,
+	})
+}
+
+// valueUndoSet sets val to a copy of v -- maps are set in place, as they are
+// shared by reference and the map value itself need not be settable
+func valueUndoSet(val, v reflect.Value) {
+	if val.Kind() == reflect.Map && !val.IsNil() && !v.IsNil() {
+		for _, k := range val.MapKeys() {
+			val.SetMapIndex(k, reflect.Value{})
+		}
+		for _, k := range v.MapKeys() {
+			val.SetMapIndex(k, v.MapIndex(k))
+		}
+		return
+	}
+	val.Set(ValueUndoCopy(v))
+}
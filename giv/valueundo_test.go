@@ -0,0 +1,229 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/goki/gi"
+)
+
+// testValueUndo returns a ValueUndo of a window that is not opened
+func testValueUndo() *ValueUndo {
+	win := &gi.Window{}
+	win.InitName(win, "value-undo-test")
+	return &ValueUndo{Win: win}
+}
+
+// testUndoRec returns a record of the change of *val from before to after,
+// counting its updates in *upd
+func testUndoRec(val *int, before, after int, upd *int) *ValueUndoRec {
+	return &ValueUndoRec{
+		Undo:   func() { *val = before },
+		Redo:   func() { *val = after },
+		Update: func() { *upd++ },
+	}
+}
+
+func TestValueUndoGroups(t *testing.T) {
+	tests := []struct {
+		op  string // set, undo or redo
+		seq int64  // EventSeq of set
+		set int    // value of set
+		ok  bool   // result of undo / redo
+		val int    // value after op
+		pos int
+		upd int // number of updates by op
+	}{
+		{"set", 1, 1, true, 1, 1, 0},
+		{"set", 2, 2, true, 2, 2, 0},
+		{"set", 2, 3, true, 3, 3, 0}, // same event: same group
+		{"undo", 0, 0, true, 1, 1, 2},
+		{"undo", 0, 0, true, 0, 0, 1},
+		{"undo", 0, 0, false, 0, 0, 0},
+		{"redo", 0, 0, true, 1, 1, 1},
+		{"redo", 0, 0, true, 3, 3, 2},
+		{"redo", 0, 0, false, 3, 3, 0},
+		{"undo", 0, 0, true, 1, 1, 2},
+		{"set", 3, 5, true, 5, 2, 0}, // discards the undone group
+		{"redo", 0, 0, false, 5, 2, 0},
+		{"undo", 0, 0, true, 1, 1, 1},
+		{"redo", 0, 0, true, 5, 2, 1},
+		{"set", 3, 6, true, 6, 3, 0}, // same event as the redone group
+		{"undo", 0, 0, true, 1, 1, 2},
+	}
+	vu := testValueUndo()
+	val, upd := 0, 0
+	for i, test := range tests {
+		ok := true
+		pupd := upd
+		switch test.op {
+		case "set":
+			vu.Win.EventSeq = test.seq
+			vu.SaveUndo(testUndoRec(&val, val, test.set, &upd))
+			val = test.set
+		case "undo":
+			ok = vu.Undo()
+		case "redo":
+			ok = vu.Redo()
+		}
+		if ok != test.ok || val != test.val || vu.Pos != test.pos || upd-pupd != test.upd {
+			t.Errorf("ValueUndo %v %v: got %v, value %v, pos %v, updates %v, expected %v, %v, %v, %v\n", i, test.op, ok, val, vu.Pos, upd-pupd, test.ok, test.val, test.pos, test.upd)
+		}
+	}
+	for i, rec := range vu.Recs {
+		if exp := []int64{1, 3, 3}[i]; rec.Group != exp {
+			t.Errorf("ValueUndo Recs[%v].Group: got %v, expected %v\n", i, rec.Group, exp)
+		}
+	}
+
+	var nilvu *ValueUndo
+	nilvu.SaveUndo(testUndoRec(&val, 0, 1, &upd))
+	if nilvu.Undo() || nilvu.Redo() {
+		t.Errorf("nil ValueUndo: Undo or Redo returned true\n")
+	}
+}
+
+func TestValueUndoApplying(t *testing.T) {
+	vu := testValueUndo()
+	val, upd := 0, 0
+	rec := testUndoRec(&val, 0, 1, &upd)
+	undo := rec.Undo
+	rec.Undo = func() { // changes made by undo are not saved as new changes
+		undo()
+		vu.SaveUndo(testUndoRec(&val, 1, 0, &upd))
+	}
+	rec.Update = func() {
+		upd++
+		vu.SaveUndo(testUndoRec(&val, 1, 0, &upd))
+	}
+	vu.SaveUndo(rec)
+	val = 1
+	if !vu.Undo() || val != 0 || len(vu.Recs) != 1 || vu.Pos != 0 || upd != 1 {
+		t.Errorf("ValueUndo Undo saving changes: got value %v, recs %v, pos %v, updates %v, expected 0, 1, 0, 1\n", val, len(vu.Recs), vu.Pos, upd)
+	}
+}
+
+func TestValueUndoMax(t *testing.T) {
+	defer func(mx int) { ValueUndoMax = mx }(ValueUndoMax)
+	ValueUndoMax = 3
+	vu := testValueUndo()
+	val, upd := 0, 0
+	for i := 1; i <= 5; i++ {
+		vu.Win.EventSeq = int64(i)
+		vu.SaveUndo(testUndoRec(&val, val, i, &upd))
+		val = i
+	}
+	if len(vu.Recs) != 3 || vu.Pos != 3 || vu.Recs[0].Group != 3 {
+		t.Errorf("ValueUndoMax: got %v recs, pos %v, expected 3 recs, pos 3\n", len(vu.Recs), vu.Pos)
+	}
+	for vu.Undo() {
+	}
+	if val != 2 {
+		t.Errorf("ValueUndoMax undo all: got %v, expected 2\n", val)
+	}
+}
+
+func TestValueUndoCopy(t *testing.T) {
+	sl := []int{1, 2, 3}
+	mp := map[string]int{"a": 1, "b": 2}
+	st := struct{ A, B int }{1, 2}
+	tests := []struct {
+		val    interface{}
+		change func()
+		exp    interface{}
+	}{
+		{sl, func() { sl[0] = 9 }, []int{1, 2, 3}},
+		{mp, func() { mp["a"] = 9; mp["c"] = 3 }, map[string]int{"a": 1, "b": 2}},
+		{st, func() { st.A = 9 }, struct{ A, B int }{1, 2}},
+		{[]int(nil), func() {}, []int(nil)},
+		{"str", func() {}, "str"},
+	}
+	for _, test := range tests {
+		cpy := ValueUndoCopy(reflect.ValueOf(test.val))
+		test.change()
+		if !reflect.DeepEqual(cpy.Interface(), test.exp) {
+			t.Errorf("ValueUndoCopy(%v): got %v, expected %v\n", test.val, cpy.Interface(), test.exp)
+		}
+	}
+	if cpy := ValueUndoCopy(reflect.Value{}); cpy.IsValid() {
+		t.Errorf("ValueUndoCopy(invalid): got %v, expected invalid\n", cpy)
+	}
+}
+
+func TestValueUndoSet(t *testing.T) {
+	mp := map[string]int{"a": 1, "b": 2}
+	before := ValueUndoCopy(reflect.ValueOf(mp))
+	mp["a"] = 9
+	mp["c"] = 3
+	delete(mp, "b")
+	after := ValueUndoCopy(reflect.ValueOf(mp))
+	valueUndoSet(reflect.ValueOf(mp), before) // in place, not settable
+	if exp := map[string]int{"a": 1, "b": 2}; !reflect.DeepEqual(mp, exp) {
+		t.Errorf("valueUndoSet map undo: got %v, expected %v\n", mp, exp)
+	}
+	valueUndoSet(reflect.ValueOf(mp), after)
+	if exp := map[string]int{"a": 9, "c": 3}; !reflect.DeepEqual(mp, exp) {
+		t.Errorf("valueUndoSet map redo: got %v, expected %v\n", mp, exp)
+	}
+
+	sl := []int{1, 2}
+	bsl := ValueUndoCopy(reflect.ValueOf(sl))
+	sl = append(sl, 3)
+	sl[0] = 9
+	valueUndoSet(reflect.ValueOf(&sl).Elem(), bsl)
+	if exp := []int{1, 2}; !reflect.DeepEqual(sl, exp) {
+		t.Errorf("valueUndoSet slice: got %v, expected %v\n", sl, exp)
+	}
+	sl[0] = 7 // must not change the saved copy
+	valueUndoSet(reflect.ValueOf(&sl).Elem(), bsl)
+	if sl[0] != 1 {
+		t.Errorf("valueUndoSet slice shares the saved copy: got %v, expected 1\n", sl[0])
+	}
+}
+
+func TestValueUndoViewMatch(t *testing.T) {
+	type rec struct{ A, B int }
+	sl := []rec{{1, 2}, {3, 4}}
+	vv := &ValueViewBase{}
+	vv.Init(vv)
+	vv.SetStructValue(reflect.ValueOf(&sl[1].A), &sl[1], &reflect.StructField{Name: "A"}, nil)
+	tests := []struct {
+		loc reflect.Value
+		exp bool
+	}{
+		{TableViewFieldLoc(&sl, 1, 0), true},
+		{TableViewFieldLoc(&sl, 0, 0), false}, // another row
+		{TableViewFieldLoc(&sl, 1, 1), false}, // another field
+		{TableViewFieldLoc(&sl, 2, 0), false}, // gone
+		{reflect.ValueOf(sl[1].A), false},     // equal, not the same
+	}
+	for i, test := range tests {
+		if got := valueUndoAtLoc(vv, test.loc); got != test.exp {
+			t.Errorf("valueUndoAtLoc %v: got %v, expected %v\n", i, got, test.exp)
+		}
+	}
+
+	mp := map[string]int{"a": 1}
+	ov := reflect.ValueOf(mp)
+	kv := &ValueViewBase{}
+	kv.Init(kv)
+	kv.SetMapKey(reflect.ValueOf("a"), &mp, nil)
+	mvv := &ValueViewBase{}
+	mvv.Init(mvv)
+	mvv.SetMapValue(ov.MapIndex(reflect.ValueOf("a")), &mp, "a", kv, nil)
+	other := map[string]int{"a": 1}
+	if !valueUndoInMap(kv, ov, true, "a") || valueUndoInMap(kv, ov, false, "a") || valueUndoInMap(kv, ov, true, "b") {
+		t.Errorf("valueUndoInMap key view: wrong match\n")
+	}
+	if !valueUndoInMap(mvv, ov, false, "a") || valueUndoInMap(mvv, reflect.ValueOf(other), false, "a") {
+		t.Errorf("valueUndoInMap value view: wrong match\n")
+	}
+	kv.Value = reflect.ValueOf("b") // key changed: value view follows its key view
+	if !valueUndoInMap(mvv, ov, false, "b") || valueUndoInMap(mvv, ov, false, "a") {
+		t.Errorf("valueUndoInMap value view after key change: wrong match\n")
+	}
+}
@@ -125,6 +125,11 @@ func ToValueView(it interface{}) ValueView {
 		vv.Init(&vv)
 		return &vv
 	}
+	if nptyp == reflect.TypeOf(time.Duration(0)) {
+		vv := DurationValueView{}
+		vv.Init(&vv)
+		return &vv
+	}
 
 	switch {
 	case vk >= reflect.Int && vk <= reflect.Uint64:
@@ -141,12 +146,12 @@ func ToValueView(it interface{}) ValueView {
 			vv.Init(&vv)
 			return &vv
 		}
-	case nptyp == reflect.TypeOf(time.Time{}): // todo: could do better..
-		vv := ValueViewBase{}
+	case nptyp == reflect.TypeOf(time.Time{}):
+		vv := TimeValueView{}
 		vv.Init(&vv)
 		return &vv
-	case nptyp == reflect.TypeOf(FileTime{}): // todo: could do better..
-		vv := ValueViewBase{}
+	case nptyp == reflect.TypeOf(FileTime{}):
+		vv := TimeValueView{}
 		vv.Init(&vv)
 		return &vv
 	case vk == reflect.Bool:
@@ -428,6 +433,7 @@ type ValueViewBase struct {
 	WidgetTyp reflect.Type         `desc:"type of widget to create -- cached during WidgetType method -- chosen based on the ValueView type and reflect.Value type -- see ValueViewer interface"`
 	Widget    gi.Node2D            `desc:"the widget used to display and edit the value in the interface -- this is created for us externally and we cache it during ConfigWidget"`
 	TmpSave   ValueView            `desc:"value view that needs to have SaveTmp called on it whenever a change is made to one of the underlying values -- pass this down to any sub-views created from a parent"`
+	UndoLoc   func() reflect.Value `view:"-" json:"-" xml:"-" desc:"optional function that returns the current location of the value, for undo / redo of its edits -- set by views of values that can move, e.g., the fields of the elements of a slice, which are re-allocated by inserts and deletes"`
 }
 
 var KiT_ValueViewBase = kit.Types.AddType(&ValueViewBase{}, ValueViewBaseProps)
@@ -516,6 +522,11 @@ func (vv *ValueViewBase) SetValue(val interface{}) bool {
 		return false
 	}
 	rval := false
+	var fv, before reflect.Value // field or element value, for undo
+	if vv.Value.IsValid() && !(vv.Owner != nil && vv.OwnKind == reflect.Map) {
+		fv = kit.PtrValue(vv.Value).Elem()
+		before = ValueUndoCopy(fv)
+	}
 	if vv.Owner != nil {
 		switch vv.OwnKind {
 		case reflect.Struct:
@@ -548,10 +559,12 @@ func (vv *ValueViewBase) SetValue(val interface{}) bool {
 									vp.FullRender2DTree()
 								}
 							case 1:
+								oldk := vv.Value
 								cv := ov.MapIndex(vv.Value)               // get current value
 								ov.SetMapIndex(vv.Value, reflect.Value{}) // delete old key
 								ov.SetMapIndex(nv, cv)                    // set new key to current value
 								vv.Value = nv                             // update value to new key
+								vv.saveMapKeyUndo(ov, oldk, nv, curnv)
 								vv.This.(ValueView).SaveTmp()
 								vv.ViewSig.Emit(vv.This, 0, nil)
 								if vp != nil {
@@ -561,18 +574,21 @@ func (vv *ValueViewBase) SetValue(val interface{}) bool {
 						})
 					return false // abort this action right now
 				}
+				oldk := vv.Value
 				ov.SetMapIndex(vv.Value, reflect.Value{}) // delete old key
 				ov.SetMapIndex(nv, cv)                    // set new key to current value
 				vv.Value = nv                             // update value to new key
+				vv.saveMapKeyUndo(ov, oldk, nv, curnv)
 				rval = true
 			} else {
-				vv.Value = reflect.ValueOf(val)
+				key := reflect.ValueOf(vv.Key)
 				if vv.KeyView != nil {
-					ck := vv.KeyView.Val() // current key value
-					ov.SetMapIndex(ck, vv.Value)
-				} else { // static, key not editable?
-					ov.SetMapIndex(reflect.ValueOf(vv.Key), vv.Value)
+					key = vv.KeyView.Val() // current key value
 				}
+				before := ValueUndoCopy(ov.MapIndex(key))
+				vv.Value = reflect.ValueOf(val)
+				ov.SetMapIndex(key, vv.Value)
+				vv.saveMapValueUndo(ov, key, before)
 				rval = true
 			}
 		case reflect.Slice:
@@ -582,6 +598,13 @@ func (vv *ValueViewBase) SetValue(val interface{}) bool {
 		rval = kit.SetRobust(kit.PtrValue(vv.Value).Interface(), val)
 	}
 	if rval {
+		if fv.IsValid() && vv.Widget != nil {
+			loc := vv.undoLoc(fv)
+			find := vv.undoView(func(cv *ValueViewBase) bool {
+				return valueUndoAtLoc(cv, loc())
+			})
+			SaveValueUndoAt(vv.Widget, "Set "+vv.Nm, loc, before, undoUpdate(find))
+		}
 		vv.This.(ValueView).SaveTmp()
 	}
 	// fmt.Printf("value view: %T sending for setting val %v\n", vv.This, val)
@@ -589,6 +612,184 @@ func (vv *ValueViewBase) SetValue(val interface{}) bool {
 	return rval
 }
 
+// undoLoc returns the function that returns the current location of the
+// value fv set by SetValue, for undo / redo: UndoLoc if set, or the element
+// of the owner slice at Idx, which moves when the slice is re-allocated
+func (vv *ValueViewBase) undoLoc(fv reflect.Value) func() reflect.Value {
+	if vv.UndoLoc != nil {
+		return vv.UndoLoc
+	}
+	if vv.OwnKind != reflect.Slice || vv.Owner == nil {
+		return func() reflect.Value { return fv }
+	}
+	owner, idx, typ := vv.Owner, vv.Idx, fv.Type()
+	return func() reflect.Value {
+		sv := kit.NonPtrValue(reflect.ValueOf(owner))
+		if idx >= sv.Len() {
+			return reflect.Value{}
+		}
+		ev := sv.Index(idx)
+		for ev.Type() != typ && ev.Kind() == reflect.Ptr && !ev.IsNil() { // pointer lists
+			ev = ev.Elem()
+		}
+		if ev.Type() != typ {
+			return reflect.Value{}
+		}
+		return ev
+	}
+}
+
+// undoView returns a function that returns the current value view of the
+// view that owns vv (see ValueUndoOwnerView) for which match is true, for
+// undo / redo -- vv itself can be stale by then, as value views are re-made
+// when their view is re-configured -- vv if it has no owning view, and nil if
+// the value is no longer in view
+func (vv *ValueViewBase) undoView(match func(cv *ValueViewBase) bool) func() *ValueViewBase {
+	ownv := ValueUndoOwnerView(vv.Widget)
+	return func() *ValueViewBase {
+		if ownv == nil {
+			return vv
+		}
+		if ownv.IsDestroyed() {
+			return nil
+		}
+		for _, cv := range ValueUndoViews(ownv) {
+			if !kit.IfaceIsNil(cv) && match(cv.AsValueViewBase()) {
+				return cv.AsValueViewBase()
+			}
+		}
+		return nil
+	}
+}
+
+// undoUpdate returns the Update function of an undo record, which calls
+// UndoUpdate on the current value view from find, if the value is in view
+func undoUpdate(find func() *ValueViewBase) func() {
+	return func() {
+		if cv := find(); cv != nil {
+			cv.UndoUpdate()
+		}
+	}
+}
+
+// valueUndoAtLoc returns true if the value of value view cv is at location
+// loc -- the same Go value, not only an equal one
+func valueUndoAtLoc(cv *ValueViewBase, loc reflect.Value) bool {
+	if cv.OwnKind == reflect.Map || !cv.Value.IsValid() || !loc.IsValid() || !loc.CanAddr() {
+		return false
+	}
+	pv := cv.Value
+	if pv.Kind() != reflect.Ptr {
+		if !pv.CanAddr() {
+			return false
+		}
+		pv = pv.Addr()
+	}
+	lp := loc.Addr()
+	return pv.Type() == lp.Type() && pv.Pointer() == lp.Pointer()
+}
+
+// valueUndoInMap returns true if value view cv is a key (isKey) or a value of
+// map ov, at key k
+func valueUndoInMap(cv *ValueViewBase, ov reflect.Value, isKey bool, k interface{}) bool {
+	if cv.OwnKind != reflect.Map || cv.IsMapKey != isKey || cv.Owner == nil {
+		return false
+	}
+	if kit.NonPtrValue(reflect.ValueOf(cv.Owner)).Pointer() != ov.Pointer() {
+		return false
+	}
+	ck := cv.Key
+	switch {
+	case isKey:
+		if !cv.Value.IsValid() {
+			return false
+		}
+		ck = cv.Value.Interface()
+	case cv.KeyView != nil:
+		kv := cv.KeyView.Val()
+		if !kv.IsValid() {
+			return false
+		}
+		ck = kv.Interface()
+	}
+	return ck == k
+}
+
+// UndoUpdate updates the widget and emits the ViewSig after an undo or redo
+// of a value set by SetValue -- see ValueUndo
+func (vv *ValueViewBase) UndoUpdate() {
+	if kiv, ok := vv.Owner.(ki.Ki); ok {
+		kiv.UpdateSig()
+	}
+	vv.This.(ValueView).UpdateWidget()
+	vv.This.(ValueView).SaveTmp()
+	vv.ViewSig.Emit(vv.This, 0, nil)
+}
+
+// saveMapValueUndo saves the undo record for setting the value at key in map
+// ov, which was before
+func (vv *ValueViewBase) saveMapValueUndo(ov, key, before reflect.Value) {
+	if vv.Widget == nil {
+		return
+	}
+	after := ValueUndoCopy(ov.MapIndex(key))
+	find := vv.undoView(func(cv *ValueViewBase) bool {
+		return valueUndoInMap(cv, ov, false, key.Interface())
+	})
+	ValueUndoFor(vv.Widget).SaveUndo(&ValueUndoRec{
+		Desc: "Set " + vv.Nm,
+		Undo: func() {
+			ov.SetMapIndex(key, before) // deletes if there was none
+			if cv := find(); cv != nil && before.IsValid() {
+				cv.Value = before
+			}
+		},
+		Redo: func() {
+			ov.SetMapIndex(key, after)
+			if cv := find(); cv != nil {
+				cv.Value = after
+			}
+		},
+		Update: undoUpdate(find),
+	})
+}
+
+// saveMapKeyUndo saves the undo record for changing key oldk to newk in map
+// ov, which overwrote the value that was at newk (invalid if none)
+func (vv *ValueViewBase) saveMapKeyUndo(ov, oldk, newk, overwritten reflect.Value) {
+	if vv.Widget == nil || oldk.Interface() == newk.Interface() {
+		return
+	}
+	curk := newk // current key, for finding the current key view
+	find := vv.undoView(func(cv *ValueViewBase) bool {
+		return valueUndoInMap(cv, ov, true, curk.Interface())
+	})
+	ValueUndoFor(vv.Widget).SaveUndo(&ValueUndoRec{
+		Desc: "Change key " + kit.ToString(oldk.Interface()),
+		Undo: func() {
+			kv := find()
+			cv := ov.MapIndex(newk)
+			ov.SetMapIndex(newk, overwritten) // deletes if there was none
+			ov.SetMapIndex(oldk, cv)
+			curk = oldk
+			if kv != nil {
+				kv.Value = oldk
+			}
+		},
+		Redo: func() {
+			kv := find()
+			cv := ov.MapIndex(oldk)
+			ov.SetMapIndex(oldk, reflect.Value{})
+			ov.SetMapIndex(newk, cv)
+			curk = newk
+			if kv != nil {
+				kv.Value = newk
+			}
+		},
+		Update: undoUpdate(find),
+	})
+}
+
 func (vv *ValueViewBase) SaveTmp() {
 	if vv.TmpSave == nil {
 		return
@@ -736,3 +937,17 @@ func (vi *ViewIFace) PrefsView(prefs *gi.Preferences) {
 func (vi *ViewIFace) KeyMapsView(maps *gi.KeyMaps) {
 	KeyMapsView(maps)
 }
+
+func (vi *ViewIFace) UndoValues(win *gi.Window) bool {
+	if tf, ok := win.Focus.(*gi.TextField); ok && tf.Edited {
+		return false // text being edited is not a value yet
+	}
+	return WindowValueUndo(win).Undo()
+}
+
+func (vi *ViewIFace) RedoValues(win *gi.Window) bool {
+	if tf, ok := win.Focus.(*gi.TextField); ok && tf.Edited {
+		return false
+	}
+	return WindowValueUndo(win).Redo()
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"image"
+	"image/color"
+	"sync"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// GlyphCacheOn determines whether TextRender uses TheGlyphCache to render
+// glyphs -- they are then rasterized only the first time they are used, and
+// thereafter just copied from the cache
+var GlyphCacheOn = true
+
+// GlyphSubpixels is the number of fractional horizontal positions that are
+// cached separately for each glyph -- positions are rounded to the nearest
+const GlyphSubpixels = 4
+
+// TheGlyphCache is the glyph atlas shared by all text rendering
+var TheGlyphCache = GlyphCache{PageSize: 1024, MaxPages: 4}
+
+// GlyphCache is an atlas of rendered glyph images, keyed by face (which is
+// specific to a font and size), rune, subpixel offset and color (or
+// subpixel antialiasing mode, for the color-independent masks used in
+// subpixel antialiasing -- see SubpixelMask) -- the
+// glyphs are packed into a small number of large pages, in rows (shelves)
+// of glyphs.  When all of the pages are full, the cache is cleared and
+// starts over.
+type GlyphCache struct {
+	PageSize int                     `desc:"width and height of each page image"`
+	MaxPages int                     `desc:"maximum number of pages -- the cache is cleared when they are all full"`
+	Pages    []*image.RGBA           `json:"-" xml:"-" desc:"the page images holding the glyphs"`
+	glyphs   map[glyphKey]glyphEntry // location of each glyph
+	shelfX   int                     // next free x in current shelf of last page
+	shelfY   int                     // top of current shelf
+	shelfH   int                     // height of current shelf
+	mu       sync.Mutex              // protects all the above
+}
+
+// glyphKey is the key for a glyph in the GlyphCache
+type glyphKey struct {
+	face font.Face
+	r    rune
+	subX int
+	clr  color.RGBA
+	aa   TextAntialiases
+}
+
+// glyphEntry records the location of a glyph in the GlyphCache
+type glyphEntry struct {
+	page int             // index of page -- -1 if the glyph has no pixels or is not in the font
+	rect image.Rectangle // region of page holding the glyph
+	off  image.Point     // offset of the glyph image from the dot position
+	ok   bool            // false if the face does not have the glyph
+	big  bool            // too big for a page -- drawn directly by the caller
+}
+
+// Reset clears the cache
+func (gc *GlyphCache) Reset() {
+	gc.mu.Lock()
+	gc.reset()
+	gc.mu.Unlock()
+}
+
+func (gc *GlyphCache) reset() {
+	gc.Pages = nil
+	gc.glyphs = nil
+	gc.shelfX, gc.shelfY, gc.shelfH = 0, 0, 0
+}
+
+// alloc returns the page index and region for a new glyph image of given
+// size, adding a page, or clearing the cache, as needed -- page is -1 if the
+// image is too big for a page
+func (gc *GlyphCache) alloc(w, h int) (int, image.Rectangle) {
+	if w > gc.PageSize || h > gc.PageSize {
+		return -1, image.ZR
+	}
+	if gc.shelfX+w > gc.PageSize { // next shelf
+		gc.shelfX = 0
+		gc.shelfY += gc.shelfH
+		gc.shelfH = 0
+	}
+	if len(gc.Pages) == 0 || gc.shelfY+h > gc.PageSize { // next page
+		if len(gc.Pages) >= gc.MaxPages {
+			gc.reset()
+		}
+		gc.Pages = append(gc.Pages, image.NewRGBA(image.Rect(0, 0, gc.PageSize, gc.PageSize)))
+		gc.shelfX, gc.shelfY, gc.shelfH = 0, 0, 0
+	}
+	r := image.Rect(gc.shelfX, gc.shelfY, gc.shelfX+w, gc.shelfY+h)
+	gc.shelfX += w + 1 // 1 pixel gap between glyphs
+	if h+1 > gc.shelfH {
+		gc.shelfH = h + 1
+	}
+	return len(gc.Pages) - 1, r
+}
+
+// Glyph returns the cached image of given rune rendered with given face and
+// color, at a dot position with given fractional horizontal offset (in units
+// of 1 / GlyphSubpixels): the page image and region within it, and the
+// offset of that region relative to the (integer) dot position -- the glyph
+// is rendered into the cache if not already present.  page is nil if the
+// glyph has no pixels (e.g., a space), and ok is false if the face does not
+// have the glyph.  TextFontRenderMu must be locked, as the face is used.
+func (gc *GlyphCache) Glyph(face font.Face, r rune, subX int, clr color.Color) (page *image.RGBA, rect image.Rectangle, off image.Point, ok bool) {
+	key := glyphKey{face: face, r: r, subX: subX, clr: color.RGBAModel.Convert(clr).(color.RGBA)}
+	return gc.glyph(key, func() (image.Rectangle, image.Image, image.Point, bool) {
+		dot := fixed.Point26_6{X: fixed.Int26_6(subX * 64 / GlyphSubpixels)}
+		dr, mask, maskp, _, gok := face.Glyph(dot, r)
+		return dr, mask, maskp, gok
+	})
+}
+
+// SubpixelGlyph returns the cached subpixel antialiasing mask of given rune
+// in given face, for given subpixel antialiasing mode, as in Glyph -- see
+// SubpixelMask.  ok is false if the glyph outline is not available.
+// TextFontRenderMu must be locked, as the face is used.
+func (gc *GlyphCache) SubpixelGlyph(face font.Face, r rune, subX int, aa TextAntialiases) (page *image.RGBA, rect image.Rectangle, off image.Point, ok bool) {
+	key := glyphKey{face: face, r: r, subX: subX, aa: aa}
+	return gc.glyph(key, func() (image.Rectangle, image.Image, image.Point, bool) {
+		dr, mask, gok := SubpixelMask(face, r, subX, aa)
+		if mask == nil {
+			return image.ZR, nil, image.ZP, gok
+		}
+		return dr, mask, image.ZP, gok
+	})
+}
+
+// glyph returns the cached glyph image for given key, calling render to
+// render it into the cache if not already present -- glyphs too big for a
+// page are only rendered once, to find their size, and return false
+func (gc *GlyphCache) glyph(key glyphKey, render func() (dr image.Rectangle, mask image.Image, maskp image.Point, ok bool)) (page *image.RGBA, rect image.Rectangle, off image.Point, ok bool) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	ge, has := gc.glyphs[key]
+	if !has {
+		ge = glyphEntry{page: -1}
+		dr, mask, maskp, gok := render()
+		ge.ok = gok
+		if gok && !dr.Empty() && mask != nil {
+			pi, pr := gc.alloc(dr.Dx(), dr.Dy())
+			if pi < 0 {
+				ge.big = true // noted, so it is not rendered again
+			} else {
+				if key.aa != AntialiasGray { // mask values are used directly
+					draw.Draw(gc.Pages[pi], pr, mask, maskp, draw.Src)
+				} else {
+					draw.DrawMask(gc.Pages[pi], pr, image.NewUniform(key.clr), image.ZP, mask, maskp, draw.Over)
+				}
+				ge.page = pi
+				ge.rect = pr
+				ge.off = dr.Min
+			}
+		}
+		if gc.glyphs == nil {
+			gc.glyphs = make(map[glyphKey]glyphEntry)
+		}
+		gc.glyphs[key] = ge
+	}
+	if ge.big {
+		return nil, image.ZR, image.ZP, false // render directly
+	}
+	if ge.page >= 0 {
+		page = gc.Pages[ge.page]
+	}
+	return page, ge.rect, ge.off, ge.ok
+}
+
+// DrawGlyph draws given rune with given face and color at given dot
+// position into the dst image, within given bounds, using the cache --
+// returns false if the face does not have the glyph, or it is too big for
+// the cache.  TextFontRenderMu must be locked.
+func (gc *GlyphCache) DrawGlyph(dst draw.Image, bounds image.Rectangle, face font.Face, r rune, dot fixed.Point26_6, clr color.Color) bool {
+	ix := dot.X.Floor()
+	subX := (int(dot.X-fixed.I(ix))*GlyphSubpixels + 32) / 64
+	if subX == GlyphSubpixels {
+		ix++
+		subX = 0
+	}
+	page, rect, off, ok := gc.Glyph(face, r, subX, clr)
+	if !ok {
+		return false
+	}
+	if page == nil {
+		return true // nothing to draw
+	}
+	dr := rect.Sub(rect.Min).Add(off).Add(image.Point{ix, dot.Y.Round()})
+	idr := dr.Intersect(bounds)
+	if idr.Empty() {
+		return true
+	}
+	if rgba, isRGBA := dst.(*image.RGBA); isRGBA && GammaCorrect {
+		BlendMaskGamma(rgba, idr, page, rect.Min.Add(idr.Min.Sub(dr.Min)), clr)
+		return true
+	}
+	draw.Draw(dst, idr, page, rect.Min.Add(idr.Min.Sub(dr.Min)), draw.Over)
+	return true
+}
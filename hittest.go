@@ -0,0 +1,113 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/srwiley/rasterx"
+	"github.com/srwiley/scanFT"
+)
+
+// HitTester is an optional interface for Node2D nodes whose shape is not
+// just their rectangular WinBBox (e.g., svg elements) -- the Window only
+// sends positional mouse events (including mouse focus enter / exit) to such
+// nodes when HitTest returns true for the event position, in addition to the
+// position being within their WinBBox
+type HitTester interface {
+	// HitTest returns true if the given position, in window coordinates, is
+	// within the shape of the node
+	HitTest(winPos image.Point) bool
+}
+
+// PosInNode returns true if given window position is within the given node:
+// within its WinBBox, and within its actual shape if it is a HitTester
+func PosInNode(pos image.Point, ni *Node2DBase) bool {
+	if !pos.In(ni.WinBBox) {
+		return false
+	}
+	if ht, ok := ni.This.(HitTester); ok {
+		return ht.HitTest(pos)
+	}
+	return true
+}
+
+// HitShape records the geometry of a rendered shape, for testing whether a
+// point is within its filled or stroked area -- the path is in render pixel
+// coordinates, with all transforms already applied
+type HitShape struct {
+	Path        rasterx.Path    `desc:"path of the shape, in render pixels"`
+	Fill        bool            `desc:"the fill area receives events"`
+	Stroke      bool            `desc:"the stroke receives events"`
+	NonZero     bool            `desc:"use the non-zero winding rule for the fill area, otherwise even-odd"`
+	StrokeWidth float32         `desc:"width of the stroke, in render pixels"`
+	MiterLimit  float32         `desc:"miter limit of the stroke"`
+	Bounds      image.Rectangle `desc:"bounds the shape was clipped to when rendered -- only points within these bounds can hit"`
+	capfunc     rasterx.CapFunc
+	joinmode    rasterx.JoinMode
+}
+
+// HitShape returns the hit testing geometry for the current path in the
+// render state, with the fill and stroke areas determined by the
+// pointer-events setting (see HitAreas) -- call prior to clearing the path,
+// e.g., just before FillStrokeClear.  Dashes are ignored, so the entire
+// stroke can be hit.
+func (pc *Paint) HitShape(rs *RenderState) HitShape {
+	hs := HitShape{}
+	hs.Fill, hs.Stroke = pc.HitAreas()
+	if !hs.Fill && !hs.Stroke {
+		return hs
+	}
+	hs.Path = make(rasterx.Path, len(rs.Path))
+	copy(hs.Path, rs.Path)
+	hs.NonZero = pc.FillStyle.Rule == FillRuleNonZero
+	hs.StrokeWidth = pc.StrokeWidth(rs)
+	hs.MiterLimit = pc.StrokeStyle.MiterLimit
+	hs.Bounds = rs.Bounds
+	hs.capfunc = pc.capfunc()
+	hs.joinmode = pc.joinmode()
+	return hs
+}
+
+// IsEmpty returns true if there is nothing that can be hit
+func (hs *HitShape) IsEmpty() bool {
+	return len(hs.Path) == 0 || (!hs.Fill && !hs.Stroke)
+}
+
+// Contains returns true if the given point, in render pixels, is within the
+// fill area or stroke of the shape (as enabled) -- the shape is rasterized
+// at the pixel containing the point, using the same rules as rendering, so
+// any pixel that would be (partially) painted is a hit
+func (hs *HitShape) Contains(pt image.Point) bool {
+	if hs.IsEmpty() || !pt.In(hs.Bounds) {
+		return false
+	}
+	path := XFormPath(hs.Path, Translate2D(-float32(pt.X), -float32(pt.Y)))
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	if hs.Fill {
+		sc := scanFT.NewScannerFT(1, 1, scanFT.NewRGBAPainter(img))
+		rf := rasterx.NewFiller(1, 1, sc)
+		rf.SetWinding(hs.NonZero)
+		rf.SetColor(color.Opaque)
+		path.AddTo(rf)
+		rf.Draw()
+		if img.Pix[3] > 0 {
+			return true
+		}
+	}
+	if hs.Stroke && hs.StrokeWidth > 0 {
+		sc := scanFT.NewScannerFT(1, 1, scanFT.NewRGBAPainter(img))
+		rd := rasterx.NewDasher(1, 1, sc)
+		rd.SetStroke(Float32ToFixed(hs.StrokeWidth), Float32ToFixed(hs.MiterLimit), hs.capfunc, nil, nil, hs.joinmode, nil, 0)
+		rd.SetColor(color.Opaque)
+		path.AddTo(rd)
+		rd.Draw()
+		if img.Pix[3] > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Hyphenation (hyphens: auto) uses Liang's algorithm, as in TeX, with the
+// hyphenation patterns for a given language -- no patterns are built in, so
+// an app must add the patterns for the languages it uses with
+// AddHyphenPatterns, e.g., from the hyph-en-us.pat.txt and hyph-en-us.hyp.txt
+// files of the TeX hyph-utf8 package, embedded in the app.  Without patterns,
+// words are only hyphenated at soft hyphens.
+
+// Hyphenator finds the hyphenation points in words of a given language,
+// using Liang's algorithm with the patterns and exceptions of the language
+type Hyphenator struct {
+	Lang     string           `desc:"language of the patterns, e.g., en-us"`
+	LeftMin  int              `desc:"minimum number of letters before a hyphen"`
+	RightMin int              `desc:"minimum number of letters after a hyphen"`
+	pats     map[string][]int // pattern letters to inter-letter values
+	maxLen   int              // maximum pattern length, in runes
+	excs     map[string][]int // exception words to hyphenation points
+}
+
+// HyphenLang is the language of the hyphenation patterns used for hyphens:
+// auto -- see AddHyphenPatterns
+var HyphenLang = "en"
+
+// Hyphenators are the hyphenators for each language that has patterns
+var Hyphenators = map[string]*Hyphenator{}
+
+// hyphenMu protects the Hyphenators map
+var hyphenMu sync.RWMutex
+
+// AddHyphenPatterns adds a hyphenator for given language, with given
+// hyphenation patterns and exceptions in the TeX format: the patterns are
+// separated by white space, with the inter-letter values as digits (e.g.,
+// ".ach4 4b1y"), and exceptions are words with hyphens at the hyphenation
+// points (e.g., "ta-ble") -- lines starting with % are comments
+func AddHyphenPatterns(lang, patterns, exceptions string) *Hyphenator {
+	hy := NewHyphenator(lang, patterns, exceptions)
+	hyphenMu.Lock()
+	Hyphenators[hy.Lang] = hy
+	hyphenMu.Unlock()
+	return hy
+}
+
+// HyphenatorForLang returns the hyphenator for given language, or for the
+// base language (e.g., en for en-us) if not found -- nil if none
+func HyphenatorForLang(lang string) *Hyphenator {
+	hyphenMu.RLock()
+	defer hyphenMu.RUnlock()
+	lang = strings.ToLower(lang)
+	if hy, ok := Hyphenators[lang]; ok {
+		return hy
+	}
+	if di := strings.IndexAny(lang, "-_"); di > 0 {
+		if hy, ok := Hyphenators[lang[:di]]; ok {
+			return hy
+		}
+	}
+	for hl, hy := range Hyphenators { // e.g., en-us patterns for en
+		if strings.HasPrefix(hl, lang+"-") {
+			return hy
+		}
+	}
+	return nil
+}
+
+// NewHyphenator returns a new hyphenator for given language, with given
+// patterns and exceptions (see AddHyphenPatterns)
+func NewHyphenator(lang, patterns, exceptions string) *Hyphenator {
+	hy := &Hyphenator{Lang: strings.ToLower(lang), LeftMin: 2, RightMin: 3}
+	hy.pats = make(map[string][]int)
+	hy.excs = make(map[string][]int)
+	for _, pat := range hyphenFields(patterns) {
+		var lets []rune
+		vals := []int{0}
+		for _, r := range pat {
+			if r >= '0' && r <= '9' {
+				vals[len(vals)-1] = int(r - '0')
+			} else {
+				lets = append(lets, unicode.ToLower(r))
+				vals = append(vals, 0)
+			}
+		}
+		if len(lets) == 0 {
+			continue
+		}
+		hy.pats[string(lets)] = vals
+		if len(lets) > hy.maxLen {
+			hy.maxLen = len(lets)
+		}
+	}
+	for _, exc := range hyphenFields(exceptions) {
+		var lets []rune
+		var pts []int
+		for _, r := range exc {
+			if r == '-' {
+				pts = append(pts, len(lets))
+			} else {
+				lets = append(lets, unicode.ToLower(r))
+			}
+		}
+		hy.excs[string(lets)] = pts
+	}
+	return hy
+}
+
+// hyphenFields returns the white-space separated fields of given patterns,
+// skipping % comments
+func hyphenFields(str string) []string {
+	var flds []string
+	for _, ln := range strings.Split(str, "\n") {
+		if ci := strings.Index(ln, "%"); ci >= 0 {
+			ln = ln[:ci]
+		}
+		flds = append(flds, strings.Fields(ln)...)
+	}
+	return flds
+}
+
+// Hyphenate returns the hyphenation points in given word, as the indexes of
+// the runes before which the word can be hyphenated, in order
+func (hy *Hyphenator) Hyphenate(word []rune) []int {
+	sz := len(word)
+	if sz < hy.LeftMin+hy.RightMin {
+		return nil
+	}
+	lw := make([]rune, sz+2)
+	lw[0] = '.'
+	lw[sz+1] = '.'
+	for i, r := range word {
+		lw[i+1] = unicode.ToLower(r)
+	}
+	if pts, ok := hy.excs[string(lw[1:sz+1])]; ok {
+		return pts
+	}
+	vals := make([]int, len(lw)+1) // vals[i] is the value before lw[i]
+	for st := range lw {
+		for ed := st + 1; ed <= len(lw) && ed-st <= hy.maxLen; ed++ {
+			pv, ok := hy.pats[string(lw[st:ed])]
+			if !ok {
+				continue
+			}
+			for k, v := range pv {
+				if v > vals[st+k] {
+					vals[st+k] = v
+				}
+			}
+		}
+	}
+	var pts []int
+	for i := hy.LeftMin; i <= sz-hy.RightMin; i++ {
+		if vals[i+1]%2 == 1 {
+			pts = append(pts, i)
+		}
+	}
+	return pts
+}
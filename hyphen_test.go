@@ -0,0 +1,73 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"fmt"
+	"testing"
+)
+
+// hyphenTestPats are the patterns from Liang's thesis that hyphenate
+// "hyphenation", and exceptions
+var hyphenTestPats = `% patterns for testing
+hy3ph he2n hena4 hen5at 1na n2at 1tio 2io o2n`
+
+var hyphenTestExcs = "ta-ble pro-ject"
+
+func TestHyphenate(t *testing.T) {
+	hy := NewHyphenator("en-US", hyphenTestPats, hyphenTestExcs)
+	tests := []struct {
+		word string
+		pts  []int
+	}{
+		{"hyphenation", []int{2, 6}},
+		{"Hyphenation", []int{2, 6}},
+		{"HYPHENATION", []int{2, 6}},
+		{"table", []int{2}},
+		{"Project", []int{3}},
+		{"hyph", nil},
+		{"", nil},
+	}
+	for _, test := range tests {
+		pts := hy.Hyphenate([]rune(test.word))
+		if fmt.Sprintf("%v", pts) != fmt.Sprintf("%v", test.pts) {
+			t.Errorf("Hyphenate(%q): got %v, expected %v\n", test.word, pts, test.pts)
+		}
+	}
+}
+
+func TestHyphenatorForLang(t *testing.T) {
+	hyphenMu.Lock()
+	saved := Hyphenators
+	Hyphenators = map[string]*Hyphenator{}
+	hyphenMu.Unlock()
+	defer func() {
+		hyphenMu.Lock()
+		Hyphenators = saved
+		hyphenMu.Unlock()
+	}()
+	hy := AddHyphenPatterns("en-US", hyphenTestPats, hyphenTestExcs)
+	if hy.Lang != "en-us" {
+		t.Errorf("AddHyphenPatterns: got language %q, expected en-us\n", hy.Lang)
+	}
+	tests := []struct {
+		lang string
+		has  bool
+	}{
+		{"en-US", true},
+		{"en-us", true},
+		{"EN-us", true},
+		{"en_US", false},
+		{"en", true},
+		{"EN", true},
+		{"en-GB", false},
+		{"de", false},
+	}
+	for _, test := range tests {
+		if has := HyphenatorForLang(test.lang) == hy; has != test.has {
+			t.Errorf("HyphenatorForLang(%q): got %v, expected %v\n", test.lang, has, test.has)
+		}
+	}
+}
@@ -8,9 +8,12 @@ import (
 	"image"
 	"image/color"
 	"reflect"
+	"unicode"
 
 	"github.com/goki/gi/oswin"
 	"github.com/goki/gi/oswin/cursor"
+	"github.com/goki/gi/oswin/key"
+	"github.com/goki/gi/oswin/mimedata"
 	"github.com/goki/gi/oswin/mouse"
 	"github.com/goki/gi/units"
 	"github.com/goki/ki"
@@ -60,14 +63,18 @@ func ToLabel(it interface{}) string {
 // other options to get word-wrapping etc.
 type Label struct {
 	WidgetBase
-	Text        string              `xml:"text" desc:"label to display"`
-	Selectable  bool                `desc:"is this label selectable? if so, it will change background color in response to selection events and update selection state on mouse clicks"`
-	Redrawable  bool                `desc:"is this label going to be redrawn frequently without an overall full re-render?  if so, you need to set this flag to avoid weird overlapping rendering results from antialiasing"`
-	LinkSig     ki.Signal           `json:"-" xml:"-" view:"-" desc:"signal for clicking on a link -- data is a string of the URL -- if nobody receiving this signal, calls TextLinkHandler then URLHandler"`
-	StateStyles [LabelStatesN]Style `json:"-" xml:"-" desc:"styles for different states of label"`
-	Render      TextRender          `xml:"-" json:"-" desc:"render data for text label"`
-	RenderPos   Vec2D               `xml:"-" json:"-" desc:"position offset of start of text rendering, from last render -- AllocPos plus alignment factors for center, right etc."`
-	CurBgColor  Color               `xml:"-" json:"-" desc:"current background color -- grabbed when rendering for first time, and used when toggling off of selected mode, or for redrawable, to wipe out bg"`
+	Text           string              `xml:"text" desc:"label to display"`
+	Selectable     bool                `desc:"is this label selectable? if so, it will change background color in response to selection events and update selection state on mouse clicks"`
+	TextSelectable bool                `desc:"can the text of this label be selected with the mouse (drag, or double-click for a word) and copied to the clipboard (with the copy key or context menu) as plain text and html? this is distinct from Selectable, which selects the label as a whole"`
+	Redrawable     bool                `desc:"is this label going to be redrawn frequently without an overall full re-render?  if so, you need to set this flag to avoid weird overlapping rendering results from antialiasing"`
+	LinkSig        ki.Signal           `json:"-" xml:"-" view:"-" desc:"signal for clicking on a link -- data is a string of the URL -- if nobody receiving this signal, calls TextLinkHandler then URLHandler"`
+	StateStyles    [LabelStatesN]Style `json:"-" xml:"-" desc:"styles for different states of label"`
+	Render         TextRender          `xml:"-" json:"-" desc:"render data for text label"`
+	RenderPos      Vec2D               `xml:"-" json:"-" desc:"position offset of start of text rendering, from last render -- AllocPos plus alignment factors for center, right etc."`
+	CurBgColor     Color               `xml:"-" json:"-" desc:"current background color -- grabbed when rendering for first time, and used when toggling off of selected mode, or for redrawable, to wipe out bg"`
+	SelectStart    int                 `xml:"-" json:"-" desc:"starting rune index of selected text, counting through all the spans of Render (see TextRender.RuneSpanPos)"`
+	SelectEnd      int                 `xml:"-" json:"-" desc:"ending rune index of selected text (exclusive)"`
+	SelectInit     int                 `xml:"-" json:"-" desc:"rune index where the mouse selection started"`
 }
 
 var KiT_Label = kit.Types.AddType(&Label{}, LabelProps)
@@ -123,6 +130,7 @@ func (lb *Label) SetText(txt string) {
 		lb.StyleLabel()
 	}
 	lb.Text = txt
+	lb.SelectStart, lb.SelectEnd = 0, 0
 	if lb.Text == "" {
 		lb.Render.SetHTML(" ", &lb.Sty.Font, &lb.Sty.Text, &lb.Sty.UnContext, lb.CSSAgg)
 	} else {
@@ -157,14 +165,14 @@ func (lb *Label) SetTextAction(txt string) {
 func (lb *Label) SetStateStyle() {
 	if lb.IsInactive() {
 		lb.Sty = lb.StateStyles[LabelInactive]
-		if lb.Redrawable && !lb.CurBgColor.IsNil() {
+		if (lb.Redrawable || lb.TextSelectable) && !lb.CurBgColor.IsNil() {
 			lb.Sty.Font.BgColor.SetColor(lb.CurBgColor)
 		}
 	} else if lb.IsSelected() {
 		lb.Sty = lb.StateStyles[LabelSelected]
 	} else {
 		lb.Sty = lb.StateStyles[LabelActive]
-		if (lb.Selectable || lb.Redrawable) && !lb.CurBgColor.IsNil() {
+		if (lb.Selectable || lb.Redrawable || lb.TextSelectable) && !lb.CurBgColor.IsNil() { // wipe out any prior selection
 			lb.Sty.Font.BgColor.SetColor(lb.CurBgColor)
 		}
 	}
@@ -242,6 +250,18 @@ func (lb *Label) MouseEvent() {
 				}
 			}
 		}
+		if llb.TextSelectable && me.Button == mouse.Left {
+			switch me.Action {
+			case mouse.Press:
+				me.SetProcessed()
+				llb.GrabFocus()
+				llb.SelectInit = llb.PixelToRuneIdx(me.Pos())
+				llb.SelectRange(llb.SelectInit, llb.SelectInit)
+			case mouse.DoubleClick:
+				me.SetProcessed()
+				llb.SelectWord(llb.PixelToRuneIdx(me.Pos()))
+			}
+		}
 		if me.Action == mouse.Release && me.Button == mouse.Right {
 			me.SetProcessed()
 			llb.EmitContextMenuSignal()
@@ -274,10 +294,181 @@ func (lb *Label) MouseMoveEvent() {
 	}
 }
 
+func (lb *Label) MouseDragEvent() {
+	if !lb.TextSelectable {
+		return
+	}
+	lb.ConnectEvent(oswin.MouseDragEvent, RegPri, func(recv, send ki.Ki, sig int64, d interface{}) {
+		me := d.(*mouse.DragEvent)
+		me.SetProcessed()
+		llb := recv.Embed(KiT_Label).(*Label)
+		idx := llb.PixelToRuneIdx(me.Pos())
+		if idx < llb.SelectInit {
+			llb.SelectRange(idx, llb.SelectInit)
+		} else {
+			llb.SelectRange(llb.SelectInit, idx)
+		}
+	})
+}
+
+func (lb *Label) KeyChordEvent() {
+	if !lb.TextSelectable {
+		return
+	}
+	lb.ConnectEvent(oswin.KeyChordEvent, RegPri, func(recv, send ki.Ki, sig int64, d interface{}) {
+		llb := recv.Embed(KiT_Label).(*Label)
+		kt := d.(*key.ChordEvent)
+		switch KeyFun(kt.Chord()) {
+		case KeyFunCopy:
+			kt.SetProcessed()
+			llb.Copy(false)
+		case KeyFunSelectAll:
+			kt.SetProcessed()
+			llb.SelectAll()
+		case KeyFunAbort:
+			if llb.HasSelection() {
+				kt.SetProcessed()
+				llb.SelectReset()
+			}
+		}
+	})
+}
+
 func (lb *Label) LabelEvents() {
 	lb.HoverEvent()
 	lb.MouseEvent()
 	lb.MouseMoveEvent()
+	lb.MouseDragEvent()
+	lb.KeyChordEvent()
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  Text selection
+
+// PixelToRuneIdx returns the rune index (as in TextRender.RuneSpanPos) of
+// the text cursor position closest to given window position -- RenderPos is
+// in Viewport coordinates, so both are made relative to the label
+func (lb *Label) PixelToRuneIdx(pt image.Point) int {
+	rpt := NewVec2DFmPoint(lb.PointToRelPos(pt))
+	tpos := lb.RenderPos.Sub(NewVec2DFmPoint(lb.VpBBox.Min))
+	return lb.Render.PosToRuneIdx(rpt.Sub(tpos))
+}
+
+// HasSelection returns true if there is selected text
+func (lb *Label) HasSelection() bool {
+	return lb.SelectEnd > lb.SelectStart
+}
+
+// SelectRange selects the text between given rune indexes
+func (lb *Label) SelectRange(st, ed int) {
+	if st == lb.SelectStart && ed == lb.SelectEnd {
+		return
+	}
+	updt := lb.UpdateStart()
+	lb.SelectStart = st
+	lb.SelectEnd = ed
+	lb.UpdateEnd(updt)
+}
+
+// SelectAll selects all of the text
+func (lb *Label) SelectAll() {
+	sz := 0
+	for si := range lb.Render.Spans {
+		sz += len(lb.Render.Spans[si].Render)
+	}
+	lb.SelectRange(0, sz)
+}
+
+// SelectReset resets the selection
+func (lb *Label) SelectReset() {
+	lb.SelectRange(0, 0)
+}
+
+// SelectWord selects the word at given rune index -- words are delimited as
+// in TextField.IsWordBreak
+func (lb *Label) SelectWord(idx int) {
+	si, ri, ok := lb.Render.RuneSpanPos(idx)
+	if !ok {
+		return
+	}
+	txt := lb.Render.Spans[si].Text
+	isBreak := func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsSymbol(r) || unicode.IsPunct(r)
+	}
+	st, ed := ri, ri+1
+	if !isBreak(txt[ri]) {
+		for st > 0 && !isBreak(txt[st-1]) {
+			st--
+		}
+		for ed < len(txt) && !isBreak(txt[ed]) {
+			ed++
+		}
+	}
+	lb.SelectRange(idx-(ri-st), idx+(ed-ri))
+}
+
+// Selection returns the currently selected text, as plain text
+func (lb *Label) Selection() string {
+	if !lb.HasSelection() {
+		return ""
+	}
+	return lb.Render.RangeText(lb.SelectStart, lb.SelectEnd)
+}
+
+// Copy copies any selected text to the clipboard, as text/plain and
+// text/html, optionally resetting the selection -- returns the plain text
+func (lb *Label) Copy(reset bool) string {
+	if !lb.HasSelection() || lb.Viewport == nil || lb.Viewport.Win == nil {
+		return ""
+	}
+	cpy := lb.Selection()
+	htm := lb.Render.RangeHTML(lb.SelectStart, lb.SelectEnd)
+	oswin.TheApp.ClipBoard(lb.Viewport.Win.OSWin).Write(mimedata.NewTextPlus(cpy, mimedata.TextHTML, []byte(htm)))
+	if reset {
+		lb.SelectReset()
+	}
+	return cpy
+}
+
+// RenderSelect renders the selected text region, if any, underneath the
+// text, as a box behind each selected rune
+func (lb *Label) RenderSelect() {
+	if !lb.HasSelection() {
+		return
+	}
+	rs := &lb.Viewport.Render
+	pc := &rs.Paint
+	st := &lb.Sty
+	asc := FixedToFloat32(st.Font.Face.Metrics().Ascent)
+	for idx := lb.SelectStart; idx < lb.SelectEnd; idx++ {
+		rp, si, ri, ok := lb.Render.RuneRelPos(idx)
+		if !ok {
+			break
+		}
+		rr := &lb.Render.Spans[si].Render[ri]
+		pos := lb.RenderPos.Add(rp)
+		pos.Y -= asc
+		pc.FillBoxColor(rs, pos, Vec2D{rr.Size.X, st.Font.Height}, &Prefs.Colors.Select)
+	}
+}
+
+func (lb *Label) MakeContextMenu(m *Menu) {
+	if lb.TextSelectable {
+		cpsc := ActiveKeyMap.ChordForFun(KeyFunCopy)
+		ac := m.AddAction(ActOpts{Label: "Copy", Shortcut: cpsc},
+			lb.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+				llb := recv.Embed(KiT_Label).(*Label)
+				llb.Copy(false)
+			})
+		ac.SetActiveState(lb.HasSelection())
+		m.AddAction(ActOpts{Label: "Select All"},
+			lb.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+				llb := recv.Embed(KiT_Label).(*Label)
+				llb.SelectAll()
+			})
+		m.AddSeparator("sep-sel")
+	}
+	lb.WidgetBase.MakeContextMenu(m)
 }
 
 func (lb *Label) GrabCurBgColor() {
@@ -316,6 +507,7 @@ func (lb *Label) TextPos() Vec2D {
 
 func (lb *Label) StyleLabel() {
 	lb.Style2DWidget()
+	lb.Render.Cache = true
 	if lb.Sty.Text.Align != AlignLeft && lb.Sty.Layout.AlignH == AlignLeft {
 		lb.Sty.Layout.AlignH = lb.Sty.Text.Align // keep them consistent -- this is what people expect
 	} else if lb.Sty.Layout.AlignH != AlignLeft && lb.Sty.Text.Align == AlignLeft {
@@ -370,6 +562,8 @@ func (lb *Label) Layout2D(parBBox image.Rectangle, iter int) bool {
 			lb.Size2DFromWH(lb.Render.Size.X, lb.Render.Size.Y)
 			return true // needs a redo!
 		}
+	} else if lb.Sty.Text.Overflow == TextOverflowEllipsis && sz.X > 0 && lb.Render.Size.X > sz.X {
+		lb.Render.EllipsisLR(sz.X, &lb.Sty.Text, &lb.Sty.Font)
 	}
 	return false
 }
@@ -386,6 +580,7 @@ func (lb *Label) Render2D() {
 		rs := &lb.Viewport.Render
 		lb.RenderPos = lb.TextPos()
 		lb.RenderStdBox(st)
+		lb.RenderSelect()
 		lb.Render.Render(rs, lb.RenderPos)
 		lb.Render2DChildren()
 		lb.PopBounds()
@@ -397,3 +592,9 @@ func (lb *Label) Render2D() {
 func (lb *Label) ConnectEvents2D() {
 	lb.LabelEvents()
 }
+
+func (lb *Label) FocusChanged2D(change FocusChanges) {
+	if change == FocusLost && lb.HasSelection() {
+		lb.SelectReset()
+	}
+}
@@ -1019,6 +1019,20 @@ func (ly *Layout) LayoutGrid() {
 	}
 }
 
+// MirrorRTL mirrors the horizontal positions of the children, so that they
+// are arranged from right to left -- used for horizontal and grid layouts
+// with an rtl text direction style
+func (ly *Layout) MirrorRTL() {
+	avail := ly.LayData.AllocSize.X
+	for _, c := range ly.Kids {
+		ni := c.(Node2D).AsWidget()
+		if ni == nil {
+			continue
+		}
+		ni.LayData.AllocPosRel.X = avail - ni.LayData.AllocPosRel.X - ni.LayData.AllocSize.X
+	}
+}
+
 // FinalizeLayout is final pass through children to finalize the layout,
 // computing summary size stats
 func (ly *Layout) FinalizeLayout() {
@@ -1790,6 +1804,9 @@ func (ly *Layout) Layout2D(parBBox image.Rectangle, iter int) bool {
 	case LayoutNil:
 		// nothing
 	}
+	if ly.Sty.Text.Direction.IsRTL() && (ly.Lay == LayoutHoriz || ly.Lay == LayoutGrid) {
+		ly.MirrorRTL()
+	}
 	ly.FinalizeLayout()
 	ly.ManageOverflow()
 	ly.NeedsRedo = ly.Layout2DChildren(iter) // layout done with canonical positions
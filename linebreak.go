@@ -0,0 +1,348 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import "unicode"
+
+// Line breaking follows the Unicode line breaking algorithm (UAX #14,
+// http://www.unicode.org/reports/tr14/), which determines where lines can be
+// broken when wrapping text: after spaces, after hyphens and dashes, between
+// ideographs (Chinese, Japanese), between Hangul syllables etc, but not
+// before closing punctuation, within numbers and so on.  The line breaking
+// classes of runes are derived from their unicode categories, with explicit
+// classes for the punctuation, symbols and scripts that matter most -- the
+// classes of rarely used characters may differ from those in the unicode
+// LineBreak.txt data.  Complex-context scripts (Thai, Lao, Khmer, Myanmar)
+// require dictionary-based word segmentation, which is not supported: their
+// text is only broken at spaces.
+
+// LineBreakOpps are the types of line break opportunities before a rune
+type LineBreakOpps uint8
+
+const (
+	// BreakProhibited means that the line cannot be broken before the rune
+	BreakProhibited LineBreakOpps = iota
+
+	// BreakAllowed means that the line can be broken before the rune
+	BreakAllowed
+
+	// BreakMandatory means that the line must be broken before the rune,
+	// e.g., after a newline
+	BreakMandatory
+)
+
+// SoftHyphen is the soft hyphen rune (&shy;), which marks a point where a
+// word can be hyphenated -- it is only displayed (as a hyphen) at the end of
+// a wrapped line
+const SoftHyphen = '\u00AD'
+
+// lbClass is a line breaking class from UAX #14 -- only the classes used
+// after the resolution of LB1 are included
+type lbClass uint8
+
+const (
+	lbAL  lbClass = iota // ordinary alphabetic and symbol characters
+	lbBK                 // mandatory break
+	lbCR                 // carriage return
+	lbLF                 // line feed
+	lbNL                 // next line
+	lbSP                 // space
+	lbZW                 // zero width space
+	lbZWJ                // zero width joiner
+	lbCM                 // combining mark
+	lbWJ                 // word joiner
+	lbGL                 // non-breaking ("glue")
+	lbBA                 // break after
+	lbBB                 // break before
+	lbB2                 // break opportunity before and after (em dash)
+	lbHY                 // hyphen
+	lbCB                 // contingent break
+	lbOP                 // open punctuation
+	lbCL                 // close punctuation
+	lbCP                 // close parenthesis
+	lbQU                 // ambiguous quotation
+	lbNS                 // nonstarter
+	lbEX                 // exclamation / interrogation
+	lbSY                 // symbols allowing break after (slash)
+	lbIS                 // infix numeric separator
+	lbPR                 // prefix numeric
+	lbPO                 // postfix numeric
+	lbNU                 // numeric
+	lbID                 // ideographic
+	lbIN                 // inseparable
+	lbH2                 // hangul LV syllable
+	lbH3                 // hangul LVT syllable
+	lbJL                 // hangul L jamo
+	lbJV                 // hangul V jamo
+	lbJT                 // hangul T jamo
+	lbRI                 // regional indicator
+)
+
+// lbASCII are the line breaking classes of the printable ascii characters,
+// from space (0x20) to tilde (0x7E)
+var lbASCII = [...]lbClass{
+	lbSP, lbEX, lbQU, lbAL, lbPR, lbPO, lbAL, lbQU, lbOP, lbCP, lbAL, lbPR, lbIS, lbHY, lbIS, lbSY, // space - /
+	lbNU, lbNU, lbNU, lbNU, lbNU, lbNU, lbNU, lbNU, lbNU, lbNU, lbIS, lbIS, lbAL, lbAL, lbAL, lbEX, // 0 - ?
+	lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, // @ - O
+	lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbOP, lbPR, lbCP, lbAL, lbAL, // P - _
+	lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, // ` - o
+	lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbAL, lbOP, lbBA, lbCL, lbAL, // p - ~
+}
+
+// lbSpecial are the line breaking classes of non-ascii characters that
+// differ from those given by their unicode category
+var lbSpecial = map[rune]lbClass{
+	0x85: lbNL, 0xA0: lbGL, 0xA1: lbOP, 0xA2: lbPO, 0xA3: lbPR, 0xA4: lbPR, 0xA5: lbPR,
+	0xAB: lbQU, 0xAD: lbBA, 0xB0: lbPO, 0xB1: lbPR, 0xB4: lbBB, 0xBB: lbQU, 0xBF: lbOP,
+	0x034F: lbGL, 0x058A: lbBA, 0x05BE: lbBA, 0x0F0B: lbBA, 0x0F0C: lbGL, 0x1680: lbBA,
+	0x180E: lbGL, 0x2007: lbGL, 0x200B: lbZW, 0x200C: lbCM, 0x200D: lbZWJ,
+	0x2010: lbBA, 0x2011: lbGL, 0x2012: lbBA, 0x2013: lbBA, 0x2014: lbB2, 0x2015: lbAL,
+	0x2018: lbQU, 0x2019: lbQU, 0x201A: lbOP, 0x201B: lbQU, 0x201C: lbQU, 0x201D: lbQU,
+	0x201E: lbOP, 0x201F: lbQU, 0x2024: lbIN, 0x2025: lbIN, 0x2026: lbIN, 0x2027: lbBA,
+	0x2028: lbBK, 0x2029: lbBK, 0x202F: lbGL, 0x2039: lbQU, 0x203A: lbQU, 0x203C: lbNS,
+	0x203D: lbNS, 0x2044: lbIS, 0x2047: lbNS, 0x2048: lbNS, 0x2049: lbNS, 0x205F: lbBA,
+	0x2060: lbWJ, 0x2103: lbPO, 0x2109: lbPO, 0x2116: lbPR, 0x2212: lbPR, 0x2213: lbPR,
+	0x2E3A: lbB2, 0x2E3B: lbB2, 0x3000: lbBA, 0x3001: lbCL, 0x3002: lbCL, 0x3005: lbNS,
+	0x301C: lbNS, 0x303B: lbNS, 0x303C: lbNS, 0x30A0: lbNS, 0x30FB: lbNS, 0xFE50: lbCL,
+	0xFE52: lbCL, 0xFE54: lbNS, 0xFE55: lbNS, 0xFE56: lbEX, 0xFE57: lbEX, 0xFEFF: lbWJ,
+	0xFF01: lbEX, 0xFF04: lbPR, 0xFF05: lbPO, 0xFF0C: lbCL, 0xFF0E: lbCL, 0xFF1A: lbNS,
+	0xFF1B: lbNS, 0xFF1F: lbEX, 0xFF61: lbCL, 0xFF64: lbCL, 0xFF65: lbNS, 0xFF9E: lbNS,
+	0xFF9F: lbNS, 0xFFE0: lbPO, 0xFFE1: lbPR, 0xFFE5: lbPR, 0xFFE6: lbPR,
+	// small kana and prolonged sound marks (CJ), treated as NS (strict breaking)
+	0x3041: lbNS, 0x3043: lbNS, 0x3045: lbNS, 0x3047: lbNS, 0x3049: lbNS, 0x3063: lbNS,
+	0x3083: lbNS, 0x3085: lbNS, 0x3087: lbNS, 0x308E: lbNS, 0x3095: lbNS, 0x3096: lbNS,
+	0x309B: lbNS, 0x309C: lbNS, 0x309D: lbNS, 0x309E: lbNS,
+	0x30A1: lbNS, 0x30A3: lbNS, 0x30A5: lbNS, 0x30A7: lbNS, 0x30A9: lbNS, 0x30C3: lbNS,
+	0x30E3: lbNS, 0x30E5: lbNS, 0x30E7: lbNS, 0x30EE: lbNS, 0x30F5: lbNS, 0x30F6: lbNS,
+	0x30FC: lbNS, 0x30FD: lbNS, 0x30FE: lbNS,
+}
+
+// lbRange is a range of runes (inclusive) with the same line breaking class
+type lbRange struct {
+	lo, hi rune
+	cls    lbClass
+}
+
+// lbRanges are the line breaking classes of ranges of non-ascii characters
+// not given by their unicode category, in order
+var lbRanges = []lbRange{
+	{0x1100, 0x115F, lbJL}, {0x1160, 0x11A7, lbJV}, {0x11A8, 0x11FF, lbJT},
+	{0x2000, 0x2006, lbBA}, {0x2008, 0x200A, lbBA}, {0x2030, 0x2037, lbPO},
+	{0x20A0, 0x20CF, lbPR}, {0x2E80, 0x2FFF, lbID}, {0x3003, 0x303F, lbID},
+	{0x3040, 0x30FF, lbID}, {0x3100, 0x31EF, lbID}, {0x31F0, 0x31FF, lbNS},
+	{0x3200, 0x4DBF, lbID}, {0x4E00, 0x9FFF, lbID}, {0xA000, 0xA4CF, lbID},
+	{0xA960, 0xA97F, lbJL}, {0xAC00, 0xD7A3, lbH2}, {0xD7B0, 0xD7C6, lbJV},
+	{0xD7CB, 0xD7FB, lbJT}, {0xF900, 0xFAFF, lbID}, {0xFE30, 0xFE4F, lbID},
+	{0xFF00, 0xFF60, lbID}, {0xFFE0, 0xFFE6, lbID}, {0x1F000, 0x1F0FF, lbID},
+	{0x1F1E6, 0x1F1FF, lbRI}, {0x1F200, 0x1F2FF, lbID}, {0x1F300, 0x1F64F, lbID},
+	{0x1F680, 0x1F6FF, lbID}, {0x1F900, 0x1FAFF, lbID}, {0x20000, 0x3FFFD, lbID},
+}
+
+// lineBreakClass returns the line breaking class of given rune
+func lineBreakClass(r rune) lbClass {
+	switch {
+	case r >= 0x20 && r <= 0x7E:
+		return lbASCII[r-0x20]
+	case r == '\t':
+		return lbBA
+	case r == '\n':
+		return lbLF
+	case r == '\r':
+		return lbCR
+	case r == '\v' || r == '\f':
+		return lbBK
+	}
+	if cls, ok := lbSpecial[r]; ok {
+		return cls
+	}
+	for _, rg := range lbRanges {
+		if r < rg.lo {
+			break
+		}
+		if r <= rg.hi {
+			cls := rg.cls
+			if cls == lbH2 && (r-0xAC00)%28 != 0 {
+				cls = lbH3
+			}
+			if cls == lbID {
+				switch {
+				case unicode.Is(unicode.Ps, r):
+					cls = lbOP
+				case unicode.Is(unicode.Pe, r):
+					cls = lbCL
+				}
+			}
+			return cls
+		}
+	}
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Mc, unicode.Me, unicode.Cc):
+		return lbCM
+	case unicode.Is(unicode.Nd, r):
+		return lbNU
+	case unicode.Is(unicode.Ps, r):
+		return lbOP
+	case unicode.Is(unicode.Pe, r):
+		return lbCL
+	case unicode.In(r, unicode.Pi, unicode.Pf):
+		return lbQU
+	case unicode.Is(unicode.Zs, r):
+		return lbBA
+	case unicode.Is(unicode.Sc, r):
+		return lbPR
+	}
+	return lbAL
+}
+
+// LineBreaks returns the line break opportunities in given text according
+// to the unicode line breaking algorithm (UAX #14): the value for each index
+// is the type of break before the rune at that index (the value for index 0
+// is always BreakProhibited)
+func LineBreaks(txt []rune) []LineBreakOpps {
+	sz := len(txt)
+	brks := make([]LineBreakOpps, sz)
+	if sz < 2 {
+		return brks
+	}
+	// cls are the classes, with combining marks taking the class of their
+	// base (LB9, LB10)
+	cls := make([]lbClass, sz)
+	for i, r := range txt {
+		c := lineBreakClass(r)
+		if c == lbCM || c == lbZWJ {
+			if i > 0 {
+				switch pc := cls[i-1]; pc {
+				case lbBK, lbCR, lbLF, lbNL, lbSP, lbZW:
+					c = lbAL
+				default:
+					c = pc
+				}
+			} else {
+				c = lbAL
+			}
+		}
+		cls[i] = c
+	}
+	nri := 0 // number of regional indicators in a row, for LB30a
+	if cls[0] == lbRI {
+		nri = 1
+	}
+	for i := 1; i < sz; i++ {
+		a := cls[i-1]
+		b := cls[i]
+		bs := a // class before any spaces preceding i
+		for j := i - 1; bs == lbSP && j > 0; j-- {
+			bs = cls[j-1]
+		}
+		brks[i] = lineBreakPair(txt, cls, i, a, b, bs, nri)
+		if b == lbRI {
+			if a == lbRI {
+				nri++
+			} else {
+				nri = 1
+			}
+		} else {
+			nri = 0
+		}
+	}
+	return brks
+}
+
+// lineBreakPair returns the type of break between runes of class a and b at
+// given index, where bs is the class before any spaces preceding b, and nri
+// is the number of regional indicators in a row ending at a
+func lineBreakPair(txt []rune, cls []lbClass, i int, a, b, bs lbClass, nri int) LineBreakOpps {
+	attached := lineBreakClass(txt[i]) // combining marks and ZWJ attach to their base (LB8a, LB9)
+	if a != lbBK && a != lbCR && a != lbLF && a != lbNL && a != lbSP && a != lbZW && (attached == lbCM || attached == lbZWJ) {
+		return BreakProhibited
+	}
+	if lineBreakClass(txt[i-1]) == lbZWJ {
+		return BreakProhibited
+	}
+	switch {
+	case a == lbBK: // LB4
+		return BreakMandatory
+	case a == lbCR && b == lbLF: // LB5
+		return BreakProhibited
+	case a == lbCR || a == lbLF || a == lbNL:
+		return BreakMandatory
+	case b == lbBK || b == lbCR || b == lbLF || b == lbNL: // LB6
+		return BreakProhibited
+	case b == lbSP || b == lbZW: // LB7
+		return BreakProhibited
+	case bs == lbZW: // LB8
+		return BreakAllowed
+	case a == lbWJ || b == lbWJ: // LB11
+		return BreakProhibited
+	case a == lbGL: // LB12
+		return BreakProhibited
+	case b == lbGL && a != lbSP && a != lbBA && a != lbHY: // LB12a
+		return BreakProhibited
+	case b == lbCL || b == lbCP || b == lbEX || b == lbIS || b == lbSY: // LB13
+		return BreakProhibited
+	case bs == lbOP: // LB14
+		return BreakProhibited
+	case bs == lbQU && b == lbOP: // LB15
+		return BreakProhibited
+	case (bs == lbCL || bs == lbCP) && b == lbNS: // LB16
+		return BreakProhibited
+	case bs == lbB2 && b == lbB2: // LB17
+		return BreakProhibited
+	case a == lbSP: // LB18
+		return BreakAllowed
+	case a == lbQU || b == lbQU: // LB19
+		return BreakProhibited
+	case a == lbCB || b == lbCB: // LB20
+		return BreakAllowed
+	case b == lbBA || b == lbHY || b == lbNS || a == lbBB: // LB21
+		return BreakProhibited
+	case b == lbIN: // LB22
+		return BreakProhibited
+	case (a == lbAL && b == lbNU) || (a == lbNU && b == lbAL): // LB23
+		return BreakProhibited
+	case (a == lbPR && b == lbID) || (a == lbID && b == lbPO): // LB23a
+		return BreakProhibited
+	case (a == lbPR || a == lbPO) && b == lbAL, a == lbAL && (b == lbPR || b == lbPO): // LB24
+		return BreakProhibited
+	case lineBreakNumeric(a, b): // LB25
+		return BreakProhibited
+	case a == lbJL && (b == lbJL || b == lbJV || b == lbH2 || b == lbH3): // LB26
+		return BreakProhibited
+	case (a == lbJV || a == lbH2) && (b == lbJV || b == lbJT):
+		return BreakProhibited
+	case (a == lbJT || a == lbH3) && b == lbJT:
+		return BreakProhibited
+	case lineBreakHangul(a) && b == lbPO, a == lbPR && lineBreakHangul(b): // LB27
+		return BreakProhibited
+	case a == lbAL && b == lbAL: // LB28
+		return BreakProhibited
+	case a == lbIS && b == lbAL: // LB29
+		return BreakProhibited
+	case (a == lbAL || a == lbNU) && b == lbOP, a == lbCP && (b == lbAL || b == lbNU): // LB30
+		return BreakProhibited
+	case a == lbRI && b == lbRI && nri%2 == 1: // LB30a
+		return BreakProhibited
+	}
+	return BreakAllowed // LB31
+}
+
+// lineBreakNumeric returns true if there is no break between given classes
+// within numbers (LB25, in the simplified form of the UAX #14 example)
+func lineBreakNumeric(a, b lbClass) bool {
+	switch {
+	case (a == lbCL || a == lbCP || a == lbNU) && (b == lbPO || b == lbPR):
+		return true
+	case (a == lbPO || a == lbPR) && (b == lbOP || b == lbNU):
+		return true
+	case (a == lbHY || a == lbIS || a == lbNU || a == lbSY) && b == lbNU:
+		return true
+	}
+	return false
+}
+
+// lineBreakHangul returns true for the hangul syllable and jamo classes
+func lineBreakHangul(c lbClass) bool {
+	return c >= lbH2 && c <= lbJT
+}
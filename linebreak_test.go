@@ -0,0 +1,56 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"testing"
+)
+
+func TestLineBreaks(t *testing.T) {
+	tests := []struct {
+		txt  string
+		brks []int // indexes of allowed breaks
+		mand []int // indexes of mandatory breaks
+	}{
+		{"", nil, nil},
+		{"a", nil, nil},
+		{"hello world", []int{6}, nil},
+		{"two  spaces", []int{5}, nil},
+		{"self-made", []int{5}, nil},
+		{"line\nbreak", nil, []int{5}},
+		{"end.", nil, nil},
+		{"(quoted) text", []int{9}, nil},
+		{"3.14 and 1,000", []int{5, 9}, nil},
+		{"$100 now", []int{5}, nil},
+		{"soft\u00ADhyphen", []int{5}, nil}, // soft hyphen
+		{"日本語", []int{1, 2}, nil},
+		{"日本。", []int{1}, nil},
+		{"no\u00A0break", nil, nil}, // no-break space
+	}
+	for _, test := range tests {
+		txt := []rune(test.txt)
+		brks := LineBreaks(txt)
+		if len(brks) != len(txt) {
+			t.Errorf("LineBreaks(%q): got %v breaks for %v runes\n", test.txt, len(brks), len(txt))
+			continue
+		}
+		for i, brk := range brks {
+			exp := BreakProhibited
+			for _, bi := range test.brks {
+				if bi == i {
+					exp = BreakAllowed
+				}
+			}
+			for _, bi := range test.mand {
+				if bi == i {
+					exp = BreakMandatory
+				}
+			}
+			if brk != exp {
+				t.Errorf("LineBreaks(%q): break before index %v is %v, expected %v\n", test.txt, i, brk, exp)
+			}
+		}
+	}
+}
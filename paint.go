@@ -11,6 +11,7 @@ import (
 	"image/color"
 	"log"
 	"math"
+	"strings"
 	"sync"
 
 	"github.com/chewxy/math32"
@@ -66,10 +67,11 @@ type Paint struct {
 	UnContext   units.Context `xml:"-" desc:"units context -- parameters necessary for anchoring relative units"`
 	StrokeStyle StrokeStyle
 	FillStyle   FillStyle
-	FontStyle   FontStyle    `desc:"font also has global opacity setting, along with generic color, background-color settings, which can be copied into stroke / fill as needed"`
-	TextStyle   TextStyle    `desc:"font also has global opacity setting, along with generic color, background-color settings, which can be copied into stroke / fill as needed"`
-	VecEff      VectorEffect `xml:"vector-effect" desc:"various rendering special effects settings"`
-	XForm       Matrix2D     `xml:"transform" desc:"our additions to transform -- pushed to render state"`
+	FontStyle   FontStyle     `desc:"font also has global opacity setting, along with generic color, background-color settings, which can be copied into stroke / fill as needed"`
+	TextStyle   TextStyle     `desc:"font also has global opacity setting, along with generic color, background-color settings, which can be copied into stroke / fill as needed"`
+	VecEff      VectorEffect  `xml:"vector-effect" desc:"various rendering special effects settings"`
+	XForm       Matrix2D      `xml:"transform" desc:"our additions to transform -- pushed to render state"`
+	PointerEvs  PointerEvents `xml:"-" desc:"which parts of the rendered shape receive mouse events (pointer-events property) -- inherited -- see HitShape"`
 	dotsSet     bool
 	lastUnCtxt  units.Context
 }
@@ -94,6 +96,80 @@ var KiT_VectorEffect = kit.Enums.AddEnumAltLower(VecEffN, false, StylePropProps,
 func (ev VectorEffect) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
 func (ev *VectorEffect) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
 
+// PointerEvents determines which parts of a rendered shape receive mouse
+// events, as in the SVG pointer-events property -- the visible* versions are
+// equivalent to the others, as there is no separate visibility setting
+type PointerEvents int32
+
+const (
+	// PointerVisiblePainted is the default: the fill area if there is a
+	// fill, and the stroke if there is a stroke
+	PointerVisiblePainted PointerEvents = iota
+
+	// PointerVisibleFill is the fill area, whether or not it is filled
+	PointerVisibleFill
+
+	// PointerVisibleStroke is the stroke, whether or not it is stroked
+	PointerVisibleStroke
+
+	// PointerVisible is both the fill area and the stroke
+	PointerVisible
+
+	// PointerPainted is the same as PointerVisiblePainted
+	PointerPainted
+
+	// PointerFill is the same as PointerVisibleFill
+	PointerFill
+
+	// PointerStroke is the same as PointerVisibleStroke
+	PointerStroke
+
+	// PointerAll is the same as PointerVisible
+	PointerAll
+
+	// PointerNone means the shape never receives mouse events -- they go to
+	// whatever is underneath
+	PointerNone
+
+	PointerEventsN
+)
+
+//go:generate stringer -type=PointerEvents
+
+var KiT_PointerEvents = kit.Enums.AddEnumAltLower(PointerEventsN, false, StylePropProps, "Pointer")
+
+func (ev PointerEvents) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *PointerEvents) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// SetString sets the value from the svg / css property value (e.g.,
+// visiblePainted), ignoring case -- returns error if not recognized
+func (ev *PointerEvents) SetString(str string) error {
+	str = strings.ToLower(strings.TrimSpace(str))
+	for i := PointerVisiblePainted; i < PointerEventsN; i++ {
+		if str == strings.ToLower(strings.TrimPrefix(i.String(), "Pointer")) {
+			*ev = i
+			return nil
+		}
+	}
+	return fmt.Errorf("gi.PointerEvents SetString: %v is not a valid pointer-events value", str)
+}
+
+// HitAreas returns whether the fill area and the stroke of a shape receive
+// mouse events, for given paint settings
+func (pc *Paint) HitAreas() (fill, stroke bool) {
+	switch pc.PointerEvs {
+	case PointerVisiblePainted, PointerPainted:
+		return pc.HasFill(), pc.HasStroke()
+	case PointerVisibleFill, PointerFill:
+		return true, false
+	case PointerVisibleStroke, PointerStroke:
+		return false, true
+	case PointerVisible, PointerAll:
+		return true, true
+	}
+	return false, false
+}
+
 func (pc *Paint) Defaults() {
 	pc.Off = false
 	pc.StyleSet = false
@@ -119,6 +195,7 @@ func (pc *Paint) CopyStyleFrom(cp *Paint) {
 	pc.FontStyle = cp.FontStyle
 	pc.TextStyle = cp.TextStyle
 	pc.VecEff = cp.VecEff
+	pc.PointerEvs = cp.PointerEvs
 }
 
 // InheritFields from parent: Manual inheriting of values is much faster than
@@ -140,7 +217,21 @@ func (pc *Paint) SetStyleProps(par *Paint, props ki.Props) {
 	pc.StrokeStyle.SetStylePost(props)
 	pc.FillStyle.SetStylePost(props)
 	pc.FontStyle.SetStylePost(props)
+	if par != nil {
+		pc.FontStyle.SetRelWeight(&par.FontStyle)
+	}
 	pc.TextStyle.SetStylePost(props)
+	if pe, ok := props["pointer-events"]; ok {
+		if pes, ok := pe.(string); ok {
+			if pes == "inherit" {
+				if par != nil {
+					pc.PointerEvs = par.PointerEvs
+				}
+			} else if err := pc.PointerEvs.SetString(pes); err != nil {
+				log.Println(err)
+			}
+		}
+	}
 	pc.PropsNil = (len(props) == 0)
 	pc.StyleSet = true
 }
@@ -242,6 +333,7 @@ type RenderState struct {
 	ClipStack      []*image.Alpha    `desc:"stack of clips, if needed"`
 	PaintBack      Paint             `desc:"backup of paint -- don't need a full stack but sometimes safer to backup and restore"`
 	RasterMu       sync.Mutex        `desc:"mutex for final rasterx rendering -- only one at a time"`
+	Vector         VectorRenderer    `json:"-" xml:"-" desc:"if non-nil, all drawing operations are also sent to this vector renderer (e.g., a VecRecord for exporting to PDF)"`
 }
 
 // Init initializes RenderState -- must be called whenever image size changes
@@ -252,7 +344,7 @@ func (rs *RenderState) Init(width, height int, img *image.RGBA) {
 	// to use the golang.org/x/image/vector scanner, do this:
 	// rs.Scanner = rasterx.NewScannerGV(width, height, img, img.Bounds())
 	// and cut out painter:
-	painter := scanFT.NewRGBAPainter(img)
+	painter := newRenderPainter(img) // blends in linear light if GammaCorrect
 	rs.Scanner = scanFT.NewScannerFT(width, height, painter)
 	rs.Raster = rasterx.NewDasher(width, height, rs.Scanner)
 }
@@ -542,6 +634,11 @@ func (pc *Paint) stroke(rs *RenderState) {
 	rs.Raster.Draw()
 	rs.Raster.Clear()
 
+	if rs.Vector != nil {
+		rs.Vector.StrokePath(rs.Path, VecColor(&pc.StrokeStyle.Color, pc.FontStyle.Opacity*pc.StrokeStyle.Opacity),
+			&VecStroke{Width: pc.StrokeWidth(rs), Cap: pc.StrokeStyle.Cap, Join: pc.StrokeStyle.Join, MiterLimit: pc.StrokeStyle.MiterLimit, Dashes: dash}, rs.Bounds)
+	}
+
 	pr.End()
 }
 
@@ -567,6 +664,10 @@ func (pc *Paint) fill(rs *RenderState) {
 	rf.Draw()
 	rf.Clear()
 
+	if rs.Vector != nil {
+		rs.Vector.FillPath(rs.Path, VecColor(&pc.FillStyle.Color, pc.FontStyle.Opacity*pc.FillStyle.Opacity), pc.FillStyle.Rule == FillRuleNonZero, rs.Bounds)
+	}
+
 	pr.End()
 }
 
@@ -604,6 +705,9 @@ func (pc *Paint) FillBox(rs *RenderState, pos, size Vec2D, clr *ColorSpec) {
 	if clr.Source == SolidColor {
 		b := rs.Bounds.Intersect(RectFromPosSizeMax(pos, size))
 		draw.Draw(rs.Image, b, &image.Uniform{clr.Color}, image.ZP, draw.Src)
+		if rs.Vector != nil {
+			rs.Vector.FillPath(RectPath(b), clr.Color, true, rs.Bounds)
+		}
 	} else {
 		pc.FillStyle.SetColorSpec(clr)
 		pc.DrawRectangle(rs, pos.X, pos.Y, size.X, size.Y)
@@ -615,6 +719,9 @@ func (pc *Paint) FillBox(rs *RenderState, pos, size Vec2D, clr *ColorSpec) {
 func (pc *Paint) FillBoxColor(rs *RenderState, pos, size Vec2D, clr color.Color) {
 	b := rs.Bounds.Intersect(RectFromPosSizeMax(pos, size))
 	draw.Draw(rs.Image, b, &image.Uniform{clr}, image.ZP, draw.Src)
+	if rs.Vector != nil {
+		rs.Vector.FillPath(RectPath(b), clr, true, rs.Bounds)
+	}
 }
 
 // ClipPreserve updates the clipping region by intersecting the current
@@ -959,6 +1066,9 @@ func (pc *Paint) DrawImageAnchored(rs *RenderState, fmIm image.Image, x, y int,
 			DstMaskP: image.ZP,
 		})
 	}
+	if rs.Vector != nil {
+		rs.Vector.DrawImage(fmIm, m, rs.Bounds)
+	}
 }
 
 //////////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,195 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/srwiley/rasterx"
+)
+
+// PaintServer is implemented by any node that can generate the color for
+// filling or stroking an object, beyond the solid colors and gradients that
+// are directly represented in a ColorSpec -- e.g., the svg.Pattern element.
+// Paint servers are found by name using url(#name) in the fill, stroke or
+// background-color style properties.
+type PaintServer interface {
+	// PaintColor returns the color for rendering, as either a color.Color or
+	// a rasterx.ColorFunc, for given opacity, bounding box of the object
+	// being rendered (in render pixels), and the current transform from
+	// user space to render pixels
+	PaintColor(opacity float32, bounds image.Rectangle, xform Matrix2D) interface{}
+}
+
+// UserBBox returns the bounding box in user space (i.e., prior to given
+// transform) corresponding to given render bounding box -- exact for
+// transforms without rotation or skew
+func UserBBox(bounds image.Rectangle, xform Matrix2D) (pos, size Vec2D) {
+	inv := xform.Inverse()
+	mn := Vec2D{float32(bounds.Min.X), float32(bounds.Min.Y)}
+	mx := Vec2D{float32(bounds.Max.X), float32(bounds.Max.Y)}
+	pts := [4]Vec2D{mn, {mx.X, mn.Y}, mx, {mn.X, mx.Y}}
+	for i, p := range pts {
+		up := inv.TransformPointVec2D(p)
+		if i == 0 {
+			mn, mx = up, up
+		} else {
+			mn.SetMin(up)
+			mx.SetMax(up)
+		}
+	}
+	return mn, mx.Sub(mn)
+}
+
+// GradientColorFunc returns the color for rendering the given gradient as a
+// rasterx.ColorFunc (or a single color for degenerate gradients), for an
+// object with given render bounding box, under the given transform from user
+// space to render pixels.  The gradient Matrix (gradientTransform) is
+// applied within the gradient's own units, and all of the spread methods are
+// supported.  Unlike the rasterx version, render pixels are mapped back into
+// gradient space through the inverse of the full transform, so rotations and
+// skews of the gradient and of the object are rendered correctly.
+func GradientColorFunc(g *rasterx.Gradient, opacity float32, bounds image.Rectangle, xform Matrix2D) interface{} {
+	opc := float64(opacity)
+	switch len(g.Stops) {
+	case 0:
+		return rasterx.ApplyOpacity(color.RGBA{255, 0, 255, 255}, opc) // error color for gradient w/o stops
+	case 1:
+		return rasterx.ApplyOpacity(g.Stops[0].StopColor, g.Stops[0].Opacity*opc)
+	}
+	sort.SliceStable(g.Stops, func(i, j int) bool {
+		return g.Stops[i].Offset < g.Stops[j].Offset
+	})
+
+	gxf := Matrix2DFromRasterx(g.Matrix)
+	if g.Matrix == (rasterx.Matrix2D{}) { // not initialized
+		gxf = Identity2D()
+	}
+	if g.Units == rasterx.ObjectBoundingBox {
+		pos, sz := UserBBox(bounds, xform)
+		if sz.X == 0 || sz.Y == 0 {
+			return rasterx.ApplyOpacity(g.Stops[0].StopColor, g.Stops[0].Opacity*opc)
+		}
+		gxf = gxf.Multiply(Scale2D(sz.X, sz.Y).Multiply(Translate2D(pos.X, pos.Y)))
+	}
+	nxf := gxf.Multiply(xform)
+	if nxf.IsSingular() {
+		return rasterx.ApplyOpacity(g.Stops[0].StopColor, g.Stops[0].Opacity*opc)
+	}
+	inv := nxf.Inverse()
+
+	if !g.IsRadial {
+		x1, y1, x2, y2 := g.Points[0], g.Points[1], g.Points[2], g.Points[3]
+		dx := x2 - x1
+		dy := y2 - y1
+		d := dx*dx + dy*dy
+		if d == 0 {
+			ls := g.Stops[len(g.Stops)-1]
+			return rasterx.ApplyOpacity(ls.StopColor, ls.Opacity*opc)
+		}
+		return rasterx.ColorFunc(func(xi, yi int) color.Color {
+			x, y := inv.TransformPoint(float32(xi)+0.5, float32(yi)+0.5)
+			t := ((float64(x)-x1)*dx + (float64(y)-y1)*dy) / d
+			return GradientColorAt(g, t, opc)
+		})
+	}
+
+	cx, cy, fx, fy, r := g.Points[0], g.Points[1], g.Points[2], g.Points[3], g.Points[4]
+	if r <= 0 {
+		ls := g.Stops[len(g.Stops)-1]
+		return rasterx.ApplyOpacity(ls.StopColor, ls.Opacity*opc)
+	}
+	ex, ey := fx-cx, fy-cy
+	if fd := math.Sqrt(ex*ex + ey*ey); fd > r*0.999 { // focus must be inside circle
+		sc := r * 0.999 / fd
+		ex *= sc
+		ey *= sc
+		fx, fy = cx+ex, cy+ey
+	}
+	cc := ex*ex + ey*ey - r*r // always < 0
+	return rasterx.ColorFunc(func(xi, yi int) color.Color {
+		x, y := inv.TransformPoint(float32(xi)+0.5, float32(yi)+0.5)
+		dx, dy := float64(x)-fx, float64(y)-fy
+		a := dx*dx + dy*dy
+		if a == 0 {
+			return GradientColorAt(g, 0, opc)
+		}
+		// the ray from focus through point intersects circle at focus + s*d
+		b := dx*ex + dy*ey
+		s := (-b + math.Sqrt(b*b-a*cc)) / a
+		return GradientColorAt(g, 1/s, opc)
+	})
+}
+
+// GradientColorAt returns the color of the gradient at given position t
+// along it (0 = first stop, 1 = last), applying the spread method for values
+// outside of that range -- stops must be sorted by offset
+func GradientColorAt(g *rasterx.Gradient, t, opacity float64) color.Color {
+	switch g.Spread {
+	case rasterx.RepeatSpread:
+		t -= math.Floor(t)
+	case rasterx.ReflectSpread:
+		t = math.Mod(math.Abs(t), 2)
+		if t > 1 {
+			t = 2 - t
+		}
+	}
+	n := len(g.Stops)
+	if t <= g.Stops[0].Offset {
+		return rasterx.ApplyOpacity(g.Stops[0].StopColor, g.Stops[0].Opacity*opacity)
+	}
+	if t >= g.Stops[n-1].Offset {
+		return rasterx.ApplyOpacity(g.Stops[n-1].StopColor, g.Stops[n-1].Opacity*opacity)
+	}
+	i := 1
+	for i < n-1 && t > g.Stops[i].Offset {
+		i++
+	}
+	s1, s2 := g.Stops[i-1], g.Stops[i]
+	if s2.Offset == s1.Offset {
+		return rasterx.ApplyOpacity(s2.StopColor, s2.Opacity*opacity)
+	}
+	tp := (t - s1.Offset) / (s2.Offset - s1.Offset)
+	r1, g1, b1, _ := s1.StopColor.RGBA()
+	r2, g2, b2, _ := s2.StopColor.RGBA()
+	return rasterx.ApplyOpacity(color.RGBA{
+		uint8((float64(r1)*(1-tp) + float64(r2)*tp) / 256),
+		uint8((float64(g1)*(1-tp) + float64(g2)*tp) / 256),
+		uint8((float64(b1)*(1-tp) + float64(b2)*tp) / 256),
+		0xFF}, (s1.Opacity*(1-tp)+s2.Opacity*tp)*opacity)
+}
+
+// TileColorFunc returns a rasterx.ColorFunc that tiles the given image
+// across the plane -- pos, size give the rectangle of one tile in pattern
+// space, which is mapped to render pixels by the given transform, and the
+// image is stretched to fill the tile
+func TileColorFunc(img image.Image, pos, size Vec2D, xform Matrix2D, opacity float32) interface{} {
+	ib := img.Bounds()
+	isz := ib.Size()
+	if isz.X == 0 || isz.Y == 0 || size.X <= 0 || size.Y <= 0 || xform.IsSingular() {
+		return color.Transparent
+	}
+	inv := xform.Inverse()
+	scx := float64(isz.X) / float64(size.X)
+	scy := float64(isz.Y) / float64(size.Y)
+	return rasterx.ColorFunc(func(xi, yi int) color.Color {
+		x, y := inv.TransformPoint(float32(xi)+0.5, float32(yi)+0.5)
+		u := float64(x-pos.X) * scx
+		v := float64(y-pos.Y) * scy
+		u -= math.Floor(u/float64(isz.X)) * float64(isz.X)
+		v -= math.Floor(v/float64(isz.Y)) * float64(isz.Y)
+		px := InRangeInt(int(u), 0, isz.X-1) + ib.Min.X
+		py := InRangeInt(int(v), 0, isz.Y-1) + ib.Min.Y
+		clr := img.At(px, py)
+		if opacity >= 1 {
+			return clr
+		}
+		r, g, b, a := clr.RGBA()
+		return color.RGBA64{uint16(float32(r) * opacity), uint16(float32(g) * opacity), uint16(float32(b) * opacity), uint16(float32(a) * opacity)}
+	})
+}
@@ -74,6 +74,7 @@ type Preferences struct {
 	ScreenPrefs     map[string]ScreenPrefs `desc:"screen-specific preferences -- will override overall defaults if set"`
 	Colors          ColorPrefs             `desc:"color preferences"`
 	Params          ParamPrefs             `desc:"parameters controlling GUI behavior"`
+	TextRender      TextRenderPrefs        `desc:"text rendering: antialiasing, font hinting and gamma-correct blending"`
 	KeyMap          KeyMapName             `desc:"select the active keymap from list of available keymaps -- see Edit KeyMaps for editing / saving / loading that list"`
 	SaveKeyMaps     bool                   `desc:"if set, the current available set of key maps is saved to your preferences directory, and automatically loaded at startup -- this should be set if you are using custom key maps, but it may be safer to keep it <i>OFF</i> if you are <i>not</i> using custom key maps, so that you'll always have the latest compiled-in standard key maps with all the current key functions bound to standard key chords"`
 	PrefsOverride   bool                   `desc:"if true my custom style preferences override other styling -- otherwise they provide defaults that can be overriden by app-specific styling"`
@@ -84,6 +85,7 @@ type Preferences struct {
 	FavPaths        FavPaths               `desc:"favorite paths, shown in FileViewer and also editable there"`
 	SavedPathsMax   int                    `desc:"maximum number of saved paths to save in FileView"`
 	FileViewSort    string                 `view:"-" desc:"column to sort by in FileView, and :up or :down for direction -- updated automatically via FileView"`
+	TableViews      TableViewPrefsMap      `view:"-" desc:"sort and filter state of TableViews, by the type of struct they show -- updated automatically for TableViews with the save-state property"`
 	ColorFilename   FileName               `view:"-" ext:".json" desc:"filename for saving / loading colors"`
 	Changed         bool                   `view:"-" changeflag:"+" json:"-" xml:"-" desc:"flag that is set by StructView by virtue of changeflag tag, whenever an edit is made.  Used to drive save menus etc."`
 }
@@ -142,6 +144,7 @@ func (pf *Preferences) Defaults() {
 	pf.LogicalDPIScale = 1.0
 	pf.Colors.Defaults()
 	pf.Params.Defaults()
+	pf.TextRender.Defaults()
 	pf.FavPaths.SetToDefaults()
 	pf.FontFamily = "Go"
 	pf.SavedPathsMax = 20
@@ -223,6 +226,7 @@ func (pf *Preferences) Apply() {
 	mouse.DoubleClickMSec = pf.Params.DoubleClickMSec
 	mouse.ScrollWheelRate = pf.Params.ScrollWheelRate
 	LocalMainMenu = pf.Params.LocalMainMenu
+	pf.TextRender.Apply()
 
 	if pf.KeyMap != "" {
 		SetActiveKeyMapName(pf.KeyMap) // fills in missing pieces
@@ -506,6 +510,34 @@ var DefaultPaths = FavPaths{
 	{"computer", "root", "/"},
 }
 
+////////////////////////////////////////////////////////////////////////////////
+//  TableViewPrefs
+
+// TableViewPrefs is the saved state of the TableViews showing a given type
+// of struct
+type TableViewPrefs struct {
+	Sort   string `desc:"fields to sort by, each with :up or :down for direction, separated by commas"`
+	Filter string `desc:"filter expression for the rows shown"`
+}
+
+// TableViewPrefsMap is the saved state of TableViews, by the type name of
+// the struct they show
+type TableViewPrefsMap map[string]*TableViewPrefs
+
+// Prefs returns the saved state for given struct type name, creating it if
+// it does not exist yet
+func (tm *TableViewPrefsMap) Prefs(typNm string) *TableViewPrefs {
+	if *tm == nil {
+		*tm = make(TableViewPrefsMap)
+	}
+	tp, ok := (*tm)[typNm]
+	if !ok {
+		tp = &TableViewPrefs{}
+		(*tm)[typNm] = tp
+	}
+	return tp
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 //  FilePaths
 
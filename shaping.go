@@ -0,0 +1,769 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"image/color"
+	"math/bits"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/goki/freetype/truetype"
+	"github.com/srwiley/rasterx"
+	"golang.org/x/image/font"
+)
+
+// Text shaping converts a sequence of runes into positioned glyphs, using the
+// OpenType layout tables of the font: GSUB for glyph substitutions
+// (ligatures, and the conjunct and half forms of Indic scripts), and GPOS for
+// glyph positioning (pair kerning and the placement of combining marks on
+// their base characters).  Shaping is done as part of SpanRender.SetRunePosLR
+// -- the runes of the span are kept, and the RuneRender for each rune records
+// the shaping results: a substituted Glyph to draw in place of the rune's
+// own glyph, and Ligated for runes that are drawn as part of a ligature
+// glyph of a prior rune.  Each rune still has its own position, so rune
+// positions and cursor positioning work as before -- the runes of a ligature
+// evenly divide up the ligature glyph.  Fonts without layout tables use the
+// kern table of the font for kerning.  Additional features (e.g., tnum for
+// tabular numbers, smcp for small caps, ss01 for a stylistic set) can be
+// turned on, and the standard ones off, with the font-feature-settings style
+// property, which is recorded in the face (see FontLib.FontOpts).
+
+// FontShaper provides the OpenType layout (GSUB, GPOS) information of a font
+// needed for shaping text.  The most commonly-used lookup types are
+// supported: single and ligature substitution, pair adjustment (kerning),
+// and mark-to-base attachment -- contextual lookups and the positional forms
+// of joining scripts (e.g., Arabic) are not supported.
+type FontShaper struct {
+	UnitsPerEm int `desc:"font design units per em, for scaling positions to the font size"`
+	gsub       []byte
+	gpos       []byte
+	scripts    map[string]map[string]*shapeLookups
+	mu         sync.Mutex
+}
+
+// shapeLookups are the lookups used for shaping text in a given script
+type shapeLookups struct {
+	subs []otLookup // required substitutions
+	ligs []otLookup // required substitutions plus standard ligatures
+	kern []otLookup
+	mark []otLookup
+}
+
+// otLookup is an OpenType layout lookup, with extension subtables resolved
+type otLookup struct {
+	typ  int
+	subs [][]byte
+}
+
+// ShapeSubFeatures are the GSUB features that are always applied in
+// shaping: glyph composition and the forms required for Indic scripts
+var ShapeSubFeatures = map[string]bool{
+	"ccmp": true, "rlig": true, "nukt": true, "akhn": true, "rphf": true,
+	"rkrf": true, "pref": true, "blwf": true, "half": true, "pstf": true,
+	"vatu": true, "cjct": true, "pres": true, "abvs": true, "blws": true,
+	"psts": true, "haln": true,
+}
+
+// ShapeLigFeatures are the GSUB features for standard ligatures (e.g., fi,
+// or the operator ligatures of code fonts) -- these are not applied to
+// letter-spaced text
+var ShapeLigFeatures = map[string]bool{"liga": true, "clig": true}
+
+// ShapeKernFeatures are the GPOS features used for kerning
+var ShapeKernFeatures = map[string]bool{"kern": true, "dist": true}
+
+// ShapeMarkFeatures are the GPOS features used for positioning marks
+var ShapeMarkFeatures = map[string]bool{"mark": true}
+
+// NewFontShaper returns a new FontShaper for given font file data, or nil if
+// the font has no layout tables (or is not a valid font)
+func NewFontShaper(data []byte) *FontShaper {
+	tbls := otTables(data)
+	fs := &FontShaper{gsub: tbls["GSUB"], gpos: tbls["GPOS"]}
+	if fs.gsub == nil && fs.gpos == nil {
+		return nil
+	}
+	fs.UnitsPerEm = otU16(tbls["head"], 18)
+	if fs.UnitsPerEm == 0 {
+		fs.UnitsPerEm = 1000
+	}
+	fs.scripts = make(map[string]map[string]*shapeLookups)
+	return fs
+}
+
+// Shaper returns the FontShaper for font of given name, or nil if the font
+// does not have layout tables or its outlines cannot be read (see
+// TrueTypeFont)
+func (fl *FontLib) Shaper(fontnm string) *FontShaper {
+	if _, err := fl.TrueTypeFont(fontnm); err != nil {
+		return nil
+	}
+	loadFontMu.Lock()
+	defer loadFontMu.Unlock()
+	return fl.shapers[strings.ToLower(fontnm)]
+}
+
+// FaceShaper returns the shaper (nil if none), truetype font (nil if not
+// available) and size in dots of given face from the library -- faces with
+// synthesized small caps are not shaped, and return only the size
+func (fl *FontLib) FaceShaper(face font.Face) (*FontShaper, *truetype.Font, float32) {
+	fontnm, size, ok := fl.FaceInfo(face)
+	if !ok {
+		return nil, nil, 0
+	}
+	if _, caps := face.(*capsFace); caps {
+		return nil, nil, float32(size)
+	}
+	f, err := fl.TrueTypeFont(fontnm)
+	if err != nil {
+		return nil, nil, float32(size)
+	}
+	return fl.Shaper(fontnm), f, float32(size)
+}
+
+// shapeScripts are the OpenType script tags for the scripts that have them
+// -- the first tag is preferred, e.g., the newer Indic shaping tags
+var shapeScripts = []struct {
+	tbl  *unicode.RangeTable
+	tags []string
+}{
+	{unicode.Latin, []string{"latn"}},
+	{unicode.Devanagari, []string{"dev2", "deva"}},
+	{unicode.Bengali, []string{"bng2", "beng"}},
+	{unicode.Gurmukhi, []string{"gur2", "guru"}},
+	{unicode.Gujarati, []string{"gjr2", "gujr"}},
+	{unicode.Oriya, []string{"ory2", "orya"}},
+	{unicode.Tamil, []string{"tml2", "taml"}},
+	{unicode.Telugu, []string{"tel2", "telu"}},
+	{unicode.Kannada, []string{"knd2", "knda"}},
+	{unicode.Malayalam, []string{"mlm2", "mlym"}},
+	{unicode.Greek, []string{"grek"}},
+	{unicode.Cyrillic, []string{"cyrl"}},
+	{unicode.Hebrew, []string{"hebr"}},
+	{unicode.Arabic, []string{"arab"}},
+	{unicode.Thai, []string{"thai"}},
+}
+
+// ShapeScript returns the OpenType script tags to use for shaping given
+// text, based on the first character that belongs to a known script -- nil
+// if none, in which case the default script of the font is used
+func ShapeScript(txt []rune) []string {
+	for _, r := range txt {
+		if r < 0x80 && !unicode.IsLetter(r) {
+			continue
+		}
+		for _, ss := range shapeScripts {
+			if unicode.Is(ss.tbl, r) {
+				return ss.tags
+			}
+		}
+	}
+	return nil
+}
+
+// lookups returns the lookups for given script tags and font-feature-settings
+// (in the canonical form of FontFeatures.String), cached
+func (fs *FontShaper) lookups(script []string, feats string) *shapeLookups {
+	key := ""
+	if len(script) > 0 {
+		key = script[0]
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fsl, ok := fs.scripts[key]
+	if !ok {
+		fsl = make(map[string]*shapeLookups)
+		fs.scripts[key] = fsl
+	}
+	if sl, ok := fsl[feats]; ok {
+		return sl
+	}
+	ff := ParseFontFeatures(feats)
+	sl := &shapeLookups{}
+	sl.subs = otFeatureLookups(fs.gsub, script, ff.Apply(ShapeSubFeatures), 7)
+	sl.ligs = otFeatureLookups(fs.gsub, script, ff.Apply(ShapeSubFeatures, ShapeLigFeatures), 7)
+	sl.kern = otFeatureLookups(fs.gpos, script, ff.Apply(ShapeKernFeatures), 9)
+	sl.mark = otFeatureLookups(fs.gpos, script, ShapeMarkFeatures, 9)
+	fsl[feats] = sl
+	return sl
+}
+
+// HasFeature returns true if the font has given feature (e.g., smcp) in its
+// GSUB or GPOS table
+func (fs *FontShaper) HasFeature(tag string) bool {
+	for _, tbl := range [][]byte{fs.gsub, fs.gpos} {
+		fl := otSub(tbl, otU16(tbl, 6))
+		nf := otU16(fl, 0)
+		for i := 0; i < nf; i++ {
+			if otTag(fl, 2+6*i) == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasKern returns true if the font has kerning information for given script
+// and font-feature-settings in its layout tables
+func (fs *FontShaper) HasKern(script []string, feats string) bool {
+	return len(fs.lookups(script, feats).kern) > 0
+}
+
+// Substitute applies the glyph substitutions of the font for given script
+// and font-feature-settings to given glyphs, in place -- ncomp has the
+// number of runes represented by each glyph, initially 1 for each, which is
+// updated for ligatures: the number of runes for the first glyph, and 0 for
+// the others, which are merged into it.  ligs includes the standard
+// ligatures.
+func (fs *FontShaper) Substitute(script []string, feats string, glyphs, ncomp []int, ligs bool) {
+	sl := fs.lookups(script, feats)
+	lks := sl.subs
+	if ligs {
+		lks = sl.ligs
+	}
+	for _, lk := range lks {
+		for i := range glyphs {
+			if ncomp[i] == 0 {
+				continue
+			}
+			for _, st := range lk.subs {
+				if otApplySub(lk.typ, st, glyphs, ncomp, i) {
+					break
+				}
+			}
+		}
+	}
+}
+
+// Kern returns the kerning adjustment of the advance of glyph a when
+// followed by glyph b, in font units
+func (fs *FontShaper) Kern(script []string, feats string, a, b int) int {
+	kern := 0
+	for _, lk := range fs.lookups(script, feats).kern {
+		if lk.typ != 2 {
+			continue
+		}
+		for _, st := range lk.subs {
+			if k, ok := otPairKern(st, a, b); ok {
+				kern += k
+				break
+			}
+		}
+	}
+	return kern
+}
+
+// MarkOffset returns the offset of the origin of given mark glyph from the
+// origin of given base glyph that it is attached to, in font units (with Y
+// upward as in the font) -- false if the font does not specify the
+// attachment
+func (fs *FontShaper) MarkOffset(script []string, base, mark int) (dx, dy int, ok bool) {
+	for _, lk := range fs.lookups(script, "").mark {
+		if lk.typ != 4 {
+			continue
+		}
+		for _, st := range lk.subs {
+			if dx, dy, ok = otMarkBase(st, base, mark); ok {
+				return
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+//////////////////////////////////////////////////////////////////////////////////
+//  SpanRender shaping
+
+// Shape applies the glyph substitutions (ligatures, and other forms as
+// required for complex scripts) of the fonts used in the span, setting the
+// Glyph and Ligated values of the runes -- ligs enables the standard
+// ligatures (liga, clig features), which should not be used for
+// letter-spaced text.  Called by SetRunePosLR.
+func (sr *SpanRender) Shape(ligs bool) {
+	sz := len(sr.Text)
+	if sz == 0 {
+		return
+	}
+	script := ShapeScript(sr.Text)
+	curFace := sr.Render[0].Face
+	st := 0
+	for i := 0; i <= sz; i++ {
+		if i < sz {
+			rr := &(sr.Render[i])
+			rr.Glyph = 0
+			rr.Ligated = false
+			if rr.Face == nil || rr.Face == curFace {
+				continue
+			}
+		}
+		sr.shapeRun(curFace, script, st, i, ligs)
+		if i < sz {
+			curFace = sr.Render[i].Face
+			st = i
+		}
+	}
+}
+
+// shapeRun applies glyph substitutions to the runes from st to ed, which all
+// use given face
+func (sr *SpanRender) shapeRun(face font.Face, script []string, st, ed int, ligs bool) {
+	if ed-st < 1 {
+		return
+	}
+	sh, f, _ := FontLibrary.FaceShaper(face)
+	if sh == nil || f == nil || sh.gsub == nil {
+		return
+	}
+	feats := FontLibrary.FaceFeatures(face)
+	n := ed - st
+	glyphs := make([]int, n)
+	ncomp := make([]int, n)
+	for k := range glyphs {
+		glyphs[k] = int(f.Index(sr.Text[st+k]))
+		ncomp[k] = 1
+	}
+	sh.Substitute(script, feats, glyphs, ncomp, ligs)
+	for k, g := range glyphs {
+		rr := &(sr.Render[st+k])
+		if ncomp[k] == 0 {
+			rr.Ligated = true
+		} else if g != int(f.Index(sr.Text[st+k])) {
+			rr.Glyph = g
+		}
+	}
+}
+
+// ClusterBounds returns the range of rune indexes (st inclusive, ed
+// exclusive) of the cluster that the rune at given index belongs to: a base
+// character with any following combining marks, and all of the runes of a
+// ligature -- positions within a cluster are not distinct character
+// positions for editing purposes
+func (sr *SpanRender) ClusterBounds(idx int) (st, ed int) {
+	sz := len(sr.Text)
+	if idx < 0 || idx >= sz {
+		return idx, idx
+	}
+	inCluster := func(i int) bool {
+		return sr.Render[i].Ligated || unicode.Is(unicode.Mn, sr.Text[i])
+	}
+	st = idx
+	for st > 0 && inCluster(st) {
+		st--
+	}
+	ed = idx + 1
+	for ed < sz && inCluster(ed) {
+		ed++
+	}
+	return
+}
+
+// indic pre-base matras are vowel signs that are written before the
+// consonant cluster that they follow in the text
+func isIndicPreBase(r rune) bool {
+	switch r {
+	case 0x093F, 0x09BF, 0x09C7, 0x09C8, 0x0A3F, 0x0ABF, 0x0B47, 0x0BC6, 0x0BC7, 0x0BC8, 0x0D46, 0x0D47, 0x0D48:
+		return true
+	}
+	return false
+}
+
+// isIndicVirama returns true for the virama (halant) signs that join
+// consonants into clusters
+func isIndicVirama(r rune) bool {
+	switch r {
+	case 0x094D, 0x09CD, 0x0A4D, 0x0ACD, 0x0B4D, 0x0BCD, 0x0C4D, 0x0CCD, 0x0D4D:
+		return true
+	}
+	return false
+}
+
+// indicClusterStart returns the index of the start of the consonant cluster
+// ending just before given index (of a pre-base matra)
+func indicClusterStart(txt []rune, idx int) int {
+	j := idx - 1
+	for j > 0 && unicode.Is(unicode.Mn, txt[j]) && !isIndicVirama(txt[j]) { // nukta
+		j--
+	}
+	for j >= 2 && isIndicVirama(txt[j-1]) {
+		j -= 2
+		for j > 0 && unicode.Is(unicode.Mn, txt[j]) && !isIndicVirama(txt[j]) {
+			j--
+		}
+	}
+	if j < 0 {
+		j = 0
+	}
+	return j
+}
+
+// RenderGlyph renders a glyph given by its index in the font (e.g., a
+// ligature substituted in shaping), from its outline, at given position (left
+// baseline), with given rotation and x scaling -- returns false if the glyph
+// outline is not available
+func RenderGlyph(rs *RenderState, face font.Face, glyph int, pos Vec2D, rot, scalex float32, clr color.Color) bool {
+	if scalex == 0 {
+		scalex = 1
+	}
+	xf := Scale2D(scalex, 1).Rotate(rot)
+	var p rasterx.Path
+	var ok bool
+	if of, isOf := face.(OutlineFace); isOf {
+		p, ok = of.GlyphIndexOutline(glyph, pos, xf)
+	} else {
+		_, f, size := FontLibrary.FaceShaper(face)
+		if f == nil {
+			return false
+		}
+		p, ok = GlyphIndexOutline(f, size, truetype.Index(glyph), pos, xf)
+	}
+	if !ok {
+		return true // e.g., a space
+	}
+	rs.RasterMu.Lock()
+	rf := &rs.Raster.Filler
+	rf.SetWinding(true)
+	rs.Scanner.SetClip(rs.Bounds)
+	p.AddTo(rf)
+	rf.SetColor(clr)
+	rf.Draw()
+	rf.Clear()
+	rs.RasterMu.Unlock()
+	if rs.Vector != nil {
+		rs.Vector.FillPath(p, clr, true, rs.Bounds)
+	}
+	return true
+}
+
+//////////////////////////////////////////////////////////////////////////////////
+//  OpenType table parsing
+
+// otU16 returns the big-endian uint16 at given offset, 0 if out of range
+func otU16(b []byte, off int) int {
+	if off < 0 || off+2 > len(b) {
+		return 0
+	}
+	return int(b[off])<<8 | int(b[off+1])
+}
+
+// otI16 returns the big-endian int16 at given offset, 0 if out of range
+func otI16(b []byte, off int) int {
+	return int(int16(otU16(b, off)))
+}
+
+// otU32 returns the big-endian uint32 at given offset, 0 if out of range
+func otU32(b []byte, off int) int {
+	if off < 0 || off+4 > len(b) {
+		return 0
+	}
+	return int(b[off])<<24 | int(b[off+1])<<16 | int(b[off+2])<<8 | int(b[off+3])
+}
+
+// otSub returns the data starting at given offset, nil for a null (0) or
+// out of range offset
+func otSub(b []byte, off int) []byte {
+	if off <= 0 || off >= len(b) {
+		return nil
+	}
+	return b[off:]
+}
+
+// otTag returns the 4-byte tag at given offset
+func otTag(b []byte, off int) string {
+	if off < 0 || off+4 > len(b) {
+		return ""
+	}
+	return string(b[off : off+4])
+}
+
+// otTables returns the tables of an sfnt font file, by tag
+func otTables(data []byte) map[string][]byte {
+	tbls := make(map[string][]byte)
+	n := otU16(data, 4)
+	for i := 0; i < n; i++ {
+		rec := 12 + 16*i
+		off := otU32(data, rec+8)
+		ln := otU32(data, rec+12)
+		if off+ln > len(data) || off <= 0 {
+			continue
+		}
+		tbls[otTag(data, rec)] = data[off : off+ln]
+	}
+	return tbls
+}
+
+// otFeatureLookups returns the lookups for the given features in the first
+// of given scripts present in a GSUB or GPOS table (or the default script),
+// in the order in which they are to be applied -- extType is the lookup
+// type for extension lookups, which are resolved
+func otFeatureLookups(tbl []byte, script []string, feats map[string]bool, extType int) []otLookup {
+	if len(tbl) < 10 {
+		return nil
+	}
+	sl := otSub(tbl, otU16(tbl, 4))
+	fl := otSub(tbl, otU16(tbl, 6))
+	ll := otSub(tbl, otU16(tbl, 8))
+	var scr []byte
+	tags := append(append([]string{}, script...), "DFLT", "latn")
+	for _, tag := range tags {
+		ns := otU16(sl, 0)
+		for i := 0; i < ns; i++ {
+			if otTag(sl, 2+6*i) == tag {
+				scr = otSub(sl, otU16(sl, 2+6*i+4))
+				break
+			}
+		}
+		if scr != nil {
+			break
+		}
+	}
+	ls := otSub(scr, otU16(scr, 0)) // default language system
+	if ls == nil {
+		return nil
+	}
+	lidxs := []int{}
+	has := map[int]bool{}
+	nf := otU16(ls, 4)
+	for i := 0; i < nf; i++ {
+		fi := otU16(ls, 6+2*i)
+		if !feats[otTag(fl, 2+6*fi)] {
+			continue
+		}
+		ft := otSub(fl, otU16(fl, 2+6*fi+4))
+		nl := otU16(ft, 2)
+		for j := 0; j < nl; j++ {
+			li := otU16(ft, 4+2*j)
+			if !has[li] {
+				has[li] = true
+				lidxs = append(lidxs, li)
+			}
+		}
+	}
+	sort.Ints(lidxs) // lookups are applied in lookup list order
+	var lks []otLookup
+	for _, li := range lidxs {
+		lk := otSub(ll, otU16(ll, 2+2*li))
+		if lk == nil {
+			continue
+		}
+		olk := otLookup{typ: otU16(lk, 0)}
+		nst := otU16(lk, 4)
+		for k := 0; k < nst; k++ {
+			st := otSub(lk, otU16(lk, 6+2*k))
+			if st == nil {
+				continue
+			}
+			if olk.typ == extType {
+				olk.typ = otU16(st, 2)
+			}
+			if otU16(lk, 0) == extType {
+				st = otSub(st, otU32(st, 4))
+				if st == nil {
+					continue
+				}
+			}
+			olk.subs = append(olk.subs, st)
+		}
+		lks = append(lks, olk)
+	}
+	return lks
+}
+
+// otCoverage returns the coverage index of given glyph in a coverage table,
+// -1 if not covered
+func otCoverage(cov []byte, g int) int {
+	switch otU16(cov, 0) {
+	case 1:
+		lo, hi := 0, otU16(cov, 2)-1
+		for lo <= hi {
+			m := (lo + hi) / 2
+			mg := otU16(cov, 4+2*m)
+			switch {
+			case g < mg:
+				hi = m - 1
+			case g > mg:
+				lo = m + 1
+			default:
+				return m
+			}
+		}
+	case 2:
+		lo, hi := 0, otU16(cov, 2)-1
+		for lo <= hi {
+			m := (lo + hi) / 2
+			rec := 4 + 6*m
+			switch {
+			case g < otU16(cov, rec):
+				hi = m - 1
+			case g > otU16(cov, rec+2):
+				lo = m + 1
+			default:
+				return otU16(cov, rec+4) + g - otU16(cov, rec)
+			}
+		}
+	}
+	return -1
+}
+
+// otClass returns the class of given glyph in a class definition table
+func otClass(cd []byte, g int) int {
+	switch otU16(cd, 0) {
+	case 1:
+		st := otU16(cd, 2)
+		if g >= st && g < st+otU16(cd, 4) {
+			return otU16(cd, 6+2*(g-st))
+		}
+	case 2:
+		lo, hi := 0, otU16(cd, 2)-1
+		for lo <= hi {
+			m := (lo + hi) / 2
+			rec := 4 + 6*m
+			switch {
+			case g < otU16(cd, rec):
+				hi = m - 1
+			case g > otU16(cd, rec+2):
+				lo = m + 1
+			default:
+				return otU16(cd, rec+4)
+			}
+		}
+	}
+	return 0
+}
+
+// otApplySub applies a GSUB single (1) or ligature (4) substitution subtable
+// at position i of glyphs -- returns true if applied
+func otApplySub(typ int, st []byte, glyphs, ncomp []int, i int) bool {
+	ci := otCoverage(otSub(st, otU16(st, 2)), glyphs[i])
+	if ci < 0 {
+		return false
+	}
+	switch typ {
+	case 1:
+		switch otU16(st, 0) {
+		case 1:
+			glyphs[i] = (glyphs[i] + otI16(st, 4)) & 0xFFFF
+			return true
+		case 2:
+			if ci < otU16(st, 4) {
+				glyphs[i] = otU16(st, 6+2*ci)
+				return true
+			}
+		}
+	case 4:
+		set := otSub(st, otU16(st, 6+2*ci))
+		nl := otU16(set, 0)
+		for l := 0; l < nl; l++ {
+			lig := otSub(set, otU16(set, 2+2*l))
+			nc := otU16(lig, 2)
+			if nc < 2 {
+				continue
+			}
+			comps := make([]int, 0, nc-1)
+			j := i
+			for c := 1; c < nc; c++ {
+				j++
+				for j < len(glyphs) && ncomp[j] == 0 {
+					j++
+				}
+				if j >= len(glyphs) || glyphs[j] != otU16(lig, 4+2*(c-1)) {
+					break
+				}
+				comps = append(comps, j)
+			}
+			if len(comps) != nc-1 {
+				continue
+			}
+			glyphs[i] = otU16(lig, 0)
+			for _, j := range comps {
+				ncomp[i] += ncomp[j]
+				ncomp[j] = 0
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// otValueSize returns the size of a GPOS value record of given format
+func otValueSize(vf int) int {
+	return 2 * bits.OnesCount16(uint16(vf&0xFF))
+}
+
+// otValueXAdv returns the x advance value of a GPOS value record of given
+// format at given offset
+func otValueXAdv(b []byte, off, vf int) int {
+	if vf&0x4 == 0 {
+		return 0
+	}
+	return otI16(b, off+2*bits.OnesCount16(uint16(vf&0x3)))
+}
+
+// otPairKern returns the kerning (x advance adjustment of the first glyph)
+// for given pair of glyphs in a GPOS pair adjustment subtable -- false if
+// the pair is not in the subtable
+func otPairKern(st []byte, a, b int) (int, bool) {
+	ci := otCoverage(otSub(st, otU16(st, 2)), a)
+	if ci < 0 {
+		return 0, false
+	}
+	vf1 := otU16(st, 4)
+	vf2 := otU16(st, 6)
+	vsz := otValueSize(vf1) + otValueSize(vf2)
+	switch otU16(st, 0) {
+	case 1:
+		set := otSub(st, otU16(st, 10+2*ci))
+		rsz := 2 + vsz
+		lo, hi := 0, otU16(set, 0)-1
+		for lo <= hi {
+			m := (lo + hi) / 2
+			rec := 2 + rsz*m
+			sg := otU16(set, rec)
+			switch {
+			case b < sg:
+				hi = m - 1
+			case b > sg:
+				lo = m + 1
+			default:
+				return otValueXAdv(set, rec+2, vf1), true
+			}
+		}
+	case 2:
+		c1 := otClass(otSub(st, otU16(st, 8)), a)
+		c2 := otClass(otSub(st, otU16(st, 10)), b)
+		n1 := otU16(st, 12)
+		n2 := otU16(st, 14)
+		if c1 >= n1 || c2 >= n2 {
+			return 0, false
+		}
+		return otValueXAdv(st, 16+(c1*n2+c2)*vsz, vf1), true
+	}
+	return 0, false
+}
+
+// otMarkBase returns the offset of a mark glyph from the base glyph it
+// attaches to, from a GPOS mark-to-base attachment subtable
+func otMarkBase(st []byte, base, mark int) (dx, dy int, ok bool) {
+	mci := otCoverage(otSub(st, otU16(st, 2)), mark)
+	bci := otCoverage(otSub(st, otU16(st, 4)), base)
+	if mci < 0 || bci < 0 {
+		return 0, 0, false
+	}
+	ncls := otU16(st, 6)
+	ma := otSub(st, otU16(st, 8))
+	ba := otSub(st, otU16(st, 10))
+	cls := otU16(ma, 2+4*mci)
+	if cls >= ncls {
+		return 0, 0, false
+	}
+	manc := otSub(ma, otU16(ma, 2+4*mci+2))
+	banc := otSub(ba, otU16(ba, 2+2*(bci*ncls+cls)))
+	if manc == nil || banc == nil {
+		return 0, 0, false
+	}
+	return otI16(banc, 2) - otI16(manc, 2), otI16(banc, 4) - otI16(manc, 4), true
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"reflect"
+	"testing"
+)
+
+// testOTTable returns an OpenType table of given big-endian 16-bit values
+func testOTTable(vals ...int) []byte {
+	b := make([]byte, 2*len(vals))
+	for i, v := range vals {
+		b[2*i] = byte(v >> 8)
+		b[2*i+1] = byte(v)
+	}
+	return b
+}
+
+func TestShapeScript(t *testing.T) {
+	tests := []struct {
+		txt  string
+		tags []string
+	}{
+		{"", nil},
+		{"123 !?", nil},
+		{"hello", []string{"latn"}},
+		{"12 नमस्ते", []string{"dev2", "deva"}},
+		{"Привет", []string{"cyrl"}},
+		{"1 שלום", []string{"hebr"}},
+		{"مرحبا", []string{"arab"}},
+		{"สวัสดี", []string{"thai"}},
+		{"日本", nil},
+	}
+	for _, tst := range tests {
+		tags := ShapeScript([]rune(tst.txt))
+		if !reflect.DeepEqual(tags, tst.tags) {
+			t.Errorf("ShapeScript(%q): %v != correct: %v\n", tst.txt, tags, tst.tags)
+		}
+	}
+}
+
+func TestOTCoverage(t *testing.T) {
+	cov1 := testOTTable(1, 3, 3, 7, 9)              // glyphs 3, 7, 9
+	cov2 := testOTTable(2, 2, 10, 12, 0, 20, 25, 3) // ranges 10-12, 20-25
+	tests := []struct {
+		cov []byte
+		g   int
+		ci  int
+	}{
+		{cov1, 3, 0},
+		{cov1, 7, 1},
+		{cov1, 9, 2},
+		{cov1, 5, -1},
+		{cov1, 10, -1},
+		{cov2, 10, 0},
+		{cov2, 12, 2},
+		{cov2, 20, 3},
+		{cov2, 25, 8},
+		{cov2, 15, -1},
+		{cov2, 30, -1},
+		{nil, 3, -1},
+	}
+	for _, tst := range tests {
+		if ci := otCoverage(tst.cov, tst.g); ci != tst.ci {
+			t.Errorf("otCoverage(%v, %v): %v != correct: %v\n", tst.cov, tst.g, ci, tst.ci)
+		}
+	}
+}
+
+func TestOTClass(t *testing.T) {
+	cd1 := testOTTable(1, 5, 3, 1, 2, 0)           // glyphs 5..7
+	cd2 := testOTTable(2, 2, 10, 12, 3, 20, 20, 4) // ranges 10-12, 20
+	tests := []struct {
+		cd  []byte
+		g   int
+		cls int
+	}{
+		{cd1, 5, 1},
+		{cd1, 6, 2},
+		{cd1, 7, 0},
+		{cd1, 4, 0},
+		{cd1, 8, 0},
+		{cd2, 11, 3},
+		{cd2, 20, 4},
+		{cd2, 15, 0},
+		{cd2, 21, 0},
+	}
+	for _, tst := range tests {
+		if cls := otClass(tst.cd, tst.g); cls != tst.cls {
+			t.Errorf("otClass(%v, %v): %v != correct: %v\n", tst.cd, tst.g, cls, tst.cls)
+		}
+	}
+}
+
+func TestOTApplySub(t *testing.T) {
+	single1 := testOTTable(1, 6, 3, 1, 1, 5)             // glyph 5 + 3
+	single2 := testOTTable(2, 10, 2, 40, 41, 1, 2, 5, 6) // glyphs 5, 6 -> 40, 41
+	// ligatures of glyph 10 (f): 10 10 11 (ffi) -> 101, and 10 11 (fi) -> 100
+	lig := testOTTable(1, 8, 1, 14, 1, 1, 10, 2, 6, 14, 101, 3, 10, 11, 100, 2, 11)
+	tests := []struct {
+		typ    int
+		st     []byte
+		glyphs []int
+		ncomp  []int
+		i      int
+		ok     bool
+		rglyph []int
+		rncomp []int
+	}{
+		{1, single1, []int{5, 4}, []int{1, 1}, 0, true, []int{8, 4}, []int{1, 1}},
+		{1, single1, []int{5, 4}, []int{1, 1}, 1, false, []int{5, 4}, []int{1, 1}},
+		{1, single2, []int{6}, []int{1}, 0, true, []int{41}, []int{1}},
+		{1, single2, []int{7}, []int{1}, 0, false, []int{7}, []int{1}},
+		{4, lig, []int{10, 10, 11, 12}, []int{1, 1, 1, 1}, 0, true, []int{101, 10, 11, 12}, []int{3, 0, 0, 1}},
+		{4, lig, []int{10, 11}, []int{1, 1}, 0, true, []int{100, 11}, []int{2, 0}},
+		{4, lig, []int{10, 12}, []int{1, 1}, 0, false, []int{10, 12}, []int{1, 1}},
+		{4, lig, []int{10}, []int{1}, 0, false, []int{10}, []int{1}},
+		{4, lig, []int{10, 99, 11}, []int{1, 0, 1}, 0, true, []int{100, 99, 11}, []int{2, 0, 0}}, // skips ligated components
+	}
+	for _, tst := range tests {
+		glyphs := append([]int{}, tst.glyphs...)
+		ncomp := append([]int{}, tst.ncomp...)
+		ok := otApplySub(tst.typ, tst.st, glyphs, ncomp, tst.i)
+		if ok != tst.ok || !reflect.DeepEqual(glyphs, tst.rglyph) || !reflect.DeepEqual(ncomp, tst.rncomp) {
+			t.Errorf("otApplySub(%v, %v, %v, %v): %v %v %v != correct: %v %v %v\n", tst.typ, tst.glyphs, tst.ncomp, tst.i, ok, glyphs, ncomp, tst.ok, tst.rglyph, tst.rncomp)
+		}
+	}
+}
+
+func TestOTPairKern(t *testing.T) {
+	// format 1: pairs of glyph 20 with 30 and 31
+	pair1 := testOTTable(1, 12, 4, 0, 1, 18, 1, 1, 20, 2, 30, -50&0xFFFF, 31, 25)
+	// format 2: glyph 21 in class 1, glyph 30 in class 1
+	pair2 := testOTTable(2, 24, 4, 0, 32, 42, 2, 2, 0, -10&0xFFFF, 0, -20&0xFFFF, 1, 2, 20, 21, 1, 20, 2, 0, 1, 1, 30, 1, 1)
+	tests := []struct {
+		st   []byte
+		a, b int
+		kern int
+		ok   bool
+	}{
+		{pair1, 20, 30, -50, true},
+		{pair1, 20, 31, 25, true},
+		{pair1, 20, 32, 0, false},
+		{pair1, 21, 30, 0, false},
+		{pair2, 20, 30, -10, true},
+		{pair2, 21, 30, -20, true},
+		{pair2, 21, 31, 0, true},
+		{pair2, 22, 30, 0, false},
+	}
+	for _, tst := range tests {
+		kern, ok := otPairKern(tst.st, tst.a, tst.b)
+		if kern != tst.kern || ok != tst.ok {
+			t.Errorf("otPairKern(%v, %v): %v %v != correct: %v %v\n", tst.a, tst.b, kern, ok, tst.kern, tst.ok)
+		}
+	}
+}
+
+func TestIndicClusterStart(t *testing.T) {
+	tests := []struct {
+		txt string
+		idx int
+		st  int
+	}{
+		{"\u0928\u092e\u093f", 2, 1},                         // na ma i
+		{"\u0915\u094d\u0937\u093f", 3, 0},                   // ka virama ssa i
+		{"\u0915\u093c\u093f", 2, 0},                         // ka nukta i
+		{"\u0905\u0938\u094d\u0924\u094d\u0930\u093f", 6, 1}, // a sa virama ta virama ra i
+	}
+	for _, tst := range tests {
+		if st := indicClusterStart([]rune(tst.txt), tst.idx); st != tst.st {
+			t.Errorf("indicClusterStart(%q, %v): %v != correct: %v\n", tst.txt, tst.idx, st, tst.st)
+		}
+	}
+}
@@ -196,6 +196,52 @@ func (s *ShadowStyle) HasShadow() bool {
 	return (s.HOffset.Dots > 0 || s.VOffset.Dots > 0)
 }
 
+// SetString sets the shadow from the css shorthand form: h-offset v-offset
+// [blur [spread]] [color] [inset] -- none clears the shadow, and the color
+// is nil if not specified
+func (s *ShadowStyle) SetString(str string) {
+	*s = ShadowStyle{}
+	str = strings.TrimSpace(str)
+	if str == "" || str == "none" {
+		return
+	}
+	var vals []units.Value
+	clr := ""
+	depth := 0 // within parens of color function
+	for _, tok := range strings.Fields(str) {
+		c := tok[0]
+		switch {
+		case depth == 0 && tok == "inset":
+			s.Inset = true
+		case depth == 0 && (c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9')):
+			vals = append(vals, units.StringToValue(tok))
+		default:
+			if clr != "" {
+				clr += " "
+			}
+			clr += tok
+			depth += strings.Count(tok, "(") - strings.Count(tok, ")")
+		}
+	}
+	for i, v := range vals {
+		switch i {
+		case 0:
+			s.HOffset = v
+		case 1:
+			s.VOffset = v
+		case 2:
+			s.Blur = v
+		case 3:
+			s.Spread = v
+		}
+	}
+	if clr != "" {
+		if err := s.Color.SetString(clr, nil); err != nil {
+			log.Printf("gi.ShadowStyle SetString: %v\n", err)
+		}
+	}
+}
+
 // CurrentColor is automatically updated from the Color setting of a Style and
 // accessible as a color name in any other style as currentColor
 var CurrentColor Color
@@ -252,6 +298,9 @@ func (s *Style) SetStyleProps(par *Style, props ki.Props) {
 	}
 	s.Layout.SetStylePost(props)
 	s.Font.SetStylePost(props)
+	if par != nil {
+		s.Font.SetRelWeight(&par.Font)
+	}
 	s.Text.SetStylePost(props)
 	s.PropsNil = (len(props) == 0)
 	s.IsSet = true
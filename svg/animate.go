@@ -0,0 +1,574 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package svg
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/goki/gi"
+	"github.com/goki/ki/kit"
+	"golang.org/x/image/colornames"
+)
+
+// see animator.go for the Animator that drives these animations over time,
+// and for binding attributes to changing data from Go
+
+// Animate is an SMIL animation element (animate, set or animateTransform)
+// that animates an attribute of its target element over time -- the target
+// is the parent element, unless Href names another one -- values are
+// interpolated as numbers (including lists of numbers, e.g., in path data),
+// or colors, and otherwise change discretely
+type Animate struct {
+	NodeBase
+	Kind        AnimKinds `desc:"kind of animation element"`
+	Href        string    `xml:"href" desc:"name of the target element, if not the parent"`
+	AttrName    string    `xml:"attributeName" desc:"name of the attribute to animate -- always transform for animateTransform"`
+	TransType   string    `xml:"type" desc:"for animateTransform, the type of transform: translate, scale, rotate, skewX or skewY"`
+	From        string    `xml:"from" desc:"starting value -- defaults to the base value of the attribute"`
+	To          string    `xml:"to" desc:"ending value"`
+	By          string    `xml:"by" desc:"relative offset to add to From to get the ending value, if To is not set"`
+	Values      []string  `xml:"values" desc:"list of values to animate through -- overrides From, To and By"`
+	KeyTimes    []float32 `xml:"keyTimes" desc:"times (as proportions of the duration) for each of the Values -- evenly spaced if not set"`
+	Begin       float32   `xml:"begin" desc:"time in seconds when the animation begins -- negative means indefinite, i.e., it never begins on its own"`
+	Dur         float32   `xml:"dur" desc:"simple duration of the animation in seconds -- 0 means indefinite"`
+	RepeatCount float32   `xml:"repeatCount" desc:"number of times to repeat the simple duration (can be fractional) -- negative means indefinitely, 0 is once"`
+	RepeatDur   float32   `xml:"repeatDur" desc:"if > 0, total duration of the repeated animation, in seconds, overriding RepeatCount"`
+	Freeze      bool      `xml:"fill" desc:"keep the final value after the animation ends (fill=freeze) instead of restoring the base value (fill=remove)"`
+	Discrete    bool      `xml:"calcMode" desc:"jump between values instead of interpolating (calcMode=discrete)"`
+	Additive    bool      `xml:"additive" desc:"add the animated value to the base value (additive=sum) instead of replacing it"`
+	BaseVal     string    `json:"-" xml:"-" desc:"base value of the attribute before animation, recorded when the Animator starts"`
+	Active      bool      `json:"-" xml:"-" desc:"animation is currently setting the value of the attribute"`
+}
+
+var KiT_Animate = kit.Types.AddType(&Animate{}, nil)
+
+// AnimKinds are the kinds of SMIL animation elements
+type AnimKinds int32
+
+const (
+	// AnimAnimate is the animate element, which animates any attribute
+	AnimAnimate AnimKinds = iota
+
+	// AnimSet is the set element, which sets an attribute to the To value
+	// for the duration
+	AnimSet
+
+	// AnimTransform is the animateTransform element, which animates the
+	// transform attribute
+	AnimTransform
+
+	AnimKindsN
+)
+
+//go:generate stringer -type=AnimKinds
+
+var KiT_AnimKinds = kit.Enums.AddEnumAltLower(AnimKindsN, false, nil, "Anim")
+
+func (ev AnimKinds) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *AnimKinds) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// Render2D does nothing: animations are applied by the Animator
+func (am *Animate) Render2D() {
+}
+
+// Target returns the element that is animated
+func (am *Animate) Target() gi.Node2D {
+	if am.Href != "" {
+		return am.FindNamedElement(strings.TrimPrefix(am.Href, "#"))
+	}
+	pgi, _ := gi.KiToNode2D(am.Par)
+	return pgi
+}
+
+// TargetAttr returns the name of the attribute that is animated
+func (am *Animate) TargetAttr() string {
+	if am.Kind == AnimTransform {
+		return "transform"
+	}
+	return am.AttrName
+}
+
+// Init records the base value of the attribute, prior to animation
+func (am *Animate) Init() {
+	am.Active = false
+	if tg := am.Target(); tg != nil {
+		am.BaseVal = NodeAttr(tg, am.TargetAttr())
+	}
+}
+
+// Restore restores the base value of the attribute, if the animation is
+// active, returning true if the target must be re-styled
+func (am *Animate) Restore() bool {
+	if !am.Active {
+		return false
+	}
+	am.Active = false
+	if tg := am.Target(); tg != nil {
+		return SetNodeAttr(tg, am.TargetAttr(), am.BaseVal)
+	}
+	return false
+}
+
+// Apply applies the animation at given time on the timeline, in seconds,
+// returning restyle = true if the target must be re-styled, and more = true
+// if the animation will still be changing after this time
+func (am *Animate) Apply(t float32) (restyle, more bool) {
+	tg := am.Target()
+	if tg == nil {
+		return false, false
+	}
+	val, active, more := am.ValueAt(t)
+	if !active {
+		return am.Restore(), more
+	}
+	am.Active = true
+	if am.Kind == AnimTransform {
+		tt := strings.ToLower(am.TransType)
+		if tt == "" {
+			tt = "translate"
+		}
+		val = tt + "(" + val + ")"
+		if am.Additive && am.BaseVal != "" {
+			val = am.BaseVal + " " + val
+		}
+	} else if am.Additive {
+		if sv, ok := CombineValues(am.BaseVal, val, func(a, b float32) float32 { return a + b }); ok {
+			val = sv
+		}
+	}
+	return SetNodeAttr(tg, am.TargetAttr(), val), more
+}
+
+// ValueAt returns the value of the animation at given time on the timeline,
+// whether the animation is active at that time, and whether it will still be
+// changing after that time
+func (am *Animate) ValueAt(t float32) (val string, active, more bool) {
+	if am.Begin < 0 {
+		return "", false, false
+	}
+	if t < am.Begin {
+		return "", false, true
+	}
+	vals := am.ValueList()
+	if len(vals) == 0 {
+		return "", false, false
+	}
+	if am.Dur <= 0 { // indefinite: no interpolation possible
+		return vals[0], true, false
+	}
+	lt := t - am.Begin
+	actDur := float32(math.MaxFloat32)
+	switch {
+	case am.RepeatDur > 0:
+		actDur = am.RepeatDur
+	case am.RepeatCount == 0:
+		actDur = am.Dur
+	case am.RepeatCount > 0:
+		actDur = am.Dur * am.RepeatCount
+	}
+	if lt >= actDur {
+		if !am.Freeze {
+			return "", false, false
+		}
+		p := float32(math.Mod(float64(actDur), float64(am.Dur))) / am.Dur
+		if p == 0 {
+			p = 1
+		}
+		return am.Interp(vals, p), true, false
+	}
+	p := float32(math.Mod(float64(lt), float64(am.Dur))) / am.Dur
+	return am.Interp(vals, p), true, true
+}
+
+// ValueList returns the list of values to animate through
+func (am *Animate) ValueList() []string {
+	if am.Kind == AnimSet {
+		return []string{am.To}
+	}
+	if len(am.Values) > 0 {
+		return am.Values
+	}
+	from := am.From
+	if from == "" {
+		if am.Kind == AnimTransform {
+			to := am.To
+			if to == "" {
+				to = am.By
+			}
+			from = transformIdentity(am.TransType, to)
+		} else {
+			from = am.BaseVal
+		}
+	}
+	switch {
+	case am.To != "":
+		return []string{from, am.To}
+	case am.By != "":
+		to, ok := CombineValues(from, am.By, func(a, b float32) float32 { return a + b })
+		if !ok {
+			to = am.By
+		}
+		return []string{from, to}
+	}
+	return nil
+}
+
+// transformIdentity returns the values for given type of transform that
+// leave things unchanged, with the same number of values as given target
+// values (e.g., the To value) -- the center of a rotation is kept
+func transformIdentity(ttype, to string) string {
+	ttype = strings.ToLower(ttype)
+	idx := 0
+	id, ok := CombineValues(to, to, func(a, b float32) float32 {
+		idx++
+		switch {
+		case ttype == "scale":
+			return 1
+		case ttype == "rotate" && idx > 1:
+			return a
+		}
+		return 0
+	})
+	if !ok {
+		if ttype == "scale" {
+			return "1"
+		}
+		return "0"
+	}
+	return id
+}
+
+// Interp returns the value at given proportion p (0-1) through the simple
+// duration, for given list of values
+func (am *Animate) Interp(vals []string, p float32) string {
+	n := len(vals)
+	if n == 1 {
+		return vals[0]
+	}
+	kt := am.KeyTimes
+	if len(kt) != n {
+		kt = make([]float32, n)
+		div := float32(n - 1)
+		if am.Discrete {
+			div = float32(n)
+		}
+		for i := range kt {
+			kt[i] = float32(i) / div
+		}
+	}
+	i := 0
+	for i < n-1 && p >= kt[i+1] {
+		i++
+	}
+	if am.Discrete || i == n-1 {
+		return vals[i]
+	}
+	seg := kt[i+1] - kt[i]
+	if seg <= 0 {
+		return vals[i+1]
+	}
+	return InterpValues(vals[i], vals[i+1], (p-kt[i])/seg)
+}
+
+// SetXMLAttr sets an attribute of the animation element from svg xml
+func (am *Animate) SetXMLAttr(name, val string) error {
+	var err error
+	val = strings.TrimSpace(val)
+	switch name {
+	case "href":
+		am.Href = strings.TrimPrefix(val, "#")
+	case "attributeName":
+		am.AttrName = val
+	case "type":
+		am.TransType = val
+	case "from":
+		am.From = val
+	case "to":
+		am.To = val
+	case "by":
+		am.By = val
+	case "values":
+		am.Values = nil
+		for _, v := range strings.Split(val, ";") {
+			if v = strings.TrimSpace(v); v != "" {
+				am.Values = append(am.Values, v)
+			}
+		}
+	case "keyTimes":
+		am.KeyTimes = nil
+		for _, v := range strings.Split(val, ";") {
+			if v = strings.TrimSpace(v); v != "" {
+				var kt float32
+				kt, err = gi.ParseFloat32(v)
+				am.KeyTimes = append(am.KeyTimes, kt)
+			}
+		}
+	case "begin":
+		am.Begin = -1 // event-based begin values are not supported: indefinite
+		for _, v := range strings.Split(val, ";") {
+			if bt, berr := ParseClockValue(v); berr == nil {
+				am.Begin = bt
+				break
+			}
+		}
+	case "dur":
+		if val != "indefinite" && val != "media" {
+			am.Dur, err = ParseClockValue(val)
+		}
+	case "repeatCount":
+		if val == "indefinite" {
+			am.RepeatCount = -1
+		} else {
+			am.RepeatCount, err = gi.ParseFloat32(val)
+		}
+	case "repeatDur":
+		if val == "indefinite" {
+			am.RepeatCount = -1
+		} else {
+			am.RepeatDur, err = ParseClockValue(val)
+		}
+	case "fill":
+		am.Freeze = val == "freeze"
+	case "calcMode":
+		am.Discrete = val == "discrete"
+	case "additive":
+		am.Additive = val == "sum"
+	default:
+		am.SetProp(name, val)
+	}
+	return err
+}
+
+// ParseClockValue parses an SMIL clock value, e.g., 2s, 500ms, 1.5min, 2h
+// or 01:30.5, returning the time in seconds
+func ParseClockValue(str string) (float32, error) {
+	str = strings.TrimSpace(str)
+	if strings.Contains(str, ":") {
+		var secs float32
+		for _, f := range strings.Split(str, ":") {
+			v, err := strconv.ParseFloat(f, 32)
+			if err != nil {
+				return 0, err
+			}
+			secs = secs*60 + float32(v)
+		}
+		return secs, nil
+	}
+	mult := float32(1)
+	for _, u := range []struct {
+		sfx  string
+		mult float32
+	}{{"ms", 0.001}, {"min", 60}, {"h", 3600}, {"s", 1}} {
+		if strings.HasSuffix(str, u.sfx) {
+			str = strings.TrimSuffix(str, u.sfx)
+			mult = u.mult
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(str, 32)
+	return float32(v) * mult, err
+}
+
+/////////////////////////////////////////////////////////////////////////////
+//  Attribute values
+
+// NodeAttr returns the current value of the attribute of given svg element,
+// using the same names as in the svg file -- see SetNodeAttr
+func NodeAttr(node gi.Node2D, attr string) string {
+	if p, ok := node.(*Path); ok && attr == "d" {
+		return p.DataStr
+	}
+	if fv, ok := nodeAttrField(node, attr); ok {
+		return kit.ToString(fv.Interface())
+	}
+	if pv, ok := node.Prop(attr); ok {
+		return kit.ToString(pv)
+	}
+	return ""
+}
+
+// SetNodeAttr sets the attribute of given svg element to given value, using
+// the same names as in the svg file: struct fields with a matching xml tag
+// are set directly (including the {x,y} style tags of Vec2D fields), Path d
+// data is re-parsed, and anything else (style properties, transform) is set
+// as a property -- returns true if a property was set, in which case the
+// element must be re-styled for it to take effect
+func SetNodeAttr(node gi.Node2D, attr, val string) bool {
+	if p, ok := node.(*Path); ok && attr == "d" {
+		p.SetData(val)
+		return false
+	}
+	if fv, ok := nodeAttrField(node, attr); ok {
+		if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() == reflect.Float32 {
+			fv.Set(reflect.ValueOf(gi.ReadPoints(val)))
+		} else {
+			kit.SetRobust(fv.Addr().Interface(), val)
+		}
+		return false
+	}
+	node.SetProp(attr, val)
+	return true
+}
+
+// nodeAttrField returns the struct field for the given attribute name, based
+// on the xml tag of the field
+func nodeAttrField(node gi.Node2D, attr string) (reflect.Value, bool) {
+	v := reflect.ValueOf(node)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	v = v.Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			continue
+		}
+		tag := f.Tag.Get("xml")
+		if tag == attr {
+			return v.Field(i), true
+		}
+		if !strings.HasPrefix(tag, "{") {
+			continue
+		}
+		for j, nm := range strings.Split(strings.Trim(tag, "{}"), ",") {
+			fv := v.Field(i)
+			if nm == attr && fv.Kind() == reflect.Struct && j < fv.NumField() {
+				return fv.Field(j), true
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// animToken is a number or other text in an animation value
+type animToken struct {
+	num bool
+	str string
+	val float32
+}
+
+// animTokens splits an animation value into numbers and the other text
+// between them
+func animTokens(s string) []animToken {
+	var toks []animToken
+	isDigit := func(i int) bool { return i < len(s) && s[i] >= '0' && s[i] <= '9' }
+	isNumStart := func(i int) bool {
+		switch {
+		case isDigit(i):
+			return true
+		case s[i] == '.':
+			return isDigit(i + 1)
+		case s[i] == '-' || s[i] == '+':
+			return isDigit(i+1) || (i+1 < len(s) && s[i+1] == '.' && isDigit(i+2))
+		}
+		return false
+	}
+	st := 0
+	for i := 0; i < len(s); {
+		if !isNumStart(i) {
+			i++
+			continue
+		}
+		if i > st {
+			toks = append(toks, animToken{str: s[st:i]})
+		}
+		j := i + 1
+		dot := s[i] == '.'
+		for j < len(s) {
+			switch {
+			case isDigit(j):
+				j++
+				continue
+			case s[j] == '.' && !dot:
+				dot = true
+				j++
+				continue
+			case (s[j] == 'e' || s[j] == 'E') && (isDigit(j+1) || ((j+2 < len(s)) && (s[j+1] == '-' || s[j+1] == '+') && isDigit(j+2))):
+				j += 2
+				continue
+			}
+			break
+		}
+		v, _ := strconv.ParseFloat(s[i:j], 32)
+		toks = append(toks, animToken{num: true, str: s[i:j], val: float32(v)})
+		st, i = j, j
+	}
+	if st < len(s) {
+		toks = append(toks, animToken{str: s[st:]})
+	}
+	return toks
+}
+
+// animSepNorm normalizes the text between numbers for comparison, removing
+// space and commas
+func animSepNorm(s string) string {
+	return strings.Join(strings.Fields(strings.Replace(s, ",", " ", -1)), "")
+}
+
+// CombineValues combines the numbers in two animation values using given
+// function, if they have the same structure (i.e., the same text between
+// the numbers, ignoring spacing) -- returns false if not
+func CombineValues(a, b string, fun func(a, b float32) float32) (string, bool) {
+	at := animTokens(a)
+	bt := animTokens(b)
+	if len(at) != len(bt) || len(at) == 0 {
+		return "", false
+	}
+	var sb strings.Builder
+	for i, tk := range at {
+		if tk.num != bt[i].num {
+			return "", false
+		}
+		if !tk.num {
+			if animSepNorm(tk.str) != animSepNorm(bt[i].str) {
+				return "", false
+			}
+			sb.WriteString(tk.str)
+			continue
+		}
+		sb.WriteString(strconv.FormatFloat(float64(fun(tk.val, bt[i].val)), 'g', -1, 32))
+	}
+	return sb.String(), true
+}
+
+// animColor parses a color value, returning false if it is not a color
+func animColor(s string) (gi.Color, bool) {
+	var c gi.Color
+	s = strings.TrimSpace(s)
+	low := strings.ToLower(s)
+	_, named := colornames.Map[low]
+	if s == "" || !(named || s[0] == '#' || strings.HasPrefix(low, "rgb") || strings.HasPrefix(low, "hsl")) {
+		return c, false
+	}
+	if err := c.SetString(s, nil); err != nil {
+		return c, false
+	}
+	return c, true
+}
+
+// InterpValues returns the value interpolated between a and b by proportion t
+// (0 = a, 1 = b) -- colors are blended, numbers are interpolated if the
+// values have the same structure, and otherwise the value switches from a to
+// b halfway through
+func InterpValues(a, b string, t float32) string {
+	if ca, ok := animColor(a); ok {
+		if cb, ok := animColor(b); ok {
+			c := ca.Blend(t*100, cb)
+			return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+		}
+	}
+	if v, ok := CombineValues(a, b, func(x, y float32) float32 { return x + (y-x)*t }); ok {
+		return v
+	}
+	if t < 0.5 {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,340 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package svg
+
+import (
+	"sync"
+	"time"
+
+	"github.com/goki/gi"
+	"github.com/goki/ki"
+	"github.com/goki/ki/kit"
+)
+
+// Animator drives the timeline for all of the animations within an SVG:
+// both the SMIL animation elements (see Animate) and the Go-side attribute
+// bindings (see AttrBinding).  Each frame is applied within an update of the
+// SVG, so it is re-rendered through the usual window update and publish
+// cycle, which only re-renders the SVG viewport.
+type Animator struct {
+	SVG      *SVG           `desc:"the svg that we animate"`
+	FPS      float32        `desc:"frames per second to update at while running"`
+	Time     float32        `desc:"current time on the timeline, in seconds"`
+	Running  bool           `desc:"is the timeline running?"`
+	Anims    []*Animate     `desc:"the SMIL animation elements within the svg, collected when starting"`
+	Bindings []*AttrBinding `desc:"Go-side attribute bindings"`
+	start    time.Time
+	ticker   *time.Ticker
+	mu       sync.Mutex
+}
+
+// AnimatorFPS is the default frames per second for Animator updates
+var AnimatorFPS = float32(30)
+
+// Animator returns the Animator for this svg, creating it if needed
+func (svg *SVG) Animator() *Animator {
+	if svg.Anim == nil {
+		svg.Anim = &Animator{SVG: svg, FPS: AnimatorFPS}
+	}
+	return svg.Anim
+}
+
+// HasAnimations returns true if the svg has any SMIL animation elements
+func (svg *SVG) HasAnimations() bool {
+	has := false
+	svg.FuncDownMeFirst(0, svg.This, func(k ki.Ki, level int, d interface{}) bool {
+		if _, ok := k.(*Animate); ok {
+			has = true
+		}
+		return !has
+	})
+	return has
+}
+
+// CollectAnims collects all the SMIL animation elements in the svg, and
+// records the base values of the attributes they animate
+func (an *Animator) CollectAnims() {
+	an.Anims = nil
+	an.SVG.FuncDownMeFirst(0, an.SVG.This, func(k ki.Ki, level int, d interface{}) bool {
+		if am, ok := k.(*Animate); ok {
+			an.Anims = append(an.Anims, am)
+		}
+		return true
+	})
+	for _, am := range an.Anims {
+		am.Init()
+	}
+}
+
+// CurTime returns the current time on the timeline, in seconds
+func (an *Animator) CurTime() float32 {
+	if an.Running {
+		return float32(time.Since(an.start).Seconds())
+	}
+	return an.Time
+}
+
+// Start starts the timeline running from the current time (or resumes it
+// after Stop) -- the SMIL animations are collected the first time
+func (an *Animator) Start() {
+	an.mu.Lock()
+	defer an.mu.Unlock()
+	if an.Running {
+		return
+	}
+	if an.Anims == nil {
+		an.CollectAnims()
+	}
+	an.Running = true
+	an.start = time.Now().Add(-time.Duration(float64(an.Time) * float64(time.Second)))
+	fps := an.FPS
+	if fps <= 0 {
+		fps = AnimatorFPS
+	}
+	an.ticker = time.NewTicker(time.Duration(float32(time.Second) / fps))
+	go an.run(an.ticker)
+}
+
+// Stop stops (pauses) the timeline at the current time
+func (an *Animator) Stop() {
+	an.mu.Lock()
+	defer an.mu.Unlock()
+	an.stop()
+}
+
+// stop stops the timeline -- must be called under mutex
+func (an *Animator) stop() {
+	if !an.Running {
+		return
+	}
+	an.Time = an.CurTime()
+	an.Running = false
+	an.ticker.Stop()
+	an.ticker = nil
+}
+
+// Seek sets the current time on the timeline, in seconds, and renders the
+// animations at that time
+func (an *Animator) Seek(t float32) {
+	an.mu.Lock()
+	if an.Anims == nil {
+		an.CollectAnims()
+	}
+	an.Time = t
+	if an.Running {
+		an.start = time.Now().Add(-time.Duration(float64(t) * float64(time.Second)))
+	}
+	an.mu.Unlock()
+	an.Frame()
+}
+
+// Reset stops the timeline, restores the base values of all animated
+// attributes, and sets the time back to 0 -- the SMIL animations are
+// collected again on the next Start, e.g., after the svg has changed
+func (an *Animator) Reset() {
+	an.mu.Lock()
+	an.stop()
+	an.Time = 0
+	svg := an.SVG
+	updt := svg.UpdateStart()
+	for _, am := range an.Anims {
+		if am.Restore() {
+			if tg := am.Target(); tg != nil {
+				tg.AsNode2D().Style2DTree()
+			}
+		}
+	}
+	an.Anims = nil
+	for _, ab := range an.Bindings {
+		ab.Active = false
+	}
+	an.mu.Unlock()
+	svg.UpdateEnd(updt)
+}
+
+// Frame applies all the animations at the current time, within an update
+// of the svg, which re-renders it -- returns true if there will be further
+// changes after this time
+func (an *Animator) Frame() bool {
+	svg := an.SVG
+	updt := svg.UpdateStart()
+	an.mu.Lock()
+	t := an.CurTime()
+	an.Time = t
+	more := false
+	var restyle []gi.Node2D
+	for _, am := range an.Anims {
+		rs, mr := am.Apply(t)
+		more = more || mr
+		if rs {
+			restyle = append(restyle, am.Target())
+		}
+	}
+	for _, ab := range an.Bindings {
+		rs, mr := ab.Apply(t)
+		more = more || mr
+		if rs {
+			restyle = append(restyle, ab.Node)
+		}
+	}
+	an.mu.Unlock()
+	for _, nd := range restyle {
+		if nd != nil {
+			nd.AsNode2D().Style2DTree()
+		}
+	}
+	svg.UpdateEnd(updt)
+	return more
+}
+
+// run is the goroutine that updates the frames of a running timeline
+func (an *Animator) run(ticker *time.Ticker) {
+	for range ticker.C {
+		svg := an.SVG
+		if svg.IsDestroyed() || svg.IsDeleted() {
+			an.Stop()
+			return
+		}
+		an.mu.Lock()
+		if an.ticker != ticker { // stopped
+			an.mu.Unlock()
+			return
+		}
+		win := svg.ParentWindow()
+		if win == nil || svg.Viewport == nil { // not yet visible: time stands still
+			an.start = time.Now().Add(-time.Duration(float64(an.Time) * float64(time.Second)))
+			an.mu.Unlock()
+			continue
+		}
+		an.mu.Unlock()
+		if win.IsClosed() {
+			an.Stop()
+			return
+		}
+		if win.IsResizing() || win.IsUpdating() {
+			continue
+		}
+		if !an.Frame() && an.stopIfIdle() {
+			return
+		}
+	}
+}
+
+// stopIfIdle stops the timeline if no bindings have started a new
+// transition, returning true if stopped
+func (an *Animator) stopIfIdle() bool {
+	an.mu.Lock()
+	defer an.mu.Unlock()
+	for _, ab := range an.Bindings {
+		if ab.Active {
+			return false
+		}
+	}
+	an.stop()
+	return true
+}
+
+/////////////////////////////////////////////////////////////////////////////
+//  AttrBinding
+
+// AttrBinding binds an attribute of an svg element to changing data from
+// Go: each time a new value is Set, the attribute smoothly transitions from
+// its current value to the new one over the Dur duration, driven by the
+// Animator of the SVG -- values are interpolated as in Animate (numbers,
+// including path data with the same structure, and colors)
+type AttrBinding struct {
+	Node   gi.Node2D               `desc:"the element whose attribute is bound"`
+	Attr   string                  `desc:"name of the attribute, as in the svg file (e.g., width, d, fill)"`
+	Dur    float32                 `desc:"duration of each transition, in seconds -- 0 = change immediately"`
+	Ease   func(t float32) float32 `view:"-" json:"-" desc:"easing function mapping time proportion to value proportion -- EaseInOut if nil"`
+	From   string                  `desc:"value at the start of the current transition"`
+	To     string                  `desc:"value at the end of the current transition"`
+	Start  float32                 `desc:"time on the timeline when the current transition started"`
+	Active bool                    `desc:"a transition is in progress"`
+	Anim   *Animator               `view:"-" json:"-" desc:"the animator that drives this binding"`
+}
+
+var KiT_AttrBinding = kit.Types.AddType(&AttrBinding{}, nil)
+
+// EaseInOut is the default easing function for AttrBinding transitions,
+// which starts and ends smoothly
+func EaseInOut(t float32) float32 {
+	return t * t * (3 - 2*t)
+}
+
+// BindAttr binds the given attribute of given element (which must be within
+// this svg) to Go data, with transitions of given duration -- use Set on the
+// returned binding to change the value
+func (svg *SVG) BindAttr(node gi.Node2D, attr string, dur time.Duration) *AttrBinding {
+	an := svg.Animator()
+	ab := &AttrBinding{Node: node, Attr: attr, Dur: float32(dur.Seconds()), Anim: an}
+	an.mu.Lock()
+	an.Bindings = append(an.Bindings, ab)
+	an.mu.Unlock()
+	return ab
+}
+
+// Unbind removes the binding from its Animator
+func (ab *AttrBinding) Unbind() {
+	an := ab.Anim
+	an.mu.Lock()
+	defer an.mu.Unlock()
+	for i, b := range an.Bindings {
+		if b == ab {
+			an.Bindings = append(an.Bindings[:i], an.Bindings[i+1:]...)
+			return
+		}
+	}
+}
+
+// Set starts a transition of the attribute from its current value to the
+// given value (converted to a string), starting the Animator if needed
+func (ab *AttrBinding) Set(val interface{}) {
+	an := ab.Anim
+	to := kit.ToString(val)
+	an.mu.Lock()
+	ab.From = NodeAttr(ab.Node, ab.Attr)
+	ab.To = to
+	ab.Start = an.CurTime()
+	ab.Active = true
+	an.mu.Unlock()
+	if ab.Dur <= 0 || an.SVG.ParentWindow() == nil {
+		an.Frame()
+		return
+	}
+	an.Start()
+}
+
+// ValueAt returns the value of the attribute at given time on the timeline
+func (ab *AttrBinding) ValueAt(t float32) string {
+	if ab.Dur <= 0 || t >= ab.Start+ab.Dur {
+		return ab.To
+	}
+	p := (t - ab.Start) / ab.Dur
+	if p < 0 {
+		p = 0
+	}
+	if ab.Ease != nil {
+		p = ab.Ease(p)
+	} else {
+		p = EaseInOut(p)
+	}
+	return InterpValues(ab.From, ab.To, p)
+}
+
+// Apply applies the binding at given time on the timeline, returning
+// restyle = true if the element must be re-styled, and more = true if the
+// transition is still in progress
+func (ab *AttrBinding) Apply(t float32) (restyle, more bool) {
+	if !ab.Active {
+		return false, false
+	}
+	val := ab.ValueAt(t)
+	more = ab.Dur > 0 && t < ab.Start+ab.Dur
+	if !more {
+		ab.Active = false
+	}
+	return SetNodeAttr(ab.Node, ab.Attr, val), more
+}
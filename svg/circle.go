@@ -23,7 +23,7 @@ func (g *Circle) Render2D() {
 	rs := &g.Viewport.Render
 	rs.PushXForm(pc.XForm)
 	pc.DrawCircle(rs, g.Pos.X, g.Pos.Y, g.Radius)
-	pc.FillStrokeClear(rs)
+	g.FillStrokeClear(rs)
 	g.ComputeBBoxSVG()
 	g.Render2DChildren()
 	rs.PopXForm()
@@ -75,7 +75,7 @@ func (svg *Editor) EditorEvents() {
 			oswin.TheApp.Cursor(ssvg.Viewport.Win.OSWin).Pop()
 			ssvg.SetDragCursor = false
 		}
-		obj := ssvg.FirstContainingPoint(me.Where, true)
+		obj := ssvg.ElementAt(me.Where)
 		if me.Action == mouse.Release && me.Button == mouse.Right {
 			me.SetProcessed()
 			if obj != nil {
@@ -87,7 +87,7 @@ func (svg *Editor) EditorEvents() {
 		me := d.(*mouse.HoverEvent)
 		me.SetProcessed()
 		ssvg := recv.Embed(KiT_Editor).(*Editor)
-		obj := ssvg.FirstContainingPoint(me.Where, true)
+		obj := ssvg.ElementAt(me.Where)
 		if obj != nil {
 			pos := me.Where
 			ttxt := fmt.Sprintf("element name: %v -- use right mouse click to edit", obj.Name())
@@ -23,7 +23,7 @@ func (g *Ellipse) Render2D() {
 	rs := &g.Viewport.Render
 	rs.PushXForm(pc.XForm)
 	pc.DrawEllipse(rs, g.Pos.X, g.Pos.Y, g.Radii.X, g.Radii.Y)
-	pc.FillStrokeClear(rs)
+	g.FillStrokeClear(rs)
 	g.ComputeBBoxSVG()
 	g.Render2DChildren()
 	rs.PopXForm()
@@ -0,0 +1,109 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package svg
+
+import (
+	"image"
+
+	"github.com/goki/gi"
+	"github.com/goki/ki"
+)
+
+// FillStrokeClear fills and strokes the current path as in
+// gi.Paint.FillStrokeClear, first recording its geometry in Hit, for hit
+// testing -- shapes should use this instead of the Paint version
+func (g *NodeBase) FillStrokeClear(rs *gi.RenderState) {
+	g.Hit = g.Pnt.HitShape(rs)
+	g.Pnt.FillStrokeClear(rs)
+}
+
+// ContainsPoint returns true if the given window position is within the
+// shape of this element itself as last rendered (not including its
+// children), honoring the pointer-events property -- text elements use the
+// bounding box of their glyphs
+func (g *NodeBase) ContainsPoint(winPos image.Point) bool {
+	if g.Viewport == nil || g.Pnt.PointerEvs == gi.PointerNone || !winPos.In(g.WinBBox) {
+		return false
+	}
+	if _, ok := g.This.(*Text); ok {
+		return true
+	}
+	return g.Hit.Contains(winPos.Sub(g.Viewport.WinBBox.Min))
+}
+
+// HitTest satisfies the gi.HitTester interface, so mouse events are only
+// sent to this element when they are within its actual shape (or that of
+// one of its children), and it is not covered by another element drawn on
+// top of it at that point -- see SVG.ElementAt
+func (g *NodeBase) HitTest(winPos image.Point) bool {
+	sv := g.ParentSVG()
+	if sv == nil {
+		return g.ContainsPoint(winPos)
+	}
+	top := sv.ElementAt(winPos)
+	if top == nil {
+		return false
+	}
+	for k := ki.Ki(top); k != nil; k = k.Parent() {
+		if k == g.This {
+			return true
+		}
+	}
+	return false
+}
+
+// ElementAt returns the topmost element of the svg whose rendered shape
+// contains the given window position, or nil if none.  Elements are tested
+// against their actual filled or stroked geometry, with all transforms,
+// honoring the pointer-events property.  The result is cached until the
+// next render, as it is used for each element that receives mouse events.
+func (svg *SVG) ElementAt(winPos image.Point) gi.Node2D {
+	if svg.hitValid && svg.hitPos == winPos {
+		return svg.hitNode
+	}
+	var top gi.Node2D
+	if winPos.In(svg.WinBBox) {
+		top = elementAt(svg.Kids, winPos)
+	}
+	svg.hitPos = winPos
+	svg.hitNode = top
+	svg.hitValid = true
+	return top
+}
+
+// elementAt returns the topmost element among given children (in reverse
+// order of rendering) and their children that contains given window position
+func elementAt(kids ki.Slice, winPos image.Point) gi.Node2D {
+	for i := len(kids) - 1; i >= 0; i-- {
+		k := kids[i]
+		switch k.(type) {
+		case *Marker, *Pattern, *ClipPath, *Filter, *Animate: // not rendered in place
+			continue
+		}
+		nii, ok := k.(gi.Node2D)
+		if !ok {
+			continue
+		}
+		sn, ok := k.(interface {
+			AsSVGNode() *NodeBase
+		})
+		if !ok {
+			continue
+		}
+		g := sn.AsSVGNode()
+		if !winPos.In(g.WinBBox) {
+			continue
+		}
+		if len(g.Kids) > 0 { // children are rendered on top
+			if hit := elementAt(g.Kids, winPos); hit != nil {
+				return hit
+			}
+		}
+		if g.ContainsPoint(winPos) {
+			return nii
+		}
+	}
+	return nil
+}
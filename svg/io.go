@@ -18,6 +18,7 @@ import (
 	"log"
 	"os"
 	"strings"
+	"unicode"
 
 	"github.com/goki/gi"
 	"github.com/goki/gi/units"
@@ -101,11 +102,10 @@ func (svg *SVG) UnmarshalXML(decoder *xml.Decoder, se xml.StartElement) error {
 	inDef := false
 	inCSS := false
 	var curCSS *gi.StyleSheet
-	inTxt := false
-	var curTxt *Text
-	inTspn := false
-	var curTspn *Text
+	var txtStack []*Text     // stack of nested text, tspan, textPath elements
+	var lastTxt *Text        // last text element within current text that received chardata
 	var defPrevPar gi.Node2D // previous parent before a def encountered
+	var curLeaf gi.Node2D    // current shape element that does not become curPar, e.g., for animate within rect
 
 	for {
 		var t xml.Token
@@ -182,6 +182,7 @@ func (svg *SVG) UnmarshalXML(decoder *xml.Decoder, se xml.StartElement) error {
 				}
 			case nm == "rect":
 				rect := curPar.AddNewChild(KiT_Rect, "rect").(*Rect)
+				curLeaf = rect
 				var x, y, w, h, rx, ry float32
 				for _, attr := range se.Attr {
 					if rect.SetStdXMLAttr(attr.Name.Local, attr.Value) {
@@ -212,6 +213,7 @@ func (svg *SVG) UnmarshalXML(decoder *xml.Decoder, se xml.StartElement) error {
 				rect.Radius.Set(rx, ry)
 			case nm == "circle":
 				circle := curPar.AddNewChild(KiT_Circle, "circle").(*Circle)
+				curLeaf = circle
 				var cx, cy, r float32
 				for _, attr := range se.Attr {
 					if circle.SetStdXMLAttr(attr.Name.Local, attr.Value) {
@@ -235,6 +237,7 @@ func (svg *SVG) UnmarshalXML(decoder *xml.Decoder, se xml.StartElement) error {
 				circle.Radius = r
 			case nm == "ellipse":
 				ellipse := curPar.AddNewChild(KiT_Ellipse, "ellipse").(*Ellipse)
+				curLeaf = ellipse
 				var cx, cy, rx, ry float32
 				for _, attr := range se.Attr {
 					if ellipse.SetStdXMLAttr(attr.Name.Local, attr.Value) {
@@ -260,6 +263,7 @@ func (svg *SVG) UnmarshalXML(decoder *xml.Decoder, se xml.StartElement) error {
 				ellipse.Radii.Set(rx, ry)
 			case nm == "line":
 				line := curPar.AddNewChild(KiT_Line, "line").(*Line)
+				curLeaf = line
 				var x1, x2, y1, y2 float32
 				for _, attr := range se.Attr {
 					if line.SetStdXMLAttr(attr.Name.Local, attr.Value) {
@@ -285,6 +289,7 @@ func (svg *SVG) UnmarshalXML(decoder *xml.Decoder, se xml.StartElement) error {
 				line.End.Set(x2, y2)
 			case nm == "polygon":
 				polygon := curPar.AddNewChild(KiT_Polygon, "polygon").(*Polygon)
+				curLeaf = polygon
 				for _, attr := range se.Attr {
 					if polygon.SetStdXMLAttr(attr.Name.Local, attr.Value) {
 						continue
@@ -314,6 +319,7 @@ func (svg *SVG) UnmarshalXML(decoder *xml.Decoder, se xml.StartElement) error {
 				}
 			case nm == "polyline":
 				polyline := curPar.AddNewChild(KiT_Polyline, "polyline").(*Polyline)
+				curLeaf = polyline
 				for _, attr := range se.Attr {
 					if polyline.SetStdXMLAttr(attr.Name.Local, attr.Value) {
 						continue
@@ -343,6 +349,7 @@ func (svg *SVG) UnmarshalXML(decoder *xml.Decoder, se xml.StartElement) error {
 				}
 			case nm == "path":
 				path := curPar.AddNewChild(KiT_Path, "path").(*Path)
+				curLeaf = path
 				for _, attr := range se.Attr {
 					if path.SetStdXMLAttr(attr.Name.Local, attr.Value) {
 						continue
@@ -357,51 +364,59 @@ func (svg *SVG) UnmarshalXML(decoder *xml.Decoder, se xml.StartElement) error {
 						return err
 					}
 				}
-			case nm == "tspan":
+			case nm == "tspan" || nm == "textPath":
 				fallthrough
 			case nm == "text":
 				var txt *Text
-				if se.Name.Local == "text" {
+				switch {
+				case len(txtStack) > 0:
+					txt = txtStack[len(txtStack)-1].AddNewChild(KiT_Text, nm).(*Text)
+				case nm == "text":
 					txt = curPar.AddNewChild(KiT_Text, "txt").(*Text)
-					inTxt = true
-					curTxt = txt
-				} else {
-					if inTxt && curTxt != nil {
-						txt = curTxt.AddNewChild(KiT_Text, "tspan").(*Text)
-						txt.Pos = curTxt.Pos
-					} else {
-						txt = curPar.AddNewChild(KiT_Text, "tspan").(*Text)
-					}
-					inTspn = true
-					curTspn = txt
+					lastTxt = nil
+				default:
+					txt = curPar.AddNewChild(KiT_Text, nm).(*Text)
+					lastTxt = nil
 				}
+				root := len(txtStack) == 0
+				txtStack = append(txtStack, txt)
 				for _, attr := range se.Attr {
 					if txt.SetStdXMLAttr(attr.Name.Local, attr.Value) {
 						continue
 					}
 					switch attr.Name.Local {
 					case "x":
-						pts := gi.ReadPoints(attr.Value)
-						if len(pts) > 1 {
-							txt.CharPosX = pts
-						} else if len(pts) == 1 {
+						pts, ok := readTextPoints(attr.Value)
+						switch {
+						case !ok:
+							txt.SetProp(attr.Name.Local, attr.Value)
+						case root && len(pts) == 1:
 							txt.Pos.X = pts[0]
+						default:
+							txt.CharPosX = pts
 						}
 					case "y":
-						pts := gi.ReadPoints(attr.Value)
-						if len(pts) > 1 {
-							txt.CharPosY = pts
-						} else if len(pts) == 1 {
+						pts, ok := readTextPoints(attr.Value)
+						switch {
+						case !ok:
+							txt.SetProp(attr.Name.Local, attr.Value)
+						case root && len(pts) == 1:
 							txt.Pos.Y = pts[0]
+						default:
+							txt.CharPosY = pts
 						}
 					case "dx":
-						pts := gi.ReadPoints(attr.Value)
-						if len(pts) > 0 {
+						pts, ok := readTextPoints(attr.Value)
+						if !ok {
+							txt.SetProp(attr.Name.Local, attr.Value)
+						} else {
 							txt.CharPosDX = pts
 						}
 					case "dy":
-						pts := gi.ReadPoints(attr.Value)
-						if len(pts) > 0 {
+						pts, ok := readTextPoints(attr.Value)
+						if !ok {
+							txt.SetProp(attr.Name.Local, attr.Value)
+						} else {
 							txt.CharPosDY = pts
 						}
 					case "rotate":
@@ -411,7 +426,7 @@ func (svg *SVG) UnmarshalXML(decoder *xml.Decoder, se xml.StartElement) error {
 						}
 					case "textLength":
 						tl, err := gi.ParseFloat32(attr.Value)
-						if err != nil {
+						if err == nil {
 							txt.TextLength = tl
 						}
 					case "lengthAdjust":
@@ -420,6 +435,25 @@ func (svg *SVG) UnmarshalXML(decoder *xml.Decoder, se xml.StartElement) error {
 						} else {
 							txt.AdjustGlyphs = false
 						}
+					case "href":
+						if nm == "textPath" {
+							txt.TextPathRef = attr.Value
+						} else {
+							txt.SetProp(attr.Name.Local, attr.Value)
+						}
+					case "startOffset":
+						so := strings.TrimSpace(attr.Value)
+						if strings.HasSuffix(so, "%") {
+							txt.StartOffsetPct = true
+							so = strings.TrimSuffix(so, "%")
+						}
+						sov, err := gi.ParseFloat32(so)
+						if err == nil {
+							if txt.StartOffsetPct {
+								sov *= 0.01
+							}
+							txt.StartOffset = sov
+						}
 					default:
 						txt.SetProp(attr.Name.Local, attr.Value)
 					}
@@ -429,50 +463,14 @@ func (svg *SVG) UnmarshalXML(decoder *xml.Decoder, se xml.StartElement) error {
 				}
 			case nm == "linearGradient":
 				grad := curPar.AddNewChild(gi.KiT_Gradient, "lin-grad").(*gi.Gradient)
-				for _, attr := range se.Attr {
-					if grad.SetStdXMLAttr(attr.Name.Local, attr.Value) {
-						continue
-					}
-					switch attr.Name.Local {
-					case "href":
-						nm := attr.Value
-						if strings.HasPrefix(nm, "#") {
-							nm = strings.TrimPrefix(nm, "#")
-						}
-						hr, ok := curPar.ChildByName(nm, 0)
-						if ok {
-							if hrg, ok := hr.(*gi.Gradient); ok {
-								grad.Grad.CopyFrom(&hrg.Grad)
-								// fmt.Printf("successful href: %v\n", nm)
-							}
-						}
-					}
-				}
+				readGradRef(grad, se.Attr)
 				err = grad.Grad.UnmarshalXML(decoder, se)
 				if err != nil {
 					return err
 				}
 			case nm == "radialGradient":
 				grad := curPar.AddNewChild(gi.KiT_Gradient, "rad-grad").(*gi.Gradient)
-				for _, attr := range se.Attr {
-					if grad.SetStdXMLAttr(attr.Name.Local, attr.Value) {
-						continue
-					}
-					switch attr.Name.Local {
-					case "href":
-						nm := attr.Value
-						if strings.HasPrefix(nm, "#") {
-							nm = strings.TrimPrefix(nm, "#")
-						}
-						hr, ok := curPar.ChildByName(nm, 0)
-						if ok {
-							if hrg, ok := hr.(*gi.Gradient); ok {
-								grad.Grad.CopyFrom(&hrg.Grad)
-								// fmt.Printf("successful href: %v\n", nm)
-							}
-						}
-					}
-				}
+				readGradRef(grad, se.Attr)
 				err = grad.Grad.UnmarshalXML(decoder, se)
 				if err != nil {
 					return err
@@ -499,6 +497,68 @@ func (svg *SVG) UnmarshalXML(decoder *xml.Decoder, se xml.StartElement) error {
 						cp.SetProp(attr.Name.Local, attr.Value)
 					}
 				}
+			case nm == "animate" || nm == "set" || nm == "animateTransform":
+				tpar := curPar
+				switch {
+				case curLeaf != nil:
+					tpar = curLeaf
+				case len(txtStack) > 0:
+					tpar = txtStack[len(txtStack)-1]
+				}
+				am := tpar.AddNewChild(KiT_Animate, nm).(*Animate)
+				switch nm {
+				case "set":
+					am.Kind = AnimSet
+				case "animateTransform":
+					am.Kind = AnimTransform
+				}
+				for _, attr := range se.Attr {
+					if am.SetStdXMLAttr(attr.Name.Local, attr.Value) {
+						continue
+					}
+					err = am.SetXMLAttr(attr.Name.Local, attr.Value)
+					if err != nil {
+						return err
+					}
+				}
+			case nm == "pattern":
+				curPar = curPar.AddNewChild(KiT_Pattern, "pattern").(gi.Node2D)
+				pt := curPar.(*Pattern)
+				pt.XForm = gi.Identity2D()
+				pt.ContentUnits = PatternUserSpaceOnUse
+				for _, attr := range se.Attr {
+					if pt.SetStdXMLAttr(attr.Name.Local, attr.Value) {
+						continue
+					}
+					switch attr.Name.Local {
+					case "x":
+						pt.Pos.X, err = readPatternLength(attr.Value)
+					case "y":
+						pt.Pos.Y, err = readPatternLength(attr.Value)
+					case "width":
+						pt.Size.X, err = readPatternLength(attr.Value)
+					case "height":
+						pt.Size.Y, err = readPatternLength(attr.Value)
+					case "patternUnits":
+						pt.PatternUnits = readPatternUnits(attr.Value)
+					case "patternContentUnits":
+						pt.ContentUnits = readPatternUnits(attr.Value)
+					case "patternTransform":
+						err = pt.XForm.SetString(attr.Value)
+					case "viewBox":
+						pts := gi.ReadPoints(attr.Value)
+						if len(pts) != 4 {
+							return paramMismatchError
+						}
+						pt.ViewBox.Min.Set(pts[0], pts[1])
+						pt.ViewBox.Size.Set(pts[2], pts[3])
+					default:
+						pt.SetProp(attr.Name.Local, attr.Value)
+					}
+					if err != nil {
+						return err
+					}
+				}
 			case nm == "marker":
 				curPar = curPar.AddNewChild(KiT_Marker, "marker").(gi.Node2D)
 				mrk := curPar.(*Marker)
@@ -551,6 +611,7 @@ func (svg *SVG) UnmarshalXML(decoder *xml.Decoder, se xml.StartElement) error {
 					cln := itm.Clone().(gi.Node2D)
 					if cln != nil {
 						curPar.AddChild(cln)
+						curLeaf = cln
 						for _, attr := range se.Attr {
 							if cln.AsNode2D().SetStdXMLAttr(attr.Name.Local, attr.Value) {
 								continue
@@ -637,25 +698,22 @@ func (svg *SVG) UnmarshalXML(decoder *xml.Decoder, se xml.StartElement) error {
 			case "style":
 				inCSS = false
 				curCSS = nil
-			case "text":
-				inTxt = false
-				curTxt = nil
-			case "tspan":
-				inTspn = false
-				curTspn = nil
+			case "text", "tspan", "textPath":
+				if len(txtStack) > 0 {
+					txtStack = txtStack[:len(txtStack)-1]
+				}
+				if len(txtStack) == 0 && lastTxt != nil {
+					lastTxt.Text = strings.TrimRightFunc(lastTxt.Text, unicode.IsSpace)
+					lastTxt = nil
+				}
 			case "defs":
 				if inDef {
 					inDef = false
 					curPar = defPrevPar
 				}
-			case "rect":
-			case "circle":
-			case "ellipse":
-			case "line":
-			case "polygon":
-			case "polyline":
-			case "path":
-			case "use":
+			case "rect", "circle", "ellipse", "line", "polygon", "polyline", "path", "use":
+				curLeaf = nil
+			case "animate", "set", "animateTransform":
 			case "linearGradient":
 			case "radialGradient":
 			default:
@@ -684,10 +742,18 @@ func (svg *SVG) UnmarshalXML(decoder *xml.Decoder, se xml.StartElement) error {
 				curSvg.Title += trspc
 			case inDesc:
 				curSvg.Desc += trspc
-			case inTspn && curTspn != nil:
-				curTspn.Text = trspc
-			case inTxt && curTxt != nil:
-				curTxt.Text = trspc
+			case len(txtStack) > 0:
+				str := collapseTextSpace(string(se), lastTxt == nil)
+				if str == "" {
+					break
+				}
+				txt := txtStack[len(txtStack)-1]
+				if txt.HasChildren() {
+					// mixed content following a child element: anonymous tspan
+					txt = txt.AddNewChild(KiT_Text, "tspan").(*Text)
+				}
+				txt.Text += str
+				lastTxt = txt
 			case inCSS && curCSS != nil:
 				curCSS.ParseString(trspc)
 				cp := curCSS.CSSProps()
@@ -701,5 +767,81 @@ func (svg *SVG) UnmarshalXML(decoder *xml.Decoder, se xml.StartElement) error {
 			}
 		}
 	}
+	if svg.HasAnimations() { // smil animations run as soon as the svg is visible
+		svg.Animator().Start()
+	}
 	return nil
 }
+
+// readPatternLength reads a pattern position or size value, which can be a
+// percentage, converted to a proportion
+func readPatternLength(str string) (float32, error) {
+	str = strings.TrimSpace(str)
+	if strings.HasSuffix(str, "%") {
+		v, err := gi.ParseFloat32(strings.TrimSuffix(str, "%"))
+		return v * 0.01, err
+	}
+	return gi.ParseFloat32(str)
+}
+
+// readPatternUnits reads the patternUnits or patternContentUnits value
+func readPatternUnits(str string) PatternUnits {
+	if strings.TrimSpace(str) == "userSpaceOnUse" {
+		return PatternUserSpaceOnUse
+	}
+	return PatternObjectBoundingBox
+}
+
+// readTextPoints reads a list of text position values, returning false if
+// any of them cannot be parsed as plain numbers (e.g., they have units, as
+// in dy="1.2em") -- those must then be evaluated in the unit context at
+// render time
+func readTextPoints(str string) ([]float32, bool) {
+	flds := strings.FieldsFunc(str, func(r rune) bool { return unicode.IsSpace(r) || r == ',' })
+	pts := make([]float32, 0, len(flds))
+	for _, f := range flds {
+		v, err := gi.ParseFloat32(f)
+		if err != nil {
+			return nil, false
+		}
+		pts = append(pts, v)
+	}
+	return pts, true
+}
+
+// collapseTextSpace implements the default svg xml:space handling of text
+// chardata: newlines and tabs become spaces and runs of space are collapsed
+// to a single space -- leading space is removed at the start of a text
+// element, and whitespace-only content between elements is ignored
+func collapseTextSpace(str string, atStart bool) string {
+	flds := strings.Fields(str)
+	if len(flds) == 0 {
+		return ""
+	}
+	cs := strings.Join(flds, " ")
+	if !atStart && unicode.IsSpace(rune(str[0])) {
+		cs = " " + cs
+	}
+	if unicode.IsSpace(rune(str[len(str)-1])) {
+		cs += " "
+	}
+	return cs
+}
+
+// readGradRef sets the Ref gradient from any href attribute, along with the
+// names of the other attributes set directly on the gradient, which are not
+// inherited -- the ref is only resolved when the gradient is used, so it can
+// refer to a gradient that is defined later
+func readGradRef(grad *gi.Gradient, attrs []xml.Attr) {
+	for _, attr := range attrs {
+		if grad.SetStdXMLAttr(attr.Name.Local, attr.Value) {
+			continue
+		}
+		switch attr.Name.Local {
+		case "href":
+			grad.Ref = strings.TrimPrefix(strings.TrimSpace(attr.Value), "#")
+		default:
+			grad.RefAttrs = append(grad.RefAttrs, attr.Name.Local)
+		}
+	}
+}
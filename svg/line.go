@@ -24,6 +24,7 @@ func (g *Line) Render2D() {
 	rs := &g.Viewport.Render
 	rs.PushXForm(pc.XForm)
 	pc.DrawLine(rs, g.Start.X, g.Start.Y, g.End.X, g.End.Y)
+	g.Hit = pc.HitShape(rs)
 	pc.Stroke(rs)
 	g.ComputeBBoxSVG()
 
@@ -19,7 +19,8 @@ import (
 // layout logic -- just renders into parent SVG viewport
 type NodeBase struct {
 	gi.Node2DBase
-	Pnt gi.Paint `json:"-" xml:"-" desc:"full paint information for this node"`
+	Pnt gi.Paint    `json:"-" xml:"-" desc:"full paint information for this node"`
+	Hit gi.HitShape `json:"-" xml:"-" view:"-" desc:"geometry of the shape as last rendered, for hit testing -- see HitTest"`
 }
 
 var KiT_NodeBase = kit.Types.AddType(&NodeBase{}, NodeBaseProps)
@@ -14,6 +14,7 @@ import (
 	"github.com/chewxy/math32"
 	"github.com/goki/gi"
 	"github.com/goki/ki/kit"
+	"golang.org/x/image/math/fixed"
 )
 
 // Path renders SVG data sequences that can render just about anything
@@ -49,7 +50,7 @@ func (g *Path) Render2D() {
 	rs := &g.Viewport.Render
 	rs.PushXForm(pc.XForm)
 	PathDataRender(g.Data, pc, rs)
-	pc.FillStrokeClear(rs)
+	g.FillStrokeClear(rs)
 	g.ComputeBBoxSVG()
 
 	if mrk := g.Marker("marker-start"); mrk != nil {
@@ -778,3 +779,121 @@ func PathDataParse(d string) ([]PathData, error) {
 	return pd, nil
 	// todo: add some error checking..
 }
+
+//////////////////////////////////////////////////////////////////////////
+//  PathPolyline
+
+// PathPolyline is a flattened version of path data, with curves and arcs
+// approximated by straight line segments, and the cumulative distance along
+// the path at each point -- used for laying out text along a path
+type PathPolyline struct {
+	Pts  []gi.Vec2D `desc:"points along the path"`
+	Dist []float32  `desc:"cumulative distance along the path at each point"`
+}
+
+// PathDataPolyline flattens the given path data into a PathPolyline, with
+// all coordinates transformed by given transform
+func PathDataPolyline(data []PathData, xf gi.Matrix2D) *PathPolyline {
+	pc := gi.NewPaint()
+	rs := &gi.RenderState{}
+	rs.XForm = xf
+	PathDataRender(data, &pc, rs)
+	pl := &PathPolyline{}
+	rs.Path.AddTo(&pathFlattener{pl: pl})
+	return pl
+}
+
+// Length returns the total length of the path
+func (pl *PathPolyline) Length() float32 {
+	if len(pl.Dist) == 0 {
+		return 0
+	}
+	return pl.Dist[len(pl.Dist)-1]
+}
+
+// PointAt returns the point at given distance along the path, and the angle
+// of the path tangent at that point, in radians -- returns false if the
+// distance is off the path
+func (pl *PathPolyline) PointAt(dist float32) (pt gi.Vec2D, ang float32, ok bool) {
+	sz := len(pl.Pts)
+	if sz < 2 || dist < 0 || dist > pl.Length() {
+		return
+	}
+	for i := 1; i < sz; i++ {
+		sd := pl.Dist[i] - pl.Dist[i-1]
+		if pl.Dist[i] < dist || sd == 0 {
+			continue
+		}
+		st, ed := pl.Pts[i-1], pl.Pts[i]
+		pt = st.Interpolate(ed, (dist-pl.Dist[i-1])/sd)
+		ang = math32.Atan2(ed.Y-st.Y, ed.X-st.X)
+		return pt, ang, true
+	}
+	return
+}
+
+// pathFlattener is a rasterx.Adder that accumulates a PathPolyline
+type pathFlattener struct {
+	pl    *PathPolyline
+	start gi.Vec2D
+}
+
+// pathFlattenSteps is the number of line segments used to approximate each
+// bezier curve segment
+const pathFlattenSteps = 16
+
+func (pf *pathFlattener) add(pt gi.Vec2D, jump bool) {
+	pl := pf.pl
+	sz := len(pl.Pts)
+	if sz == 0 {
+		pl.Pts = append(pl.Pts, pt)
+		pl.Dist = append(pl.Dist, 0)
+		return
+	}
+	d := pl.Dist[sz-1]
+	if !jump {
+		d += pt.Distance(pl.Pts[sz-1])
+	}
+	pl.Pts = append(pl.Pts, pt)
+	pl.Dist = append(pl.Dist, d)
+}
+
+func (pf *pathFlattener) last() gi.Vec2D {
+	return pf.pl.Pts[len(pf.pl.Pts)-1]
+}
+
+func (pf *pathFlattener) Start(a fixed.Point26_6) {
+	pf.start = gi.NewVec2DFmFixed(a)
+	pf.add(pf.start, true)
+}
+
+func (pf *pathFlattener) Line(b fixed.Point26_6) {
+	pf.add(gi.NewVec2DFmFixed(b), false)
+}
+
+func (pf *pathFlattener) QuadBezier(b, c fixed.Point26_6) {
+	p0 := pf.last()
+	p1, p2 := gi.NewVec2DFmFixed(b), gi.NewVec2DFmFixed(c)
+	for i := 1; i <= pathFlattenSteps; i++ {
+		t := float32(i) / pathFlattenSteps
+		mt := 1 - t
+		pf.add(p0.MulVal(mt*mt).Add(p1.MulVal(2*mt*t)).Add(p2.MulVal(t*t)), false)
+	}
+}
+
+func (pf *pathFlattener) CubeBezier(b, c, d fixed.Point26_6) {
+	p0 := pf.last()
+	p1, p2, p3 := gi.NewVec2DFmFixed(b), gi.NewVec2DFmFixed(c), gi.NewVec2DFmFixed(d)
+	for i := 1; i <= pathFlattenSteps; i++ {
+		t := float32(i) / pathFlattenSteps
+		mt := 1 - t
+		pt := p0.MulVal(mt * mt * mt).Add(p1.MulVal(3 * mt * mt * t)).Add(p2.MulVal(3 * mt * t * t)).Add(p3.MulVal(t * t * t))
+		pf.add(pt, false)
+	}
+}
+
+func (pf *pathFlattener) Stop(closeLoop bool) {
+	if closeLoop && len(pf.pl.Pts) > 0 {
+		pf.add(pf.start, false)
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package svg
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/chewxy/math32"
+	"github.com/goki/gi"
+	"github.com/goki/ki"
+	"github.com/goki/ki/kit"
+)
+
+// Pattern is a paint server that renders its children into a tile which is
+// repeated to fill or stroke any element that refers to it by url(#name) --
+// it implements the gi.PaintServer interface, so it can also be used for
+// the background-color of widgets, when it is found in their parents
+type Pattern struct {
+	NodeBase
+	Pos          gi.Vec2D     `xml:"{x,y}" desc:"position of the pattern tile, in PatternUnits"`
+	Size         gi.Vec2D     `xml:"{width,height}" desc:"size of the pattern tile, in PatternUnits -- nothing is rendered if either is zero"`
+	PatternUnits PatternUnits `xml:"patternUnits" desc:"units for the tile Pos and Size -- the default objectBoundingBox makes them proportions of the bounding box of the element being painted"`
+	ContentUnits PatternUnits `xml:"patternContentUnits" desc:"units for the contents (children) of the pattern -- the default is userSpaceOnUse -- ignored if ViewBox is set"`
+	ViewBox      ViewBox      `desc:"if set, defines the coordinate system for the contents, which is mapped onto the tile"`
+	XForm        gi.Matrix2D  `xml:"patternTransform" desc:"additional transform from pattern space into the user space of the element being painted"`
+	Tile         *image.RGBA  `json:"-" xml:"-" view:"-" desc:"last rendered tile image"`
+	TileXForm    gi.Matrix2D  `json:"-" xml:"-" view:"-" desc:"content transform used to render the Tile -- it is re-rendered when this changes, or when the pattern or its children are updated (see InvalidateTile)"`
+	rendering    bool
+}
+
+var KiT_Pattern = kit.Types.AddType(&Pattern{}, nil)
+
+// PatternUnits specifies the coordinate system for pattern tiles and contents
+type PatternUnits int32
+
+const (
+	// PatternObjectBoundingBox specifies values as proportions of the
+	// bounding box of the element being painted
+	PatternObjectBoundingBox PatternUnits = iota
+
+	// PatternUserSpaceOnUse specifies values in the user space of the
+	// element being painted
+	PatternUserSpaceOnUse
+
+	PatternUnitsN
+)
+
+//go:generate stringer -type=PatternUnits
+
+var KiT_PatternUnits = kit.Enums.AddEnumAltLower(PatternUnitsN, false, gi.StylePropProps, "Pattern")
+
+func (ev PatternUnits) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *PatternUnits) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// MaxPatternTileSize is the maximum size in pixels of the tile image rendered
+// for a pattern, along either dimension
+var MaxPatternTileSize = 2048
+
+// Init2D connects the update signals of the pattern and its children, so
+// that any update of them invalidates the Tile
+func (pt *Pattern) Init2D() {
+	pt.NodeBase.Init2D()
+	pt.FuncDownMeFirst(0, pt.This, func(k ki.Ki, level int, d interface{}) bool {
+		k.NodeSignal().Connect(pt.This, func(recv, send ki.Ki, sig int64, data interface{}) {
+			recv.Embed(KiT_Pattern).(*Pattern).InvalidateTile()
+		})
+		return true
+	})
+}
+
+// InvalidateTile clears the last rendered Tile, so that it is rendered again
+// when next used -- called on updates of the pattern or its children, and
+// can be called after changing them without an update
+func (pt *Pattern) InvalidateTile() {
+	pt.Tile = nil
+}
+
+// Render2D does nothing: patterns are only rendered where they are used
+func (pt *Pattern) Render2D() {
+}
+
+// PaintColor satisfies the gi.PaintServer interface, returning a color
+// function that tiles the pattern across the given render bounding box
+func (pt *Pattern) PaintColor(opacity float32, bounds image.Rectangle, xform gi.Matrix2D) interface{} {
+	if pt.rendering { // pattern contents that refer to the pattern itself
+		return color.Transparent
+	}
+	bpos, bsz := gi.UserBBox(bounds, xform)
+	pos, sz := pt.Pos, pt.Size
+	if pt.PatternUnits == PatternObjectBoundingBox {
+		pos = bpos.Add(pos.Mul(bsz))
+		sz = sz.Mul(bsz)
+	}
+	if sz.X <= 0 || sz.Y <= 0 {
+		return color.Transparent
+	}
+	pxf := pt.XForm
+	if pxf == (gi.Matrix2D{}) {
+		pxf = gi.Identity2D()
+	}
+	pxf = pxf.Multiply(xform)
+
+	// render the tile at the resolution it will be displayed at
+	scx, scy := pxf.ExtractScale()
+	isz := image.Point{int(math32.Ceil(sz.X * math32.Abs(scx))), int(math32.Ceil(sz.Y * math32.Abs(scy)))}
+	isz.X = gi.InRangeInt(isz.X, 1, MaxPatternTileSize)
+	isz.Y = gi.InRangeInt(isz.Y, 1, MaxPatternTileSize)
+
+	cxf := gi.Identity2D()
+	switch {
+	case pt.ViewBox.Size.X > 0 && pt.ViewBox.Size.Y > 0:
+		// todo: preserveAspectRatio, as elsewhere
+		cxf = gi.Translate2D(-pt.ViewBox.Min.X, -pt.ViewBox.Min.Y).Multiply(gi.Scale2D(sz.X/pt.ViewBox.Size.X, sz.Y/pt.ViewBox.Size.Y))
+	case pt.ContentUnits == PatternObjectBoundingBox:
+		cxf = gi.Scale2D(bsz.X, bsz.Y)
+	}
+	cxf = cxf.Multiply(gi.Scale2D(float32(isz.X)/sz.X, float32(isz.Y)/sz.Y))
+	img := pt.RenderTile(isz, cxf)
+	return gi.TileColorFunc(img, pos, sz, pxf, opacity)
+}
+
+// RenderTile renders the children of the pattern into a tile image of given
+// size, using given transform from content coordinates to tile pixels --
+// the last tile is re-used if nothing has changed
+func (pt *Pattern) RenderTile(isz image.Point, cxf gi.Matrix2D) *image.RGBA {
+	if pt.Tile != nil && pt.Tile.Bounds().Size() == isz && pt.TileXForm == cxf {
+		return pt.Tile
+	}
+	vp := &gi.Viewport2D{}
+	vp.Geom.Size = isz
+	vp.Pixels = image.NewRGBA(image.Rectangle{Max: isz})
+	vp.VpBBox = vp.Pixels.Bounds()
+	vp.Render.Init(isz.X, isz.Y, vp.Pixels)
+	if pt.Viewport != nil {
+		vp.Win = pt.Viewport.Win
+	}
+	rs := &vp.Render
+
+	// the contents render into the viewport of the tile instead of their own,
+	// which is restored after, along with the bounding boxes set in rendering
+	type nodeState struct {
+		nb                             *gi.Node2DBase
+		vp                             *gi.Viewport2D
+		bbox, objBBox, vpBBox, winBBox image.Rectangle
+	}
+	var saved []nodeState
+	pt.FuncDownMeFirst(0, pt.This, func(k ki.Ki, level int, d interface{}) bool {
+		_, nb := gi.KiToNode2D(k)
+		if nb == nil {
+			return false
+		}
+		if k != pt.This {
+			saved = append(saved, nodeState{nb, nb.Viewport, nb.BBox, nb.ObjBBox, nb.VpBBox, nb.WinBBox})
+			nb.Viewport = vp
+		}
+		return true
+	})
+	pt.rendering = true
+	rs.PushBounds(vp.Pixels.Bounds())
+	rs.PushXForm(cxf)
+	pt.Render2DChildren()
+	rs.PopXForm()
+	rs.PopBounds()
+	pt.rendering = false
+	for _, sv := range saved {
+		sv.nb.Viewport = sv.vp
+		sv.nb.BBox, sv.nb.ObjBBox, sv.nb.VpBBox, sv.nb.WinBBox = sv.bbox, sv.objBBox, sv.vpBBox, sv.winBBox
+	}
+	pt.Tile = vp.Pixels
+	pt.TileXForm = cxf
+	return pt.Tile
+}
@@ -27,7 +27,7 @@ func (g *Polygon) Render2D() {
 	rs := &g.Viewport.Render
 	rs.PushXForm(pc.XForm)
 	pc.DrawPolygon(rs, g.Points)
-	pc.FillStrokeClear(rs)
+	g.FillStrokeClear(rs)
 	g.ComputeBBoxSVG()
 
 	if mrk := g.Marker("marker-start"); mrk != nil {
@@ -29,7 +29,7 @@ func (g *Rect) Render2D() {
 		// todo: only supports 1 radius right now -- easy to add another
 		pc.DrawRoundedRectangle(rs, g.Pos.X, g.Pos.Y, g.Size.X, g.Size.Y, g.Radius.X)
 	}
-	pc.FillStrokeClear(rs)
+	g.FillStrokeClear(rs)
 	g.ComputeBBoxSVG()
 	g.Render2DChildren()
 	rs.PopXForm()
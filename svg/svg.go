@@ -21,11 +21,15 @@ import (
 // svg tag in html -- it provides its own bitmap for drawing into
 type SVG struct {
 	gi.Viewport2D
-	ViewBox ViewBox  `desc:"viewbox defines the coordinate system for the drawing"`
-	Pnt     gi.Paint `json:"-" xml:"-" desc:"paint styles -- inherited by nodes"`
-	Defs    Group    `desc:"all defs defined elements go here (gradients, symbols, etc)"`
-	Title   string   `xml:"title" desc:"the title of the svg"`
-	Desc    string   `xml:"desc" desc:"the description of the svg"`
+	ViewBox  ViewBox   `desc:"viewbox defines the coordinate system for the drawing"`
+	Pnt      gi.Paint  `json:"-" xml:"-" desc:"paint styles -- inherited by nodes"`
+	Defs     Group     `desc:"all defs defined elements go here (gradients, symbols, etc)"`
+	Title    string    `xml:"title" desc:"the title of the svg"`
+	Desc     string    `xml:"desc" desc:"the description of the svg"`
+	Anim     *Animator `json:"-" xml:"-" view:"-" desc:"drives the SMIL animations and Go-side attribute bindings -- see Animator()"`
+	hitPos   image.Point
+	hitNode  gi.Node2D
+	hitValid bool
 }
 
 var KiT_SVG = kit.Types.AddType(&SVG{}, nil)
@@ -38,6 +42,10 @@ func (g *SVG) Paint() *gi.Paint {
 // DeleteAll deletes any existing elements in this svg
 func (svg *SVG) DeleteAll() {
 	updt := svg.UpdateStart()
+	if svg.Anim != nil {
+		svg.Anim.Stop()
+		svg.Anim = nil
+	}
 	svg.DeleteChildren(true)
 	svg.ViewBox.Defaults()
 	svg.Pnt.Defaults()
@@ -103,6 +111,7 @@ func (svg *SVG) Layout2D(parBBox image.Rectangle, iter int) bool {
 }
 
 func (svg *SVG) Render2D() {
+	svg.hitValid = false
 	if svg.PushBounds() {
 		rs := &svg.Render
 		if svg.Fill {
@@ -142,3 +151,20 @@ func (svg *SVG) FindNamedElement(name string) gi.Node2D {
 	log.Printf("gi.SVG FindNamedElement: could not find name: %v\n", name)
 	return nil
 }
+
+// SavePNG renders the svg drawing to a PNG image file at given
+// dots-per-inch resolution (0 = screen resolution)
+func (svg *SVG) SavePNG(filename string, dpi float32) error {
+	return gi.SaveNode2DPNG(svg.This.(gi.Node2D), filename, dpi)
+}
+
+// SavePDF renders the svg drawing to a PDF vector graphics file
+func (svg *SVG) SavePDF(filename string) error {
+	return gi.SaveNode2DPDF(svg.This.(gi.Node2D), filename)
+}
+
+// SaveEPS renders the svg drawing to an Encapsulated PostScript vector
+// graphics file
+func (svg *SVG) SaveEPS(filename string) error {
+	return gi.SaveNode2DEPS(svg.This.(gi.Node2D), filename)
+}
@@ -6,111 +6,462 @@ package svg
 
 import (
 	"image"
+	"log"
+	"strings"
+	"unicode"
 
+	"github.com/chewxy/math32"
 	"github.com/goki/gi"
 	"github.com/goki/gi/units"
 	"github.com/goki/ki/ints"
 	"github.com/goki/ki/kit"
 )
 
-// Text renders SVG text-- it handles both text and tspan elements (a tspan is
-// just nested under a parent text)
+// Text renders SVG text -- it handles text, tspan and textPath elements (a
+// tspan or textPath is nested under a parent text).  The outermost text
+// element lays out all of the text within it, including nested elements, in
+// document order, with each element continuing from the current text
+// position where the previous one left off.
 type Text struct {
 	NodeBase
-	Pos          gi.Vec2D      `xml:"{x,y}" desc:"position of the left, baseline of the text"`
-	Width        float32       `xml:"width" desc:"width of text to render if using word-wrapping"`
-	Text         string        `xml:"text" desc:"text string to render"`
-	Render       gi.TextRender `xml:"-" json:"-" desc:"render version of text"`
-	CharPosX     []float32     `desc:"character positions along X axis, if specified"`
-	CharPosY     []float32     `desc:"character positions along Y axis, if specified"`
-	CharPosDX    []float32     `desc:"character delta-positions along X axis, if specified"`
-	CharPosDY    []float32     `desc:"character delta-positions along Y axis, if specified"`
-	CharRots     []float32     `desc:"character rotations, if specified"`
-	TextLength   float32       `desc:"author's computed text length, if specified -- we attempt to match"`
-	AdjustGlyphs bool          `desc:"in attempting to match TextLength, should we adjust glyphs in addition to spacing?"`
+	Pos            gi.Vec2D        `xml:"{x,y}" desc:"position of the left, baseline of the text"`
+	Width          float32         `xml:"width" desc:"width of text to render if using word-wrapping -- the SVG 2 inline-size property can also be used -- see WrapWidth"`
+	Text           string          `xml:"text" desc:"text string to render"`
+	Render         gi.TextRender   `xml:"-" json:"-" desc:"render version of text"`
+	CharPosX       []float32       `desc:"character positions along X axis, if specified"`
+	CharPosY       []float32       `desc:"character positions along Y axis, if specified"`
+	CharPosDX      []float32       `desc:"character delta-positions along X axis, if specified"`
+	CharPosDY      []float32       `desc:"character delta-positions along Y axis, if specified"`
+	CharRots       []float32       `desc:"character rotations, if specified -- the last value applies to all remaining characters"`
+	TextLength     float32         `desc:"author's computed text length, if specified -- we attempt to match"`
+	AdjustGlyphs   bool            `desc:"in attempting to match TextLength, should we adjust glyphs in addition to spacing?"`
+	TextPathRef    string          `xml:"href" desc:"for textPath elements: url of the path element along which the text is laid out"`
+	StartOffset    float32         `xml:"startOffset" desc:"for textPath elements: distance along the path at which the text starts -- if StartOffsetPct then it is a proportion of the total path length"`
+	StartOffsetPct bool            `desc:"StartOffset was specified as a percentage, and is a proportion of the total path length"`
+	LastPos        gi.Vec2D        `xml:"-" json:"-" desc:"current text position after laying out this element, in user coordinates -- a following element continues from here"`
+	TextBBox       image.Rectangle `xml:"-" json:"-" desc:"bounding box of the rendered glyphs of this element and all nested elements, in window coordinates"`
 }
 
 var KiT_Text = kit.Types.AddType(&Text{}, nil)
 
-func (g *Text) BBox2D() image.Rectangle {
-	rs := &g.Viewport.Render
-	// todo: could be much more accurate..
-	return g.Pnt.BoundingBox(rs, g.Pos.X, g.Pos.Y, g.Pos.X+g.Render.Size.X, g.Pos.Y+g.Render.Size.Y)
+// IsParText returns true if this element is nested within a parent text
+// element (i.e., it is a tspan or textPath), which then lays it out
+func (g *Text) IsParText() bool {
+	if g.Par == nil {
+		return false
+	}
+	_, ok := g.Par.(*Text)
+	return ok
 }
 
-func (g *Text) Render2D() {
-	pc := &g.Pnt
-	rs := &g.Viewport.Render
-	rs.PushXForm(pc.XForm)
-	if len(g.Text) > 0 {
-		orgsz := pc.FontStyle.Size
-		pos := rs.XForm.TransformPointVec2D(gi.Vec2D{g.Pos.X, g.Pos.Y})
-		rot := rs.XForm.ExtractRot()
-		scx, scy := rs.XForm.ExtractScale()
-		scalex := scx / scy
-		if scalex == 1 {
-			scalex = 0
-		}
-		pc.FontStyle.OpenFont(&pc.UnContext) // use original size font
-		if !pc.FillStyle.Color.IsNil() {
-			pc.FontStyle.Color = pc.FillStyle.Color.Color
-		}
-		g.Render.SetString(g.Text, &pc.FontStyle, &pc.UnContext, &pc.TextStyle, true, rot, scalex)
-		g.Render.Size = g.Render.Size.Mul(gi.Vec2D{scx, scy})
-		if gi.IsAlignMiddle(pc.TextStyle.Align) || pc.TextStyle.Anchor == gi.AnchorMiddle {
-			pos.X -= g.Render.Size.X * .5
-		} else if gi.IsAlignEnd(pc.TextStyle.Align) || pc.TextStyle.Anchor == gi.AnchorEnd {
-			pos.X -= g.Render.Size.X
-		}
-		pc.FontStyle.Size = units.Value{orgsz.Val * scy, orgsz.Un, orgsz.Dots * scy} // rescale by y
-		pc.FontStyle.OpenFont(&pc.UnContext)
-		sr := &(g.Render.Spans[0])
-		sr.Render[0].Face = pc.FontStyle.Face // upscale
+// TextNodes returns this element and all the text elements nested within it,
+// in document order
+func (g *Text) TextNodes() []*Text {
+	tn := []*Text{g}
+	for _, k := range g.Kids {
+		if kt, ok := k.(*Text); ok {
+			tn = append(tn, kt.TextNodes()...)
+		}
+	}
+	return tn
+}
+
+// CharPosUnits returns the given character position values, or if those are
+// empty, values parsed from the property of given name, which can have units
+// (e.g., dy="1.2em") -- these are converted using the current unit context
+func (g *Text) CharPosUnits(name string, vals []float32) []float32 {
+	if len(vals) > 0 {
+		return vals
+	}
+	pv, ok := g.Props[name]
+	if !ok {
+		return nil
+	}
+	ps, ok := pv.(string)
+	if !ok {
+		return nil
+	}
+	flds := strings.FieldsFunc(ps, func(r rune) bool { return unicode.IsSpace(r) || r == ',' })
+	pts := make([]float32, 0, len(flds))
+	for _, f := range flds {
+		uv := units.StringToValue(f)
+		pts = append(pts, uv.ToDots(&g.Pnt.UnContext))
+	}
+	return pts
+}
+
+// TextPathPolyline returns the flattened version of the path referred to by
+// TextPathRef -- returns nil if not found
+func (g *Text) TextPathPolyline() *PathPolyline {
+	pn := g.FindSVGURL(g.TextPathRef)
+	if pn == nil {
+		return nil
+	}
+	pth, ok := pn.(*Path)
+	if !ok {
+		log.Printf("gi.svg Text TextPathRef must refer to a path element, instead is: %T\n", pn)
+		return nil
+	}
+	return PathDataPolyline(pth.Data, pth.Pnt.XForm)
+}
+
+// textAnchor returns the effective text anchor for given text style, also
+// taking into account the general Align setting
+func textAnchor(ts *gi.TextStyle) gi.TextAnchors {
+	switch {
+	case ts.Anchor == gi.AnchorMiddle || gi.IsAlignMiddle(ts.Align):
+		return gi.AnchorMiddle
+	case ts.Anchor == gi.AnchorEnd || gi.IsAlignEnd(ts.Align):
+		return gi.AnchorEnd
+	}
+	return gi.AnchorStart
+}
+
+// WrapWidth returns the width within which the text of this element is
+// wrapped onto multiple lines: the SVG 2 inline-size property if set, else
+// Width -- 0 if the text is not wrapped.  Wrapping is only supported for
+// text elements without nested elements, character positions or a path.
+func (g *Text) WrapWidth() float32 {
+	if len(g.Kids) > 0 || g.TextPathRef != "" || len(g.CharPosX) > 1 || len(g.CharPosY) > 1 {
+		return 0
+	}
+	if pv, ok := g.Props["inline-size"]; ok {
+		uv := units.StringToValue(kit.ToString(pv))
+		return uv.ToDots(&g.Pnt.UnContext)
+	}
+	return g.Width
+}
+
+// LayoutWrapped lays out the text of this element wrapped onto lines of
+// given width, with the baseline of the first line at Pos, and the lines
+// aligned according to text-anchor: start, middle or end at Pos.X -- words
+// are broken and hyphenated according to the hyphens and overflow-wrap
+// styles, as in other text
+func (g *Text) LayoutWrapped(width float32) {
+	tpc := &g.Pnt
+	g.TextBBox = image.ZR
+	tpc.FontStyle.OpenFont(&tpc.UnContext)
+	if len(g.Text) == 0 {
+		g.Render.Spans = nil
+		g.LastPos = g.Pos
+		return
+	}
+	if !tpc.FillStyle.Color.IsNil() {
+		tpc.FontStyle.Color = tpc.FillStyle.Color.Color
+	}
+	ts := tpc.TextStyle
+	x := g.Pos.X
+	switch textAnchor(&ts) {
+	case gi.AnchorMiddle:
+		ts.Align = gi.AlignCenter
+		x -= 0.5 * width
+	case gi.AnchorEnd:
+		ts.Align = gi.AlignRight
+		x -= width
+	default:
+		ts.Align = gi.AlignLeft
+	}
+	g.Render.SetString(g.Text, &tpc.FontStyle, &tpc.UnContext, &ts, true, 0, 0)
+	g.Render.LayoutStdLR(&ts, &tpc.FontStyle, &tpc.UnContext, gi.Vec2D{width, 0})
+	bo := ts.BaselineOffset(&tpc.FontStyle)
+	y0 := g.Render.Spans[0].RelPos.Y
+	for si := range g.Render.Spans {
+		sr := &(g.Render.Spans[si])
+		off := gi.Vec2D{x + sr.RelPos.X, g.Pos.Y + bo + sr.RelPos.Y - y0}
 		for i := range sr.Render {
-			sr.Render[i].RelPos = rs.XForm.TransformVectorVec2D(sr.Render[i].RelPos)
-			sr.Render[i].Size.Y *= scy
-			sr.Render[i].Size.X *= scx
-		}
-		pc.FontStyle.Size = orgsz
-		if len(g.CharPosX) > 0 {
-			mx := ints.MinInt(len(g.CharPosX), len(sr.Render))
-			for i := 0; i < mx; i++ {
-				// todo: this may not be fully correct, given relativity constraints
-				sr.Render[i].RelPos.X, _ = rs.XForm.TransformVector(g.CharPosX[i], 0)
+			sr.Render[i].RelPos = sr.Render[i].RelPos.Add(off)
+		}
+		sr.LastPos = gi.Vec2D{off.X + sr.LastPos.X, off.Y}
+		sr.RelPos = gi.Vec2D{}
+		g.LastPos = sr.LastPos
+	}
+}
+
+// textGlyph refers to one laid-out glyph during text layout
+type textGlyph struct {
+	sr   *gi.SpanRender
+	idx  int
+	adv  float32
+	path *PathPolyline
+}
+
+// textChunk is a range of glyphs that is anchored as a unit -- each absolute
+// x or y position starts a new chunk
+type textChunk struct {
+	st, ed int
+	anchor gi.TextAnchors
+}
+
+// LayoutText lays out the glyphs of this element and all nested text
+// elements, in user coordinates, applying character positions and
+// rotations, textLength, text-anchor, dominant-baseline and textPath
+// placement -- only called on the outermost text element
+func (g *Text) LayoutText() {
+	if wd := g.WrapWidth(); wd > 0 {
+		g.LayoutWrapped(wd)
+		return
+	}
+	var glyphs []textGlyph
+	var chunks []textChunk
+	paths := map[*Text]*PathPolyline{}
+	var prvPath *PathPolyline
+	cur := g.Pos
+	for _, t := range g.TextNodes() {
+		t.TextBBox = image.ZR
+		tpc := &t.Pnt
+		var path *PathPolyline
+		newPath := false
+		if t.TextPathRef != "" {
+			path = t.TextPathPolyline()
+			newPath = path != nil
+		} else if pt, ok := t.Par.(*Text); ok && t != g {
+			path = paths[pt]
+		}
+		paths[t] = path
+		if newPath {
+			so := t.StartOffset
+			if t.StartOffsetPct {
+				so *= path.Length()
+			}
+			cur = gi.Vec2D{so, 0}
+		} else if path == nil && prvPath != nil {
+			// continue after the end of the text on the path
+			if pt, _, ok := prvPath.PointAt(cur.X); ok {
+				cur = pt
+			} else if np := len(prvPath.Pts); np > 0 {
+				cur = prvPath.Pts[np-1]
 			}
 		}
-		if len(g.CharPosY) > 0 {
-			mx := ints.MinInt(len(g.CharPosY), len(sr.Render))
-			for i := 0; i < mx; i++ {
-				_, sr.Render[i].RelPos.Y = rs.XForm.TransformPoint(g.CharPosY[i], 0)
+		prvPath = path
+		tpc.FontStyle.OpenFont(&tpc.UnContext) // user-coordinate size font
+		if len(t.Text) == 0 {
+			t.Render.Spans = nil
+			t.LastPos = cur
+			continue
+		}
+		xs := t.CharPosUnits("x", t.CharPosX)
+		ys := t.CharPosUnits("y", t.CharPosY)
+		dxs := t.CharPosUnits("dx", t.CharPosDX)
+		dys := t.CharPosUnits("dy", t.CharPosDY)
+		if !tpc.FillStyle.Color.IsNil() {
+			tpc.FontStyle.Color = tpc.FillStyle.Color.Color
+		}
+		t.Render.SetString(t.Text, &tpc.FontStyle, &tpc.UnContext, &tpc.TextStyle, true, 0, 0)
+		sr := &(t.Render.Spans[0])
+		bo := tpc.TextStyle.BaselineOffset(&tpc.FontStyle)
+		anchor := textAnchor(&tpc.TextStyle)
+		sz := len(sr.Render)
+		adv := make([]float32, sz)
+		for i := range sr.Render {
+			if i < sz-1 {
+				adv[i] = sr.Render[i+1].RelPos.X - sr.Render[i].RelPos.X
+			} else {
+				adv[i] = sr.LastPos.X - sr.Render[i].RelPos.X
 			}
 		}
-		if len(g.CharPosDX) > 0 {
-			mx := ints.MinInt(len(g.CharPosDX), len(sr.Render))
-			for i := 0; i < mx; i++ {
-				dx, _ := rs.XForm.TransformVector(g.CharPosDX[i], 0)
-				if i > 0 {
-					sr.Render[i].RelPos.X = sr.Render[i-1].RelPos.X + dx
-				} else {
-					sr.Render[i].RelPos.X = dx // todo: not sure this is right
+		gst := len(glyphs)
+		for i := range sr.Render {
+			rr := &(sr.Render[i])
+			newChunk := len(glyphs) == 0 || (i == 0 && newPath)
+			if i < len(xs) {
+				cur.X = xs[i]
+				newChunk = true
+			}
+			if i < len(ys) {
+				cur.Y = ys[i]
+				newChunk = true
+			}
+			if i < len(dxs) {
+				cur.X += dxs[i]
+			}
+			if i < len(dys) {
+				cur.Y += dys[i]
+			}
+			if newChunk {
+				if nc := len(chunks); nc > 0 {
+					chunks[nc-1].ed = len(glyphs)
+				}
+				chunks = append(chunks, textChunk{st: len(glyphs), anchor: anchor})
+			}
+			rr.RelPos = gi.Vec2D{cur.X, cur.Y + bo + rr.RelPos.Y} // RelPos.Y has super / sub offset
+			if nr := len(t.CharRots); nr > 0 {
+				rr.RotRad = gi.Radians(t.CharRots[ints.MinInt(i, nr-1)])
+			}
+			glyphs = append(glyphs, textGlyph{sr: sr, idx: i, adv: adv[i], path: path})
+			cur.X += adv[i]
+		}
+		sr.LastPos = cur
+		if t.TextLength > 0 {
+			sr.AdjustLengthLR(t.TextLength, t.AdjustGlyphs)
+			cur.X = sr.LastPos.X
+			for j := gst; j < len(glyphs); j++ {
+				nx := cur.X
+				if j < len(glyphs)-1 {
+					nx = glyphs[j+1].sr.Render[glyphs[j+1].idx].RelPos.X
+				}
+				glyphs[j].adv = nx - sr.Render[glyphs[j].idx].RelPos.X
+			}
+		}
+		t.LastPos = cur
+	}
+	if nc := len(chunks); nc > 0 {
+		chunks[nc-1].ed = len(glyphs)
+	}
+
+	for _, ch := range chunks {
+		if ch.anchor == gi.AnchorStart || ch.ed <= ch.st {
+			continue
+		}
+		mn := glyphs[ch.st].sr.Render[glyphs[ch.st].idx].RelPos.X
+		mx := mn
+		for j := ch.st; j < ch.ed; j++ {
+			gl := glyphs[j]
+			x := gl.sr.Render[gl.idx].RelPos.X
+			mn = math32.Min(mn, x)
+			mx = math32.Max(mx, x+gl.adv)
+		}
+		off := mn - mx
+		if ch.anchor == gi.AnchorMiddle {
+			off *= 0.5
+		}
+		for j := ch.st; j < ch.ed; j++ {
+			gl := glyphs[j]
+			gl.sr.Render[gl.idx].RelPos.X += off
+		}
+	}
+
+	for _, gl := range glyphs {
+		if gl.path == nil {
+			continue
+		}
+		rr := &(gl.sr.Render[gl.idx])
+		pt, ang, ok := gl.path.PointAt(rr.RelPos.X + 0.5*gl.adv)
+		if !ok { // glyphs whose midpoint is off the path are not rendered
+			gl.sr.Text[gl.idx] = ' '
+			continue
+		}
+		dir := gi.Vec2D{math32.Cos(ang), math32.Sin(ang)}
+		nrm := gi.Vec2D{-dir.Y, dir.X}
+		rr.RelPos = pt.Sub(dir.MulVal(0.5 * gl.adv)).Add(nrm.MulVal(rr.RelPos.Y))
+		rr.RotRad += ang
+	}
+}
+
+// RenderText renders the laid-out glyphs of this element and all nested
+// text elements, transforming them into window coordinates using the
+// current transform -- only called on the outermost text element
+func (g *Text) RenderText() {
+	rs := &g.Viewport.Render
+	xf := rs.XForm
+	rot := xf.ExtractRot()
+	scx, scy := xf.ExtractScale()
+	scalex := scx / scy
+	if scalex == 1 {
+		scalex = 0
+	}
+	fsc := math32.Abs(scy)
+	for _, t := range g.TextNodes() {
+		if len(t.Render.Spans) == 0 || t.Render.Spans[0].IsValid() != nil {
+			continue
+		}
+		tpc := &t.Pnt
+		orgsz := tpc.FontStyle.Size
+		tpc.FontStyle.Size = units.Value{orgsz.Val * fsc, orgsz.Un, orgsz.Dots * fsc} // rescale by y
+		tpc.FontStyle.OpenFont(&tpc.UnContext)
+		tpc.FontStyle.Size = orgsz
+		for si := range t.Render.Spans { // multiple spans (lines) for wrapped text
+			sr := &(t.Render.Spans[si])
+			if sr.IsValid() != nil {
+				continue
+			}
+			sr.Render[0].Face = tpc.FontStyle.Face // upscale
+			for i := range sr.Render {
+				rr := &(sr.Render[i])
+				rr.RelPos = xf.TransformPointVec2D(rr.RelPos)
+				rr.RotRad += rot
+				if scalex != 0 {
+					if rr.ScaleX == 0 {
+						rr.ScaleX = scalex
+					} else {
+						rr.ScaleX *= scalex
+					}
 				}
+				rr.Size.X *= scx
+				rr.Size.Y *= scy
 			}
 		}
-		if len(g.CharPosDY) > 0 {
-			mx := ints.MinInt(len(g.CharPosDY), len(sr.Render))
-			for i := 0; i < mx; i++ {
-				dy, _ := rs.XForm.TransformVector(g.CharPosDY[i], 0)
-				if i > 0 {
-					sr.Render[i].RelPos.Y = sr.Render[i-1].RelPos.Y + dy
+		t.Render.Render(rs, gi.Vec2D{})
+		t.TextBBox = t.glyphsBBox()
+	}
+	g.unionTextBBox()
+}
+
+// glyphsBBox returns the bounding box of the glyphs of this element, which
+// must have already been transformed into window coordinates
+func (g *Text) glyphsBBox() image.Rectangle {
+	var mn, mx gi.Vec2D
+	gi.TextFontRenderMu.Lock()
+	defer gi.TextFontRenderMu.Unlock()
+	first := true
+	for si := range g.Render.Spans {
+		sr := &(g.Render.Spans[si])
+		if sr.IsValid() != nil {
+			continue
+		}
+		curFace := sr.Render[0].Face
+		for i := range sr.Render {
+			rr := &(sr.Render[i])
+			curFace = rr.CurFace(curFace)
+			dsc := gi.FixedToFloat32(curFace.Metrics().Descent)
+			scx := float32(1)
+			if rr.ScaleX != 0 {
+				scx = rr.ScaleX
+			}
+			tx := gi.Scale2D(scx, 1).Rotate(rr.RotRad)
+			crnrs := [4]gi.Vec2D{{0, dsc}, {rr.Size.X, dsc}, {0, dsc - rr.Size.Y}, {rr.Size.X, dsc - rr.Size.Y}}
+			for _, c := range crnrs {
+				p := rr.RelPos.Add(tx.TransformVectorVec2D(c))
+				if first {
+					mn, mx = p, p
+					first = false
 				} else {
-					sr.Render[i].RelPos.Y = dy // todo: not sure this is right
+					mn = mn.Min(p)
+					mx = mx.Max(p)
 				}
 			}
 		}
-		// todo: TextLength, AdjustGlyphs -- also svg2 at least supports word wrapping!
-		g.Render.Render(rs, pos)
+	}
+	return image.Rectangle{mn.ToPointFloor(), mx.ToPointCeil()}
+}
+
+// unionTextBBox sets the TextBBox of this element to include those of all
+// nested text elements, returning the result
+func (g *Text) unionTextBBox() image.Rectangle {
+	for _, k := range g.Kids {
+		if kt, ok := k.(*Text); ok {
+			g.TextBBox = g.TextBBox.Union(kt.unionTextBBox())
+		}
+	}
+	return g.TextBBox
+}
+
+func (g *Text) BBox2D() image.Rectangle {
+	return g.TextBBox
+}
+
+func (g *Text) Render2D() {
+	if g.IsParText() {
+		// already laid out and rendered by the outermost text element
 		g.ComputeBBoxSVG()
+		g.Render2DChildren()
+		return
 	}
+	pc := &g.Pnt
+	rs := &g.Viewport.Render
+	rs.PushXForm(pc.XForm)
+	g.LayoutText()
+	g.RenderText()
+	g.ComputeBBoxSVG()
 	g.Render2DChildren()
 	rs.PopXForm()
 }
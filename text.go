@@ -14,6 +14,7 @@ import (
 	"image/color"
 	"io"
 	"math"
+	"reflect"
 	"strings"
 	"sync"
 
@@ -22,6 +23,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/chewxy/math32"
+	"github.com/goki/freetype/truetype"
 	"github.com/goki/gi/oswin"
 	"github.com/goki/gi/units"
 	"github.com/goki/ki"
@@ -60,6 +62,8 @@ type RuneRender struct {
 	Size    Vec2D           `desc:"size of the rune itself, exclusive of spacing that might surround it"`
 	RotRad  float32         `desc:"rotation in radians for this character, relative to its lower-left baseline rendering position"`
 	ScaleX  float32         `desc:"scaling of the X dimension, in case of non-uniform scaling, 0 = no separate scaling"`
+	Glyph   int             `desc:"index of the font glyph to draw in place of the rune's own glyph, as substituted in shaping (e.g., a ligature or conjunct form) -- 0 = the rune's own glyph"`
+	Ligated bool            `desc:"this rune is drawn as part of the ligature Glyph of a prior rune, and is not drawn itself -- its position is within that glyph, for cursor positioning"`
 }
 
 // HasNil returns error if any of the key info (face, color) is nil -- only
@@ -128,6 +132,8 @@ type SpanRender struct {
 	LastPos Vec2D           `desc:"rune position for further edge of last rune -- for standard flat strings this is the overall length of the string -- used for size / layout computations -- you do not add RelPos to this -- it is in same TextRender relative coordinates"`
 	Dir     TextDirections  `desc:"where relevant, this is the (default, dominant) text direction for the span"`
 	HasDeco TextDecorations `desc:"mask of decorations that have been set on this span -- optimizes rendering passes"`
+	Levels  []uint8         `desc:"bidi embedding levels of each rune (odd = right-to-left), from the unicode bidi algorithm -- nil if all text is left-to-right -- see BidiLevels"`
+	LogPos  []float32       `desc:"for bidi text (Levels non-nil), the logical X positions of each rune, prior to reordering into visual order in the Render RelPos values -- used for wrapping and measuring"`
 }
 
 // Init initializes a new span with given capacity
@@ -135,6 +141,8 @@ func (sr *SpanRender) Init(capsz int) {
 	sr.Text = make([]rune, 0, capsz)
 	sr.Render = make([]RuneRender, 0, capsz)
 	sr.HasDeco = 0
+	sr.Levels = nil
+	sr.LogPos = nil
 }
 
 // IsValid ensures that at least some text is represented and the sizes of
@@ -157,6 +165,9 @@ func (sr *SpanRender) SizeHV() Vec2D {
 		return Vec2D{}
 	}
 	sz := sr.Render[0].RelPos.Sub(sr.LastPos)
+	if sr.LogPos != nil {
+		sz.X = sr.LogPos[0] - sr.LastPos.X
+	}
 	if sz.X < 0 {
 		sz.X = -sz.X
 	}
@@ -189,7 +200,8 @@ func (sr *SpanRender) RuneEndPos(idx int) Vec2D {
 	return spos
 }
 
-// AppendRune adds one rune and associated formatting info
+// HasDecoUpdate updates the HasDeco flags of the span for given background
+// color and decorations
 func (sr *SpanRender) HasDecoUpdate(bg color.Color, deco TextDecorations) {
 	sr.HasDeco |= deco
 	if bg != nil {
@@ -212,62 +224,42 @@ func (sr *SpanRender) SetNewPara() {
 	}
 }
 
-// AppendRune adds one rune and associated formatting info
+// AppendRune adds one rune and associated formatting info -- a fallback
+// face is used if the face does not have the rune (see FontLib.RuneFace)
 func (sr *SpanRender) AppendRune(r rune, face font.Face, clr, bg color.Color, deco TextDecorations) {
 	sr.Text = append(sr.Text, r)
-	rr := RuneRender{Face: face, Color: clr, BgColor: bg, Deco: deco}
+	rr := RuneRender{Face: FontLibrary.RuneFace(face, "", r), Color: clr, BgColor: bg, Deco: deco}
 	sr.Render = append(sr.Render, rr)
 	sr.HasDecoUpdate(bg, deco)
 }
 
 // AppendString adds string and associated formatting info, optimized with
-// only first rune having non-nil face and color settings
+// only first rune having non-nil face and color settings -- runes that are
+// missing from the face use a fallback face from the font-family list in
+// sty and the system fallbacks (see FontLib.RuneFace)
 func (sr *SpanRender) AppendString(str string, face font.Face, clr, bg color.Color, deco TextDecorations, sty *FontStyle, ctxt *units.Context) {
 	if len(str) == 0 {
 		return
 	}
-	ucfont := FontStyle{}
-	if oswin.TheApp.Platform() == oswin.MacOS {
-		ucfont.Family = "Arial Unicode"
-	} else {
-		ucfont.Family = "Arial"
-	}
-	ucfont.Size = sty.Size
-	ucfont.OpenFont(ctxt)
-
 	nwr := []rune(str)
 	sz := len(nwr)
 	sr.Text = append(sr.Text, nwr...)
-	rr := RuneRender{Face: face, Color: clr, BgColor: bg, Deco: deco}
-	r := nwr[0]
-	lastUc := false
-	if r > 0xFF && unicode.IsSymbol(r) {
-		rr.Face = ucfont.Face
-		lastUc = true
-	}
+	curFace := FontLibrary.RuneFace(face, sty.Family, nwr[0])
+	rr := RuneRender{Face: curFace, Color: clr, BgColor: bg, Deco: deco}
 	sr.HasDecoUpdate(bg, deco)
 	sr.Render = append(sr.Render, rr)
 	for i := 1; i < sz; i++ { // optimize by setting rest to nil for same
 		rp := RuneRender{Deco: deco, BgColor: bg}
-		r := nwr[i]
-		if oswin.TheApp.Platform() == oswin.MacOS {
-			if r > 0xFF && unicode.IsSymbol(r) {
-				if !lastUc {
-					rp.Face = ucfont.Face
-					lastUc = true
-				}
-			} else {
-				if lastUc {
-					rp.Face = face
-					lastUc = false
-				}
-			}
+		if rf := FontLibrary.RuneFace(face, sty.Family, nwr[i]); rf != curFace {
+			rp.Face = rf
+			curFace = rf
 		}
 		sr.Render = append(sr.Render, rp)
 	}
 }
 
-// SetRenders sets rendering parameters based on style
+// SetRenders sets rendering parameters based on style -- runes that are
+// missing from the style's face use a fallback face (see FontLib.RuneFace)
 func (sr *SpanRender) SetRenders(sty *FontStyle, ctxt *units.Context, noBG bool, rot, scalex float32) {
 	sz := len(sr.Text)
 	if sz == 0 {
@@ -279,11 +271,6 @@ func (sr *SpanRender) SetRenders(sty *FontStyle, ctxt *units.Context, noBG bool,
 		bgc = nil
 	}
 
-	ucfont := FontStyle{}
-	ucfont.Family = "Arial Unicode"
-	ucfont.Size = sty.Size
-	ucfont.OpenFont(ctxt)
-
 	sr.HasDecoUpdate(bgc, sty.Deco)
 	sr.Render = make([]RuneRender, sz)
 	sr.Render[0].Face = sty.Face
@@ -307,19 +294,12 @@ func (sr *SpanRender) SetRenders(sty *FontStyle, ctxt *units.Context, noBG bool,
 			sr.Render[i].Deco = sty.Deco
 		}
 	}
-	// use unicode font for all non-ascii symbols
-	lastUc := false
+	// use fallback fonts for runes missing from the face
+	curFace := sty.Face
 	for i, r := range sr.Text {
-		if r > 0xFF && unicode.IsSymbol(r) {
-			if !lastUc {
-				sr.Render[i].Face = ucfont.Face
-				lastUc = true
-			}
-		} else {
-			if lastUc {
-				sr.Render[i].Face = sty.Face
-				lastUc = false
-			}
+		if rf := FontLibrary.RuneFace(sty.Face, sty.Family, r); rf != curFace || i == 0 {
+			sr.Render[i].Face = rf
+			curFace = rf
 		}
 	}
 }
@@ -329,6 +309,7 @@ func (sr *SpanRender) SetRenders(sty *FontStyle, ctxt *units.Context, noBG bool,
 // slice of same size as Text
 func (sr *SpanRender) SetString(str string, sty *FontStyle, ctxt *units.Context, noBG bool, rot, scalex float32) {
 	sr.Text = []rune(str)
+	sr.Levels = nil
 	sr.SetRenders(sty, ctxt, noBG, rot, scalex)
 }
 
@@ -337,6 +318,7 @@ func (sr *SpanRender) SetString(str string, sty *FontStyle, ctxt *units.Context,
 // slice of same size as Text
 func (sr *SpanRender) SetRunes(str []rune, sty *FontStyle, ctxt *units.Context, noBG bool, rot, scalex float32) {
 	sr.Text = str
+	sr.Levels = nil
 	sr.SetRenders(sty, ctxt, noBG, rot, scalex)
 }
 
@@ -348,15 +330,24 @@ var TextFontRenderMu sync.Mutex
 
 // SetRunePosLR sets relative positions of each rune using a flat
 // left-to-right text layout, based on font size info and additional extra
-// letter and word spacing parameters (which can be negative)
+// letter and word spacing parameters (which can be negative) -- the text is
+// first shaped (see Shape), and kerning and combining mark positions from
+// the font are applied -- any
+// right-to-left text is then reordered for display according to the unicode
+// bidi algorithm, using Dir as the paragraph direction (see ReorderBidiLR)
 func (sr *SpanRender) SetRunePosLR(letterSpace, wordSpace, chsz float32, tabSize int) {
 	if err := sr.IsValid(); err != nil {
 		// log.Println(err)
 		return
 	}
-	sr.Dir = LRTB
+	sr.Shape(letterSpace == 0)
+	script := ShapeScript(sr.Text)
 	sz := len(sr.Text)
 	prevR := rune(-1)
+	prevG := -1   // glyph of previous char, for kerning
+	baseIdx := -1 // index of previous base char, for positioning marks
+	baseG := 0
+	var baseEnd float32
 	lspc := letterSpace
 	wspc := wordSpace
 	if tabSize == 0 {
@@ -364,34 +355,128 @@ func (sr *SpanRender) SetRunePosLR(letterSpace, wordSpace, chsz float32, tabSize
 	}
 	var fpos float32
 	curFace := sr.Render[0].Face
+	var shFace font.Face
+	var sh *FontShaper
+	var ttf *truetype.Font
+	var fsz, fsc float32 // font size, scale from font units
+	var feats string     // font-feature-settings of face
+	hasKern := false
+	noKern := false // kerning turned off in font-feature-settings
 	TextFontRenderMu.Lock()
 	defer TextFontRenderMu.Unlock()
 	col := 0 // current column position -- todo: does NOT deal with indent
 	for i, r := range sr.Text {
 		rr := &(sr.Render[i])
 		curFace = rr.CurFace(curFace)
+		if curFace != shFace {
+			shFace = curFace
+			sh, ttf, fsz = FontLibrary.FaceShaper(curFace)
+			feats = FontLibrary.FaceFeatures(curFace)
+			noKern = ParseFontFeatures(feats).IsOff("kern")
+			hasKern = sh != nil && sh.HasKern(script, feats)
+			if sh != nil {
+				fsc = fsz / float32(sh.UnitsPerEm)
+			}
+			prevG = -1
+		}
+		if rr.Ligated { // positioned with the start of its ligature
+			prevR = r
+			continue
+		}
 
 		fht := FixedToFloat32(curFace.Metrics().Height)
-		if prevR >= 0 {
-			fpos += FixedToFloat32(curFace.Kern(prevR, r))
+		g := rr.Glyph
+		if g == 0 && ttf != nil {
+			g = int(ttf.Index(r))
+		}
+		if unicode.Is(unicode.Cf, r) { // invisible format chars, e.g., soft hyphen, zero width space
+			rr.RelPos = Vec2D{fpos, 0}
+			rr.Size = Vec2D{0, fht}
+			continue
+		}
+		// todo: could check for various types of special unicode space chars here
+		var a32 float32
+		if of, ok := curFace.(OutlineFace); ok && rr.Glyph != 0 {
+			a32 = of.GlyphIndexAdvance(rr.Glyph)
+		} else if rr.Glyph != 0 && ttf != nil {
+			a32 = FixedToFloat32(ttf.HMetric(Float32ToFixed(fsz), truetype.Index(rr.Glyph)).AdvanceWidth)
+		} else {
+			a, _ := curFace.GlyphAdvance(r)
+			a32 = FixedToFloat32(a)
+		}
+
+		if baseIdx >= 0 && unicode.Is(unicode.Mn, r) { // combining mark
+			base := &(sr.Render[baseIdx])
+			placed := false
+			if sh != nil {
+				if dx, dy, ok := sh.MarkOffset(script, baseG, g); ok {
+					rr.RelPos.X = base.RelPos.X + float32(dx)*fsc
+					rr.RelPos.Y = base.RelPos.Y - float32(dy)*fsc
+					placed = true
+				}
+			}
+			if !placed && a32 == 0 { // zero-width mark, drawn over base
+				rr.RelPos = Vec2D{baseEnd, base.RelPos.Y}
+				placed = true
+			}
+			if placed {
+				rr.Size = Vec2D{0, fht}
+				prevR = r
+				continue
+			}
+		}
+
+		preBase := isIndicPreBase(r) && i > 0
+		if prevR >= 0 && !preBase && !noKern {
+			if hasKern {
+				if prevG >= 0 {
+					fpos += float32(sh.Kern(script, feats, prevG, g)) * fsc
+				}
+			} else {
+				fpos += FixedToFloat32(curFace.Kern(prevR, r))
+			}
+		}
+		if a32 == 0 {
+			a32 = .1 * fht // something..
+		}
+		if preBase { // vowel sign drawn before the consonant cluster
+			cs := indicClusterStart(sr.Text, i)
+			rr.RelPos.X = sr.Render[cs].RelPos.X
+			for j := cs; j < i; j++ {
+				sr.Render[j].RelPos.X += a32
+			}
+		} else {
+			rr.RelPos.X = fpos
 		}
-		rr.RelPos.X = fpos
 		rr.RelPos.Y = 0
 
 		if bitflag.Has32(int32(rr.Deco), int(DecoSuper)) {
-			rr.RelPos.Y = -0.45 * FixedToFloat32(curFace.Metrics().Ascent)
+			rr.RelPos.Y = FontSuperOffset(curFace)
 		}
 		if bitflag.Has32(int32(rr.Deco), int(DecoSub)) {
-			rr.RelPos.Y = 0.15 * FixedToFloat32(curFace.Metrics().Ascent)
+			rr.RelPos.Y = FontSubOffset(curFace)
 		}
 
-		// todo: could check for various types of special unicode space chars here
-		a, _ := curFace.GlyphAdvance(r)
-		a32 := FixedToFloat32(a)
-		if a32 == 0 {
-			a32 = .1 * fht // something..
+		nlig := 0 // ligature components after this one
+		for i+nlig+1 < sz && sr.Render[i+nlig+1].Ligated {
+			nlig++
+		}
+		if nlig > 0 { // components evenly divide the ligature
+			w := a32 / float32(nlig+1)
+			rr.Size = Vec2D{w, fht}
+			for k := 1; k <= nlig; k++ {
+				lr := &(sr.Render[i+k])
+				lr.RelPos = Vec2D{rr.RelPos.X + float32(k)*w, rr.RelPos.Y}
+				lr.Size = rr.Size
+			}
+		} else {
+			rr.Size = Vec2D{a32, fht}
+		}
+		if !preBase {
+			baseIdx = i
+			baseG = g
+			baseEnd = fpos + a32
 		}
-		rr.Size = Vec2D{a32, fht}
 
 		if r == '\t' {
 			curtab := col / tabSize
@@ -403,8 +488,8 @@ func (sr *SpanRender) SetRunePosLR(letterSpace, wordSpace, chsz float32, tabSize
 			}
 		} else {
 			fpos += a32
-			col++
-			if i < sz-1 {
+			col += nlig + 1
+			if i+nlig < sz-1 {
 				fpos += lspc
 				if unicode.IsSpace(r) {
 					fpos += wspc
@@ -412,80 +497,161 @@ func (sr *SpanRender) SetRunePosLR(letterSpace, wordSpace, chsz float32, tabSize
 			}
 		}
 		prevR = r
+		prevG = g
 	}
 	sr.LastPos.X = fpos
 	sr.LastPos.Y = 0
+	sr.ReorderBidiLR()
 }
 
-// FindWrapPosLR finds a position to do word wrapping to fit within trgSize --
-// RelPos positions must have already been set (e.g., SetRunePosLR)
-func (sr *SpanRender) FindWrapPosLR(trgSize, curSize float32) int {
+// FindWrapPosLR finds a position to do word wrapping to fit within trgSize,
+// at the line break opportunities of the unicode line breaking algorithm
+// (see LineBreaks), returning the index of the rune that starts the next
+// line -- RelPos positions must have already been set (e.g., SetRunePosLR).
+// Words are hyphenated according to hyph, and if ow is not
+// OverflowWrapNormal, a word that does not fit by itself is broken between
+// any characters.  hyphen is true if the line is broken within a word, so
+// that a hyphen must be displayed at the end of the line (see HyphenateLR).
+// If nothing fits, the first break opportunity is returned, and -1 if there
+// is none.
+func (sr *SpanRender) FindWrapPosLR(trgSize float32, hyph Hyphens, ow OverflowWraps) (idx int, hyphen bool) {
 	sz := len(sr.Text)
 	if sz == 0 {
-		return -1
+		return -1, false
 	}
-	idx := int(float32(sz) * (trgSize / curSize))
-	if idx >= sz {
-		idx = sz - 1
+	brks := LineBreaks(sr.Text)
+	fits := func(i int) bool { // text before i fits, not counting trailing spaces
+		if sr.Text[i-1] == SoftHyphen {
+			return sr.RelPos.X+sr.LogPosLR(i)+sr.hyphenWidth(i) <= trgSize
+		}
+		for i > 0 && unicode.IsSpace(sr.Text[i-1]) {
+			i--
+		}
+		return sr.RelPos.X+sr.LogPosLR(i) <= trgSize
 	}
-	stidx := idx
-	for idx > 0 && !unicode.IsSpace(sr.Text[idx-1]) {
-		idx--
+	last := -1 // last break that fits
+	next := -1 // first break that does not fit
+	for i := 1; i < sz; i++ {
+		if brks[i] == BreakProhibited || (hyph == HyphensNone && sr.Text[i-1] == SoftHyphen) {
+			continue
+		}
+		if !fits(i) {
+			next = i
+			break
+		}
+		last = i
 	}
-	csz := sr.RelPos.X + sr.Render[idx].RelPos.X
-	lstgoodi := -1
-	nofit := false
-	if idx > 0 && csz <= trgSize {
-		lstgoodi = idx
-	} else {
-		nofit = true
-		lstgoodi = stidx
-		idx = stidx
+	if hyph == HyphensAuto {
+		st, ed := 0, sz
+		if last > 0 {
+			st = last
+		}
+		if next > 0 {
+			ed = next
+		}
+		if hi := sr.hyphenPosLR(st, ed, trgSize); hi > 0 {
+			return hi, true
+		}
 	}
-	for {
-		if csz > trgSize && !nofit {
-			for idx > 0 {
-				if unicode.IsSpace(sr.Text[idx]) {
-					csz = sr.RelPos.X + sr.Render[idx].RelPos.X
-					if csz <= trgSize {
-						idx++
-						for idx < sz && unicode.IsSpace(sr.Text[idx]) { // break at END of whitespace
-							idx++
-						}
-						return idx
-					}
-				}
-				idx--
-			}
-			fmt.Printf("got to nofit. idx: %v lstgoodi: %v, txt: %v\n", idx, lstgoodi, string(sr.Text))
-			nofit = true // can't find a fit, still -- shouldn't really ever get here..
-			idx = lstgoodi
-		}
-		for idx < sz {
-			if idx == sz-1 && lstgoodi > 0 {
-				return lstgoodi
-			}
-			if unicode.IsSpace(sr.Text[idx]) {
-				csz = sr.RelPos.X + sr.Render[idx].RelPos.X
-				if nofit || csz == trgSize {
-					idx++
-					for idx < sz && unicode.IsSpace(sr.Text[idx]) { // break at END of whitespace
-						idx++
-					}
-					return idx
-				} else if csz < trgSize {
-					lstgoodi = idx + 1
-				} else if lstgoodi > 0 {
-					return lstgoodi
-				} else {
-					break // go back down
-				}
+	if last > 0 {
+		return last, sr.Text[last-1] == SoftHyphen
+	}
+	if ow != OverflowWrapNormal {
+		idx = -1
+		for i := 1; i < sz; i++ {
+			if st, _ := sr.ClusterBounds(i); st != i {
+				continue
+			}
+			if idx > 0 && sr.RelPos.X+sr.LogPosLR(i) > trgSize {
+				break
 			}
-			idx++
+			idx = i // always at least the first cluster
 		}
+		return idx, false
+	}
+	if next > 0 {
+		return next, sr.Text[next-1] == SoftHyphen
+	}
+	return -1, false
+}
+
+// hyphenPosLR returns the index of the last hyphenation point, found by the
+// Hyphenator for HyphenLang, of the word within the given range of runes
+// that fits within trgSize along with a hyphen -- -1 if none
+func (sr *SpanRender) hyphenPosLR(st, ed int, trgSize float32) int {
+	hy := HyphenatorForLang(HyphenLang)
+	if hy == nil {
 		return -1
 	}
-	return lstgoodi
+	isLet := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.Is(unicode.Mn, r)
+	}
+	for st < ed && !isLet(sr.Text[st]) {
+		st++
+	}
+	we := st
+	for we < ed && isLet(sr.Text[we]) {
+		we++
+	}
+	if we < ed && !unicode.IsSpace(sr.Text[we]) && !unicode.IsPunct(sr.Text[we]) {
+		return -1 // word continues with something else, e.g., a number
+	}
+	hi := -1
+	for _, pt := range hy.Hyphenate(sr.Text[st:we]) {
+		i := st + pt
+		if cst, _ := sr.ClusterBounds(i); cst != i {
+			continue
+		}
+		if sr.RelPos.X+sr.LogPosLR(i)+sr.hyphenWidth(i) > trgSize {
+			break
+		}
+		hi = i
+	}
+	return hi
+}
+
+// hyphenWidth returns the width of a hyphen added after the rune before
+// given index
+func (sr *SpanRender) hyphenWidth(idx int) float32 {
+	hd := SpanRender{Render: sr.Render[:idx]}
+	face, _ := hd.LastFont()
+	if face == nil {
+		return 0
+	}
+	TextFontRenderMu.Lock()
+	a, _ := FontLibrary.RuneFace(face, "", '-').GlyphAdvance('-')
+	TextFontRenderMu.Unlock()
+	return FixedToFloat32(a)
+}
+
+// HyphenateLR adds a hyphen to the end of the span, for a line that was
+// wrapped within a word (see FindWrapPosLR): a trailing soft hyphen is
+// displayed as the hyphen, and otherwise one is added, and the rune
+// positions are updated using the given spacing parameters
+func (sr *SpanRender) HyphenateLR(letterSpace, wordSpace, chsz float32, tabSize int) {
+	sz := len(sr.Text)
+	if sz == 0 {
+		return
+	}
+	if sr.Text[sz-1] == SoftHyphen {
+		nt := make([]rune, sz) // don't overwrite shared text
+		copy(nt, sr.Text)
+		nt[sz-1] = '-'
+		sr.Text = nt
+	} else {
+		face, clr := sr.LastFont()
+		last := sr.Render[sz-1]
+		sr.Text = sr.Text[:sz:sz]
+		sr.Render = sr.Render[:sz:sz]
+		sr.AppendRune('-', face, clr, last.BgColor, last.Deco)
+		hr := &(sr.Render[sz])
+		hr.RotRad = last.RotRad
+		hr.ScaleX = last.ScaleX
+		if sr.Levels != nil {
+			sr.Levels = append(sr.Levels[:sz:sz], sr.Levels[sz-1])
+		}
+	}
+	sr.SetRunePosLR(letterSpace, wordSpace, chsz, tabSize)
 }
 
 // ZeroPos ensures that the positions start at 0, for LR direction
@@ -494,16 +660,176 @@ func (sr *SpanRender) ZeroPosLR() {
 	if sz == 0 {
 		return
 	}
-	sx := sr.Render[0].RelPos.X
+	sx := sr.LogPosLR(0)
 	if sx == 0 {
 		return
 	}
 	for i, _ := range sr.Render {
 		sr.Render[i].RelPos.X -= sx
 	}
+	for i := range sr.LogPos {
+		sr.LogPos[i] -= sx
+	}
 	sr.LastPos.X -= sx
 }
 
+// AdjustLengthLR adjusts the positions of the runes so that the overall
+// length from the first rune to LastPos matches the given target length, as
+// in the SVG textLength property, for LR direction.  If glyphs is true
+// (lengthAdjust = spacingAndGlyphs) then the glyphs themselves are also
+// stretched or compressed horizontally, otherwise only the spacing between
+// them is adjusted.  RelPos positions must have already been set.
+func (sr *SpanRender) AdjustLengthLR(trgLen float32, glyphs bool) {
+	sz := len(sr.Text)
+	if sz == 0 || trgLen <= 0 {
+		return
+	}
+	sx := sr.LogPosLR(0)
+	curLen := sr.LastPos.X - sx
+	if curLen <= 0 {
+		return
+	}
+	pos := func(i int) *float32 { // logical position, for bidi
+		if sr.LogPos != nil {
+			return &sr.LogPos[i]
+		}
+		return &sr.Render[i].RelPos.X
+	}
+	if glyphs {
+		sc := trgLen / curLen
+		for i := range sr.Render {
+			rr := &(sr.Render[i])
+			px := pos(i)
+			*px = sx + (*px-sx)*sc
+			if rr.ScaleX == 0 {
+				rr.ScaleX = sc
+			} else {
+				rr.ScaleX *= sc
+			}
+		}
+	} else {
+		if sz < 2 {
+			return
+		}
+		extra := (trgLen - curLen) / float32(sz-1)
+		for i := range sr.Render {
+			*pos(i) += float32(i) * extra
+		}
+	}
+	sr.LastPos.X = sx + trgLen
+	sr.bidiPosLR()
+}
+
+// EllipsisRune is the rune that ends text truncated by EllipsisLR
+var EllipsisRune = '…'
+
+// EllipsisLR truncates the span if it is wider than given width, so that it
+// ends with an ellipsis (EllipsisRune) within that width, for the
+// text-overflow: ellipsis style, for LR direction.  Truncation happens at a
+// character boundary, with any trailing space removed, and the rune
+// positions are then re-done with the given spacing parameters (see
+// SetRunePosLR).  Returns true if truncated.
+func (sr *SpanRender) EllipsisLR(width, letterSpace, wordSpace, chsz float32, tabSize int) bool {
+	sz := len(sr.Text)
+	if sz == 0 || width <= 0 || sr.SizeHV().X <= width {
+		return false
+	}
+	hd := SpanRender{Render: sr.Render[:1]} // first rune always has face and color
+	face, clr := hd.LastFont()
+	TextFontRenderMu.Lock()
+	ea, _ := FontLibrary.RuneFace(face, "", EllipsisRune).GlyphAdvance(EllipsisRune)
+	TextFontRenderMu.Unlock()
+	ew := FixedToFloat32(ea) + letterSpace
+	sx := sr.LogPosLR(0)
+	idx := 0
+	for idx < sz && sr.LogPosLR(idx)+sr.Render[idx].Size.X-sx+ew <= width {
+		idx++
+	}
+	for idx > 0 && idx < sz && (sr.Render[idx].Ligated || unicode.Is(unicode.Mn, sr.Text[idx])) {
+		idx-- // keep ligatures and combining marks with their base
+	}
+	for idx > 0 && unicode.IsSpace(sr.Text[idx-1]) {
+		idx--
+	}
+	last := sr.Render[0]
+	if idx > 0 {
+		hd.Render = sr.Render[:idx]
+		face, clr = hd.LastFont()
+		last = sr.Render[idx-1]
+	}
+	sr.Text = sr.Text[:idx:idx] // don't overwrite shared text
+	sr.Render = sr.Render[:idx:idx]
+	sr.Levels = nil
+	sr.LogPos = nil
+	sr.AppendRune(EllipsisRune, face, clr, last.BgColor, last.Deco)
+	er := &(sr.Render[idx])
+	er.RotRad = last.RotRad
+	er.ScaleX = last.ScaleX
+	sr.SetRunePosLR(letterSpace, wordSpace, chsz, tabSize)
+	return true
+}
+
+// JustifyLR expands the spacing between the runes so that the span fills
+// the given width, for full justification of wrapped text, for LR
+// direction: the extra space is added between words, or between all
+// characters if interChar is true or there are no spaces in the text --
+// trailing spaces are not counted, and ligatures and combining marks are
+// kept together.  RelPos positions must have already been set.
+func (sr *SpanRender) JustifyLR(width float32, interChar bool) {
+	sz := len(sr.Text)
+	end := sz // after last non-space rune
+	for end > 0 && unicode.IsSpace(sr.Text[end-1]) {
+		end--
+	}
+	if end < 2 {
+		return
+	}
+	sx := sr.LogPosLR(0)
+	extra := width - (sr.LogPosLR(end-1) + sr.Render[end-1].Size.X - sx)
+	if extra <= 0 {
+		return
+	}
+	isGap := func(i int) bool { // extra space goes before rune i
+		if interChar {
+			return !sr.Render[i].Ligated && !unicode.Is(unicode.Mn, sr.Text[i])
+		}
+		return unicode.IsSpace(sr.Text[i-1]) && !unicode.IsSpace(sr.Text[i])
+	}
+	ngaps := 0
+	for i := 1; i < end; i++ {
+		if isGap(i) {
+			ngaps++
+		}
+	}
+	if ngaps == 0 && !interChar {
+		interChar = true
+		for i := 1; i < end; i++ {
+			if isGap(i) {
+				ngaps++
+			}
+		}
+	}
+	if ngaps == 0 {
+		return
+	}
+	pos := func(i int) *float32 { // logical position, for bidi
+		if sr.LogPos != nil {
+			return &sr.LogPos[i]
+		}
+		return &sr.Render[i].RelPos.X
+	}
+	gap := extra / float32(ngaps)
+	var shift float32
+	for i := 1; i < sz; i++ {
+		if i < end && isGap(i) {
+			shift += gap
+		}
+		*pos(i) += shift
+	}
+	sr.LastPos.X += shift
+	sr.bidiPosLR()
+}
+
 // TrimSpaceLeft trims leading space elements from span, and updates the
 // relative positions accordingly, for LR direction
 func (sr *SpanRender) TrimSpaceLeftLR() {
@@ -512,6 +838,10 @@ func (sr *SpanRender) TrimSpaceLeftLR() {
 		if unicode.IsSpace(sr.Text[0]) {
 			sr.Text = sr.Text[1:]
 			sr.Render = sr.Render[1:]
+			if sr.Levels != nil {
+				sr.Levels = sr.Levels[1:]
+				sr.LogPos = sr.LogPos[1:]
+			}
 			if len(sr.Render) > 0 {
 				if sr.Render[0].Face == nil {
 					sr.Render[0].Face = srr0.Face
@@ -525,6 +855,7 @@ func (sr *SpanRender) TrimSpaceLeftLR() {
 		}
 	}
 	sr.ZeroPosLR()
+	sr.bidiPosLR()
 }
 
 // TrimSpaceRight trims trailing space elements from span, and updates the
@@ -535,9 +866,13 @@ func (sr *SpanRender) TrimSpaceRightLR() {
 		if unicode.IsSpace(sr.Text[lidx]) {
 			sr.Text = sr.Text[:lidx]
 			sr.Render = sr.Render[:lidx]
+			if sr.Levels != nil {
+				sr.Levels = sr.Levels[:lidx]
+				sr.LogPos = sr.LogPos[:lidx]
+			}
 			lidx--
 			if lidx >= 0 {
-				sr.LastPos.X = sr.Render[lidx].RelPosAfterLR()
+				sr.LastPos.X = sr.LogPosLR(lidx) + sr.Render[lidx].Size.X
 			} else {
 				sr.LastPos.X = sr.Render[0].Size.X
 			}
@@ -545,6 +880,7 @@ func (sr *SpanRender) TrimSpaceRightLR() {
 			break
 		}
 	}
+	sr.bidiPosLR()
 }
 
 // TrimSpace trims leading and trailing space elements from span, and updates
@@ -564,7 +900,15 @@ func (sr *SpanRender) SplitAtLR(idx int) *SpanRender {
 	nsr := SpanRender{Text: sr.Text[idx:], Render: sr.Render[idx:], Dir: sr.Dir, HasDeco: sr.HasDeco}
 	sr.Text = sr.Text[:idx]
 	sr.Render = sr.Render[:idx]
-	sr.LastPos.X = sr.Render[idx-1].RelPosAfterLR()
+	if sr.Levels != nil { // keep levels from whole paragraph, reorder each line
+		nsr.Levels = sr.Levels[idx:]
+		sr.Levels = sr.Levels[:idx:idx]
+		sr.LogPos = sr.LogPos[:idx]
+		sr.LastPos.X = sr.LogPos[idx-1] + sr.Render[idx-1].Size.X
+		sr.bidiPosLR()
+	} else {
+		sr.LastPos.X = sr.Render[idx-1].RelPosAfterLR()
+	}
 	// sr.TrimSpaceLR()
 	// nsr.TrimSpaceLeftLR() // don't trim right!
 	// go back and find latest face and color -- each sr must start with valid one
@@ -671,10 +1015,96 @@ var URLHandler = func(url string) bool {
 // TextRender contains one or more SpanRender elements, typically with each
 // representing a separate line of text (but they can be anything).
 type TextRender struct {
-	Spans []SpanRender
-	Size  Vec2D          `desc:"last size of overall rendered text"`
-	Dir   TextDirections `desc:"where relevant, this is the (default, dominant) text direction for the span"`
-	Links []TextLink     `desc:"hyperlinks within rendered text"`
+	Spans   []SpanRender
+	Size    Vec2D          `desc:"last size of overall rendered text"`
+	Dir     TextDirections `desc:"where relevant, this is the (default, dominant) text direction for the span"`
+	Links   []TextLink     `desc:"hyperlinks within rendered text"`
+	Shadow  ShadowStyle    `desc:"shadow to render behind the text, from the text-shadow style -- set in SetString, SetRunes and LayoutStdLR"`
+	Cache   bool           `desc:"cache the results of SetString, SetRunes, SetHTML and LayoutStdLR, so that calling them again with the same text, styles and size does nothing -- only use this if the spans are not otherwise modified after these calls (aside from EllipsisLR)"`
+	setKey  *textSetKey    // arguments of last Set method, for Cache
+	setCSS  ki.Props       // css props of last SetHTML, for Cache
+	layKey  *textLayKey    // arguments of last LayoutStdLR, for Cache
+	laySize Vec2D          // result of last LayoutStdLR, for Cache
+	pending bool           // spans are from a cached Set, which has had layKey layout done on them since
+}
+
+// kinds of Set methods, for textSetKey
+const (
+	textSetString = iota + 1
+	textSetRunes
+	textSetHTMLNoPre
+	textSetHTMLPre
+)
+
+// textSetKey records the arguments of a Set method of TextRender, for Cache
+type textSetKey struct {
+	kind   int
+	str    string
+	font   FontStyle
+	txt    TextStyle
+	ctxt   units.Context
+	css    uintptr // identity of css props
+	noBG   bool
+	rot    float32
+	scalex float32
+}
+
+// textLayKey records the arguments of LayoutStdLR, for Cache
+type textLayKey struct {
+	txt  TextStyle
+	font FontStyle
+	ctxt units.Context
+	size Vec2D
+}
+
+// cachedSet returns true if a Set method with the arguments in given key
+// can be skipped, because the spans are already the result of it, with
+// Cache on -- otherwise the key is recorded for next time
+func (tr *TextRender) cachedSet(key *textSetKey, css ki.Props) bool {
+	if !tr.Cache {
+		tr.setKey = nil
+		return false
+	}
+	if tr.setKey != nil && *tr.setKey == *key {
+		tr.pending = tr.layKey != nil
+		return true
+	}
+	tr.setKey = key
+	tr.setCSS = css
+	tr.layKey = nil
+	tr.pending = false
+	return false
+}
+
+// redoSet re-does the last Set method from its recorded arguments, when a
+// cached result has since been laid out differently
+func (tr *TextRender) redoSet() {
+	k := tr.setKey
+	tr.setKey = nil
+	tr.layKey = nil
+	tr.pending = false
+	if k == nil {
+		return
+	}
+	font, txt, ctxt := k.font, k.txt, k.ctxt
+	switch k.kind {
+	case textSetString:
+		tr.SetString(k.str, &font, &ctxt, &txt, k.noBG, k.rot, k.scalex)
+	case textSetRunes:
+		tr.SetRunes([]rune(k.str), &font, &ctxt, &txt, k.noBG, k.rot, k.scalex)
+	case textSetHTMLNoPre:
+		tr.SetHTMLNoPre([]byte(k.str), &font, &txt, &ctxt, tr.setCSS)
+	case textSetHTMLPre:
+		tr.SetHTMLPre([]byte(k.str), &font, &txt, &ctxt, tr.setCSS)
+	}
+}
+
+// cssIdent returns the identity of given css props, for textSetKey
+func cssIdent(css ki.Props) uintptr {
+	if css == nil {
+		return 0
+	}
+	return reflect.ValueOf(css).Pointer()
 }
 
 // InsertSpan inserts a new span at given index
@@ -693,10 +1123,14 @@ func (tr *TextRender) InsertSpan(at int, ns *SpanRender) {
 // absolute position offset (specifying position of text baseline) -- any
 // applicable transforms (aside from the char-specific rotation in Render)
 // must be applied in advance in computing the relative positions of the
-// runes, and the overall font size, etc.  todo: does not currently support
-// stroking, only filling of text -- probably need to grab path from font and
-// use paint rendering for stroking
+// runes, and the overall font size, etc.  Any Shadow is rendered first,
+// behind the text.  todo: does not currently support stroking, only filling
+// of text -- probably need to grab path from font and use paint rendering
+// for stroking
 func (tr *TextRender) Render(rs *RenderState, pos Vec2D) {
+	if tr.pending { // cached spans were laid out, but no layout requested this time
+		tr.redoSet()
+	}
 	pr := prof.Start("RenderText")
 	defer pr.End()
 
@@ -710,12 +1144,179 @@ func (tr *TextRender) Render(rs *RenderState, pos Vec2D) {
 	TextFontRenderMu.Lock()
 	defer TextFontRenderMu.Unlock()
 
+	if tr.HasShadow() {
+		tr.RenderShadow(rs, pos)
+	}
+	tr.renderSpans(rs, pos, nil)
+}
+
+// HasShadow returns true if the text has a Shadow to render
+func (tr *TextRender) HasShadow() bool {
+	sh := &tr.Shadow
+	return sh.HOffset.Dots != 0 || sh.VOffset.Dots != 0 || sh.Blur.Dots > 0
+}
+
+// RenderShadow renders the Shadow of the text, for Render: the glyphs are
+// rendered in the shadow color (or the text color if nil) into a scratch
+// image, which is blurred according to the blur radius (approximating a
+// gaussian with a standard deviation of half the radius, as in css), and
+// drawn at the shadow offset, and sent to any rs.Vector.  Background colors and decorations do not have
+// a shadow.  TextFontRenderMu must be locked.
+func (tr *TextRender) RenderShadow(rs *RenderState, pos Vec2D) {
+	sh := &tr.Shadow
+	var clr color.Color = &sh.Color
+	if sh.Color.IsNil() {
+		for _, sr := range tr.Spans {
+			if sr.IsValid() == nil {
+				clr = sr.Render[0].Color
+				break
+			}
+		}
+	}
+	rad := int(math32.Ceil(sh.Blur.Dots))
+	if rad < 0 {
+		rad = 0
+	}
+	off := Vec2D{sh.HOffset.Dots, sh.VOffset.Dots}
+	bb := image.ZR // bounds of glyphs, relative to pos
+	for _, sr := range tr.Spans {
+		if sr.IsValid() != nil {
+			continue
+		}
+		curFace := sr.Render[0].Face
+		for i := range sr.Render {
+			rr := &(sr.Render[i])
+			curFace = rr.CurFace(curFace)
+			m := curFace.Metrics()
+			rp := sr.RelPos.Add(rr.RelPos)
+			rb := image.Rect(int(math32.Floor(rp.X)), int(math32.Floor(rp.Y-FixedToFloat32(m.Ascent))),
+				int(math32.Ceil(rp.X+rr.Size.X)), int(math32.Ceil(rp.Y+FixedToFloat32(m.Descent))))
+			bb = bb.Union(rb.Inset(-int(m.Height.Ceil() / 2))) // allow for overhang, rotation
+		}
+	}
+	if bb.Empty() {
+		return
+	}
+	bb = bb.Inset(-2 * rad)
+	spos := pos.Add(off)
+	org := spos.ToPointFloor()
+	dst := bb.Add(org).Intersect(rs.Bounds)
+	if dst.Empty() {
+		return
+	}
+	img, srs := textShadowScratch(bb.Size())
+	tr.renderSpans(srs, spos.Sub(NewVec2DFmPoint(org.Add(bb.Min))), clr)
+	BoxBlurRGBA(img, rad/2)
+	sp := dst.Min.Sub(org.Add(bb.Min))
+	draw.Draw(rs.Image, dst, img, sp, draw.Over)
+	if rs.Vector != nil { // the recording keeps the image, so it gets a copy
+		vimg := image.NewRGBA(image.Rectangle{Max: dst.Size()})
+		draw.Draw(vimg, vimg.Bounds(), img, sp, draw.Src)
+		rs.Vector.DrawImage(vimg, Translate2D(float32(dst.Min.X), float32(dst.Min.Y)), rs.Bounds)
+	}
+}
+
+// textShadowImg and textShadowRS are the scratch image and RenderState that
+// text shadows are rendered into, re-used for all shadows, and grown as
+// needed -- protected by TextFontRenderMu
+var (
+	textShadowImg *image.RGBA
+	textShadowRS  RenderState
+)
+
+// textShadowScratch returns the cleared region of given size of the scratch
+// image for text shadows, and its RenderState, bounded to the region --
+// TextFontRenderMu must be locked
+func textShadowScratch(sz image.Point) (*image.RGBA, *RenderState) {
+	if textShadowImg == nil || textShadowImg.Rect.Dx() < sz.X || textShadowImg.Rect.Dy() < sz.Y {
+		w, h := sz.X, sz.Y
+		if textShadowImg != nil {
+			w = ints.MaxInt(w, textShadowImg.Rect.Dx())
+			h = ints.MaxInt(h, textShadowImg.Rect.Dy())
+		}
+		textShadowImg = image.NewRGBA(image.Rect(0, 0, w, h))
+		textShadowRS = RenderState{}
+		textShadowRS.Init(w, h, textShadowImg)
+	}
+	img := textShadowImg.SubImage(image.Rectangle{Max: sz}).(*image.RGBA)
+	for y := 0; y < sz.Y; y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+4*sz.X]
+		for i := range row {
+			row[i] = 0
+		}
+	}
+	textShadowRS.Bounds = img.Bounds()
+	return img, &textShadowRS
+}
+
+// BoxBlurRGBA blurs the image in place with three passes of a box filter of
+// given radius in each direction, which approximates a gaussian blur with a
+// standard deviation of about the radius
+func BoxBlurRGBA(img *image.RGBA, rad int) {
+	if rad <= 0 {
+		return
+	}
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	n := w
+	if h > n {
+		n = h
+	}
+	buf := make([]uint8, 4*n)
+	for pass := 0; pass < 3; pass++ {
+		for y := 0; y < h; y++ {
+			boxBlurLine(img.Pix[y*img.Stride:], 4, w, rad, buf)
+		}
+		for x := 0; x < w; x++ {
+			boxBlurLine(img.Pix[4*x:], img.Stride, h, rad, buf)
+		}
+	}
+}
+
+// boxBlurLine does one box filter pass over a line of n RGBA pixels starting
+// at pix with given stride between pixels, using buf for the source values
+func boxBlurLine(pix []uint8, stride, n, rad int, buf []uint8) {
+	for i := 0; i < n; i++ {
+		copy(buf[4*i:4*i+4], pix[i*stride:i*stride+4])
+	}
+	win := 2*rad + 1
+	var sum [4]int
+	for i := -rad; i <= rad; i++ {
+		if i >= 0 && i < n {
+			for c := 0; c < 4; c++ {
+				sum[c] += int(buf[4*i+c])
+			}
+		}
+	}
+	for i := 0; i < n; i++ {
+		for c := 0; c < 4; c++ {
+			pix[i*stride+c] = uint8((sum[c] + win/2) / win)
+		}
+		if o := i - rad; o >= 0 {
+			for c := 0; c < 4; c++ {
+				sum[c] -= int(buf[4*o+c])
+			}
+		}
+		if o := i + rad + 1; o < n {
+			for c := 0; c < 4; c++ {
+				sum[c] += int(buf[4*o+c])
+			}
+		}
+	}
+}
+
+// renderSpans renders the glyphs of all the spans for Render -- if clr is
+// non-nil, all glyphs are rendered in that color, without backgrounds or
+// decorations (e.g., for the shadow)
+func (tr *TextRender) renderSpans(rs *RenderState, pos Vec2D, clr color.Color) {
 	for _, sr := range tr.Spans {
 		if sr.IsValid() != nil {
 			continue
 		}
 		curFace := sr.Render[0].Face
 		curColor := sr.Render[0].Color
+		if clr != nil {
+			curColor = clr
+		}
 		tpos := pos.Add(sr.RelPos)
 
 		d := &font.Drawer{
@@ -725,19 +1326,21 @@ func (tr *TextRender) Render(rs *RenderState, pos Vec2D) {
 		}
 
 		// todo: cache flags if these are actually needed
-		if bitflag.Has32(int32(sr.HasDeco), int(DecoBgColor)) {
-			sr.RenderBg(rs, tpos)
-		}
-		if bitflag.HasAny32(int32(sr.HasDeco), int(DecoUnderline), int(DecoDottedUnderline)) {
-			sr.RenderUnderline(rs, tpos)
-		}
-		if bitflag.Has32(int32(sr.HasDeco), int(DecoOverline)) {
-			sr.RenderLine(rs, tpos, DecoOverline, 1.1)
+		if clr == nil {
+			if bitflag.Has32(int32(sr.HasDeco), int(DecoBgColor)) {
+				sr.RenderBg(rs, tpos)
+			}
+			if bitflag.HasAny32(int32(sr.HasDeco), int(DecoUnderline), int(DecoDottedUnderline)) {
+				sr.RenderUnderline(rs, tpos)
+			}
+			if bitflag.Has32(int32(sr.HasDeco), int(DecoOverline)) {
+				sr.RenderLine(rs, tpos, DecoOverline, 1.1)
+			}
 		}
 
 		for i, r := range sr.Text {
 			rr := &(sr.Render[i])
-			if rr.Color != nil {
+			if rr.Color != nil && clr == nil {
 				curColor = rr.Color
 				d.Src = image.NewUniform(curColor)
 			}
@@ -745,6 +1348,12 @@ func (tr *TextRender) Render(rs *RenderState, pos Vec2D) {
 			if !unicode.IsPrint(r) {
 				continue
 			}
+			if rr.Ligated {
+				continue
+			}
+			if sr.Levels != nil && sr.Levels[i]&1 == 1 {
+				r = BidiMirror(r)
+			}
 			dsc32 := FixedToFloat32(curFace.Metrics().Descent)
 			rp := tpos.Add(rr.RelPos)
 			scx := float32(1)
@@ -758,6 +1367,18 @@ func (tr *TextRender) Render(rs *RenderState, pos Vec2D) {
 				int(math32.Ceil(ur.X)) < rs.Bounds.Min.X || int(math32.Ceil(ll.Y)) < rs.Bounds.Min.Y {
 				continue
 			}
+			if rr.Glyph != 0 && RenderGlyph(rs, curFace, rr.Glyph, rp, rr.RotRad, rr.ScaleX, curColor) {
+				continue
+			}
+			if rs.Vector != nil {
+				rs.Vector.DrawGlyph(curFace, r, rp, rr.RotRad, rr.ScaleX, curColor, rs.Bounds)
+			}
+			if TextAntialias != AntialiasGray && rr.RotRad == 0 && (rr.ScaleX == 0 || rr.ScaleX == 1) && DrawGlyphSubpixel(rs.Image, rs.Bounds, curFace, r, rp.Fixed(), curColor) {
+				continue
+			}
+			if GlyphCacheOn && rr.RotRad == 0 && (rr.ScaleX == 0 || rr.ScaleX == 1) && TheGlyphCache.DrawGlyph(rs.Image, rs.Bounds, curFace, r, rp.Fixed(), curColor) {
+				continue
+			}
 			d.Face = curFace
 			d.Dot = rp.Fixed()
 			dr, mask, maskp, _, ok := d.Face.Glyph(d.Dot, r)
@@ -783,7 +1404,7 @@ func (tr *TextRender) Render(rs *RenderState, pos Vec2D) {
 				})
 			}
 		}
-		if bitflag.Has32(int32(sr.HasDeco), int(DecoLineThrough)) {
+		if clr == nil && bitflag.Has32(int32(sr.HasDeco), int(DecoLineThrough)) {
 			sr.RenderLine(rs, tpos, DecoLineThrough, 0.25)
 		}
 	}
@@ -989,12 +1610,18 @@ func (tr *TextRender) RenderTopPos(rs *RenderState, tpos Vec2D) {
 // valid Face is available.  noBG ignores any BgColor in font style, and never
 // renders background color
 func (tr *TextRender) SetString(str string, fontSty *FontStyle, ctxt *units.Context, txtSty *TextStyle, noBG bool, rot, scalex float32) {
+	if tr.cachedSet(&textSetKey{kind: textSetString, str: str, font: *fontSty, txt: *txtSty, ctxt: *ctxt, noBG: noBG, rot: rot, scalex: scalex}, nil) {
+		return
+	}
 	if len(tr.Spans) != 1 {
 		tr.Spans = make([]SpanRender, 1)
 	}
 	tr.Links = nil
+	tr.Shadow = txtSty.Shadow
 	sr := &(tr.Spans[0])
 	sr.SetString(str, fontSty, ctxt, noBG, rot, scalex)
+	sr.Text = txtSty.Transform.TransformRunes(sr.Text, 0)
+	sr.Dir = txtSty.Direction
 	sr.SetRunePosLR(txtSty.LetterSpacing.Dots, txtSty.WordSpacing.Dots, fontSty.Ch, txtSty.TabSize)
 	ssz := sr.SizeHV()
 	vht := fontSty.Face.Metrics().Height
@@ -1010,18 +1637,52 @@ func (tr *TextRender) SetString(str string, fontSty *FontStyle, ctxt *units.Cont
 // valid Face is available.  noBG ignores any BgColor in font style, and never
 // renders background color
 func (tr *TextRender) SetRunes(str []rune, fontSty *FontStyle, ctxt *units.Context, txtSty *TextStyle, noBG bool, rot, scalex float32) {
+	if tr.cachedSet(&textSetKey{kind: textSetRunes, str: string(str), font: *fontSty, txt: *txtSty, ctxt: *ctxt, noBG: noBG, rot: rot, scalex: scalex}, nil) {
+		return
+	}
 	if len(tr.Spans) != 1 {
 		tr.Spans = make([]SpanRender, 1)
 	}
 	tr.Links = nil
+	tr.Shadow = txtSty.Shadow
 	sr := &(tr.Spans[0])
 	sr.SetRunes(str, fontSty, ctxt, noBG, rot, scalex)
+	sr.Text = txtSty.Transform.TransformRunes(sr.Text, 0)
+	sr.Dir = txtSty.Direction
 	sr.SetRunePosLR(txtSty.LetterSpacing.Dots, txtSty.WordSpacing.Dots, fontSty.Ch, txtSty.TabSize)
 	ssz := sr.SizeHV()
 	vht := fontSty.Face.Metrics().Height
 	tr.Size = Vec2D{ssz.X, FixedToFloat32(vht)}
 }
 
+// EllipsisLR truncates any spans that are wider than the given width so
+// that they end with an ellipsis, as in the text-overflow: ellipsis style,
+// using the spacing parameters from given styles, for LR direction (see
+// SpanRender.EllipsisLR) -- Size is updated, and true is returned if any
+// span was truncated
+func (tr *TextRender) EllipsisLR(width float32, txtSty *TextStyle, fontSty *FontStyle) bool {
+	trunc := false
+	maxw := float32(0)
+	for si := range tr.Spans {
+		sr := &(tr.Spans[si])
+		if sr.IsValid() != nil {
+			continue
+		}
+		if sr.EllipsisLR(width-sr.RelPos.X, txtSty.LetterSpacing.Dots, txtSty.WordSpacing.Dots, fontSty.Ch, txtSty.TabSize) {
+			trunc = true
+		}
+		if w := sr.SizeHV().X + sr.RelPos.X; w > maxw {
+			maxw = w
+		}
+	}
+	if trunc {
+		tr.Size.X = maxw
+		tr.setKey = nil // can't cache
+		tr.pending = false
+	}
+	return trunc
+}
+
 // SetHTMLSimpleTag sets the styling parameters for simple html style tags
 // that only require updating the given font spec values -- returns true if handled
 // https://www.w3schools.com/cssref/css_default_values.asp
@@ -1124,6 +1785,9 @@ func (tr *TextRender) SetHTMLNoPre(str []byte, font *FontStyle, txtSty *TextStyl
 	if sz == 0 {
 		return
 	}
+	if tr.cachedSet(&textSetKey{kind: textSetHTMLNoPre, str: string(str), font: *font, txt: *txtSty, ctxt: *ctxt, css: cssIdent(cssAgg)}, cssAgg) {
+		return
+	}
 	tr.Spans = make([]SpanRender, 1)
 	tr.Links = nil
 	curSp := &(tr.Spans[0])
@@ -1282,6 +1946,9 @@ func (tr *TextRender) SetHTMLNoPre(str []byte, font *FontStyle, txtSty *TextStyl
 // including LF \n etc, except in WhiteSpacePreLine case which only preserves LF's
 func (tr *TextRender) SetHTMLPre(str []byte, font *FontStyle, txtSty *TextStyle, ctxt *units.Context, cssAgg ki.Props) {
 	errstr := "gi.TextRender SetHTMLPre"
+	if tr.cachedSet(&textSetKey{kind: textSetHTMLPre, str: string(str), font: *font, txt: *txtSty, ctxt: *ctxt, css: cssIdent(cssAgg)}, cssAgg) {
+		return
+	}
 
 	sz := len(str)
 	tr.Spans = make([]SpanRender, 1)
@@ -1578,6 +2245,154 @@ func (tx *TextRender) RuneEndPos(idx int) (pos Vec2D, si, ri int, ok bool) {
 	return Vec2DZero, -1, -1, false
 }
 
+// PosToRuneIdx returns the absolute rune index (as in RuneSpanPos) of the
+// text cursor position closest to given position, which is relative to the
+// position the text is rendered at (as for RuneRelPos): the line is the first
+// one whose descent is below the position (or the last line), and the index
+// can be the length of the line, for after its last rune.
+func (tx *TextRender) PosToRuneIdx(pos Vec2D) int {
+	idx := 0
+	lsi := -1
+	lidx := 0
+	for si := range tx.Spans {
+		sr := &tx.Spans[si]
+		if sr.IsValid() != nil {
+			idx += len(sr.Render)
+			continue
+		}
+		lsi = si
+		lidx = idx
+		dsc := FixedToFloat32(sr.Render[0].Face.Metrics().Descent)
+		if pos.Y <= sr.RelPos.Y+dsc {
+			break
+		}
+		idx += len(sr.Render)
+	}
+	if lsi < 0 {
+		return 0
+	}
+	sr := &tx.Spans[lsi]
+	return lidx + sr.CaretIdxLR(pos.X-sr.RelPos.X)
+}
+
+// rangeSpans calls given function for each span with runes within the given
+// range of absolute rune indexes (as in RuneSpanPos), with the range of rune
+// indexes within the span, and whether the range continues past the span
+func (tx *TextRender) rangeSpans(st, ed int, fun func(sr *SpanRender, sst, sed int, more bool)) {
+	idx := 0
+	for si := range tx.Spans {
+		sr := &tx.Spans[si]
+		sz := len(sr.Render)
+		if idx+sz > st && sz > 0 {
+			sst := ints.MaxInt(st-idx, 0)
+			sed := ints.MinInt(ed-idx, sz)
+			if sed <= sst {
+				break
+			}
+			fun(sr, sst, sed, ed > idx+sz)
+		}
+		idx += sz
+		if idx >= ed {
+			break
+		}
+	}
+}
+
+// RangeText returns the text between the given absolute rune indexes (as in
+// RuneSpanPos), with a newline between lines, unless the line ends in a space
+// (where it was wrapped)
+func (tx *TextRender) RangeText(st, ed int) string {
+	var b strings.Builder
+	tx.rangeSpans(st, ed, func(sr *SpanRender, sst, sed int, more bool) {
+		b.WriteString(string(sr.Text[sst:sed]))
+		if more && !unicode.IsSpace(sr.Text[len(sr.Text)-1]) {
+			b.WriteByte('\n')
+		}
+	})
+	return b.String()
+}
+
+// RangeHTML returns the text between the given absolute rune indexes (as in
+// RuneSpanPos) as html, with the font weight and style, colors and
+// decorations of the rendered text expressed as styled spans, and line breaks
+// as in RangeText
+func (tx *TextRender) RangeHTML(st, ed int) string {
+	var b strings.Builder
+	tx.rangeSpans(st, ed, func(sr *SpanRender, sst, sed int, more bool) {
+		curFace := sr.Render[0].Face
+		curColor := sr.Render[0].Color
+		cursty := ""
+		for i := 0; i < sed; i++ {
+			rr := &(sr.Render[i])
+			curFace = rr.CurFace(curFace)
+			curColor = rr.CurColor(curColor)
+			if i < sst {
+				continue
+			}
+			sty := htmlRuneStyle(curFace, curColor, rr)
+			if i == sst || sty != cursty {
+				if i > sst {
+					b.WriteString("</span>")
+				}
+				b.WriteString(`<span style="` + sty + `">`)
+				cursty = sty
+			}
+			b.WriteString(html.EscapeString(string(sr.Text[i])))
+		}
+		b.WriteString("</span>")
+		if more && !unicode.IsSpace(sr.Text[len(sr.Text)-1]) {
+			b.WriteString("<br>\n")
+		}
+	})
+	return b.String()
+}
+
+// htmlRuneStyle returns the css style for a rune rendered with given face
+// and color and render info, for RangeHTML
+func htmlRuneStyle(face font.Face, clr color.Color, rr *RuneRender) string {
+	var sty []string
+	if fnm, _, ok := FontLibrary.FaceInfo(face); ok {
+		fnm = strings.ToLower(fnm)
+		if strings.Contains(fnm, "bold") || strings.Contains(fnm, "black") || strings.Contains(fnm, "heavy") {
+			sty = append(sty, "font-weight: bold")
+		}
+		if strings.Contains(fnm, "italic") || strings.Contains(fnm, "oblique") {
+			sty = append(sty, "font-style: italic")
+		}
+	}
+	hex := func(c color.Color) string {
+		rc := color.NRGBAModel.Convert(c).(color.NRGBA)
+		return fmt.Sprintf("#%02x%02x%02x", rc.R, rc.G, rc.B)
+	}
+	if clr != nil {
+		sty = append(sty, "color: "+hex(clr))
+	}
+	if rr.BgColor != nil {
+		if _, _, _, a := rr.BgColor.RGBA(); a > 0 {
+			sty = append(sty, "background-color: "+hex(rr.BgColor))
+		}
+	}
+	var decos []string
+	if bitflag.HasAny32(int32(rr.Deco), int(DecoUnderline), int(DecoDottedUnderline)) {
+		decos = append(decos, "underline")
+	}
+	if bitflag.Has32(int32(rr.Deco), int(DecoOverline)) {
+		decos = append(decos, "overline")
+	}
+	if bitflag.Has32(int32(rr.Deco), int(DecoLineThrough)) {
+		decos = append(decos, "line-through")
+	}
+	if len(decos) > 0 {
+		sty = append(sty, "text-decoration: "+strings.Join(decos, " "))
+	}
+	if bitflag.Has32(int32(rr.Deco), int(DecoSuper)) {
+		sty = append(sty, "vertical-align: super")
+	} else if bitflag.Has32(int32(rr.Deco), int(DecoSub)) {
+		sty = append(sty, "vertical-align: sub")
+	}
+	return strings.Join(sty, "; ")
+}
+
 //////////////////////////////////////////////////////////////////////////////////
 //  TextStyle
 
@@ -1585,27 +2400,30 @@ func (tx *TextRender) RuneEndPos(idx int) (pos Vec2D, si, ri int, ok bool) {
 // FontStyle contains all the lower-level text rendering info used in SVG --
 // most of these are inherited
 type TextStyle struct {
-	Align            Align          `xml:"text-align" inherit:"true" desc:"how to align text, horizontally"`
-	AlignV           Align          `xml:"-" json:"-" desc:"vertical alignment of text -- copied from layout style AlignV"`
-	Anchor           TextAnchors    `xml:"text-anchor" inherit:"true" desc:"for svg rendering only: determines the alignment relative to text position coordinate: for RTL start is right, not left, and start is top for TB"`
-	LetterSpacing    units.Value    `xml:"letter-spacing" desc:"spacing between characters and lines"`
-	WordSpacing      units.Value    `xml:"word-spacing" inherit:"true" desc:"extra space to add between words"`
-	LineHeight       float32        `xml:"line-height" inherit:"true" desc:"specified height of a line of text, in proportion to default font height, 0 = 1 = normal (todo: specific values such as pixels are not supported, in order to properly support percentage) -- text is centered within the overall lineheight"`
-	WhiteSpace       WhiteSpaces    `xml:"white-space" inherit:"true" desc:"specifies how white space is processed, and how lines are wrapped"`
-	UnicodeBidi      UnicodeBidi    `xml:"unicode-bidi" inherit:"true" desc:"determines how to treat unicode bidirectional information"`
-	Direction        TextDirections `xml:"direction" inherit:"true" desc:"direction of text -- only applicable for unicode-bidi = bidi-override or embed -- applies to all text elements"`
-	WritingMode      TextDirections `xml:"writing-mode" inherit:"true" desc:"overall writing mode -- only for text elements, not tspan"`
-	OrientationVert  float32        `xml:"glyph-orientation-vertical" inherit:"true" desc:"for TBRL writing mode (only), determines orientation of alphabetic characters -- 90 is default (rotated) -- 0 means keep upright"`
-	OrientationHoriz float32        `xml:"glyph-orientation-horizontal" inherit:"true" desc:"for horizontal LR/RL writing mode (only), determines orientation of all characters -- 0 is default (upright)"`
-	Indent           units.Value    `xml:"text-indent" inherit:"true" desc:"how much to indent the first line in a paragraph"`
-	ParaSpacing      units.Value    `xml:"para-spacing" inherit:"true" desc:"extra spacing between paragraphs -- copied from Style.Layout.Margin per CSS spec if that is non-zero, else can be set directy with para-spacing"`
-	TabSize          int            `xml:"tab-size" inherit:"true" desc:"tab size, in number of characters"`
+	Align            Align             `xml:"text-align" inherit:"true" desc:"how to align text, horizontally"`
+	AlignV           Align             `xml:"-" json:"-" desc:"vertical alignment of text -- copied from layout style AlignV"`
+	Anchor           TextAnchors       `xml:"text-anchor" inherit:"true" desc:"for svg rendering only: determines the alignment relative to text position coordinate: for RTL start is right, not left, and start is top for TB"`
+	DominantBaseline DominantBaselines `xml:"dominant-baseline" inherit:"true" desc:"for svg rendering only: determines which baseline of the font is aligned with the text position coordinate -- computed from actual font metrics"`
+	LetterSpacing    units.Value       `xml:"letter-spacing" desc:"spacing between characters and lines"`
+	WordSpacing      units.Value       `xml:"word-spacing" inherit:"true" desc:"extra space to add between words"`
+	LineHeight       float32           `xml:"line-height" inherit:"true" desc:"specified height of a line of text, in proportion to default font height, 0 = 1 = normal (todo: specific values such as pixels are not supported, in order to properly support percentage) -- text is centered within the overall lineheight"`
+	WhiteSpace       WhiteSpaces       `xml:"white-space" inherit:"true" desc:"specifies how white space is processed, and how lines are wrapped"`
+	UnicodeBidi      UnicodeBidi       `xml:"unicode-bidi" inherit:"true" desc:"determines how to treat unicode bidirectional information"`
+	Direction        TextDirections    `xml:"direction" inherit:"true" desc:"base (paragraph) direction of text, for reordering mixed left-to-right and right-to-left text according to the unicode bidi algorithm -- rtl also switches start and end alignment, and mirrors the order of elements in horizontal and grid layouts -- the default lrtb determines the direction from the first strongly directional character in the text"`
+	WritingMode      TextDirections    `xml:"writing-mode" inherit:"true" desc:"overall writing mode -- only for text elements, not tspan"`
+	OrientationVert  float32           `xml:"glyph-orientation-vertical" inherit:"true" desc:"for TBRL writing mode (only), determines orientation of alphabetic characters -- 90 is default (rotated) -- 0 means keep upright"`
+	OrientationHoriz float32           `xml:"glyph-orientation-horizontal" inherit:"true" desc:"for horizontal LR/RL writing mode (only), determines orientation of all characters -- 0 is default (upright)"`
+	Indent           units.Value       `xml:"text-indent" inherit:"true" desc:"how much to indent the first line in a paragraph"`
+	ParaSpacing      units.Value       `xml:"para-spacing" inherit:"true" desc:"extra spacing between paragraphs -- copied from Style.Layout.Margin per CSS spec if that is non-zero, else can be set directy with para-spacing"`
+	TabSize          int               `xml:"tab-size" inherit:"true" desc:"tab size, in number of characters"`
+	Justify          TextJustifies     `xml:"text-justify" inherit:"true" desc:"how extra space is distributed for justified text (text-align: justify), which applies to all but the last line of each paragraph of wrapped text"`
+	Overflow         TextOverflows     `xml:"text-overflow" desc:"what to do with text that does not fit within the available width (and is not wrapped): clip it, or truncate it with an ellipsis (…)"`
+	Transform        TextTransforms    `xml:"text-transform" inherit:"true" desc:"transforms the case of the text for display: uppercase, lowercase or capitalize (first letter of each word) -- the underlying text is not changed"`
+	Shadow           ShadowStyle       `xml:"text-shadow" inherit:"true" desc:"shadow rendered behind the text, using the given offsets and blur radius -- can be set with the css shorthand, e.g., text-shadow: 1px 1px 2px black -- if no color is given, the text color is used"`
+	Hyphens          Hyphens           `xml:"hyphens" inherit:"true" desc:"how words are hyphenated when wrapping lines: manual only breaks words at soft hyphens (U+00AD, &shy;), auto also breaks them at the hyphenation points given by the hyphenation patterns for HyphenLang, which must be added with AddHyphenPatterns -- none are included, so without them auto is the same as manual -- and none never hyphenates"`
+	OverflowWrap     OverflowWraps     `xml:"overflow-wrap" inherit:"true" desc:"whether a line can be broken within a word that is too long to fit on the line by itself: normal only breaks lines at the break opportunities of the unicode line breaking algorithm, while anywhere (or break-word) breaks the word as needed to fit"`
 	// todo:
 	// page-break options
-	// text-justify  inherit:"true" -- how to justify text
-	// text-overflow -- clip, ellipsis, string..
-	// text-shadow  inherit:"true"
-	// text-transform --  inherit:"true" uppercase, lowercase, capitalize
 	// user-select -- can user select text?
 }
 
@@ -1666,6 +2484,71 @@ var KiT_TextAnchors = kit.Enums.AddEnumAltLower(TextAnchorsN, false, StylePropPr
 func (ev TextAnchors) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
 func (ev *TextAnchors) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
 
+// DominantBaselines determine which baseline of the font is aligned with the
+// text position coordinate in svg text rendering
+type DominantBaselines int32
+
+const (
+	// BaselineAuto is the same as BaselineAlphabetic for horizontal text
+	BaselineAuto DominantBaselines = iota
+
+	// BaselineAlphabetic aligns the standard font baseline
+	BaselineAlphabetic
+
+	// BaselineMiddle aligns the middle of the x-height of the font, i.e.,
+	// half of the Ex size above the baseline
+	BaselineMiddle
+
+	// BaselineCentral aligns the center of the em box: halfway between
+	// ascent and descent
+	BaselineCentral
+
+	// BaselineHanging aligns the top of the capital letters (cap height),
+	// as used in Devanagari and similar scripts
+	BaselineHanging
+
+	// BaselineTextBeforeEdge aligns the top of the font (ascent) -- also
+	// text-top
+	BaselineTextBeforeEdge
+
+	// BaselineTextAfterEdge aligns the bottom of the font (descent) -- also
+	// text-bottom
+	BaselineTextAfterEdge
+
+	// BaselineIdeographic aligns the bottom of ideographic glyphs, which is
+	// the descent of the font
+	BaselineIdeographic
+
+	// BaselineMathematical aligns the math axis, half of the Ex size above
+	// the baseline
+	BaselineMathematical
+
+	DominantBaselinesN
+)
+
+//go:generate stringer -type=DominantBaselines
+
+var KiT_DominantBaselines = kit.Enums.AddEnumAltLower(DominantBaselinesN, false, StylePropProps, "Baseline")
+
+func (ev DominantBaselines) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *DominantBaselines) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// DominantBaselinesCSS maps css dominant-baseline names (which have hyphens
+// and aliases not supported by the enum names) onto DominantBaselines values
+var DominantBaselinesCSS = map[string]DominantBaselines{
+	"auto":             BaselineAuto,
+	"alphabetic":       BaselineAlphabetic,
+	"middle":           BaselineMiddle,
+	"central":          BaselineCentral,
+	"hanging":          BaselineHanging,
+	"text-before-edge": BaselineTextBeforeEdge,
+	"text-top":         BaselineTextBeforeEdge,
+	"text-after-edge":  BaselineTextAfterEdge,
+	"text-bottom":      BaselineTextAfterEdge,
+	"ideographic":      BaselineIdeographic,
+	"mathematical":     BaselineMathematical,
+}
+
 // WhiteSpaces determine how white space is processed
 type WhiteSpaces int32
 
@@ -1704,6 +2587,188 @@ var KiT_WhiteSpaces = kit.Enums.AddEnumAltLower(WhiteSpacesN, false, StylePropPr
 func (ev WhiteSpaces) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
 func (ev *WhiteSpaces) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
 
+// TextJustifies determine how extra space is distributed in justified text
+type TextJustifies int32
+
+const (
+	// JustifyAuto adds space between words, or between characters for text
+	// without any spaces (e.g., Chinese or Japanese)
+	JustifyAuto TextJustifies = iota
+
+	// JustifyNone disables justification, so justified text is left aligned
+	JustifyNone
+
+	// JustifyInterWord adds space between words
+	JustifyInterWord
+
+	// JustifyInterCharacter adds space between all characters
+	JustifyInterCharacter
+
+	TextJustifiesN
+)
+
+//go:generate stringer -type=TextJustifies
+
+var KiT_TextJustifies = kit.Enums.AddEnumAltLower(TextJustifiesN, false, StylePropProps, "Justify")
+
+func (ev TextJustifies) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *TextJustifies) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// TextJustifiesCSS maps the css text-justify names to values
+var TextJustifiesCSS = map[string]TextJustifies{
+	"auto":            JustifyAuto,
+	"none":            JustifyNone,
+	"inter-word":      JustifyInterWord,
+	"inter-character": JustifyInterCharacter,
+	"distribute":      JustifyInterCharacter,
+}
+
+// TextOverflows determine what happens to text that does not fit
+type TextOverflows int32
+
+const (
+	// TextOverflowClip clips the text at the edge of the available space
+	TextOverflowClip TextOverflows = iota
+
+	// TextOverflowEllipsis truncates the text so that it ends with an
+	// ellipsis (…) within the available space
+	TextOverflowEllipsis
+
+	TextOverflowsN
+)
+
+//go:generate stringer -type=TextOverflows
+
+var KiT_TextOverflows = kit.Enums.AddEnumAltLower(TextOverflowsN, false, StylePropProps, "TextOverflow")
+
+func (ev TextOverflows) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *TextOverflows) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// Hyphens determine how words are hyphenated when wrapping lines
+type Hyphens int32
+
+const (
+	// HyphensManual only breaks words at soft hyphens (U+00AD, &shy;)
+	HyphensManual Hyphens = iota
+
+	// HyphensNone never breaks words at hyphenation points, even at soft
+	// hyphens
+	HyphensNone
+
+	// HyphensAuto breaks words at soft hyphens, and at the hyphenation points
+	// given by the hyphenation patterns for HyphenLang -- no patterns are
+	// included, so it is the same as HyphensManual unless they are added with
+	// AddHyphenPatterns
+	HyphensAuto
+
+	HyphensN
+)
+
+//go:generate stringer -type=Hyphens
+
+var KiT_Hyphens = kit.Enums.AddEnumAltLower(HyphensN, false, StylePropProps, "Hyphens")
+
+func (ev Hyphens) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *Hyphens) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// OverflowWraps determine whether lines can be broken within words that are
+// too long to fit on a line
+type OverflowWraps int32
+
+const (
+	// OverflowWrapNormal only breaks lines at the break opportunities of the
+	// unicode line breaking algorithm, so long words overflow the line
+	OverflowWrapNormal OverflowWraps = iota
+
+	// OverflowWrapAnywhere breaks a word that does not fit on a line by
+	// itself at any character
+	OverflowWrapAnywhere
+
+	// OverflowWrapBreakWord is the same as OverflowWrapAnywhere
+	OverflowWrapBreakWord
+
+	OverflowWrapsN
+)
+
+//go:generate stringer -type=OverflowWraps
+
+var KiT_OverflowWraps = kit.Enums.AddEnumAltLower(OverflowWrapsN, false, StylePropProps, "OverflowWrap")
+
+func (ev OverflowWraps) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *OverflowWraps) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// OverflowWrapsCSS maps the css overflow-wrap (and word-wrap) names to values
+var OverflowWrapsCSS = map[string]OverflowWraps{
+	"normal":     OverflowWrapNormal,
+	"anywhere":   OverflowWrapAnywhere,
+	"break-word": OverflowWrapBreakWord,
+}
+
+// TextTransforms are case transformations applied to text for display
+type TextTransforms int32
+
+const (
+	// TransformNone displays the text as is
+	TransformNone TextTransforms = iota
+
+	// TransformUppercase displays all letters in upper case
+	TransformUppercase
+
+	// TransformLowercase displays all letters in lower case
+	TransformLowercase
+
+	// TransformCapitalize displays the first letter of each word in title case
+	TransformCapitalize
+
+	TextTransformsN
+)
+
+//go:generate stringer -type=TextTransforms
+
+var KiT_TextTransforms = kit.Enums.AddEnumAltLower(TextTransformsN, false, StylePropProps, "Transform")
+
+func (ev TextTransforms) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *TextTransforms) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// TransformRunes returns the given text with the case transform applied --
+// the number of runes is always the same, so indexes into the original text
+// remain valid.  The original slice is returned if nothing changes, and a
+// new one otherwise, so the original text is never modified.  prev is the
+// rune preceding the text (0 for none), for finding the start of words with
+// TransformCapitalize.
+func (tt TextTransforms) TransformRunes(txt []rune, prev rune) []rune {
+	if tt == TransformNone {
+		return txt
+	}
+	var nt []rune
+	for i, r := range txt {
+		nr := r
+		switch tt {
+		case TransformUppercase:
+			nr = unicode.ToUpper(r)
+		case TransformLowercase:
+			nr = unicode.ToLower(r)
+		case TransformCapitalize:
+			if prev == 0 || !(unicode.IsLetter(prev) || unicode.IsDigit(prev) || unicode.Is(unicode.Mn, prev) || prev == '\'' || prev == '’') {
+				nr = unicode.ToTitle(r)
+			}
+		}
+		prev = r
+		if nr == r {
+			continue
+		}
+		if nt == nil {
+			nt = make([]rune, len(txt))
+			copy(nt, txt)
+		}
+		nt[i] = nr
+	}
+	if nt == nil {
+		return txt
+	}
+	return nt
+}
+
 // HasWordWrap returns true if current white space option supports word wrap
 func (ts *TextStyle) HasWordWrap() bool {
 	switch ts.WhiteSpace {
@@ -1743,6 +2808,34 @@ func (ts *TextStyle) Defaults() {
 
 // SetStylePost applies any updates after generic xml-tag property setting
 func (ts *TextStyle) SetStylePost(props ki.Props) {
+	if pdb, ok := props["dominant-baseline"]; ok {
+		if dbs, ok := pdb.(string); ok {
+			if db, ok := DominantBaselinesCSS[strings.ToLower(strings.TrimSpace(dbs))]; ok {
+				ts.DominantBaseline = db
+			}
+		}
+	}
+	if pj, ok := props["text-justify"]; ok {
+		if js, ok := pj.(string); ok {
+			if j, ok := TextJustifiesCSS[strings.ToLower(strings.TrimSpace(js))]; ok {
+				ts.Justify = j
+			}
+		}
+	}
+	if psh, ok := props["text-shadow"]; ok {
+		if shs, ok := psh.(string); ok {
+			ts.Shadow.SetString(shs)
+		}
+	}
+	for _, pn := range []string{"word-wrap", "overflow-wrap"} { // word-wrap is the legacy name
+		if pw, ok := props[pn]; ok {
+			if ws, ok := pw.(string); ok {
+				if w, ok := OverflowWrapsCSS[strings.ToLower(strings.TrimSpace(ws))]; ok {
+					ts.OverflowWrap = w
+				}
+			}
+		}
+	}
 }
 
 // InheritFields from parent: Manual inheriting of values is much faster than
@@ -1750,6 +2843,7 @@ func (ts *TextStyle) SetStylePost(props ki.Props) {
 func (ts *TextStyle) InheritFields(par *TextStyle) {
 	ts.Align = par.Align
 	ts.Anchor = par.Anchor
+	ts.DominantBaseline = par.DominantBaseline
 	ts.WordSpacing = par.WordSpacing
 	ts.LineHeight = par.LineHeight
 	// ts.WhiteSpace = par.WhiteSpace // todo: we can't inherit this b/c label base default then gets overwritten
@@ -1761,6 +2855,11 @@ func (ts *TextStyle) InheritFields(par *TextStyle) {
 	ts.Indent = par.Indent
 	ts.ParaSpacing = par.ParaSpacing
 	ts.TabSize = par.TabSize
+	ts.Justify = par.Justify
+	ts.Transform = par.Transform
+	ts.Shadow = par.Shadow
+	ts.Hyphens = par.Hyphens
+	ts.OverflowWrap = par.OverflowWrap
 }
 
 // EffLineHeight returns the effective line height (taking into account 0 value)
@@ -1771,31 +2870,67 @@ func (ts *TextStyle) EffLineHeight() float32 {
 	return ts.LineHeight
 }
 
-// AlignFactors gets basic text alignment factors
-func (ts *TextStyle) AlignFactors() (ax, ay float32) {
-	ax = 0.0
-	ay = 0.0
-	hal := ts.Align
-	switch {
-	case IsAlignMiddle(hal):
-		ax = 0.5 // todo: determine if font is horiz or vert..
-	case IsAlignEnd(hal):
-		ax = 1.0
-	}
-	val := ts.AlignV
-	switch {
-	case val == AlignSub:
-		ay = -0.15 // todo: fixme -- need actual font metrics
-	case val == AlignSuper:
-		ay = 0.65 // todo: fixme
-	case IsAlignStart(val):
-		ay = 0.9 // todo: need to find out actual baseline
-	case IsAlignMiddle(val):
-		ay = 0.45 // todo: determine if font is horiz or vert..
-	case IsAlignEnd(val):
-		ay = -0.1 // todo: need actual baseline
+// BaselineOffset returns the vertical offset to add to a text position
+// coordinate to obtain the alphabetic baseline position used for rendering,
+// according to the DominantBaseline setting, using the actual metrics of the
+// font in given font style (which must have been opened) -- positive values
+// move the baseline down
+func (ts *TextStyle) BaselineOffset(fs *FontStyle) float32 {
+	if fs.Face == nil {
+		return 0
+	}
+	m := fs.Face.Metrics()
+	asc := FixedToFloat32(m.Ascent)
+	dsc := FixedToFloat32(m.Descent)
+	switch ts.DominantBaseline {
+	case BaselineMiddle, BaselineMathematical:
+		return 0.5 * FontXHeight(fs.Face)
+	case BaselineCentral:
+		return 0.5 * (asc - dsc)
+	case BaselineHanging:
+		return FontCapHeight(fs.Face)
+	case BaselineTextBeforeEdge:
+		return asc
+	case BaselineTextAfterEdge, BaselineIdeographic:
+		return -dsc
+	}
+	return 0
+}
+
+// FontXHeight returns the height of the lower-case x glyph above the
+// baseline in given face, falling back on half the ascent if not available
+func FontXHeight(face font.Face) float32 {
+	TextFontRenderMu.Lock()
+	defer TextFontRenderMu.Unlock()
+	xb, _, ok := face.GlyphBounds('x')
+	if ok && xb.Min.Y < 0 {
+		return -FixedToFloat32(xb.Min.Y)
 	}
-	return
+	return 0.5 * FixedToFloat32(face.Metrics().Ascent)
+}
+
+// FontCapHeight returns the height of the capital H glyph above the baseline
+// in given face, falling back on 0.8 of the ascent if not available
+func FontCapHeight(face font.Face) float32 {
+	TextFontRenderMu.Lock()
+	defer TextFontRenderMu.Unlock()
+	hb, _, ok := face.GlyphBounds('H')
+	if ok && hb.Min.Y < 0 {
+		return -FixedToFloat32(hb.Min.Y)
+	}
+	return 0.8 * FixedToFloat32(face.Metrics().Ascent)
+}
+
+// FontSuperOffset returns the (negative, upward) baseline shift for
+// superscript text in given face, as used for DecoSuper
+func FontSuperOffset(face font.Face) float32 {
+	return -0.45 * FixedToFloat32(face.Metrics().Ascent)
+}
+
+// FontSubOffset returns the (positive, downward) baseline shift for
+// subscript text in given face, as used for DecoSub
+func FontSubOffset(face font.Face) float32 {
+	return 0.15 * FixedToFloat32(face.Metrics().Ascent)
 }
 
 //////////////////////////////////////////////////////////////////////////////////
@@ -1806,16 +2941,32 @@ func (ts *TextStyle) AlignFactors() (ax, ay float32) {
 // size overall box (nonzero values used to constrain). Returns total
 // resulting size box for text.  Font face in FontStyle is used for
 // determining line spacing here -- other versions can do more expensive
-// calculations of variable line spacing as needed.
+// calculations of variable line spacing as needed.  Lines are wrapped at the
+// break opportunities of the unicode line breaking algorithm, hyphenating
+// words according to the hyphens and overflow-wrap styles (see
+// FindWrapPosLR).
 func (tr *TextRender) LayoutStdLR(txtSty *TextStyle, fontSty *FontStyle, ctxt *units.Context, size Vec2D) Vec2D {
 	if len(tr.Spans) == 0 {
 		return Vec2DZero
 	}
 
+	var lkey *textLayKey
+	if tr.Cache {
+		lkey = &textLayKey{txt: *txtSty, font: *fontSty, ctxt: *ctxt, size: size}
+		if tr.pending {
+			tr.pending = false
+			if *tr.layKey == *lkey {
+				return tr.laySize
+			}
+			tr.redoSet()
+		}
+	}
+
 	pr := prof.Start("TextRenderLayout")
 	defer pr.End()
 
 	tr.Dir = LRTB
+	tr.Shadow = txtSty.Shadow
 	fontSty.OpenFont(ctxt)
 	fht := fontSty.Height
 	dsc := FixedToFloat32(fontSty.Face.Metrics().Descent)
@@ -1823,6 +2974,7 @@ func (tr *TextRender) LayoutStdLR(txtSty *TextStyle, fontSty *FontStyle, ctxt *u
 	lpad := (lspc - fht) / 2 // padding above / below text box for centering in line
 
 	maxw := float32(0)
+	wrapped := make(map[int]bool) // spans that continue on the next line, for justify
 
 	// first pass gets rune positions and wraps text as needed, and gets max width
 	si := 0
@@ -1834,6 +2986,14 @@ func (tr *TextRender) LayoutStdLR(txtSty *TextStyle, fontSty *FontStyle, ctxt *u
 			continue
 		}
 		if sr.LastPos.X == 0 { // don't re-do unless necessary
+			if txtSty.Transform != TransformNone {
+				prev := rune(0)
+				if si > 0 && !sr.IsNewPara() && len(tr.Spans[si-1].Text) > 0 {
+					prev = tr.Spans[si-1].Text[len(tr.Spans[si-1].Text)-1]
+				}
+				sr.Text = txtSty.Transform.TransformRunes(sr.Text, prev)
+			}
+			sr.Dir = txtSty.Direction
 			sr.SetRunePosLR(txtSty.LetterSpacing.Dots, txtSty.WordSpacing.Dots, fontSty.Ch, txtSty.TabSize)
 		}
 		if sr.IsNewPara() {
@@ -1845,10 +3005,14 @@ func (tr *TextRender) LayoutStdLR(txtSty *TextStyle, fontSty *FontStyle, ctxt *u
 		ssz.X += sr.RelPos.X
 		if size.X > 0 && ssz.X > size.X && txtSty.HasWordWrap() {
 			for {
-				wp := sr.FindWrapPosLR(size.X, ssz.X)
+				wp, hyph := sr.FindWrapPosLR(size.X, txtSty.Hyphens, txtSty.OverflowWrap)
 				if wp > 0 && wp < len(sr.Text)-1 {
 					nsr := sr.SplitAtLR(wp)
+					if hyph {
+						sr.HyphenateLR(txtSty.LetterSpacing.Dots, txtSty.WordSpacing.Dots, fontSty.Ch, txtSty.TabSize)
+					}
 					tr.InsertSpan(si+1, nsr)
+					wrapped[si] = true
 					ssz = sr.SizeHV()
 					ssz.X += sr.RelPos.X
 					if ssz.X > maxw {
@@ -1902,6 +3066,12 @@ func (tr *TextRender) LayoutStdLR(txtSty *TextStyle, fontSty *FontStyle, ctxt *u
 	}
 	// have maxw, can do alignment cases..
 
+	if size.X > 0 && maxw > size.X && txtSty.Overflow == TextOverflowEllipsis {
+		if tr.EllipsisLR(size.X, txtSty, fontSty) {
+			maxw = tr.Size.X
+		}
+	}
+
 	// make sure links are still in range
 	for li := range tr.Links {
 		tl := &tr.Links[li]
@@ -1949,6 +3119,7 @@ func (tr *TextRender) LayoutStdLR(txtSty *TextStyle, fontSty *FontStyle, ctxt *u
 
 	vbaseoff := lspc - lpad - dsc // offset of baseline within overall line
 	vpos := vpad + vbaseoff
+	rtl := txtSty.Direction.IsRTL()
 
 	for si := range tr.Spans {
 		sr := &(tr.Spans[si])
@@ -1957,6 +3128,9 @@ func (tr *TextRender) LayoutStdLR(txtSty *TextStyle, fontSty *FontStyle, ctxt *u
 		}
 		sr.RelPos.Y = vpos
 		sr.LastPos.Y = vpos
+		if wrapped[si] && txtSty.Align == AlignJustify && txtSty.Justify != JustifyNone {
+			sr.JustifyLR(size.X-sr.RelPos.X, txtSty.Justify == JustifyInterCharacter)
+		}
 		ssz := sr.SizeHV()
 		ssz.X += sr.RelPos.X
 		hextra := size.X - ssz.X
@@ -1964,12 +3138,16 @@ func (tr *TextRender) LayoutStdLR(txtSty *TextStyle, fontSty *FontStyle, ctxt *u
 			switch {
 			case IsAlignMiddle(txtSty.Align):
 				sr.RelPos.X += hextra / 2
-			case IsAlignEnd(txtSty.Align):
+			case IsAlignEnd(txtSty.Align) && !rtl, IsAlignStart(txtSty.Align) && rtl: // start is right for rtl
 				sr.RelPos.X += hextra
 			}
 		}
 		vpos += lspc
 	}
+	if lkey != nil && tr.setKey != nil {
+		tr.layKey = lkey
+		tr.laySize = size
+	}
 	return size
 }
 
@@ -230,6 +230,24 @@ func (tf *TextField) CursorBackward(steps int) {
 	}
 }
 
+// CursorVisual moves the cursor by given number of positions to the right
+// (steps > 0) or left (steps < 0) as displayed -- for text with
+// right-to-left parts, this is not the same as moving forward or backward
+func (tf *TextField) CursorVisual(steps int) {
+	np := tf.CursorPos + steps
+	if len(tf.RenderAll.Spans) == 1 {
+		sr := &(tf.RenderAll.Spans[0])
+		if sr.IsBidi() && len(sr.Text) == len(tf.EditTxt) {
+			np = sr.VisualMoveLR(tf.CursorPos, steps)
+		}
+	}
+	if np > tf.CursorPos {
+		tf.CursorForward(np - tf.CursorPos)
+	} else if np < tf.CursorPos {
+		tf.CursorBackward(tf.CursorPos - np)
+	}
+}
+
 // CursorStart moves the cursor to the start of the text, updating selection
 // if select mode is active
 func (tf *TextField) CursorStart() {
@@ -629,7 +647,8 @@ func (tf *TextField) TextWidth(st, ed int) float32 {
 	return tf.StartCharPos(ed) - tf.StartCharPos(st)
 }
 
-// StartCharPos returns the starting position of the given rune
+// StartCharPos returns the starting position of the given rune, in logical
+// order -- i.e., the width of the text up to that rune
 func (tf *TextField) StartCharPos(idx int) float32 {
 	if idx <= 0 || len(tf.RenderAll.Spans) != 1 {
 		return 0.0
@@ -639,10 +658,7 @@ func (tf *TextField) StartCharPos(idx int) float32 {
 	if sz == 0 {
 		return 0.0
 	}
-	if idx >= sz {
-		return sr.LastPos.X
-	}
-	return sr.Render[idx].RelPos.X
+	return sr.LogPosLR(idx)
 }
 
 // CharStartPos returns the starting render coords for the given character
@@ -652,10 +668,27 @@ func (tf *TextField) CharStartPos(charidx int) Vec2D {
 	st := &tf.Sty
 	spc := st.BoxSpace()
 	pos := tf.LayData.AllocPos.AddVal(spc)
+	if sr := tf.BidiVisSpan(); sr != nil {
+		return Vec2D{pos.X + sr.CaretPosLR(charidx-tf.StartPos), pos.Y}
+	}
 	cpos := tf.TextWidth(tf.StartPos, charidx)
 	return Vec2D{pos.X + cpos, pos.Y}
 }
 
+// BidiVisSpan returns the rendered span of visible text if it contains
+// right-to-left text that has been reordered for display (so positions are
+// not in logical order), and nil otherwise
+func (tf *TextField) BidiVisSpan() *SpanRender {
+	if len(tf.EditTxt) == 0 || len(tf.RenderVis.Spans) != 1 {
+		return nil
+	}
+	sr := &(tf.RenderVis.Spans[0])
+	if !sr.IsBidi() || len(sr.Text) != tf.EndPos-tf.StartPos {
+		return nil
+	}
+	return sr
+}
+
 // TextFieldBlinker is the time.Ticker for blinking cursors for text fields,
 // only one of which can be active at at a time
 var TextFieldBlinker *time.Ticker
@@ -792,6 +825,14 @@ func (tf *TextField) RenderSelect() {
 	rs := &tf.Viewport.Render
 	pc := &rs.Paint
 	st := &tf.StateStyles[TextFieldSel]
+	if sr := tf.BidiVisSpan(); sr != nil { // selection can be discontinuous
+		sx := tf.LayData.AllocPos.X + tf.Sty.BoxSpace()
+		for i := effst; i < effed; i++ {
+			rr := &(sr.Render[i-tf.StartPos])
+			pc.FillBox(rs, Vec2D{sx + rr.RelPos.X, spos.Y}, Vec2D{rr.Size.X, tf.FontHeight}, &st.Font.BgColor)
+		}
+		return
+	}
 	tsz := tf.TextWidth(effst, effed)
 	pc.FillBox(rs, spos, Vec2D{tsz, tf.FontHeight}, &st.Font.BgColor)
 }
@@ -892,6 +933,10 @@ func (tf *TextField) PixelToCursor(pixOff float32) int {
 	spc := st.BoxSpace()
 	px := pixOff - spc
 
+	if sr := tf.BidiVisSpan(); sr != nil {
+		return tf.StartPos + sr.CaretIdxLR(px)
+	}
+
 	if px <= 0 {
 		return tf.StartPos
 	}
@@ -971,11 +1016,11 @@ func (tf *TextField) KeyInput(kt *key.ChordEvent) {
 	switch kf {
 	case KeyFunMoveRight:
 		kt.SetProcessed()
-		tf.CursorForward(1)
+		tf.CursorVisual(1)
 		tf.OfferComplete()
 	case KeyFunMoveLeft:
 		kt.SetProcessed()
-		tf.CursorBackward(1)
+		tf.CursorVisual(-1)
 		tf.OfferComplete()
 	case KeyFunHome:
 		kt.SetProcessed()
@@ -1153,6 +1198,8 @@ func (tf *TextField) Init2D() {
 	tf.Init2DWidget()
 	tf.EditTxt = []rune(tf.Txt)
 	tf.Edited = false
+	tf.RenderAll.Cache = true
+	tf.RenderVis.Cache = true
 }
 
 func (tf *TextField) StyleTextField() {
@@ -1241,15 +1288,19 @@ func (tf *TextField) Render2D() {
 		st.Font.OpenFont(&st.UnContext)
 		tf.RenderStdBox(st)
 		cur := tf.EditTxt[tf.StartPos:tf.EndPos]
-		tf.RenderSelect()
 		pos := tf.LayData.AllocPos.AddVal(st.BoxSpace())
 		if len(tf.EditTxt) == 0 && len(tf.Placeholder) > 0 {
 			st.Font.Color = st.Font.Color.Highlight(50)
 			tf.RenderVis.SetString(tf.Placeholder, &st.Font, &st.UnContext, &st.Text, true, 0, 0)
 			tf.RenderVis.RenderTopPos(rs, pos)
 
+		} else if tf.IsEllipsis() {
+			tf.RenderVis.SetRunes(tf.EditTxt, &st.Font, &st.UnContext, &st.Text, true, 0, 0)
+			tf.RenderVis.EllipsisLR(tf.LayData.AllocSize.X-2*st.BoxSpace(), &st.Text, &st.Font)
+			tf.RenderVis.RenderTopPos(rs, pos)
 		} else {
 			tf.RenderVis.SetRunes(cur, &st.Font, &st.UnContext, &st.Text, true, 0, 0)
+			tf.RenderSelect() // uses RenderVis for bidi text
 			tf.RenderVis.RenderTopPos(rs, pos)
 		}
 		if tf.HasFocus() && tf.FocusActive {
@@ -1264,6 +1315,17 @@ func (tf *TextField) Render2D() {
 	}
 }
 
+// IsEllipsis returns true if the text is currently displayed truncated with
+// an ellipsis, per the text-overflow: ellipsis style: when it does not fit
+// and the field does not have the focus (when it is scrolled as usual)
+func (tf *TextField) IsEllipsis() bool {
+	st := &tf.Sty
+	if st.Text.Overflow != TextOverflowEllipsis || tf.HasFocus() || len(tf.EditTxt) == 0 {
+		return false
+	}
+	return tf.RenderAll.Size.X > tf.LayData.AllocSize.X-2*st.BoxSpace()
+}
+
 func (tf *TextField) ConnectEvents2D() {
 	tf.TextFieldEvents()
 }
@@ -0,0 +1,348 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+
+	"golang.org/x/image/math/fixed"
+)
+
+// vecpdf contains the PDF and EPS writers for VecRecord
+
+// vecNum formats a number compactly for PDF / PostScript output
+func vecNum(v float32) string {
+	return strconv.FormatFloat(float64(v), 'f', -1, 32)
+}
+
+// vecPathWriter is a rasterx.Adder that writes path commands in PDF or
+// PostScript syntax -- quadratic curves are converted to cubic
+type vecPathWriter struct {
+	buf        *bytes.Buffer
+	ps         bool
+	cur, start Vec2D
+}
+
+func (pw *vecPathWriter) pt(p Vec2D) string {
+	return vecNum(p.X) + " " + vecNum(p.Y)
+}
+
+func (pw *vecPathWriter) Start(a fixed.Point26_6) {
+	pw.cur = NewVec2DFmFixed(a)
+	pw.start = pw.cur
+	if pw.ps {
+		fmt.Fprintf(pw.buf, "%v moveto\n", pw.pt(pw.cur))
+	} else {
+		fmt.Fprintf(pw.buf, "%v m\n", pw.pt(pw.cur))
+	}
+}
+
+func (pw *vecPathWriter) Line(b fixed.Point26_6) {
+	pw.cur = NewVec2DFmFixed(b)
+	if pw.ps {
+		fmt.Fprintf(pw.buf, "%v lineto\n", pw.pt(pw.cur))
+	} else {
+		fmt.Fprintf(pw.buf, "%v l\n", pw.pt(pw.cur))
+	}
+}
+
+func (pw *vecPathWriter) QuadBezier(b, c fixed.Point26_6) {
+	p1 := NewVec2DFmFixed(b)
+	p2 := NewVec2DFmFixed(c)
+	c1 := pw.cur.Add(p1.Sub(pw.cur).MulVal(2.0 / 3.0))
+	c2 := p2.Add(p1.Sub(p2).MulVal(2.0 / 3.0))
+	pw.cube(c1, c2, p2)
+}
+
+func (pw *vecPathWriter) CubeBezier(b, c, d fixed.Point26_6) {
+	pw.cube(NewVec2DFmFixed(b), NewVec2DFmFixed(c), NewVec2DFmFixed(d))
+}
+
+func (pw *vecPathWriter) cube(c1, c2, p Vec2D) {
+	pw.cur = p
+	if pw.ps {
+		fmt.Fprintf(pw.buf, "%v %v %v curveto\n", pw.pt(c1), pw.pt(c2), pw.pt(p))
+	} else {
+		fmt.Fprintf(pw.buf, "%v %v %v c\n", pw.pt(c1), pw.pt(c2), pw.pt(p))
+	}
+}
+
+func (pw *vecPathWriter) Stop(closeLoop bool) {
+	if closeLoop {
+		pw.cur = pw.start
+		if pw.ps {
+			pw.buf.WriteString("closepath\n")
+		} else {
+			pw.buf.WriteString("h\n")
+		}
+	}
+}
+
+// vecCapJoin returns the PDF / PostScript line cap and join codes
+func vecCapJoin(st *VecStroke) (lcap, join int) {
+	switch st.Cap {
+	case LineCapButt:
+		lcap = 0
+	case LineCapSquare:
+		lcap = 2
+	default:
+		lcap = 1
+	}
+	switch st.Join {
+	case LineJoinMiter, LineJoinMiterClip:
+		join = 0
+	case LineJoinBevel:
+		join = 2
+	default:
+		join = 1
+	}
+	return
+}
+
+// vecRGBA returns the non-premultiplied color components in 0-1 range
+func vecRGBA(clr color.Color) (r, g, b, a float32) {
+	c := color.NRGBAModel.Convert(clr).(color.NRGBA)
+	return float32(c.R) / 255, float32(c.G) / 255, float32(c.B) / 255, float32(c.A) / 255
+}
+
+// vecDash returns the dash array in PDF / PostScript syntax
+func vecDash(dash []float64) string {
+	var b bytes.Buffer
+	b.WriteString("[")
+	for i, d := range dash {
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString(vecNum(float32(d)))
+	}
+	b.WriteString("] 0")
+	return b.String()
+}
+
+// WritePDF writes the recording as a single-page PDF document to given
+// writer -- text is rendered as glyph outlines, and gradients as their
+// average color (which is logged)
+func (vr *VecRecord) WritePDF(w io.Writer) error {
+	vr.logLossy("WritePDF")
+	sc := 72 / vr.DPI
+	wpt := float32(vr.Size.X) * sc
+	hpt := float32(vr.Size.Y) * sc
+
+	var objs [][]byte // object bodies, numbered from 1
+	addObj := func(body []byte) int {
+		objs = append(objs, body)
+		return len(objs)
+	}
+	streamObj := func(dict string, data []byte) []byte {
+		var zb bytes.Buffer
+		zw := zlib.NewWriter(&zb)
+		zw.Write(data)
+		zw.Close()
+		var b bytes.Buffer
+		fmt.Fprintf(&b, "<< %v /Filter /FlateDecode /Length %v >>\nstream\n", dict, zb.Len())
+		b.Write(zb.Bytes())
+		b.WriteString("\nendstream")
+		return b.Bytes()
+	}
+
+	addObj(nil) // 1: catalog, filled in below
+	addObj(nil) // 2: pages
+	addObj(nil) // 3: page
+
+	gstates := map[string]int{} // alpha key -> object number
+	var images []int
+	var cs bytes.Buffer
+	fmt.Fprintf(&cs, "%v 0 0 %v 0 %v cm\n", vecNum(sc), vecNum(-sc), vecNum(hpt))
+	pw := &vecPathWriter{buf: &cs}
+	setAlpha := func(op string, a float32) {
+		key := op + vecNum(a)
+		if _, ok := gstates[key]; !ok {
+			gstates[key] = addObj([]byte(fmt.Sprintf("<< /Type /ExtGState /%v %v >>", op, vecNum(a))))
+		}
+		fmt.Fprintf(&cs, "/GS%v gs\n", gstates[key])
+	}
+	for _, op := range vr.ops {
+		cs.WriteString("q\n")
+		if !op.clip.Empty() {
+			fmt.Fprintf(&cs, "%v %v %v %v re W n\n", op.clip.Min.X, op.clip.Min.Y, op.clip.Dx(), op.clip.Dy())
+		}
+		switch op.kind {
+		case vecOpFill:
+			r, g, b, a := vecRGBA(op.color)
+			if a == 0 {
+				break
+			}
+			if a < 1 {
+				setAlpha("ca", a)
+			}
+			fmt.Fprintf(&cs, "%v %v %v rg\n", vecNum(r), vecNum(g), vecNum(b))
+			op.path.AddTo(pw)
+			if op.nonZero {
+				cs.WriteString("f\n")
+			} else {
+				cs.WriteString("f*\n")
+			}
+		case vecOpStroke:
+			r, g, b, a := vecRGBA(op.color)
+			if a == 0 {
+				break
+			}
+			if a < 1 {
+				setAlpha("CA", a)
+			}
+			lcap, join := vecCapJoin(&op.stroke)
+			fmt.Fprintf(&cs, "%v %v %v RG %v w %v J %v j %v M %v d\n", vecNum(r), vecNum(g), vecNum(b),
+				vecNum(op.stroke.Width), lcap, join, vecNum(Max32(op.stroke.MiterLimit, 1)), vecDash(op.stroke.Dashes))
+			op.path.AddTo(pw)
+			cs.WriteString("S\n")
+		case vecOpImage:
+			bnd := op.image.Bounds()
+			iw, ih := bnd.Dx(), bnd.Dy()
+			if iw == 0 || ih == 0 {
+				break
+			}
+			rgb := make([]byte, 0, iw*ih*3)
+			alpha := make([]byte, 0, iw*ih)
+			for y := bnd.Min.Y; y < bnd.Max.Y; y++ {
+				for x := bnd.Min.X; x < bnd.Max.X; x++ {
+					c := color.NRGBAModel.Convert(op.image.At(x, y)).(color.NRGBA)
+					rgb = append(rgb, c.R, c.G, c.B)
+					alpha = append(alpha, c.A)
+				}
+			}
+			smask := addObj(streamObj(fmt.Sprintf("/Type /XObject /Subtype /Image /Width %v /Height %v /ColorSpace /DeviceGray /BitsPerComponent 8", iw, ih), alpha))
+			img := addObj(streamObj(fmt.Sprintf("/Type /XObject /Subtype /Image /Width %v /Height %v /ColorSpace /DeviceRGB /BitsPerComponent 8 /SMask %v 0 R", iw, ih, smask), rgb))
+			images = append(images, img)
+			// map the unit square (top row at 1) through the image transform
+			m := Translate2D(float32(bnd.Min.X), float32(bnd.Min.Y)).Multiply(op.xf)
+			fw, fh := float32(iw), float32(ih)
+			fmt.Fprintf(&cs, "%v %v %v %v %v %v cm /Im%v Do\n", vecNum(m.XX*fw), vecNum(m.YX*fw), vecNum(-m.XY*fh), vecNum(-m.YY*fh),
+				vecNum(m.XY*fh+m.X0), vecNum(m.YY*fh+m.Y0), img)
+		}
+		cs.WriteString("Q\n")
+	}
+	content := addObj(streamObj("", cs.Bytes()))
+
+	var res bytes.Buffer
+	res.WriteString("<< /ExtGState <<")
+	for _, on := range gstates {
+		fmt.Fprintf(&res, " /GS%v %v 0 R", on, on)
+	}
+	res.WriteString(" >> /XObject <<")
+	for _, on := range images {
+		fmt.Fprintf(&res, " /Im%v %v 0 R", on, on)
+	}
+	res.WriteString(" >> >>")
+
+	objs[0] = []byte("<< /Type /Catalog /Pages 2 0 R >>")
+	objs[1] = []byte("<< /Type /Pages /Kids [3 0 R] /Count 1 >>")
+	objs[2] = []byte(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %v %v] /Resources %v /Contents %v 0 R >>", vecNum(wpt), vecNum(hpt), res.String(), content))
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offs := make([]int, len(objs))
+	for i, body := range objs {
+		offs[i] = out.Len()
+		fmt.Fprintf(&out, "%v 0 obj\n", i+1)
+		out.Write(body)
+		out.WriteString("\nendobj\n")
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %v\n0000000000 65535 f \n", len(objs)+1)
+	for _, o := range offs {
+		fmt.Fprintf(&out, "%010d 00000 n \n", o)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %v /Root 1 0 R >>\nstartxref\n%v\n%%%%EOF\n", len(objs)+1, xref)
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// WriteEPS writes the recording as an Encapsulated PostScript document to
+// given writer -- text is rendered as glyph outlines, and gradients as their
+// average color (which is logged).  PostScript does not support transparency, so partially
+// transparent colors are drawn opaque, and images are composited over white.
+func (vr *VecRecord) WriteEPS(w io.Writer) error {
+	vr.logLossy("WriteEPS")
+	sc := 72 / vr.DPI
+	wpt := float32(vr.Size.X) * sc
+	hpt := float32(vr.Size.Y) * sc
+
+	var out bytes.Buffer
+	out.WriteString("%!PS-Adobe-3.0 EPSF-3.0\n")
+	fmt.Fprintf(&out, "%%%%BoundingBox: 0 0 %v %v\n", int(wpt+0.999), int(hpt+0.999))
+	fmt.Fprintf(&out, "%%%%HiResBoundingBox: 0 0 %v %v\n", vecNum(wpt), vecNum(hpt))
+	out.WriteString("%%Creator: GoGi\n%%LanguageLevel: 2\n%%Pages: 1\n%%EndComments\n")
+	out.WriteString("gsave\n")
+	fmt.Fprintf(&out, "0 %v translate %v %v scale\n", vecNum(hpt), vecNum(sc), vecNum(-sc))
+	pw := &vecPathWriter{buf: &out, ps: true}
+	for _, op := range vr.ops {
+		out.WriteString("gsave\n")
+		if !op.clip.Empty() {
+			fmt.Fprintf(&out, "%v %v %v %v rectclip\n", op.clip.Min.X, op.clip.Min.Y, op.clip.Dx(), op.clip.Dy())
+		}
+		switch op.kind {
+		case vecOpFill:
+			r, g, b, a := vecRGBA(op.color)
+			if a == 0 {
+				break
+			}
+			fmt.Fprintf(&out, "%v %v %v setrgbcolor newpath\n", vecNum(r), vecNum(g), vecNum(b))
+			op.path.AddTo(pw)
+			if op.nonZero {
+				out.WriteString("fill\n")
+			} else {
+				out.WriteString("eofill\n")
+			}
+		case vecOpStroke:
+			r, g, b, a := vecRGBA(op.color)
+			if a == 0 {
+				break
+			}
+			lcap, join := vecCapJoin(&op.stroke)
+			fmt.Fprintf(&out, "%v %v %v setrgbcolor %v setlinewidth %v setlinecap %v setlinejoin %v setmiterlimit %v setdash newpath\n",
+				vecNum(r), vecNum(g), vecNum(b), vecNum(op.stroke.Width), lcap, join, vecNum(Max32(op.stroke.MiterLimit, 1)), vecDash(op.stroke.Dashes))
+			op.path.AddTo(pw)
+			out.WriteString("stroke\n")
+		case vecOpImage:
+			bnd := op.image.Bounds()
+			iw, ih := bnd.Dx(), bnd.Dy()
+			if iw == 0 || ih == 0 {
+				break
+			}
+			m := Translate2D(float32(bnd.Min.X), float32(bnd.Min.Y)).Multiply(op.xf)
+			fw, fh := float32(iw), float32(ih)
+			fmt.Fprintf(&out, "[%v %v %v %v %v %v] concat\n", vecNum(m.XX*fw), vecNum(m.YX*fw), vecNum(m.XY*fh), vecNum(m.YY*fh), vecNum(m.X0), vecNum(m.Y0))
+			fmt.Fprintf(&out, "%v %v 8 [%v 0 0 %v 0 0] currentfile /ASCIIHexDecode filter false 3 colorimage\n", iw, ih, iw, ih)
+			n := 0
+			for y := bnd.Min.Y; y < bnd.Max.Y; y++ {
+				for x := bnd.Min.X; x < bnd.Max.X; x++ {
+					c := vecOverWhite(op.image.At(x, y))
+					fmt.Fprintf(&out, "%02x%02x%02x", c.R, c.G, c.B)
+					n++
+					if n%12 == 0 {
+						out.WriteString("\n")
+					}
+				}
+			}
+			out.WriteString(">\n")
+		}
+		out.WriteString("grestore\n")
+	}
+	out.WriteString("grestore\nshowpage\n%%EOF\n")
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// vecOverWhite composites given color over white
+func vecOverWhite(clr color.Color) color.RGBA {
+	r, g, b, a := clr.RGBA() // premultiplied
+	wt := 0xffff - a
+	return color.RGBA{uint8((r + wt) >> 8), uint8((g + wt) >> 8), uint8((b + wt) >> 8), 0xff}
+}
@@ -0,0 +1,443 @@
+// Copyright (c) 2018, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"log"
+
+	"github.com/chewxy/math32"
+	"github.com/goki/freetype/truetype"
+	"github.com/srwiley/rasterx"
+	"github.com/srwiley/scanFT"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/f64"
+	"golang.org/x/image/math/fixed"
+)
+
+// vecrender contains the vector rendering backend for Paint and TextRender:
+// when RenderState.Vector is set, all the drawing operations (paths, text,
+// images) are also sent to it, in device (pixel) coordinates.  VecRecord
+// records these operations, which can then be written out as PDF or
+// EPS vector graphics, or rasterized at any resolution.
+
+// VectorRenderer is implemented by vector graphics backends that receive the
+// drawing operations issued through Paint and TextRender -- all coordinates
+// are in the device (pixel) coordinates of the RenderState, and clip is the
+// current RenderState Bounds
+type VectorRenderer interface {
+	// FillPath fills the given path with given color, using the nonzero
+	// winding rule if nonZero is true, else even-odd
+	FillPath(path rasterx.Path, clr color.Color, nonZero bool, clip image.Rectangle)
+
+	// StrokePath strokes the given path with given color and stroke params
+	StrokePath(path rasterx.Path, clr color.Color, st *VecStroke, clip image.Rectangle)
+
+	// DrawGlyph draws the glyph for given rune in given face, at given
+	// position (left baseline), with given rotation and x-axis scaling
+	DrawGlyph(face font.Face, r rune, pos Vec2D, rot, scalex float32, clr color.Color, clip image.Rectangle)
+
+	// DrawImage draws given image, with given transform from image pixels
+	// to device coordinates
+	DrawImage(img image.Image, xf Matrix2D, clip image.Rectangle)
+}
+
+// VecStroke holds the parameters for stroking a path in a VectorRenderer
+type VecStroke struct {
+	Width      float32   `desc:"line width, in device coordinates"`
+	Cap        LineCap   `desc:"how to draw the end cap of lines"`
+	Join       LineJoin  `desc:"how to join line segments"`
+	MiterLimit float32   `desc:"limit of how far to miter"`
+	Dashes     []float64 `desc:"dash pattern, in device coordinates"`
+}
+
+// VecAverageColor is the color returned by VecColor for a gradient, which is
+// drawn in a VectorRenderer as the average of its stop colors -- a VecRecord
+// counts them, and logs that its output is lossy when it is written
+type VecAverageColor struct {
+	color.Color
+}
+
+// VecColor returns a single color for rendering given color spec in a
+// VectorRenderer, applying given opacity -- gradients are approximated by
+// the average of their stop colors, returned as a VecAverageColor
+func VecColor(cs *ColorSpec, opacity float32) color.Color {
+	if cs.Source == SolidColor || cs.Gradient == nil || len(cs.Gradient.Stops) == 0 {
+		return rasterx.ApplyOpacity(cs.Color, float64(opacity))
+	}
+	var r, g, b, a float32
+	for _, st := range cs.Gradient.Stops {
+		c := color.NRGBAModel.Convert(st.StopColor).(color.NRGBA)
+		r += float32(c.R)
+		g += float32(c.G)
+		b += float32(c.B)
+		a += float32(c.A) * float32(st.Opacity)
+	}
+	n := float32(len(cs.Gradient.Stops))
+	avg := color.NRGBA{uint8(r / n), uint8(g / n), uint8(b / n), uint8(a / n)}
+	return VecAverageColor{rasterx.ApplyOpacity(avg, float64(opacity))}
+}
+
+// RectPath returns a path for the given rectangle
+func RectPath(r image.Rectangle) rasterx.Path {
+	var p rasterx.Path
+	p.Start(fixed.P(r.Min.X, r.Min.Y))
+	p.Line(fixed.P(r.Max.X, r.Min.Y))
+	p.Line(fixed.P(r.Max.X, r.Max.Y))
+	p.Line(fixed.P(r.Min.X, r.Max.Y))
+	p.Stop(true)
+	return p
+}
+
+// XFormPath returns a copy of the given path with all points transformed
+// by given matrix
+func XFormPath(p rasterx.Path, xf Matrix2D) rasterx.Path {
+	var np rasterx.Path
+	p.AddTo(&xformAdder{dst: &np, xf: xf})
+	return np
+}
+
+// xformAdder is a rasterx.Adder that transforms points before adding them
+// to another Adder
+type xformAdder struct {
+	dst rasterx.Adder
+	xf  Matrix2D
+}
+
+func (xa *xformAdder) pt(p fixed.Point26_6) fixed.Point26_6 {
+	return xa.xf.TransformPointVec2D(NewVec2DFmFixed(p)).Fixed()
+}
+
+func (xa *xformAdder) Start(a fixed.Point26_6) {
+	xa.dst.Start(xa.pt(a))
+}
+
+func (xa *xformAdder) Line(b fixed.Point26_6) {
+	xa.dst.Line(xa.pt(b))
+}
+
+func (xa *xformAdder) QuadBezier(b, c fixed.Point26_6) {
+	xa.dst.QuadBezier(xa.pt(b), xa.pt(c))
+}
+
+func (xa *xformAdder) CubeBezier(b, c, d fixed.Point26_6) {
+	xa.dst.CubeBezier(xa.pt(b), xa.pt(c), xa.pt(d))
+}
+
+func (xa *xformAdder) Stop(closeLoop bool) {
+	xa.dst.Stop(closeLoop)
+}
+
+// GlyphOutline returns the outline of the glyph for given rune in given
+// truetype font at given size (in dots), as a path with its origin (left
+// baseline) at pos, transformed by xf (e.g., rotation, scaling) around that
+// origin -- returns false if the font has no glyph for the rune
+func GlyphOutline(f *truetype.Font, size float32, r rune, pos Vec2D, xf Matrix2D) (rasterx.Path, bool) {
+	idx := f.Index(r)
+	if idx == 0 {
+		return nil, false
+	}
+	return GlyphIndexOutline(f, size, idx, pos, xf)
+}
+
+// GlyphIndexOutline returns the outline of the glyph of given index in given
+// truetype font, as in GlyphOutline -- e.g., for glyphs substituted in text
+// shaping, which do not correspond to a rune -- returns false if the glyph
+// cannot be loaded or has no outline
+func GlyphIndexOutline(f *truetype.Font, size float32, idx truetype.Index, pos Vec2D, xf Matrix2D) (rasterx.Path, bool) {
+	var gb truetype.GlyphBuf
+	if err := gb.Load(f, Float32ToFixed(size), idx, font.HintingNone); err != nil {
+		return nil, false
+	}
+	return PointsOutline(gb.Points, gb.Ends, pos, xf)
+}
+
+// PointsOutline returns the outline for given truetype glyph points (in
+// dots, with y up) and contour ends, as in GlyphOutline -- returns false if
+// there is no outline
+func PointsOutline(pts []truetype.Point, ends []int, pos Vec2D, xf Matrix2D) (rasterx.Path, bool) {
+	var p rasterx.Path
+	tp := func(gp truetype.Point) Vec2D {
+		return pos.Add(xf.TransformVectorVec2D(Vec2D{FixedToFloat32(gp.X), -FixedToFloat32(gp.Y)}))
+	}
+	on := func(gp truetype.Point) bool {
+		return gp.Flags&0x01 != 0
+	}
+	st := 0
+	for _, ed := range ends {
+		ps := pts[st:ed]
+		st = ed
+		n := len(ps)
+		if n == 0 {
+			continue
+		}
+		var start Vec2D
+		if on(ps[0]) {
+			start = tp(ps[0])
+			ps = ps[1:]
+		} else if on(ps[n-1]) {
+			start = tp(ps[n-1])
+			ps = ps[:n-1]
+		} else {
+			start = tp(ps[0]).Interpolate(tp(ps[n-1]), 0.5)
+		}
+		p.Start(start.Fixed())
+		q0, on0 := start, true
+		for _, gp := range ps {
+			q, on1 := tp(gp), on(gp)
+			if on1 {
+				if on0 {
+					p.Line(q.Fixed())
+				} else {
+					p.QuadBezier(q0.Fixed(), q.Fixed())
+				}
+			} else if !on0 {
+				p.QuadBezier(q0.Fixed(), q0.Interpolate(q, 0.5).Fixed())
+			}
+			q0, on0 = q, on1
+		}
+		if on0 {
+			p.Line(start.Fixed())
+		} else {
+			p.QuadBezier(q0.Fixed(), start.Fixed())
+		}
+		p.Stop(true)
+	}
+	return p, len(p) > 0
+}
+
+//////////////////////////////////////////////////////////////////////////////////
+//  VecRecord
+
+// vecOpKinds are the kinds of recorded vector operations
+type vecOpKinds int
+
+const (
+	vecOpFill vecOpKinds = iota
+	vecOpStroke
+	vecOpImage
+)
+
+// vecOp is one recorded vector rendering operation
+type vecOp struct {
+	kind    vecOpKinds
+	path    rasterx.Path
+	color   color.Color
+	nonZero bool
+	stroke  VecStroke
+	image   image.Image
+	xf      Matrix2D
+	clip    image.Rectangle
+}
+
+// VecRecord records the rendering operations sent to it as a VectorRenderer
+// (obtained from its Renderer method), in a common coordinate system.  The
+// recording can be written out as PDF or EPS, or rasterized into an image at
+// any scale.  Text glyphs are recorded as filled outlines when the font
+// outline is available, and otherwise as glyph images.
+type VecRecord struct {
+	Size  image.Point `desc:"size of the recorded drawing, in device pixels"`
+	DPI   float32     `desc:"dots per inch of the device pixels -- determines the physical size of the drawing"`
+	ops   []vecOp
+	faces map[font.Face]*vecFace
+	nGrad int // number of fills and strokes of gradients drawn as their average color
+}
+
+// vecFace caches the outline font info for a font face
+type vecFace struct {
+	font *truetype.Font
+	size float32
+}
+
+// NewVecRecord returns a new recording of given size in pixels at given dpi
+func NewVecRecord(size image.Point, dpi float32) *VecRecord {
+	if dpi == 0 {
+		dpi = 96
+	}
+	return &VecRecord{Size: size, DPI: dpi}
+}
+
+// Renderer returns a VectorRenderer that records into this recording, with
+// all coordinates translated by given offset -- e.g., one for each viewport
+// that renders into the recording
+func (vr *VecRecord) Renderer(off image.Point) VectorRenderer {
+	return &vecRecorder{rec: vr, off: off}
+}
+
+// Translate moves all recorded operations by given offset
+func (vr *VecRecord) Translate(off image.Point) {
+	xf := Translate2D(float32(off.X), float32(off.Y))
+	for i := range vr.ops {
+		op := &vr.ops[i]
+		if op.kind == vecOpImage {
+			op.xf = op.xf.Multiply(xf)
+		} else {
+			op.path = XFormPath(op.path, xf)
+		}
+		op.clip = op.clip.Add(off)
+	}
+}
+
+// addColor notes the color of a fill or stroke, counting gradients drawn as
+// their average color
+func (vr *VecRecord) addColor(clr color.Color) {
+	if _, ok := clr.(VecAverageColor); ok {
+		vr.nGrad++
+	}
+}
+
+// logLossy logs that the output of given writer method is lossy, if any
+// gradients were drawn as their average color
+func (vr *VecRecord) logLossy(meth string) {
+	if vr.nGrad > 0 {
+		log.Printf("gi.VecRecord %v: output is lossy: %v gradient fills / strokes are drawn as the average color of their stops\n", meth, vr.nGrad)
+	}
+}
+
+// vecFace returns the outline font info for given face, or nil if not available
+func (vr *VecRecord) vecFace(face font.Face) *vecFace {
+	if vf, ok := vr.faces[face]; ok {
+		return vf
+	}
+	if vr.faces == nil {
+		vr.faces = make(map[font.Face]*vecFace)
+	}
+	var vf *vecFace
+	if fnm, sz, ok := FontLibrary.FaceInfo(face); ok {
+		if f, err := FontLibrary.TrueTypeFont(fnm); err == nil {
+			vf = &vecFace{font: f, size: float32(sz)}
+		}
+	}
+	vr.faces[face] = vf
+	return vf
+}
+
+// vecRecorder is the VectorRenderer for a VecRecord, with an offset
+type vecRecorder struct {
+	rec *VecRecord
+	off image.Point
+}
+
+func (vr *vecRecorder) xform() Matrix2D {
+	return Translate2D(float32(vr.off.X), float32(vr.off.Y))
+}
+
+func (vr *vecRecorder) FillPath(path rasterx.Path, clr color.Color, nonZero bool, clip image.Rectangle) {
+	vr.rec.addColor(clr)
+	vr.rec.ops = append(vr.rec.ops, vecOp{kind: vecOpFill, path: XFormPath(path, vr.xform()), color: clr, nonZero: nonZero, clip: clip.Add(vr.off)})
+}
+
+func (vr *vecRecorder) StrokePath(path rasterx.Path, clr color.Color, st *VecStroke, clip image.Rectangle) {
+	vr.rec.addColor(clr)
+	vst := *st
+	vst.Dashes = append([]float64(nil), st.Dashes...)
+	vr.rec.ops = append(vr.rec.ops, vecOp{kind: vecOpStroke, path: XFormPath(path, vr.xform()), color: clr, stroke: vst, clip: clip.Add(vr.off)})
+}
+
+func (vr *vecRecorder) DrawGlyph(face font.Face, r rune, pos Vec2D, rot, scalex float32, clr color.Color, clip image.Rectangle) {
+	if scalex == 0 {
+		scalex = 1
+	}
+	tx := Scale2D(scalex, 1).Rotate(rot)
+	pos = pos.Add(NewVec2DFmPoint(vr.off))
+	if of, ok := face.(OutlineFace); ok {
+		if p, ok := of.RuneOutline(r, pos, tx); ok {
+			vr.rec.ops = append(vr.rec.ops, vecOp{kind: vecOpFill, path: p, color: clr, nonZero: true, clip: clip.Add(vr.off)})
+			return
+		}
+	} else if vf := vr.rec.vecFace(face); vf != nil {
+		if p, ok := GlyphOutline(vf.font, vf.size, r, pos, tx); ok {
+			vr.rec.ops = append(vr.rec.ops, vecOp{kind: vecOpFill, path: p, color: clr, nonZero: true, clip: clip.Add(vr.off)})
+			return
+		}
+	}
+	// no outline available: record the glyph image
+	dr, mask, maskp, _, ok := face.Glyph(fixed.Point26_6{}, r)
+	if !ok || dr.Empty() {
+		return
+	}
+	gimg := image.NewRGBA(image.Rectangle{Max: dr.Size()})
+	draw.DrawMask(gimg, gimg.Bounds(), image.NewUniform(clr), image.ZP, mask, maskp, draw.Over)
+	xf := Translate2D(float32(dr.Min.X), float32(dr.Min.Y)).Multiply(tx).Multiply(Translate2D(pos.X, pos.Y))
+	vr.rec.ops = append(vr.rec.ops, vecOp{kind: vecOpImage, image: gimg, xf: xf, clip: clip.Add(vr.off)})
+}
+
+func (vr *vecRecorder) DrawImage(img image.Image, xf Matrix2D, clip image.Rectangle) {
+	vr.rec.ops = append(vr.rec.ops, vecOp{kind: vecOpImage, image: img, xf: xf.Multiply(vr.xform()), clip: clip.Add(vr.off)})
+}
+
+// RenderImage rasterizes the recording into a new image, with all
+// coordinates multiplied by given scale factor (e.g., the ratio of a target
+// DPI to the recording DPI)
+func (vr *VecRecord) RenderImage(scale float32) *image.RGBA {
+	if scale <= 0 {
+		scale = 1
+	}
+	vr.logLossy("RenderImage")
+	sz := image.Point{int(math32.Ceil(float32(vr.Size.X) * scale)), int(math32.Ceil(float32(vr.Size.Y) * scale))}
+	img := image.NewRGBA(image.Rectangle{Max: sz})
+	scanner := scanFT.NewScannerFT(sz.X, sz.Y, scanFT.NewRGBAPainter(img))
+	raster := rasterx.NewDasher(sz.X, sz.Y, scanner)
+	xf := Scale2D(scale, scale)
+	pc := &Paint{}
+	for _, op := range vr.ops {
+		clip := img.Bounds()
+		if !op.clip.Empty() {
+			clip = vecScaleRect(op.clip, scale).Intersect(clip)
+			if clip.Empty() {
+				continue
+			}
+		}
+		switch op.kind {
+		case vecOpFill:
+			rf := &raster.Filler
+			rf.SetWinding(op.nonZero)
+			scanner.SetClip(clip)
+			op.path.AddTo(&xformAdder{dst: rf, xf: xf})
+			rf.SetColor(op.color)
+			rf.Draw()
+			rf.Clear()
+		case vecOpStroke:
+			pc.StrokeStyle.Cap = op.stroke.Cap
+			pc.StrokeStyle.Join = op.stroke.Join
+			var dash []float64
+			for _, d := range op.stroke.Dashes {
+				dash = append(dash, d*float64(scale))
+			}
+			raster.SetStroke(Float32ToFixed(op.stroke.Width*scale), Float32ToFixed(op.stroke.MiterLimit),
+				pc.capfunc(), nil, nil, pc.joinmode(), dash, 0)
+			scanner.SetClip(clip)
+			op.path.AddTo(&xformAdder{dst: raster, xf: xf})
+			raster.SetColor(op.color)
+			raster.Draw()
+			raster.Clear()
+		case vecOpImage:
+			m := op.xf.Multiply(xf)
+			s2d := f64.Aff3{float64(m.XX), float64(m.XY), float64(m.X0), float64(m.YX), float64(m.YY), float64(m.Y0)}
+			draw.BiLinear.Transform(img.SubImage(clip).(*image.RGBA), s2d, op.image, op.image.Bounds(), draw.Over, nil)
+		}
+	}
+	return img
+}
+
+// WritePNG rasterizes the recording at given dpi (0 = recording DPI) and
+// writes it in PNG format to given writer
+func (vr *VecRecord) WritePNG(w io.Writer, dpi float32) error {
+	scale := float32(1)
+	if dpi > 0 {
+		scale = dpi / vr.DPI
+	}
+	return png.Encode(w, vr.RenderImage(scale))
+}
+
+// vecScaleRect returns the rectangle scaled by given factor, rounding outward
+func vecScaleRect(r image.Rectangle, scale float32) image.Rectangle {
+	return image.Rect(int(math32.Floor(float32(r.Min.X)*scale)), int(math32.Floor(float32(r.Min.Y)*scale)),
+		int(math32.Ceil(float32(r.Max.X)*scale)), int(math32.Ceil(float32(r.Max.Y)*scale)))
+}
@@ -30,6 +30,14 @@ type ViewIFace interface {
 
 	// KeyMapsView opens an interactive view of KeyMaps object
 	KeyMapsView(maps *KeyMaps)
+
+	// UndoValues undoes the last change to values made through the views in
+	// given window -- returns false if there is nothing to undo
+	UndoValues(win *Window) bool
+
+	// RedoValues redoes the last undone change to values made through the
+	// views in given window -- returns false if there is nothing to redo
+	RedoValues(win *Window) bool
 }
 
 // TheViewIFace is the implemenation of the interface, defined in giv package
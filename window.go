@@ -119,6 +119,7 @@ type Window struct {
 	OverTex          oswin.Texture                           `json:"-" xml:"-" view:"-" desc:"overlay texture that is updated by OverlayVp viewport"`
 	LastModBits      int32                                   `json:"-" xml:"-" desc:"Last modifier key bits from most recent Mouse, Keyboard events"`
 	LastSelMode      mouse.SelectModes                       `json:"-" xml:"-" desc:"Last Select Mode from most recent Mouse, Keyboard events"`
+	EventSeq         int64                                   `json:"-" xml:"-" desc:"sequence number of the event being processed -- incremented for each event, so that changes made in response to one user action can be grouped together, e.g., for undo"`
 	Focus            ki.Ki                                   `json:"-" xml:"-" desc:"node receiving keyboard events"`
 	FocusActive      bool                                    `json:"-" xml:"-" desc:"is the focused node active, or have other things been clicked in the meantime?"`
 	StartFocus       ki.Ki                                   `json:"-" xml:"-" desc:"node to focus on at start when no other focus has been set yet"`
@@ -1100,6 +1101,7 @@ mainloop:
 		if et != oswin.WindowResizeEvent && et != oswin.WindowPaintEvent {
 			w.Resizing = false
 		}
+		w.EventSeq++
 
 		////////////////////////////////////////////////////////////////////////////
 		// Detect start of drag and DND -- both require delays in starting due
@@ -1493,7 +1495,7 @@ func (w *Window) SendEventSignal(evi oswin.Event, popup bool) {
 								continue
 							}
 						} else {
-							if pos.In(ni.WinBBox) {
+							if PosInNode(pos, ni) {
 								rvs.AddDepth(recv, fun, w)
 								break
 							}
@@ -1507,7 +1509,7 @@ func (w *Window) SendEventSignal(evi oswin.Event, popup bool) {
 								continue
 							}
 						} else {
-							if pos.In(ni.WinBBox) {
+							if PosInNode(pos, ni) {
 								rvs.AddDepth(recv, fun, w)
 								break
 							}
@@ -1518,7 +1520,7 @@ func (w *Window) SendEventSignal(evi oswin.Event, popup bool) {
 							rvs.Add(recv, fun, 10000) // top priority -- can't steal!
 							break
 						}
-						if !pos.In(ni.WinBBox) {
+						if !PosInNode(pos, ni) {
 							continue
 						}
 					}
@@ -1579,7 +1581,7 @@ func (w *Window) GenMouseFocusEvents(mev *mouse.MoveEvent, popup bool) bool {
 				if !w.IsInScope(ni, popup) {
 					return false
 				}
-				in := pos.In(ni.WinBBox)
+				in := PosInNode(pos, ni)
 				if in {
 					if !bitflag.Has(ni.Flag, int(MouseHasEntered)) {
 						fe.Action = mouse.Enter
@@ -1637,7 +1639,7 @@ func (w *Window) DoInstaDrag(me *mouse.DragEvent, popup bool) bool {
 					continue
 				}
 				pos := me.Pos()
-				if pos.In(ni.WinBBox) {
+				if PosInNode(pos, ni) {
 					if ni.IsInstaDrag() {
 						w.Dragging = ni.This
 						bitflag.Set(ni.Flags(), int(NodeDragging))
@@ -1929,6 +1931,14 @@ func (w *Window) KeyChordEventLowPri(e *key.ChordEvent) bool {
 	case KeyFunPrefs:
 		TheViewIFace.PrefsView(&Prefs)
 		e.SetProcessed()
+	case KeyFunUndo:
+		if TheViewIFace.UndoValues(w) {
+			e.SetProcessed()
+		}
+	case KeyFunRedo:
+		if TheViewIFace.RedoValues(w) {
+			e.SetProcessed()
+		}
 	case KeyFunRefresh:
 		fmt.Printf("Window: %v display refreshed\n", w.Nm)
 		w.FullReRender()
@@ -2309,7 +2319,7 @@ func (w *Window) GenDNDFocusEvents(mev *dnd.MoveEvent, popup bool) bool {
 				if !w.IsInScope(ni, popup) {
 					continue
 				}
-				in := pos.In(ni.WinBBox)
+				in := PosInNode(pos, ni)
 				if in {
 					if !bitflag.Has(ni.Flag, int(DNDHasEntered)) {
 						bitflag.Set(&ni.Flag, int(DNDHasEntered))